@@ -5,12 +5,24 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/carrier"
 	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/grpc"
 	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/http"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/invoice"
 	"github.com/Mazin-Ibrahim/book-store/internal/adapter/logger"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/mail"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/oidc"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/filesystem"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/memory"
 	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
 	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres/repository"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/redis"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/tax"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/service"
 )
 
@@ -36,23 +48,202 @@ func main() {
 	}
 	defer db.Close()
 
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := http.NewAuditHandler(auditService)
+
+	translationRepo := repository.NewTranslationRepository(db)
+	translationService := service.NewTranslationService(translationRepo, auditService, config.I18N.DefaultLocale)
+	translationHandler := http.NewTranslationHandler(translationService)
+
 	bookRepo := repository.NewBookRepository(db)
-	bookService := service.NewBookService(bookRepo)
+	bookService := service.NewBookService(bookRepo, translationService, auditService)
 	bookHandler := http.NewBookHandler(bookService)
 
+	var mailService port.MailService = mail.NewLogMailer()
+	if config.Mail.Host != "" {
+		mailService = mail.NewSMTPMailer(config.Mail)
+	}
+
+	// Failed logins and pending OIDC logins are shared through redis when it is configured
+	var loginAttemptRepo port.LoginAttemptRepository = memory.NewLoginAttemptRepository()
+	var oidcStateRepo port.OIDCStateRepository = memory.NewOIDCStateRepository()
+	if config.Redis.Addr != "" {
+		rdb, err := redis.New(ctx, config.Redis)
+		if err != nil {
+			slog.Error("Error initializing redis connection", "error", err)
+			os.Exit(1)
+		}
+		defer rdb.Close()
+		loginAttemptRepo = redis.NewLoginAttemptRepository(rdb)
+		oidcStateRepo = redis.NewOIDCStateRepository(rdb)
+	}
+	lockoutPolicy := domain.LockoutPolicy{
+		MaxAccountFailures: config.Lockout.MaxAccountFailures,
+		MaxIPFailures:      config.Lockout.MaxIPFailures,
+		FailureWindow:      config.Lockout.FailureWindow,
+		LockoutDuration:    config.Lockout.LockoutDuration,
+		BaseDelay:          config.Lockout.BaseDelay,
+		MaxDelay:           config.Lockout.MaxDelay,
+	}
+
+	twoFactorPolicy := domain.TwoFactorPolicy{}
+	for _, role := range config.TwoFactor.RequiredRoles {
+		twoFactorPolicy.RequiredRoles = append(twoFactorPolicy.RequiredRoles, domain.UserRole(role))
+	}
+
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	tokenService := service.TokenService{}
+
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
+	emailChangeRepo := repository.NewEmailChangeRepository(db)
+	userService := service.NewUserService(userRepo, emailChangeRepo, mailService, &tokenService, auditService)
 	userHandler := http.NewUserHandler(userService)
 
-	tokenService := service.TokenService{}
-	authService := service.NewAuthService(userRepo, &tokenService)
-	authHandler := http.NewAuthHandler(authService)
+	authService := service.NewAuthService(userRepo, &tokenService, passwordResetRepo, mailService, loginAttemptRepo, lockoutPolicy, twoFactorRepo, twoFactorPolicy, auditService)
+
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, auditService)
+	apiKeyHandler := http.NewAPIKeyHandler(apiKeyService)
+	authHandler := http.NewAuthHandler(authService, apiKeyService)
+
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, auditService, config.App.Name)
+	twoFactorHandler := http.NewTwoFactorHandler(twoFactorService)
+
+	var oidcProviders []port.OIDCProvider
+	for _, provider := range config.OIDC.Providers {
+		oidcProviders = append(oidcProviders, oidc.NewProvider(provider))
+	}
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	oidcService := service.NewOIDCService(oidcProviders, oidcStateRepo, userIdentityRepo, userRepo, twoFactorRepo, &tokenService, auditService)
+	oidcHandler := http.NewOIDCHandler(oidcService)
+
+	addressRepo := repository.NewAddressRepository(db)
+	addressService := service.NewAddressService(addressRepo, auditService)
+	addressHandler := http.NewAddressHandler(addressService)
+
+	categoryRepo := repository.NewCategoryRepository(db)
+	categoryService := service.NewCategoryService(categoryRepo, translationService, auditService)
+	categoryHandler := http.NewCategoryHandler(categoryService)
+
+	publisherRepo := repository.NewPublisherRepository(db)
+	publisherService := service.NewPublisherService(publisherRepo, bookRepo, translationService, auditService)
+	publisherHandler := http.NewPublisherHandler(publisherService)
+
+	seriesRepo := repository.NewSeriesRepository(db)
+	seriesService := service.NewSeriesService(seriesRepo, bookRepo, translationService, auditService)
+	seriesHandler := http.NewSeriesHandler(seriesService)
+
+	promotionRepo := repository.NewPromotionRepository(db)
+	promotionService := service.NewPromotionService(promotionRepo, auditService)
+	promotionHandler := http.NewPromotionHandler(promotionService)
+
+	shippingRepo := repository.NewShippingRepository(db)
+	shippingService := service.NewShippingService(shippingRepo, auditService)
+	shippingHandler := http.NewShippingHandler(shippingService)
+
+	taxMode, err := domain.ParseTaxMode(config.Tax.Mode)
+	if err != nil {
+		slog.Error("Error loading tax configuration", "error", err)
+		os.Exit(1)
+	}
+	// Other tax providers plug in here by implementing port.TaxCalculator
+	var taxCalculator port.TaxCalculator
+	switch config.Tax.Provider {
+	case "", "table":
+		taxCalculator = tax.NewTableCalculator(tax.DefaultRates)
+	default:
+		slog.Error("Error loading tax configuration", "error", fmt.Sprintf("unknown tax provider %q", config.Tax.Provider))
+		os.Exit(1)
+	}
+
+	blobStorage, err := filesystem.NewBlobStorage(config.Blob.Dir)
+	if err != nil {
+		slog.Error("Error initializing blob storage", "error", err)
+		os.Exit(1)
+	}
+	editionRepo := repository.NewEditionRepository(db)
+	editionService := service.NewEditionService(editionRepo, bookRepo, blobStorage, auditService)
+	editionHandler := http.NewEditionHandler(editionService)
+
+	orderRepo := repository.NewOrderReposiotory(db)
+	invoiceSeller := domain.InvoiceSeller{
+		Name:    config.Invoice.SellerName,
+		Address: config.Invoice.SellerAddress,
+		TaxID:   config.Invoice.SellerTaxID,
+	}
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	invoiceService := service.NewInvoiceService(invoiceRepo, orderRepo, bookRepo, invoice.NewPDFRenderer(), blobStorage, invoiceSeller, auditService)
+	downloadRepo := repository.NewDownloadRepository(db)
+	downloadService := service.NewDownloadService(downloadRepo, orderRepo, editionRepo, blobStorage, config.Download.SigningKey, config.Download.LinkTTL, config.Download.ResumeTTL, config.Download.MaxDownloads)
+	orderService := service.NewOrderService(orderRepo, bookRepo, editionRepo, addressRepo, promotionService, shippingService, taxCalculator, invoiceService, downloadService, auditService, taxMode)
+	orderHandler := http.NewOrderService(orderService)
+
+	// Other carriers plug in here by implementing port.Carrier
+	var shipmentCarrier port.Carrier
+	switch config.Carrier.Provider {
+	case "", "fake":
+		shipmentCarrier = carrier.NewFakeCarrier(config.Carrier.FakeStep)
+	default:
+		slog.Error("Error loading carrier configuration", "error", fmt.Sprintf("unknown carrier provider %q", config.Carrier.Provider))
+		os.Exit(1)
+	}
+	fulfillmentRepo := repository.NewFulfillmentRepository(db)
+	fulfillmentService := service.NewFulfillmentService(fulfillmentRepo, orderRepo, bookRepo, shipmentCarrier, auditService)
+	fulfillmentHandler := http.NewFulfillmentHandler(fulfillmentService, orderService)
+
+	invoiceHandler := http.NewInvoiceHandler(invoiceService, orderService)
+
+	downloadHandler := http.NewDownloadHandler(downloadService, orderService)
+
+	erasureRepo := repository.NewErasureRepository(db)
+	privacyService := service.NewPrivacyService(erasureRepo, userRepo, addressRepo, orderRepo, mailService, auditService, config.Privacy.ErasureGracePeriod)
+	privacyHandler := http.NewPrivacyHandler(privacyService)
+
+	priceRepo := repository.NewPriceRepository(db)
+	pricingService := service.NewPricingService(priceRepo, editionRepo, auditService)
+	pricingHandler := http.NewPricingHandler(pricingService)
+
+	// Erasures are carried out once their grace period has passed and soft-deleted rows are
+	// purged after the retention window
+	go runPeriodically(ctx, "data erasure", config.Privacy.ErasureInterval, privacyService.ProcessDueErasures)
+	go runPeriodically(ctx, "soft delete purge", config.Retention.PurgeInterval, func(ctx context.Context) error {
+		books, err := bookService.PurgeDeletedBooks(ctx, config.Retention.SoftDeleteRetention)
+		if err != nil {
+			return err
+		}
+		users, err := userService.PurgeDeletedUsers(ctx, config.Retention.SoftDeleteRetention)
+		if err != nil {
+			return err
+		}
+		slog.Info("Purged soft-deleted rows", "books", books, "users", users)
+		return nil
+	})
+	// Scheduled prices are applied and reverted as their start and end times pass
+	go runPeriodically(ctx, "price scheduler", config.Pricing.SchedulerInterval, pricingService.ApplyDuePriceSchedules)
+	// Shipments on their way are followed with the carrier until they are delivered
+	go runPeriodically(ctx, "shipment tracking", config.Carrier.TrackingInterval, fulfillmentService.RefreshTracking)
 
-	router, err := http.NewRouter(config.HTTP, *bookHandler, *userHandler, *authHandler)
+	router, err := http.NewRouter(config.HTTP, config.I18N, *bookHandler, *userHandler, *authHandler, *twoFactorHandler, *oidcHandler, *apiKeyHandler, *orderHandler, *addressHandler, *privacyHandler, *auditHandler, *pricingHandler, *categoryHandler, *promotionHandler, *shippingHandler, *fulfillmentHandler, *invoiceHandler, *editionHandler, *downloadHandler, *publisherHandler, *seriesHandler, *translationHandler)
 	if err != nil {
 		slog.Error("Error initializing router", "error", err)
 		os.Exit(1)
 	}
+
+	// Internal services call the catalog and orders over gRPC next to the HTTP API
+	if config.GRPC.Port != "" {
+		grpcServer := grpc.NewServer(authService, apiKeyService, bookService, orderService, userService)
+		grpcAddr := fmt.Sprintf("%s:%s", config.GRPC.URL, config.GRPC.Port)
+		go func() {
+			slog.Info("Starting the gRPC server", "listen_address", grpcAddr)
+			if err := grpcServer.Serve(grpcAddr); err != nil {
+				slog.Error("Error starting the gRPC server", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	listenAddr := fmt.Sprintf("%s:%s", config.HTTP.URL, config.HTTP.Port)
 	slog.Info("Starting the HTTP server", "listen_address", listenAddr)
 	err = router.Serve(listenAddr)
@@ -62,3 +253,19 @@ func main() {
 	}
 
 }
+
+// runPeriodically runs a background job every interval until the context is done
+func runPeriodically(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				slog.Error("Error running background job", "job", name, "error", err)
+			}
+		}
+	}
+}
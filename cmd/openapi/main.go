@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/http"
+)
+
+// openapi writes the OpenAPI document of the /v1 API, or with -check fails when a route of the
+// router is missing from the document or the document describes a route that no longer exists
+func main() {
+	out := flag.String("out", "", "file to write the OpenAPI document to, stdout when empty")
+	check := flag.Bool("check", false, "check that the document covers every route of the router")
+	flag.Parse()
+
+	if *check {
+		// The handlers are never called, walking the router only needs their methods
+		router, err := http.NewRouter(&config.HTTP{}, &config.I18N{DefaultLocale: "en", Locales: []string{"en"}},
+			http.BookHandler{}, http.UserHandler{}, http.AuthHandler{}, http.TwoFactorHandler{}, http.OIDCHandler{},
+			http.APIKeyHandler{}, http.OrderHandler{}, http.AddressHandler{}, http.PrivacyHandler{}, http.AuditHandler{},
+			http.PricingHandler{}, http.CategoryHandler{}, http.PromotionHandler{}, http.ShippingHandler{},
+			http.FulfillmentHandler{}, http.InvoiceHandler{}, http.EditionHandler{}, http.DownloadHandler{},
+			http.PublisherHandler{}, http.SeriesHandler{}, http.TranslationHandler{})
+		if err != nil {
+			slog.Error("Error initializing router", "error", err)
+			os.Exit(1)
+		}
+		missing, stale, err := router.UndocumentedRoutes()
+		if err != nil {
+			slog.Error("Error walking the router", "error", err)
+			os.Exit(1)
+		}
+		for _, route := range missing {
+			fmt.Fprintf(os.Stderr, "route missing from the OpenAPI document: %s\n", route)
+		}
+		for _, route := range stale {
+			fmt.Fprintf(os.Stderr, "documented route missing from the router: %s\n", route)
+		}
+		if len(missing) > 0 || len(stale) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	document, err := http.OpenAPI()
+	if err != nil {
+		slog.Error("Error generating the OpenAPI document", "error", err)
+		os.Exit(1)
+	}
+	if *out == "" {
+		os.Stdout.Write(append(document, '\n'))
+		return
+	}
+	if err := os.WriteFile(*out, append(document, '\n'), 0o644); err != nil {
+		slog.Error("Error writing the OpenAPI document", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,84 @@
+package carrier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+const fakePrefix = "FAKE"
+
+// fakeSteps is the journey of every fake parcel, one step passes every step duration
+var fakeSteps = []struct {
+	status      domain.FulfillmentStatus
+	description string
+	location    string
+}{
+	{domain.FulfillmentInTransit, "Picked up by the courier", "Local depot"},
+	{domain.FulfillmentInTransit, "Arrived at the sorting center", "Sorting center"},
+	{domain.FulfillmentInTransit, "Out for delivery", "Delivery depot"},
+	{domain.FulfillmentDelivered, "Delivered", ""},
+}
+
+// FakeCarrier pretends to ship parcels for local testing. Parcels move one step of their journey
+// every step duration until they are delivered. The shipping time is encoded in the tracking
+// number so tracking keeps working across restarts.
+type FakeCarrier struct {
+	step time.Duration
+	now  func() time.Time
+}
+
+func NewFakeCarrier(step time.Duration) *FakeCarrier {
+	return &FakeCarrier{
+		step: step,
+		now:  time.Now,
+	}
+}
+
+func (fc *FakeCarrier) Name() string {
+	return "fake"
+}
+
+// CreateShipment returns a tracking number of the form FAKE-<shipped at>-<random>
+func (fc *FakeCarrier) CreateShipment(ctx context.Context, parcel *domain.Parcel) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	shippedAt := strconv.FormatInt(fc.now().Unix(), 36)
+	return strings.ToUpper(fmt.Sprintf("%s-%s-%s", fakePrefix, shippedAt, hex.EncodeToString(suffix))), nil
+}
+
+// Track returns the steps the parcel went through so far
+func (fc *FakeCarrier) Track(ctx context.Context, trackingNumber string) ([]domain.TrackingEvent, error) {
+	parts := strings.Split(trackingNumber, "-")
+	if len(parts) != 3 || parts[0] != fakePrefix {
+		return nil, domain.ErrUnknownTracking
+	}
+	seconds, err := strconv.ParseInt(strings.ToLower(parts[1]), 36, 64)
+	if err != nil {
+		return nil, domain.ErrUnknownTracking
+	}
+	shippedAt := time.Unix(seconds, 0)
+
+	events := []domain.TrackingEvent{}
+	for i, step := range fakeSteps {
+		occurredAt := shippedAt.Add(time.Duration(i+1) * fc.step)
+		if occurredAt.After(fc.now()) {
+			break
+		}
+		events = append(events, domain.TrackingEvent{
+			Status:      step.status,
+			Description: step.description,
+			Location:    step.location,
+			OccurredAt:  occurredAt,
+		})
+	}
+	return events, nil
+}
@@ -0,0 +1,60 @@
+package carrier
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+func TestFakeCarrierTrack(t *testing.T) {
+	shippedAt := time.Date(2025, 2, 7, 10, 0, 0, 0, time.UTC)
+	carrier := NewFakeCarrier(time.Hour)
+	carrier.now = func() time.Time { return shippedAt }
+
+	trackingNumber, err := carrier.CreateShipment(context.Background(), &domain.Parcel{FulfillmentID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(trackingNumber, fakePrefix+"-") {
+		t.Fatalf("tracking number %q does not start with %s", trackingNumber, fakePrefix)
+	}
+
+	tests := []struct {
+		name       string
+		elapsed    time.Duration
+		wantEvents int
+		wantLast   domain.FulfillmentStatus
+	}{
+		{"just shipped", 0, 0, ""},
+		{"picked up", time.Hour, 1, domain.FulfillmentInTransit},
+		{"out for delivery", 3*time.Hour + time.Minute, 3, domain.FulfillmentInTransit},
+		{"delivered", 4 * time.Hour, 4, domain.FulfillmentDelivered},
+		{"long after delivery", 48 * time.Hour, 4, domain.FulfillmentDelivered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			carrier.now = func() time.Time { return shippedAt.Add(tt.elapsed) }
+			events, err := carrier.Track(context.Background(), trackingNumber)
+			if err != nil {
+				t.Fatalf("Track() error = %v", err)
+			}
+			if len(events) != tt.wantEvents {
+				t.Fatalf("got %d events, want %d", len(events), tt.wantEvents)
+			}
+			if tt.wantEvents > 0 && events[len(events)-1].Status != tt.wantLast {
+				t.Errorf("last status = %s, want %s", events[len(events)-1].Status, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestFakeCarrierUnknownTracking(t *testing.T) {
+	for _, trackingNumber := range []string{"", "FAKE", "UPS-1-2", "FAKE-!!-00", "FAKE-1-2-3"} {
+		if _, err := NewFakeCarrier(time.Hour).Track(context.Background(), trackingNumber); err != domain.ErrUnknownTracking {
+			t.Errorf("Track(%q) error = %v, want %v", trackingNumber, err, domain.ErrUnknownTracking)
+		}
+	}
+}
@@ -1,16 +1,35 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type (
 	Container struct {
-		App  *App
-		DB   *DB
-		HTTP *HTTP
+		App       *App
+		DB        *DB
+		HTTP      *HTTP
+		GRPC      *GRPC
+		Mail      *Mail
+		Redis     *Redis
+		Lockout   *Lockout
+		TwoFactor *TwoFactor
+		OIDC      *OIDC
+		Privacy   *Privacy
+		Retention *Retention
+		Pricing   *Pricing
+		Tax       *Tax
+		Carrier   *Carrier
+		Blob      *Blob
+		Invoice   *Invoice
+		Download  *Download
+		I18N      *I18N
 	}
 	App struct {
 		Name string
@@ -32,6 +51,102 @@ type (
 		URL            string
 		AllowedOrigins string
 	}
+
+	// GRPC is where the gRPC server listens, it is not started without a port
+	GRPC struct {
+		Port string
+		URL  string
+	}
+
+	Mail struct {
+		Host     string
+		Port     string
+		Username string
+		Password string
+		From     string
+	}
+
+	Redis struct {
+		Addr     string
+		Password string
+	}
+
+	Lockout struct {
+		MaxAccountFailures int64
+		MaxIPFailures      int64
+		FailureWindow      time.Duration
+		LockoutDuration    time.Duration
+		BaseDelay          time.Duration
+		MaxDelay           time.Duration
+	}
+
+	TwoFactor struct {
+		RequiredRoles []string
+	}
+
+	OIDC struct {
+		Providers []*OIDCProvider
+	}
+
+	Privacy struct {
+		ErasureGracePeriod time.Duration
+		ErasureInterval    time.Duration
+	}
+
+	Retention struct {
+		SoftDeleteRetention time.Duration
+		PurgeInterval       time.Duration
+	}
+
+	Pricing struct {
+		SchedulerInterval time.Duration
+	}
+
+	Tax struct {
+		Provider string
+		Mode     string
+	}
+
+	Carrier struct {
+		Provider         string
+		FakeStep         time.Duration
+		TrackingInterval time.Duration
+	}
+
+	Blob struct {
+		Dir string
+	}
+
+	// Invoice holds the seller printed on invoices, the address lines are separated by commas
+	Invoice struct {
+		SellerName    string
+		SellerAddress []string
+		SellerTaxID   string
+	}
+
+	Download struct {
+		SigningKey string
+		LinkTTL    time.Duration
+		// ResumeTTL is how long a counted download can be continued with range requests
+		ResumeTTL    time.Duration
+		MaxDownloads int
+	}
+
+	// I18N holds the locales content is served in. Base content is written in the default locale,
+	// which is always supported.
+	I18N struct {
+		DefaultLocale string
+		Locales       []string
+	}
+
+	OIDCProvider struct {
+		Name         string
+		IssuerURL    string
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		Scopes       []string
+	}
 )
 
 func New() (*Container, error) {
@@ -60,9 +175,215 @@ func New() (*Container, error) {
 		URL:            os.Getenv("HTTP_URL"),
 		AllowedOrigins: os.Getenv("HTTP_ALLOWED_ORIGINS"),
 	}
+	grpc := &GRPC{
+		Port: os.Getenv("GRPC_PORT"),
+		URL:  os.Getenv("GRPC_URL"),
+	}
+	if grpc.URL == "" {
+		grpc.URL = http.URL
+	}
+	mail := &Mail{
+		Host:     os.Getenv("MAIL_HOST"),
+		Port:     os.Getenv("MAIL_PORT"),
+		Username: os.Getenv("MAIL_USERNAME"),
+		Password: os.Getenv("MAIL_PASSWORD"),
+		From:     os.Getenv("MAIL_FROM"),
+	}
+	redis := &Redis{
+		Addr:     os.Getenv("REDIS_ADDR"),
+		Password: os.Getenv("REDIS_PASSWORD"),
+	}
+
+	var err error
+	lockout := &Lockout{}
+	if lockout.MaxAccountFailures, err = envInt("LOGIN_MAX_ACCOUNT_FAILURES", 5); err != nil {
+		return nil, err
+	}
+	if lockout.MaxIPFailures, err = envInt("LOGIN_MAX_IP_FAILURES", 50); err != nil {
+		return nil, err
+	}
+	if lockout.FailureWindow, err = envPositiveDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if lockout.LockoutDuration, err = envPositiveDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if lockout.BaseDelay, err = envDuration("LOGIN_BASE_DELAY", 250*time.Millisecond); err != nil {
+		return nil, err
+	}
+	if lockout.MaxDelay, err = envDuration("LOGIN_MAX_DELAY", 4*time.Second); err != nil {
+		return nil, err
+	}
+
+	twoFactor := &TwoFactor{
+		RequiredRoles: envList("TWO_FACTOR_REQUIRED_ROLES"),
+	}
+
+	// Every provider in OIDC_PROVIDERS is configured by variables prefixed with its upper-cased name,
+	// e.g. OIDC_GOOGLE_ISSUER_URL
+	oidc := &OIDC{}
+	for _, name := range envList("OIDC_PROVIDERS") {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		oidc.Providers = append(oidc.Providers, &OIDCProvider{
+			Name:         name,
+			IssuerURL:    os.Getenv(prefix + "ISSUER_URL"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       envList(prefix + "SCOPES"),
+		})
+	}
+
+	privacy := &Privacy{}
+	if privacy.ErasureGracePeriod, err = envDuration("PRIVACY_ERASURE_GRACE_PERIOD", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if privacy.ErasureInterval, err = envPositiveDuration("PRIVACY_ERASURE_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+
+	retention := &Retention{}
+	if retention.SoftDeleteRetention, err = envPositiveDuration("SOFT_DELETE_RETENTION", 90*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if retention.PurgeInterval, err = envPositiveDuration("SOFT_DELETE_PURGE_INTERVAL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+
+	pricing := &Pricing{}
+	if pricing.SchedulerInterval, err = envPositiveDuration("PRICE_SCHEDULER_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+
+	tax := &Tax{
+		Provider: os.Getenv("TAX_PROVIDER"),
+		Mode:     os.Getenv("TAX_MODE"),
+	}
+
+	carrier := &Carrier{
+		Provider: os.Getenv("CARRIER_PROVIDER"),
+	}
+	if carrier.FakeStep, err = envPositiveDuration("CARRIER_FAKE_STEP", time.Minute); err != nil {
+		return nil, err
+	}
+	if carrier.TrackingInterval, err = envPositiveDuration("TRACKING_REFRESH_INTERVAL", 5*time.Minute); err != nil {
+		return nil, err
+	}
+
+	blob := &Blob{
+		Dir: os.Getenv("BLOB_DIR"),
+	}
+	if blob.Dir == "" {
+		blob.Dir = "storage"
+	}
+
+	invoice := &Invoice{
+		SellerName:    os.Getenv("INVOICE_SELLER_NAME"),
+		SellerAddress: envList("INVOICE_SELLER_ADDRESS"),
+		SellerTaxID:   os.Getenv("INVOICE_SELLER_TAX_ID"),
+	}
+	if invoice.SellerName == "" {
+		invoice.SellerName = app.Name
+	}
+
+	download := &Download{
+		SigningKey: os.Getenv("DOWNLOAD_SIGNING_KEY"),
+	}
+	if download.SigningKey == "" {
+		download.SigningKey = os.Getenv("JWT_SECRET")
+	}
+	if download.LinkTTL, err = envPositiveDuration("DOWNLOAD_LINK_TTL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if download.ResumeTTL, err = envPositiveDuration("DOWNLOAD_RESUME_TTL", 6*time.Hour); err != nil {
+		return nil, err
+	}
+	maxDownloads, err := envInt("DOWNLOAD_MAX_COUNT", 5)
+	if err != nil {
+		return nil, err
+	}
+	download.MaxDownloads = int(maxDownloads)
+
+	i18n := &I18N{
+		DefaultLocale: os.Getenv("DEFAULT_LOCALE"),
+	}
+	if i18n.DefaultLocale == "" {
+		i18n.DefaultLocale = "en"
+	}
+	i18n.Locales = append([]string{i18n.DefaultLocale}, envList("SUPPORTED_LOCALES")...)
+
 	return &Container{
-		App:  app,
-		DB:   db,
-		HTTP: http,
+		App:       app,
+		DB:        db,
+		HTTP:      http,
+		GRPC:      grpc,
+		Mail:      mail,
+		Redis:     redis,
+		Lockout:   lockout,
+		TwoFactor: twoFactor,
+		OIDC:      oidc,
+		Privacy:   privacy,
+		Retention: retention,
+		Pricing:   pricing,
+		Tax:       tax,
+		Carrier:   carrier,
+		Blob:      blob,
+		Invoice:   invoice,
+		Download:  download,
+		I18N:      i18n,
 	}, nil
 }
+
+// envInt reads an integer environment variable, falling back to def when it is not set
+func envInt(key string, def int64) (int64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return i, nil
+}
+
+// envDuration reads a duration environment variable such as "15m", falling back to def when it is
+// not set. Negative durations are rejected.
+func envDuration(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid %s: %s is negative", key, value)
+	}
+	return d, nil
+}
+
+// envPositiveDuration reads a duration environment variable like envDuration that also has to be
+// above zero, such as the interval of a periodic job or how long data is kept
+func envPositiveDuration(key string, def time.Duration) (time.Duration, error) {
+	d, err := envDuration(key, def)
+	if err != nil {
+		return 0, err
+	}
+	if d == 0 {
+		return 0, fmt.Errorf("invalid %s: must be above zero", key)
+	}
+	return d, nil
+}
+
+// envList reads a comma separated environment variable
+func envList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
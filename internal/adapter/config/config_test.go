@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvDuration(t *testing.T) {
+	tests := []struct {
+		name            string
+		value           string
+		want            time.Duration
+		wantErr         bool
+		wantPositiveErr bool
+	}{
+		{"not set", "", time.Hour, false, false},
+		{"duration", "15m", 15 * time.Minute, false, false},
+		{"zero", "0", 0, false, true},
+		{"negative", "-24h", 0, true, true},
+		{"not a duration", "daily", 0, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_DURATION", tt.value)
+
+			got, err := envDuration("TEST_DURATION", time.Hour)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("envDuration() = %s, %v, want %s, error %t", got, err, tt.want, tt.wantErr)
+			}
+			got, err = envPositiveDuration("TEST_DURATION", time.Hour)
+			if wantErr := tt.wantErr || tt.wantPositiveErr; (err != nil) != wantErr || (!wantErr && got != tt.want) {
+				t.Errorf("envPositiveDuration() = %s, %v, want %s, error %t", got, err, tt.want, wantErr)
+			}
+		})
+	}
+}
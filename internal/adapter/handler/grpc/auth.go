@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/grpc/pb"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+var errMissingCredentials = domain.NewError(domain.KindUnauthorized, "missing_credentials", "authorization metadata is missing")
+
+type contextKey string
+
+const authIdentityKey contextKey = "auth_identity"
+
+// policy is who may call a method. Public methods still authenticate callers that send
+// credentials. API keys are only accepted by methods with a scope and every caller has to pass
+// the two-factor policy of their role, like the authenticated routes of the HTTP API.
+type policy struct {
+	public bool
+	roles  []domain.UserRole
+	scope  domain.APIKeyScope
+}
+
+var staffRoles = []domain.UserRole{domain.Staff, domain.Admin}
+
+// policies holds the policy of every method, methods without one need an authenticated caller
+// and do not accept API keys
+var policies = map[string]policy{
+	healthpb.Health_Check_FullMethodName: {public: true},
+
+	pb.BookService_GetBook_FullMethodName:     {public: true, scope: domain.ScopeBooksRead},
+	pb.BookService_ListBooks_FullMethodName:   {public: true, scope: domain.ScopeBooksRead},
+	pb.BookService_SearchBooks_FullMethodName: {public: true, scope: domain.ScopeBooksRead},
+	pb.BookService_CreateBook_FullMethodName:  {roles: staffRoles, scope: domain.ScopeBooksWrite},
+	pb.BookService_UpdateBook_FullMethodName:  {roles: staffRoles, scope: domain.ScopeBooksWrite},
+	pb.BookService_DeleteBook_FullMethodName:  {roles: staffRoles, scope: domain.ScopeBooksWrite},
+	pb.BookService_RestoreBook_FullMethodName: {roles: staffRoles, scope: domain.ScopeBooksWrite},
+
+	pb.OrderService_CreateOrder_FullMethodName:   {scope: domain.ScopeOrdersWrite},
+	pb.OrderService_QuoteOrder_FullMethodName:    {scope: domain.ScopeOrdersWrite},
+	pb.OrderService_QuoteShipping_FullMethodName: {scope: domain.ScopeOrdersWrite},
+	pb.OrderService_GetOrder_FullMethodName:      {scope: domain.ScopeOrdersRead},
+	pb.OrderService_ListOrders_FullMethodName:    {scope: domain.ScopeOrdersRead},
+
+	pb.UserService_GetUser_FullMethodName:     {scope: domain.ScopeUsersRead},
+	pb.UserService_ListUsers_FullMethodName:   {scope: domain.ScopeUsersRead},
+	pb.UserService_UpdateUser_FullMethodName:  {},
+	pb.UserService_DeleteUser_FullMethodName:  {},
+	pb.UserService_RestoreUser_FullMethodName: {roles: staffRoles},
+}
+
+// authenticator authenticates calls from the authorization or x-api-key metadata and enforces
+// the policy of the method
+type authenticator struct {
+	authService   port.AuthService
+	apiKeyService port.APIKeyService
+}
+
+// intercept is a unary interceptor that stores the identity of the caller and the audit metadata
+// of the call in the context
+func (a *authenticator) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = domain.WithAuditMeta(ctx, domain.AuditMeta{
+		RequestID: firstValue(md, "x-request-id"),
+		IP:        peerIP(ctx),
+	})
+
+	rule := policies[info.FullMethod]
+	identity, err := a.authenticate(ctx, md)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	if identity == nil {
+		if rule.public {
+			return handler(ctx, req)
+		}
+		return nil, errorStatus(errMissingCredentials)
+	}
+
+	if len(rule.roles) > 0 && !slices.Contains(rule.roles, identity.User.Role) {
+		return nil, errorStatus(domain.ErrForbidden)
+	}
+	if identity.APIKey != nil && rule.scope == "" {
+		return nil, errorStatus(domain.ErrAPIKeyNotAllowed)
+	}
+	if !identity.HasScope(rule.scope) {
+		return nil, errorStatus(fmt.Errorf("%w: %s", domain.ErrMissingScope, rule.scope))
+	}
+	if err := a.authService.AuthorizeTwoFactor(identity); err != nil {
+		return nil, errorStatus(err)
+	}
+
+	ctx = context.WithValue(ctx, authIdentityKey, identity)
+	meta := domain.AuditMetaFrom(ctx)
+	meta.ActorID = identity.User.ID
+	ctx = domain.WithAuditMeta(ctx, meta)
+	return handler(ctx, req)
+}
+
+// authenticate returns the identity of the credentials in the metadata, nil when there are none
+func (a *authenticator) authenticate(ctx context.Context, md metadata.MD) (*domain.Identity, error) {
+	if key := firstValue(md, "x-api-key"); key != "" {
+		return a.apiKeyService.Authenticate(ctx, key)
+	}
+	header := firstValue(md, "authorization")
+	if header == "" {
+		return nil, nil
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, fmt.Errorf("%w: authorization metadata is malformed", domain.ErrInvalidToken)
+	}
+	return a.authService.Authenticate(ctx, token)
+}
+
+// authIdentity returns the authenticated identity stored in the context
+func authIdentity(ctx context.Context) *domain.Identity {
+	identity, _ := ctx.Value(authIdentityKey).(*domain.Identity)
+	return identity
+}
+
+// authUser returns the authenticated user stored in the context
+func authUser(ctx context.Context) *domain.User {
+	if identity := authIdentity(ctx); identity != nil {
+		return identity.User
+	}
+	return nil
+}
+
+func isStaff(user *domain.User) bool {
+	return user != nil && (user.Role == domain.Staff || user.Role == domain.Admin)
+}
+
+// canManageUser reports whether the authenticated user may change the user with the id
+func canManageUser(ctx context.Context, id int64) bool {
+	user := authUser(ctx)
+	return user != nil && (user.ID == id || user.Role == domain.Admin)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// peerIP returns the IP address of the caller
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,239 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/grpc/pb"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func init() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// stubAuth is a port.AuthService authenticating the tokens it was built with and enforcing
+// two-factor authentication for staff like the default policy
+type stubAuth struct {
+	port.AuthService
+	identities map[string]*domain.Identity
+}
+
+func (sa stubAuth) Authenticate(ctx context.Context, token string) (*domain.Identity, error) {
+	identity, ok := sa.identities[token]
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+	return identity, nil
+}
+
+func (sa stubAuth) AuthorizeTwoFactor(identity *domain.Identity) error {
+	if identity.APIKey == nil && identity.User.Role != domain.Customer && !identity.TwoFactorVerified {
+		return domain.ErrTwoFactorRequired
+	}
+	return nil
+}
+
+// stubAPIKeys is a port.APIKeyService authenticating the keys it was built with
+type stubAPIKeys struct {
+	port.APIKeyService
+	identities map[string]*domain.Identity
+}
+
+func (sk stubAPIKeys) Authenticate(ctx context.Context, key string) (*domain.Identity, error) {
+	identity, ok := sk.identities[key]
+	if !ok {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	return identity, nil
+}
+
+// stubBooks, stubOrders and stubUsers are services answering the calls the tests make
+type stubBooks struct {
+	port.BookService
+}
+
+func (stubBooks) GetBook(ctx context.Context, id int64) (*domain.Book, error) {
+	return &domain.Book{ID: id, Name: "Dune"}, nil
+}
+
+func (stubBooks) RestoreBook(ctx context.Context, id int64) (*domain.Book, error) {
+	return &domain.Book{ID: id, Name: "Dune"}, nil
+}
+
+type stubOrders struct {
+	port.OrderService
+}
+
+func (stubOrders) OrderLists(ctx context.Context, skip, limit int64) ([]domain.Order, error) {
+	return nil, nil
+}
+
+func (stubOrders) ListUserOrders(ctx context.Context, userID, skip, limit int64) ([]domain.Order, error) {
+	return nil, nil
+}
+
+type stubUsers struct {
+	port.UserService
+}
+
+func (stubUsers) UpdateUser(ctx context.Context, user *domain.User) (*domain.UserUpdate, error) {
+	return &domain.UserUpdate{User: user}, nil
+}
+
+func (stubUsers) DeleteUser(ctx context.Context, id, version int64) error {
+	return nil
+}
+
+// newTestConn serves the server in memory and returns a connection to it
+func newTestConn(t *testing.T, server *Server) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	go server.Server.Serve(listener)
+	t.Cleanup(server.Server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestPolicies(t *testing.T) {
+	customer := &domain.User{ID: 1, Role: domain.Customer}
+	staff := &domain.User{ID: 2, Role: domain.Staff}
+	auth := stubAuth{identities: map[string]*domain.Identity{
+		"customer":          {User: customer},
+		"staff":             {User: staff, TwoFactorVerified: true},
+		"staff-without-2fa": {User: staff},
+	}}
+	keys := stubAPIKeys{identities: map[string]*domain.Identity{
+		"books-key":  {User: customer, APIKey: &domain.APIKey{ID: 1, UserID: customer.ID, Scopes: []domain.APIKeyScope{domain.ScopeBooksRead}}},
+		"orders-key": {User: customer, APIKey: &domain.APIKey{ID: 2, UserID: customer.ID, Scopes: []domain.APIKeyScope{domain.ScopeOrdersRead, domain.ScopeOrdersWrite}}},
+		"staff-key":  {User: staff, APIKey: &domain.APIKey{ID: 3, UserID: staff.ID, Scopes: []domain.APIKeyScope{domain.ScopeBooksWrite, domain.ScopeOrdersRead}}},
+	}}
+	conn := newTestConn(t, NewServer(auth, keys, stubBooks{}, stubOrders{}, stubUsers{}))
+	books, orders, users := pb.NewBookServiceClient(conn), pb.NewOrderServiceClient(conn), pb.NewUserServiceClient(conn)
+	health := healthpb.NewHealthClient(conn)
+
+	getBook := func(ctx context.Context) error {
+		_, err := books.GetBook(ctx, &pb.GetBookRequest{Id: 1})
+		return err
+	}
+	restoreBook := func(ctx context.Context) error {
+		_, err := books.RestoreBook(ctx, &pb.RestoreBookRequest{Id: 1})
+		return err
+	}
+	listOrders := func(ctx context.Context) error {
+		_, err := orders.ListOrders(ctx, &pb.ListOrdersRequest{})
+		return err
+	}
+	updateUser := func(ctx context.Context) error {
+		_, err := users.UpdateUser(ctx, &pb.UpdateUserRequest{Id: 1, Name: "Paul", Email: "paul@example.com", Version: 1})
+		return err
+	}
+	deleteUser := func(ctx context.Context) error {
+		_, err := users.DeleteUser(ctx, &pb.DeleteUserRequest{Id: 1, Version: 1})
+		return err
+	}
+	checkHealth := func(ctx context.Context) error {
+		_, err := health.Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+
+	tests := []struct {
+		name       string
+		call       func(context.Context) error
+		credential []string
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{"health without credentials", checkHealth, nil, codes.OK, ""},
+		{"public method without credentials", getBook, nil, codes.OK, ""},
+		{"public method with a token", getBook, []string{"authorization", "Bearer customer"}, codes.OK, ""},
+		{"public method with a key of its scope", getBook, []string{"x-api-key", "books-key"}, codes.OK, ""},
+		{"public method with a key of another scope", getBook, []string{"x-api-key", "orders-key"}, codes.PermissionDenied, "MISSING_SCOPE"},
+		{"invalid token", getBook, []string{"authorization", "Bearer stolen"}, codes.Unauthenticated, "INVALID_TOKEN"},
+		{"malformed authorization", getBook, []string{"authorization", "customer"}, codes.Unauthenticated, "INVALID_TOKEN"},
+
+		{"update user without credentials", updateUser, nil, codes.Unauthenticated, "MISSING_CREDENTIALS"},
+		{"update user", updateUser, []string{"authorization", "Bearer customer"}, codes.OK, ""},
+		{"update user with a key", updateUser, []string{"x-api-key", "orders-key"}, codes.PermissionDenied, "API_KEY_NOT_ALLOWED"},
+		{"update user without two-factor", updateUser, []string{"authorization", "Bearer staff-without-2fa"}, codes.PermissionDenied, "TWO_FACTOR_REQUIRED"},
+		{"delete user without credentials", deleteUser, nil, codes.Unauthenticated, "MISSING_CREDENTIALS"},
+		{"delete user", deleteUser, []string{"authorization", "Bearer customer"}, codes.OK, ""},
+		{"delete user with a key", deleteUser, []string{"x-api-key", "books-key"}, codes.PermissionDenied, "API_KEY_NOT_ALLOWED"},
+		{"delete user without two-factor", deleteUser, []string{"authorization", "Bearer staff-without-2fa"}, codes.PermissionDenied, "TWO_FACTOR_REQUIRED"},
+
+		{"scoped method without two-factor", listOrders, []string{"authorization", "Bearer staff-without-2fa"}, codes.PermissionDenied, "TWO_FACTOR_REQUIRED"},
+		{"scoped method", listOrders, []string{"authorization", "Bearer customer"}, codes.OK, ""},
+		{"scoped method with a key of its scope", listOrders, []string{"x-api-key", "orders-key"}, codes.OK, ""},
+		{"scoped method with a key of another scope", listOrders, []string{"x-api-key", "books-key"}, codes.PermissionDenied, "MISSING_SCOPE"},
+
+		{"staff method as a customer", restoreBook, []string{"authorization", "Bearer customer"}, codes.PermissionDenied, "FORBIDDEN"},
+		{"staff method without two-factor", restoreBook, []string{"authorization", "Bearer staff-without-2fa"}, codes.PermissionDenied, "TWO_FACTOR_REQUIRED"},
+		{"staff method", restoreBook, []string{"authorization", "Bearer staff"}, codes.OK, ""},
+		{"staff method with a staff key", restoreBook, []string{"x-api-key", "staff-key"}, codes.OK, ""},
+		{"staff method with a customer key", restoreBook, []string{"x-api-key", "books-key"}, codes.PermissionDenied, "FORBIDDEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.credential != nil {
+				ctx = metadata.AppendToOutgoingContext(ctx, tt.credential...)
+			}
+
+			st := status.Convert(tt.call(ctx))
+			if st.Code() != tt.wantCode {
+				t.Fatalf("code = %s, want %s: %s", st.Code(), tt.wantCode, st.Message())
+			}
+			if reason := errorReason(st); reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+// TestEveryMethodHasAPolicy makes sure a new method decides who may call it instead of only
+// requiring an authenticated caller
+func TestEveryMethodHasAPolicy(t *testing.T) {
+	server := NewServer(stubAuth{}, stubAPIKeys{}, stubBooks{}, stubOrders{}, stubUsers{})
+	for service, info := range server.Server.GetServiceInfo() {
+		if !strings.HasPrefix(service, "bookstore.") {
+			continue
+		}
+		for _, method := range info.Methods {
+			if _, ok := policies["/"+service+"/"+method.Name]; !ok {
+				t.Errorf("/%s/%s has no policy", service, method.Name)
+			}
+		}
+	}
+}
+
+func errorReason(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,203 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/grpc/pb"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type bookServer struct {
+	pb.UnimplementedBookServiceServer
+	service port.BookService
+}
+
+// bookInput holds the fields of a created or updated book with the rules of the HTTP API
+type bookInput struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	Description  string   `json:"description" validate:"required,max=1000"`
+	Author       string   `json:"author" validate:"required_without=Authors,max=50"`
+	Authors      []string `json:"authors" validate:"omitempty,max=20,dive,required,max=50"`
+	Cover        string   `json:"cover"`
+	CategoryID   *int64   `json:"category_id" validate:"omitempty,gt=0"`
+	PublisherID  *int64   `json:"publisher_id" validate:"omitempty,gt=0"`
+	SeriesID     *int64   `json:"series_id" validate:"omitempty,gt=0"`
+	SeriesVolume *int32   `json:"series_volume" validate:"omitempty,gt=0"`
+}
+
+func (bi *bookInput) toDomain(id, version int64) *domain.Book {
+	return &domain.Book{
+		ID:           id,
+		Name:         bi.Name,
+		Description:  bi.Description,
+		Author:       bi.Author,
+		Authors:      bi.Authors,
+		Cover:        bi.Cover,
+		CategoryID:   bi.CategoryID,
+		PublisherID:  bi.PublisherID,
+		SeriesID:     bi.SeriesID,
+		SeriesVolume: optionalInt(bi.SeriesVolume),
+		Version:      version,
+	}
+}
+
+func (bs *bookServer) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.Book, error) {
+	book, err := bs.service.GetBook(ctx, req.GetId())
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return newBook(book), nil
+}
+
+func (bs *bookServer) ListBooks(ctx context.Context, req *pb.ListBooksRequest) (*pb.ListBooksResponse, error) {
+	skip, limit := pagination(req.GetSkip(), req.GetLimit())
+	// Only staff may see soft-deleted books
+	includeDeleted := req.GetIncludeDeleted() && isStaff(authUser(ctx))
+	books, err := bs.service.ListBooks(ctx, skip, limit, includeDeleted)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return newListBooksResponse(books), nil
+}
+
+func (bs *bookServer) SearchBooks(ctx context.Context, req *pb.SearchBooksRequest) (*pb.ListBooksResponse, error) {
+	search := &domain.BookSearch{
+		Text:        req.GetQuery(),
+		CategoryID:  req.GetCategoryId(),
+		PublisherID: req.GetPublisherId(),
+		SeriesID:    req.GetSeriesId(),
+		EditionFilter: domain.EditionFilter{
+			Format:   domain.FormatType(req.GetFormat()),
+			Language: req.GetLanguage(),
+			ISBN:     req.GetIsbn(),
+		},
+	}
+	search.Skip, search.Limit = pagination(req.GetSkip(), req.GetLimit())
+
+	books, err := bs.service.SearchBooks(ctx, search)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return newListBooksResponse(books), nil
+}
+
+func (bs *bookServer) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb.Book, error) {
+	input := bookInput{
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+		Author:       req.GetAuthor(),
+		Authors:      req.GetAuthors(),
+		Cover:        req.GetCover(),
+		CategoryID:   req.CategoryId,
+		PublisherID:  req.PublisherId,
+		SeriesID:     req.SeriesId,
+		SeriesVolume: req.SeriesVolume,
+	}
+	if err := validate.Struct(input); err != nil {
+		return nil, validationStatus(err)
+	}
+
+	book, err := bs.service.CreateBook(ctx, input.toDomain(0, 0))
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return newBook(book), nil
+}
+
+func (bs *bookServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.Book, error) {
+	input := bookInput{
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+		Author:       req.GetAuthor(),
+		Authors:      req.GetAuthors(),
+		Cover:        req.GetCover(),
+		CategoryID:   req.CategoryId,
+		PublisherID:  req.PublisherId,
+		SeriesID:     req.SeriesId,
+		SeriesVolume: req.SeriesVolume,
+	}
+	if err := validate.Struct(input); err != nil {
+		return nil, validationStatus(err)
+	}
+	if err := requireVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+
+	book, err := bs.service.UpdateBook(ctx, input.toDomain(req.GetId(), req.GetVersion()))
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return newBook(book), nil
+}
+
+func (bs *bookServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest) (*emptypb.Empty, error) {
+	if err := requireVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+	if err := bs.service.DeleteBook(ctx, req.GetId(), req.GetVersion()); err != nil {
+		return nil, errorStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (bs *bookServer) RestoreBook(ctx context.Context, req *pb.RestoreBookRequest) (*pb.Book, error) {
+	book, err := bs.service.RestoreBook(ctx, req.GetId())
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return newBook(book), nil
+}
+
+func newBook(book *domain.Book) *pb.Book {
+	response := &pb.Book{
+		Id:           book.ID,
+		Name:         book.Name,
+		Description:  book.Description,
+		Author:       book.Author,
+		Authors:      book.Authors,
+		Cover:        book.Cover,
+		CategoryId:   book.CategoryID,
+		PublisherId:  book.PublisherID,
+		SeriesId:     book.SeriesID,
+		SeriesVolume: optionalInt32(book.SeriesVolume),
+		Version:      book.Version,
+		DeletedAt:    timestamp(book.DeletedAt),
+		Locale:       book.Locale,
+	}
+	for _, edition := range book.Editions {
+		response.Editions = append(response.Editions, newEdition(&edition))
+	}
+	return response
+}
+
+func newEdition(edition *domain.Edition) *pb.Edition {
+	return &pb.Edition{
+		Id:            edition.ID,
+		BookId:        edition.BookID,
+		Isbn:          edition.ISBN,
+		Format:        string(edition.Format),
+		Digital:       edition.Format.IsDigital(),
+		Language:      edition.Language,
+		EditionNumber: int32(edition.EditionNumber),
+		PageCount:     int32(edition.PageCount),
+		PublishedOn:   timestamp(edition.PublishedOn),
+		Price:         edition.Price,
+		Stock:         optionalInt32(edition.Stock),
+		WeightGrams:   int32(edition.WeightGrams),
+		FileName:      edition.FileName,
+		ContentType:   edition.ContentType,
+		FileSize:      edition.FileSize,
+		UpdatedAt:     timestamppb.New(edition.UpdatedAt),
+	}
+}
+
+func newListBooksResponse(books []domain.Book) *pb.ListBooksResponse {
+	response := &pb.ListBooksResponse{}
+	for _, book := range books {
+		response.Books = append(response.Books, newBook(&book))
+	}
+	return response
+}
@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details of errors, their reason is the code of the
+// domain error in upper case
+const errorDomain = "book-store"
+
+// kindCodes maps the kinds of domain errors to gRPC status codes
+var kindCodes = map[domain.ErrorKind]codes.Code{
+	domain.KindInvalid:              codes.InvalidArgument,
+	domain.KindUnauthorized:         codes.Unauthenticated,
+	domain.KindForbidden:            codes.PermissionDenied,
+	domain.KindNotFound:             codes.NotFound,
+	domain.KindConflict:             codes.Aborted,
+	domain.KindPreconditionFailed:   codes.FailedPrecondition,
+	domain.KindPreconditionRequired: codes.FailedPrecondition,
+	domain.KindGone:                 codes.NotFound,
+	domain.KindLocked:               codes.PermissionDenied,
+	domain.KindRateLimited:          codes.ResourceExhausted,
+	domain.KindInternal:             codes.Internal,
+}
+
+// errorStatus returns the status of an error returned by a service. Domain errors are mapped by
+// their kind, everything else is an internal error whose detail is only logged.
+func errorStatus(err error) error {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind == domain.KindInternal {
+		return internalError(err)
+	}
+	code, ok := kindCodes[domainErr.Kind]
+	if !ok {
+		return internalError(err)
+	}
+	st, detailErr := status.New(code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason: strings.ToUpper(domainErr.Code),
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return internalError(detailErr)
+	}
+	return st.Err()
+}
+
+func internalError(err error) error {
+	slog.Error("internal server error", "error", err.Error())
+	return status.Error(codes.Internal, "the server encountered a problem")
+}
+
+// validationStatus returns the status of a request that failed validation, the field violations
+// are named by the proto field paths
+func validationStatus(err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return internalError(err)
+	}
+	badRequest := &errdetails.BadRequest{}
+	for _, fe := range validationErrs {
+		// The namespace starts with the name of the validated struct
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fmt.Sprintf("must satisfy %s", rule),
+		})
+	}
+	st, detailErr := status.New(codes.InvalidArgument, "validation failed").WithDetails(badRequest)
+	if detailErr != nil {
+		return internalError(detailErr)
+	}
+	return st.Err()
+}
+
+// logCalls is a unary interceptor that logs every call with its status and duration
+func logCalls(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if code != codes.OK {
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, "grpc call", "method", info.FullMethod, "code", code.String(), "duration", time.Since(start))
+	return resp, err
+}
+
+// recoverPanics is a unary interceptor that turns a panic in a handler into an internal error
+func recoverPanics(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = internalError(fmt.Errorf("panic in %s: %v", info.FullMethod, p))
+		}
+	}()
+	return handler(ctx, req)
+}
@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// validate checks the inputs of the handlers, fields are named by their json tag which follows
+// the proto field name
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+var errVersionRequired = domain.NewError(domain.KindPreconditionRequired, "version_required", "version must name the version the change is based on")
+
+// requireVersion fails for changes that do not name the version they are based on, so clients
+// cannot overwrite changes they have not seen
+func requireVersion(version int64) error {
+	if version <= 0 {
+		return errorStatus(errVersionRequired)
+	}
+	return nil
+}
+
+// pagination clamps the skip and limit of a request, limit defaults to 20 and is capped at 100
+// like in the HTTP API
+func pagination(skip, limit int64) (int64, int64) {
+	if skip < 0 {
+		skip = 0
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	return skip, min(limit, 100)
+}
+
+// timestamp converts an optional time, nil stays unset
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// optionalInt32 converts an optional int to the proto optional field
+func optionalInt32(i *int) *int32 {
+	if i == nil {
+		return nil
+	}
+	v := int32(*i)
+	return &v
+}
+
+// optionalInt converts a proto optional field to an optional int
+func optionalInt(i *int32) *int {
+	if i == nil {
+		return nil
+	}
+	v := int(*i)
+	return &v
+}
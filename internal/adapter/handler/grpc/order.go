@@ -0,0 +1,228 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/grpc/pb"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type orderServer struct {
+	pb.UnimplementedOrderServiceServer
+	service port.OrderService
+}
+
+type orderItemInput struct {
+	EditionID int64 `json:"edition_id" validate:"required,gt=0"`
+	Quantity  int32 `json:"quantity" validate:"required,gt=0,lte=100"`
+}
+
+// orderInput holds an order request with the rules of the HTTP API
+type orderInput struct {
+	Items             []orderItemInput `json:"items" validate:"required,max=50,dive"`
+	CouponCode        string           `json:"coupon_code" validate:"max=50"`
+	ShippingAddressID int64            `json:"shipping_address_id" validate:"omitempty,gt=0"`
+	BillingAddressID  int64            `json:"billing_address_id" validate:"omitempty,gt=0"`
+	ShippingMethodID  *int64           `json:"shipping_method_id" validate:"omitempty,gt=0"`
+}
+
+// readOrderRequest validates an order request and returns it with the order of the caller it asks for
+func readOrderRequest(ctx context.Context, req *pb.OrderRequest) (*orderInput, *domain.Order, error) {
+	input := &orderInput{
+		CouponCode:        req.GetCouponCode(),
+		ShippingAddressID: req.GetShippingAddressId(),
+		BillingAddressID:  req.GetBillingAddressId(),
+		ShippingMethodID:  req.ShippingMethodId,
+	}
+	for _, item := range req.GetItems() {
+		input.Items = append(input.Items, orderItemInput{
+			EditionID: item.GetEditionId(),
+			Quantity:  item.GetQuantity(),
+		})
+	}
+	if err := validate.Struct(input); err != nil {
+		return nil, nil, validationStatus(err)
+	}
+
+	order := &domain.Order{
+		UserId:           authUser(ctx).ID,
+		CouponCode:       input.CouponCode,
+		ShippingMethodID: input.ShippingMethodID,
+	}
+	for _, item := range input.Items {
+		order.Items = append(order.Items, domain.OrderItem{EditionID: &item.EditionID, Quantity: int(item.Quantity)})
+	}
+	return input, order, nil
+}
+
+var errUnknownOrderItem = domain.NewError(domain.KindInvalid, "unknown_order_item", "book or address not found")
+
+// orderError reports missing books and addresses of an order as invalid arguments
+func orderError(err error) error {
+	if errors.Is(err, domain.ErrDataNotFound) {
+		return errorStatus(errUnknownOrderItem)
+	}
+	if errors.Is(err, domain.ErrNoDigitalFile) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return errorStatus(err)
+}
+
+func (ors *orderServer) CreateOrder(ctx context.Context, req *pb.OrderRequest) (*pb.Order, error) {
+	input, order, err := readOrderRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err = ors.service.CreateOrder(ctx, order, input.ShippingAddressID, input.BillingAddressID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	return newOrder(order), nil
+}
+
+func (ors *orderServer) QuoteOrder(ctx context.Context, req *pb.OrderRequest) (*pb.Order, error) {
+	input, order, err := readOrderRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err = ors.service.QuoteOrder(ctx, order, input.ShippingAddressID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	return newOrder(order), nil
+}
+
+func (ors *orderServer) QuoteShipping(ctx context.Context, req *pb.OrderRequest) (*pb.QuoteShippingResponse, error) {
+	input, order, err := readOrderRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := ors.service.QuoteShipping(ctx, order, input.ShippingAddressID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	response := &pb.QuoteShippingResponse{}
+	for _, quote := range quotes {
+		response.Quotes = append(response.Quotes, &pb.ShippingQuote{
+			MethodId:    quote.MethodID,
+			Name:        quote.Name,
+			RateType:    string(quote.Type),
+			Cost:        quote.Cost,
+			Explanation: quote.Explanation,
+		})
+	}
+	return response, nil
+}
+
+func (ors *orderServer) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
+	order, err := ors.service.GetOrder(ctx, req.GetId())
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	// Orders of other users are reported as missing unless the caller is staff
+	user := authUser(ctx)
+	if user.ID != order.UserId && !isStaff(user) {
+		return nil, errorStatus(domain.ErrDataNotFound)
+	}
+	return newOrder(order), nil
+}
+
+// ListOrders lists every order for staff and the caller's own orders for everyone else
+func (ors *orderServer) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	skip, limit := pagination(req.GetSkip(), req.GetLimit())
+	user := authUser(ctx)
+
+	var orders []domain.Order
+	var err error
+	if isStaff(user) {
+		orders, err = ors.service.OrderLists(ctx, skip, limit)
+	} else {
+		orders, err = ors.service.ListUserOrders(ctx, user.ID, skip, limit)
+	}
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+
+	response := &pb.ListOrdersResponse{}
+	for _, order := range orders {
+		response.Orders = append(response.Orders, newOrder(&order))
+	}
+	return response, nil
+}
+
+func newOrder(order *domain.Order) *pb.Order {
+	response := &pb.Order{
+		Id:               order.ID,
+		UserId:           order.UserId,
+		BookId:           order.BookId,
+		Status:           string(order.Status),
+		ShippingAddress:  newAddress(order.ShippingAddress),
+		BillingAddress:   newAddress(order.BillingAddress),
+		CouponCode:       order.CouponCode,
+		Subtotal:         order.Subtotal,
+		DiscountTotal:    order.DiscountTotal,
+		ShippingMethodId: order.ShippingMethodID,
+		ShippingMethod:   order.ShippingMethod,
+		ShippingCost:     order.ShippingCost,
+		TaxMode:          string(order.TaxMode),
+		TaxTotal:         order.TaxTotal,
+		Total:            order.Total,
+		CreatedAt:        timestamppb.New(order.CreatedAt),
+	}
+	for _, item := range order.Items {
+		response.Items = append(response.Items, &pb.OrderItem{
+			Id:           item.ID,
+			BookId:       item.BookID,
+			EditionId:    item.EditionID,
+			Format:       string(item.Format),
+			CategoryId:   item.CategoryID,
+			ProductClass: string(item.Class),
+			Quantity:     int32(item.Quantity),
+			UnitPrice:    item.UnitPrice,
+		})
+	}
+	for _, line := range order.TaxLines {
+		response.TaxLines = append(response.TaxLines, &pb.TaxLine{
+			BookId:       line.BookID,
+			ProductClass: string(line.Class),
+			Name:         line.Name,
+			Rate:         line.Rate,
+			Taxable:      line.Taxable,
+			Amount:       line.Amount,
+		})
+	}
+	for _, promotion := range order.Promotions {
+		response.Promotions = append(response.Promotions, &pb.AppliedPromotion{
+			Kind:        string(promotion.Kind),
+			Id:          promotion.ID,
+			Name:        promotion.Name,
+			Amount:      promotion.Amount,
+			Explanation: promotion.Explanation,
+		})
+	}
+	return response
+}
+
+func newAddress(address *domain.AddressSnapshot) *pb.Address {
+	if address == nil {
+		return nil
+	}
+	return &pb.Address{
+		FullName:   address.FullName,
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+		Phone:      address.Phone,
+	}
+}
@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"net"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/grpc/pb"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server serves the book, order and user services over gRPC with the health service and server
+// reflection next to them
+type Server struct {
+	*grpc.Server
+}
+
+func NewServer(authService port.AuthService, apiKeyService port.APIKeyService, bookService port.BookService, orderService port.OrderService, userService port.UserService) *Server {
+	auth := &authenticator{authService: authService, apiKeyService: apiKeyService}
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(logCalls, recoverPanics, auth.intercept))
+
+	pb.RegisterBookServiceServer(server, &bookServer{service: bookService})
+	pb.RegisterOrderServiceServer(server, &orderServer{service: orderService})
+	pb.RegisterUserServiceServer(server, &userServer{service: userService})
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	reflection.Register(server)
+
+	return &Server{
+		Server: server,
+	}
+}
+
+// Serve accepts connections on the address until the server is stopped
+func (s *Server) Serve(listenAddr string) error {
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return err
+	}
+	return s.Server.Serve(listener)
+}
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/handler/grpc/pb"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+	service port.UserService
+}
+
+type userInput struct {
+	Email string `json:"email" validate:"required,email"`
+	Name  string `json:"name" validate:"required,max=100"`
+}
+
+func (us *userServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	user, err := us.service.GetUser(ctx, req.GetId())
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return newUser(user), nil
+}
+
+func (us *userServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	skip, limit := pagination(req.GetSkip(), req.GetLimit())
+	// Only staff may see soft-deleted users
+	includeDeleted := req.GetIncludeDeleted() && isStaff(authUser(ctx))
+	users, err := us.service.ListUsers(ctx, skip, limit, includeDeleted)
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+
+	response := &pb.ListUsersResponse{}
+	for _, user := range users {
+		response.Users = append(response.Users, newUser(&user))
+	}
+	return response, nil
+}
+
+func (us *userServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserUpdate, error) {
+	input := userInput{Email: req.GetEmail(), Name: req.GetName()}
+	if err := validate.Struct(input); err != nil {
+		return nil, validationStatus(err)
+	}
+	if !canManageUser(ctx, req.GetId()) {
+		return nil, errorStatus(domain.ErrForbidden)
+	}
+	if err := requireVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+
+	update, err := us.service.UpdateUser(ctx, &domain.User{
+		ID:      req.GetId(),
+		Name:    input.Name,
+		Email:   input.Email,
+		Version: req.GetVersion(),
+	})
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			err = domain.ErrEmailTaken
+		}
+		return nil, errorStatus(err)
+	}
+	return &pb.UserUpdate{
+		User:         newUser(update.User),
+		PendingEmail: update.PendingEmail,
+	}, nil
+}
+
+func (us *userServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*emptypb.Empty, error) {
+	if !canManageUser(ctx, req.GetId()) {
+		return nil, errorStatus(domain.ErrForbidden)
+	}
+	if err := requireVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+	if err := us.service.DeleteUser(ctx, req.GetId(), req.GetVersion()); err != nil {
+		return nil, errorStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (us *userServer) RestoreUser(ctx context.Context, req *pb.RestoreUserRequest) (*pb.User, error) {
+	user, err := us.service.RestoreUser(ctx, req.GetId())
+	if err != nil {
+		return nil, errorStatus(err)
+	}
+	return newUser(user), nil
+}
+
+func newUser(user *domain.User) *pb.User {
+	return &pb.User{
+		Id:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		Version:   user.Version,
+		DeletedAt: timestamp(user.DeletedAt),
+	}
+}
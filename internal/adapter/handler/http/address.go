@@ -0,0 +1,147 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type AddressHandler struct {
+	service port.AddressService
+}
+
+func NewAddressHandler(service port.AddressService) *AddressHandler {
+	return &AddressHandler{
+		service: service,
+	}
+}
+
+type addressRequest struct {
+	Label             string `json:"label" validate:"max=50"`
+	FullName          string `json:"full_name" validate:"required,max=200"`
+	Line1             string `json:"line1" validate:"required,max=200"`
+	Line2             string `json:"line2" validate:"max=200"`
+	City              string `json:"city" validate:"required,max=100"`
+	Region            string `json:"region" validate:"max=100"`
+	PostalCode        string `json:"postal_code" validate:"max=20"`
+	Country           string `json:"country" validate:"required,len=2"`
+	Phone             string `json:"phone" validate:"max=30"`
+	IsDefaultShipping bool   `json:"is_default_shipping"`
+	IsDefaultBilling  bool   `json:"is_default_billing"`
+}
+
+func (ar *addressRequest) toDomain(userID, id int64) *domain.Address {
+	return &domain.Address{
+		ID:                id,
+		UserID:            userID,
+		Label:             ar.Label,
+		FullName:          ar.FullName,
+		Line1:             ar.Line1,
+		Line2:             ar.Line2,
+		City:              ar.City,
+		Region:            ar.Region,
+		PostalCode:        ar.PostalCode,
+		Country:           ar.Country,
+		Phone:             ar.Phone,
+		IsDefaultShipping: ar.IsDefaultShipping,
+		IsDefaultBilling:  ar.IsDefaultBilling,
+	}
+}
+
+// readAddressRequest decodes and validates an address payload, it writes the error response itself
+func readAddressRequest(w http.ResponseWriter, r *http.Request) (*addressRequest, bool) {
+	var payload addressRequest
+	if !readValidated(w, r, &payload) {
+		return nil, false
+	}
+	return &payload, true
+}
+
+func (ah *AddressHandler) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	payload, ok := readAddressRequest(w, r)
+	if !ok {
+		return
+	}
+
+	address, err := ah.service.CreateAddress(r.Context(), payload.toDomain(authUser(r).ID, 0))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newAddressResponse(address)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ah *AddressHandler) ListAddresses(w http.ResponseWriter, r *http.Request) {
+	addresses, err := ah.service.ListAddresses(r.Context(), authUser(r).ID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	addressesList := []addressResponse{}
+	for _, address := range addresses {
+		addressesList = append(addressesList, newAddressResponse(&address))
+	}
+	if err := jsonResponse(w, http.StatusOK, addressesList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ah *AddressHandler) GetAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	address, err := ah.service.GetAddress(r.Context(), authUser(r).ID, id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newAddressResponse(address)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ah *AddressHandler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	payload, ok := readAddressRequest(w, r)
+	if !ok {
+		return
+	}
+
+	address, err := ah.service.UpdateAddress(r.Context(), payload.toDomain(authUser(r).ID, id))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newAddressResponse(address)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ah *AddressHandler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := ah.service.DeleteAddress(r.Context(), authUser(r).ID, id); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type APIKeyHandler struct {
+	service port.APIKeyService
+}
+
+func NewAPIKeyHandler(service port.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+	}
+}
+
+type createAPIKeyRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=730"`
+}
+
+func (ah *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	// Api keys cannot mint further keys, otherwise a leaked key could widen its own scopes
+	if authIdentity(r).APIKey != nil {
+		forbiddenResponse(w, r)
+		return
+	}
+
+	var payload createAPIKeyRequest
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	scopes := make([]domain.APIKeyScope, len(payload.Scopes))
+	for i, scope := range payload.Scopes {
+		scopes[i] = domain.APIKeyScope(scope)
+	}
+	expiresIn := time.Duration(payload.ExpiresInDays) * 24 * time.Hour
+
+	key, plain, err := ah.service.CreateAPIKey(r.Context(), authUser(r), payload.Name, scopes, expiresIn)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	// The plain key is only returned once
+	response := newAPIKeyResponse(key)
+	response.Key = plain
+	if err := jsonResponse(w, http.StatusCreated, response); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ah *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := ah.service.ListAPIKeys(r.Context(), authUser(r))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	keysList := []apiKeyResponse{}
+	for _, key := range keys {
+		keysList = append(keysList, newAPIKeyResponse(&key))
+	}
+	if err := jsonResponse(w, http.StatusOK, keysList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ah *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := ah.service.RevokeAPIKey(r.Context(), authUser(r), id); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type AuditHandler struct {
+	service port.AuditService
+}
+
+func NewAuditHandler(service port.AuditService) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+	}
+}
+
+// ListAuditEntries lists the audit log, newest first. It can be filtered by actor_id, action,
+// entity_type, entity_id and a from/to time range in RFC 3339 format.
+func (ah *AuditHandler) ListAuditEntries(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilter(r.URL.Query())
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	filter.Skip, filter.Limit = extractPagination(r)
+
+	entries, err := ah.service.ListAuditEntries(r.Context(), filter)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	entriesList := []auditEntryResponse{}
+	for _, entry := range entries {
+		entriesList = append(entriesList, newAuditEntryResponse(&entry))
+	}
+	if err := jsonResponse(w, http.StatusOK, entriesList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func auditFilter(query url.Values) (*domain.AuditFilter, error) {
+	filter := &domain.AuditFilter{
+		Action:     query.Get("action"),
+		EntityType: query.Get("entity_type"),
+	}
+
+	var err error
+	for param, target := range map[string]*int64{
+		"actor_id":  &filter.ActorID,
+		"entity_id": &filter.EntityID,
+	} {
+		if value := query.Get(param); value != "" {
+			if *target, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid %s", param)
+			}
+		}
+	}
+	for param, target := range map[string]*time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	} {
+		if value := query.Get(param); value != "" {
+			if *target, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("invalid %s, expected RFC 3339 time", param)
+			}
+		}
+	}
+	return filter, nil
+}
@@ -8,12 +8,14 @@ import (
 )
 
 type AuthHandler struct {
-	authService port.AuthService
+	authService   port.AuthService
+	apiKeyService port.APIKeyService
 }
 
-func NewAuthHandler(authService port.AuthService) *AuthHandler {
+func NewAuthHandler(authService port.AuthService, apiKeyService port.APIKeyService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:   authService,
+		apiKeyService: apiKeyService,
 	}
 }
 
@@ -32,35 +34,133 @@ func (as *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	err := Validate.Struct(payload)
 
 	if err != nil {
-		messages, err := validationErrors(err)
-		if err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	result, err := as.authService.Login(r.Context(), payload.Email, payload.Password, clientIP(r))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if result.TwoFactorRequired {
+		if err := jsonResponse(w, http.StatusOK, newTwoFactorChallengeResponse(result)); err != nil {
 			internalServerError(w, r, err)
-			return
 		}
-		badRequestResponseWithTags(w, r, messages)
 		return
 	}
 
-	token, err := as.authService.Login(r.Context(), payload.Email, payload.Password)
+	if err := jsonResponse(w, http.StatusOK, result.AccessToken); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+
+}
+
+type verifyTwoFactorRequestPayload struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,max=20"`
+}
+
+func (as *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var payload verifyTwoFactorRequestPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	token, err := as.authService.VerifyTwoFactor(r.Context(), payload.ChallengeToken, payload.Code)
 	if err != nil {
 		switch err {
-		case domain.ErrInvalidCredentials:
-			if err := jsonResponse(w, http.StatusNotFound, err); err != nil {
-				internalServerError(w, r, err)
-				return
-			}
-			return
-		case domain.ErrInternal:
-			internalServerError(w, r, err)
-			return
-		case domain.ErrTokenCreation:
-			internalServerError(w, r, err)
+		case domain.ErrInvalidTwoFactor, domain.ErrTwoFactorDisabled:
+			// The challenge fails as a whole, like a wrong password does
+			unauthorizedErrorResponse(w, r, err)
+		default:
+			errorResponse(w, r, err)
 		}
+		return
 	}
 
 	if err := jsonResponse(w, http.StatusOK, token); err != nil {
 		internalServerError(w, r, err)
 		return
 	}
+}
+
+func (as *AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
 
+	if err := as.authService.UnlockUser(r.Context(), id); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, "user has been unlocked"); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+type forgotPasswordRequestPayload struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (as *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var payload forgotPasswordRequestPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	if err := as.authService.ForgotPassword(r.Context(), payload.Email); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	// The same response is returned whether or not the email belongs to an account
+	if err := jsonResponse(w, http.StatusAccepted, "if the email is registered, a password reset link has been sent"); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+type resetPasswordRequestPayload struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,max=100,min=3"`
+}
+
+func (as *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var payload resetPasswordRequestPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	err := as.authService.ResetPassword(r.Context(), payload.Token, payload.Password)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, "password has been reset"); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
 }
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
@@ -18,11 +20,15 @@ func NewBookHandler(service port.BookService) *BookHandler {
 }
 
 type createBookRequest struct {
-	Name        string  `json:"name" validate:"required,max=100"`
-	Description string  `json:"description" validate:"required,max=1000"`
-	Author      string  `json:"author" validate:"required,max=50"`
-	Price       float64 `json:"price" validate:"required,gt=0"`
-	Cover       string  `json:"cover"`
+	Name         string   `json:"name" validate:"required,max=100"`
+	Description  string   `json:"description" validate:"required,max=1000"`
+	Author       string   `json:"author" validate:"required_without=Authors,max=50"`
+	Authors      []string `json:"authors" validate:"omitempty,max=20,dive,required,max=50"`
+	Cover        string   `json:"cover"`
+	CategoryId   *int64   `json:"category_id" validate:"omitempty,gt=0"`
+	PublisherId  *int64   `json:"publisher_id" validate:"omitempty,gt=0"`
+	SeriesId     *int64   `json:"series_id" validate:"omitempty,gt=0"`
+	SeriesVolume *int     `json:"series_volume" validate:"omitempty,gt=0"`
 }
 
 func (bh *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
@@ -33,24 +39,23 @@ func (bh *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := Validate.Struct(payload); err != nil {
-		validationErrors, err := validationErrors(err)
-		if err != nil {
-			internalServerError(w, r, err)
-			return
-		}
-		badRequestResponseWithTags(w, r, validationErrors)
+		validationFailedResponse(w, r, err)
 		return
 	}
 	book := domain.Book{
-		Name:        payload.Name,
-		Author:      payload.Author,
-		Description: payload.Description,
-		Cover:       payload.Cover,
-		Price:       payload.Price,
+		Name:         payload.Name,
+		Author:       payload.Author,
+		Authors:      payload.Authors,
+		Description:  payload.Description,
+		Cover:        payload.Cover,
+		CategoryID:   payload.CategoryId,
+		PublisherID:  payload.PublisherId,
+		SeriesID:     payload.SeriesId,
+		SeriesVolume: payload.SeriesVolume,
 	}
 	_, err := bh.service.CreateBook(r.Context(), &book)
 	if err != nil {
-		internalServerError(w, r, err)
+		errorResponse(w, r, err)
 		return
 	}
 	if err := jsonResponse(w, http.StatusCreated, newBookResponse(&book)); err != nil {
@@ -61,9 +66,10 @@ func (bh *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 
 func (bh *BookHandler) ListBooks(w http.ResponseWriter, r *http.Request) {
 
-	books, err := bh.service.ListBooks(r.Context(), 0, 20)
+	skip, limit := extractPagination(r)
+	books, err := bh.service.ListBooks(r.Context(), skip, limit, includeDeleted(r))
 	if err != nil {
-		internalServerError(w, r, err)
+		errorResponse(w, r, err)
 		return
 	}
 
@@ -80,24 +86,66 @@ func (bh *BookHandler) ListBooks(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// SearchBooks searches the books by text, category, publisher, series and edition. Every book lists the editions
+// matching the search.
+func (bh *BookHandler) SearchBooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	search := &domain.BookSearch{
+		Text: query.Get("q"),
+		EditionFilter: domain.EditionFilter{
+			Format:   domain.FormatType(query.Get("format")),
+			Language: query.Get("language"),
+			ISBN:     query.Get("isbn"),
+		},
+	}
+	for param, target := range map[string]*int64{
+		"category_id":  &search.CategoryID,
+		"publisher_id": &search.PublisherID,
+		"series_id":    &search.SeriesID,
+	} {
+		if value := query.Get(param); value != "" {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				badRequestResponse(w, r, fmt.Errorf("invalid %s", param))
+				return
+			}
+			*target = id
+		}
+	}
+	search.Skip, search.Limit = extractPagination(r)
+
+	books, err := bh.service.SearchBooks(r.Context(), search)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	booksList := []bookResponse{}
+	for _, book := range books {
+		booksList = append(booksList, newBookResponse(&book))
+	}
+	if err := jsonResponse(w, http.StatusOK, booksList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
 func (bh *BookHandler) GetBookById(w http.ResponseWriter, r *http.Request) {
 	id, err := extractID(r)
 	if err != nil {
-		internalServerError(w, r, err)
+		badRequestResponse(w, r, err)
 		return
 	}
 	book, err := bh.service.GetBook(r.Context(), id)
 	if err != nil {
-		switch err {
-		case domain.ErrDataNotFound:
-			badRequestResponse(w, r, err)
-			return
-		default:
-			internalServerError(w, r, err)
-			return
-		}
+		errorResponse(w, r, err)
+		return
 	}
-	if err := jsonResponse(w, http.StatusOK, bookResponse(*book)); err != nil {
+	setETag(w, book.Version)
+	if notModified(w, r, book.Version) {
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newBookResponse(book)); err != nil {
 		internalServerError(w, r, err)
 	}
 }
@@ -107,17 +155,18 @@ func (bh *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 		badRequestResponse(w, r, err)
 		return
 	}
-	book, err := bh.service.GetBook(r.Context(), id)
+	version, err := ifMatchVersion(r)
 	if err != nil {
-		badRequestResponse(w, r, err)
+		errorResponse(w, r, err)
 		return
 	}
-	if book == nil {
-		badRequestResponse(w, r, nil)
+	if _, err := bh.service.GetBook(r.Context(), id); err != nil {
+		errorResponse(w, r, err)
+		return
 	}
 
-	if err = bh.service.DeleteBook(r.Context(), id); err != nil {
-		internalServerError(w, r, err)
+	if err = bh.service.DeleteBook(r.Context(), id, version); err != nil {
+		errorResponse(w, r, err)
 		return
 	}
 	if err = jsonResponse(w, http.StatusNoContent, nil); err != nil {
@@ -127,26 +176,26 @@ func (bh *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 }
 
 type updateBookRequest struct {
-	Name        string  `json:"name" validate:"required,max=100"`
-	Description string  `json:"description" validate:"required,max=1000"`
-	Author      string  `json:"author" validate:"required,max=50"`
-	Price       float64 `json:"price" validate:"required,gt=0"`
-	Cover       string  `json:"cover"`
+	Name         string   `json:"name" validate:"required,max=100"`
+	Description  string   `json:"description" validate:"required,max=1000"`
+	Author       string   `json:"author" validate:"required_without=Authors,max=50"`
+	Authors      []string `json:"authors" validate:"omitempty,max=20,dive,required,max=50"`
+	Cover        string   `json:"cover"`
+	CategoryId   *int64   `json:"category_id" validate:"omitempty,gt=0"`
+	PublisherId  *int64   `json:"publisher_id" validate:"omitempty,gt=0"`
+	SeriesId     *int64   `json:"series_id" validate:"omitempty,gt=0"`
+	SeriesVolume *int     `json:"series_volume" validate:"omitempty,gt=0"`
 }
 
 func (bh *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	var payload updateBookRequest
 	if err := readJSON(w, r, &payload); err != nil {
-		internalServerError(w, r, err)
+		badRequestResponse(w, r, err)
+		return
 	}
 
 	if err := Validate.Struct(payload); err != nil {
-		validationErrors, err := validationErrors(err)
-		if err != nil {
-			internalServerError(w, r, err)
-			return
-		}
-		badRequestResponseWithTags(w, r, validationErrors)
+		validationFailedResponse(w, r, err)
 		return
 	}
 	id, err := extractID(r)
@@ -154,31 +203,51 @@ func (bh *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 		badRequestResponse(w, r, err)
 		return
 	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
 
 	book := domain.Book{
-		ID:          id,
-		Name:        payload.Name,
-		Author:      payload.Author,
-		Description: payload.Description,
-		Cover:       payload.Cover,
-		Price:       payload.Price,
+		ID:           id,
+		Name:         payload.Name,
+		Author:       payload.Author,
+		Authors:      payload.Authors,
+		Description:  payload.Description,
+		Cover:        payload.Cover,
+		CategoryID:   payload.CategoryId,
+		PublisherID:  payload.PublisherId,
+		SeriesID:     payload.SeriesId,
+		SeriesVolume: payload.SeriesVolume,
+		Version:      version,
 	}
 	_, err = bh.service.UpdateBook(r.Context(), &book)
 	if err != nil {
-		switch err {
-		case domain.ErrConflictingData:
-			if err := jsonResponse(w, http.StatusConflict, err); err != nil {
-				internalServerError(w, r, err)
-				return
-			}
-			return
-		default:
-			internalServerError(w, r, err)
-			return
-		}
+		errorResponse(w, r, err)
+		return
 	}
+	setETag(w, book.Version)
 	if err = jsonResponse(w, http.StatusOK, newBookResponse(&book)); err != nil {
 		internalServerError(w, r, err)
 		return
 	}
 }
+
+func (bh *BookHandler) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	book, err := bh.service.RestoreBook(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newBookResponse(book)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// failingBooks is a port.BookService failing every call with the error it was built with
+type failingBooks struct {
+	port.BookService
+	err error
+}
+
+func (fb failingBooks) ListBooks(ctx context.Context, skip, limit int64, includeDeleted bool) ([]domain.Book, error) {
+	return nil, fb.err
+}
+
+func (fb failingBooks) SearchBooks(ctx context.Context, search *domain.BookSearch) ([]domain.Book, error) {
+	return nil, fb.err
+}
+
+func TestBookHandlerErrorResponses(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		handle     func(*BookHandler) http.HandlerFunc
+		target     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "malformed update",
+			handle:     func(bh *BookHandler) http.HandlerFunc { return bh.UpdateBook },
+			target:     "/v1/books/1",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "listing forbidden",
+			err:        domain.ErrForbidden,
+			handle:     func(bh *BookHandler) http.HandlerFunc { return bh.ListBooks },
+			target:     "/v1/books",
+			wantStatus: http.StatusForbidden,
+			wantCode:   "forbidden",
+		},
+		{
+			name:       "listing failed",
+			err:        errors.New("connection refused"),
+			handle:     func(bh *BookHandler) http.HandlerFunc { return bh.ListBooks },
+			target:     "/v1/books",
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid search",
+			err:        fmt.Errorf("%w: invalid ISBN", domain.ErrInvalidEdition),
+			handle:     func(bh *BookHandler) http.HandlerFunc { return bh.SearchBooks },
+			target:     "/v1/books/search?isbn=123",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_edition",
+		},
+		{
+			name:       "search failed",
+			err:        errors.New("connection refused"),
+			handle:     func(bh *BookHandler) http.HandlerFunc { return bh.SearchBooks },
+			target:     "/v1/books/search?q=dune",
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bh := NewBookHandler(failingBooks{err: tt.err})
+			r := httptest.NewRequest(http.MethodGet, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			tt.handle(bh)(rec, r)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if code := problemCode(t, rec); code != tt.wantCode {
+				t.Errorf("code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
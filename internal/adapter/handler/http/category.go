@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type CategoryHandler struct {
+	service port.CategoryService
+}
+
+func NewCategoryHandler(service port.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		service: service,
+	}
+}
+
+type createCategoryRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+func (ch *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var payload createCategoryRequest
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	category, err := ch.service.CreateCategory(r.Context(), &domain.Category{Name: payload.Name})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newCategoryResponse(category)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ch *CategoryHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := ch.service.ListCategories(r.Context())
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	categoriesList := []categoryResponse{}
+	for _, category := range categories {
+		categoriesList = append(categoriesList, newCategoryResponse(&category))
+	}
+	if err := jsonResponse(w, http.StatusOK, categoriesList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
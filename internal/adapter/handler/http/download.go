@@ -0,0 +1,133 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type DownloadHandler struct {
+	service port.DownloadService
+	orders  port.OrderService
+}
+
+func NewDownloadHandler(service port.DownloadService, orders port.OrderService) *DownloadHandler {
+	return &DownloadHandler{
+		service: service,
+		orders:  orders,
+	}
+}
+
+// ListOrderDownloads lists the downloads of the digital items of an order for its owner
+func (dh *DownloadHandler) ListOrderDownloads(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	order, err := dh.orders.GetOrder(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if !canViewOrder(r, order) {
+		errorResponse(w, r, domain.ErrDataNotFound)
+		return
+	}
+
+	grants, err := dh.service.ListOrderDownloads(r.Context(), order.ID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	grantsList := []downloadGrantResponse{}
+	for _, grant := range grants {
+		grantsList = append(grantsList, newDownloadGrantResponse(&grant))
+	}
+	if err := jsonResponse(w, http.StatusOK, grantsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// CreateLink signs a short lived download URL for a purchase of the caller
+func (dh *DownloadHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	link, err := dh.service.CreateLink(r.Context(), id, authUser(r).ID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newDownloadLinkResponse(link)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// resumeURLHeader carries the URL that continues a counted download with range requests
+const resumeURLHeader = "Download-Resume-URL"
+
+// continuesDownload reports whether a request is a range request that does not ask for the first
+// byte of the file, only those can continue a download
+func continuesDownload(r *http.Request) bool {
+	ranges := strings.TrimSpace(r.Header.Get("Range"))
+	return ranges != "" && !strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(ranges, "bytes=")), "0-")
+}
+
+// DownloadFile streams the file of a signed download link, the signature stands in for
+// authentication so the link works in any download manager. Every request through the link is
+// counted, range requests continue a download for free with the URL of the resume header.
+func (dh *DownloadHandler) DownloadFile(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	request := &domain.DownloadRequest{
+		GrantID:   id,
+		Signature: r.URL.Query().Get("signature"),
+		Resume:    r.URL.Query().Get("resume"),
+		Continues: continuesDownload(r),
+	}
+	if request.Resume == "" {
+		if request.Expires, err = strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64); err != nil {
+			errorResponse(w, r, domain.ErrInvalidDownloadLink)
+			return
+		}
+	}
+
+	download, file, info, err := dh.service.OpenDownload(r.Context(), request)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	defer file.Close()
+	edition := download.Edition
+	if download.ResumeURL != "" {
+		w.Header().Set(resumeURLHeader, download.ResumeURL)
+	}
+
+	// Large files take longer than the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(time.Hour)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		internalServerError(w, r, err)
+		return
+	}
+	name := edition.FileName
+	if name == "" {
+		name = fmt.Sprintf("book-%d-%s", edition.BookID, edition.Format)
+	}
+	w.Header().Set("Content-Type", edition.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, name, info.ModifiedAt, file)
+}
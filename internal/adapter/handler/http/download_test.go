@@ -0,0 +1,33 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContinuesDownload(t *testing.T) {
+	tests := []struct {
+		ranges string
+		want   bool
+	}{
+		{"", false},
+		{"bytes=0-", false},
+		{"bytes=0-1023", false},
+		{" bytes= 0-99", false},
+		{"bytes=1024-", true},
+		{"bytes=-500", true},
+		{"bytes=100-199,0-", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ranges, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/downloads/1/file", nil)
+			if tt.ranges != "" {
+				r.Header.Set("Range", tt.ranges)
+			}
+			if got := continuesDownload(r); got != tt.want {
+				t.Errorf("continuesDownload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
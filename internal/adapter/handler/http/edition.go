@@ -0,0 +1,187 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxEditionFileSize bounds the upload of a digital file, audiobooks can be large
+const maxEditionFileSize = 2 << 30
+
+type EditionHandler struct {
+	service port.EditionService
+}
+
+func NewEditionHandler(service port.EditionService) *EditionHandler {
+	return &EditionHandler{
+		service: service,
+	}
+}
+
+// editionRequest leaves stock out for digital editions, they have none
+type editionRequest struct {
+	ISBN          string  `json:"isbn" validate:"omitempty,max=17"`
+	Language      string  `json:"language" validate:"required,bcp47_language_tag"`
+	EditionNumber int     `json:"edition_number" validate:"gte=0,lte=1000"`
+	PageCount     int     `json:"page_count" validate:"gte=0,lte=100000"`
+	PublishedOn   string  `json:"published_on" validate:"omitempty,datetime=2006-01-02"`
+	Price         float64 `json:"price" validate:"required,gt=0"`
+	Stock         *int    `json:"stock" validate:"omitempty,gte=0"`
+	WeightGrams   int     `json:"weight_grams" validate:"gte=0,lte=100000"`
+}
+
+type createEditionRequest struct {
+	Format string `json:"format" validate:"required,oneof=hardcover paperback ebook audiobook"`
+	editionRequest
+}
+
+// edition returns the edition the request describes, the publication date was validated already
+func (er *editionRequest) edition(bookID int64) *domain.Edition {
+	edition := &domain.Edition{
+		BookID:        bookID,
+		ISBN:          er.ISBN,
+		Language:      er.Language,
+		EditionNumber: er.EditionNumber,
+		PageCount:     er.PageCount,
+		Price:         er.Price,
+		Stock:         er.Stock,
+		WeightGrams:   er.WeightGrams,
+	}
+	if publishedOn, err := time.Parse(time.DateOnly, er.PublishedOn); err == nil {
+		edition.PublishedOn = &publishedOn
+	}
+	return edition
+}
+
+// extractEditionID returns the edition id of a /books/{id}/editions/{editionId} route
+func extractEditionID(r *http.Request) (int64, error) {
+	return strconv.ParseInt(chi.URLParam(r, "editionId"), 10, 64)
+}
+
+func (eh *EditionHandler) ListEditions(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	editions, err := eh.service.ListBookEditions(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	editionsList := []editionResponse{}
+	for _, edition := range editions {
+		editionsList = append(editionsList, newEditionResponse(&edition))
+	}
+	if err := jsonResponse(w, http.StatusOK, editionsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (eh *EditionHandler) CreateEdition(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload createEditionRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	edition := payload.edition(id)
+	edition.Format = domain.FormatType(payload.Format)
+	edition, err = eh.service.CreateEdition(r.Context(), edition)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newEditionResponse(edition)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (eh *EditionHandler) UpdateEdition(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	editionID, err := extractEditionID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload editionRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	edition := payload.edition(id)
+	edition.ID = editionID
+	edition, err = eh.service.UpdateEdition(r.Context(), edition)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newEditionResponse(edition)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// UploadFile stores the request body as the file of a digital edition. The body is the raw file,
+// its type comes from the Content-Type header and its name from the filename query parameter.
+func (eh *EditionHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	editionID, err := extractEditionID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	fileName := path.Base(r.URL.Query().Get("filename"))
+	if fileName == "." || fileName == "/" {
+		fileName = ""
+	}
+
+	// Large files take longer than the server's read timeout
+	if err := http.NewResponseController(w).SetReadDeadline(time.Now().Add(time.Hour)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		internalServerError(w, r, err)
+		return
+	}
+	body := http.MaxBytesReader(w, r.Body, maxEditionFileSize)
+	edition, err := eh.service.UploadFile(r.Context(), id, editionID, fileName, contentType, body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			badRequestResponse(w, r, err)
+			return
+		}
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newEditionResponse(edition)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
@@ -1,52 +1,137 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/go-chi/chi/middleware"
 )
 
+// problemTypeBase prefixes the code of an error to build the type of its problem
+const problemTypeBase = "urn:book-store:problem:"
+
+// problem is an RFC 9457 problem details object. Code repeats the last part of the type so
+// clients can switch on it, fields holds the validation errors keyed by JSON path.
+type problem struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Code     string                `json:"code,omitempty"`
+	Fields   map[string]fieldError `json:"fields,omitempty"`
+}
+
+// kindStatuses maps the kinds of domain errors to HTTP status codes
+var kindStatuses = map[domain.ErrorKind]int{
+	domain.KindInvalid:              http.StatusBadRequest,
+	domain.KindUnauthorized:         http.StatusUnauthorized,
+	domain.KindForbidden:            http.StatusForbidden,
+	domain.KindNotFound:             http.StatusNotFound,
+	domain.KindConflict:             http.StatusConflict,
+	domain.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	domain.KindPreconditionRequired: http.StatusPreconditionRequired,
+	domain.KindGone:                 http.StatusGone,
+	domain.KindLocked:               http.StatusLocked,
+	domain.KindRateLimited:          http.StatusTooManyRequests,
+	domain.KindInternal:             http.StatusInternalServerError,
+}
+
+// newProblem returns a problem of the status, problems without a code are typed about:blank
+// and titled with the status text
+func newProblem(r *http.Request, status int, code, title, detail string) *problem {
+	p := &problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: middleware.GetReqID(r.Context()),
+	}
+	if code != "" {
+		p.Type = problemTypeBase + code
+		p.Title = title
+		p.Code = code
+	}
+	return p
+}
+
+func writeProblem(w http.ResponseWriter, p *problem) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// errorResponse writes the problem of an error returned by a service. Domain errors are mapped by
+// their kind, everything else is an internal error whose detail is only logged.
+func errorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind == domain.KindInternal {
+		internalServerError(w, r, err)
+		return
+	}
+	status, ok := kindStatuses[domainErr.Kind]
+	if !ok {
+		internalServerError(w, r, err)
+		return
+	}
+	slog.Warn("request failed", "method", r.Method, "path", r.URL.Path, "status", status, "error", err.Error())
+	writeProblem(w, newProblem(r, status, domainErr.Code, domainErr.Message, err.Error()))
+}
+
 func internalServerError(w http.ResponseWriter, r *http.Request, err error) {
 	slog.Error("internal server error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
-	writeJSONErorr(w, http.StatusInternalServerError, "the server encounterd a problem")
+	writeProblem(w, newProblem(r, http.StatusInternalServerError, "", "", "the server encountered a problem"))
 }
 
 func forbiddenResponse(w http.ResponseWriter, r *http.Request) {
-	slog.Warn("forbidden", "method", r.Method, "path", r.URL.Path, "error")
-
-	writeJSONErorr(w, http.StatusForbidden, "forbidden")
+	slog.Warn("forbidden", "method", r.Method, "path", r.URL.Path)
+	writeProblem(w, newProblem(r, http.StatusForbidden, "", "", ""))
 }
 
 func badRequestResponse(w http.ResponseWriter, r *http.Request, err error) {
 	slog.Warn("bad request error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
-	writeJSONErorr(w, http.StatusBadRequest, err.Error())
+	writeProblem(w, newProblem(r, http.StatusBadRequest, "", "", err.Error()))
 }
-func badRequestResponseWithTags(w http.ResponseWriter, r *http.Request, data []byte) {
-	slog.Warn("bad request error", "method", r.Method, "path", r.URL.Path, "error", string(data))
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	w.Write(data)
+
+// validationFailedResponse writes the field errors of a payload that failed validation
+func validationFailedResponse(w http.ResponseWriter, r *http.Request, err error) {
+	fields, err := fieldErrors(r, err)
+	if err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+	slog.Warn("validation error", "method", r.Method, "path", r.URL.Path, "fields", len(fields))
+	p := newProblem(r, http.StatusBadRequest, "validation_failed", "validation failed", "")
+	p.Fields = fields
+	writeProblem(w, p)
 }
 
 func notFoundResponse(w http.ResponseWriter, r *http.Request, err error) {
-
 	slog.Warn("not found error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
-	writeJSONErorr(w, http.StatusNotFound, "not found")
+	writeProblem(w, newProblem(r, http.StatusNotFound, "", "", ""))
 }
 
-func conflictResponse(w http.ResponseWriter, r *http.Request, err error) {
-	slog.Warn("conflict response", "method", r.Method, "path", r.URL.Path, "error", err.Error())
-	writeJSONErorr(w, http.StatusConflict, err.Error())
-}
 func unauthorizedErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	slog.Warn("unauthorized error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
-
-	writeJSONErorr(w, http.StatusUnauthorized, "unauthorized")
+	writeProblem(w, newProblem(r, http.StatusUnauthorized, "", "", ""))
 }
 
 func unauthorizedBasicErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	slog.Warn("unauthorized basic error", "method", r.Method, "path", r.URL.Path, "error", err.Error())
 
 	w.Header().Set("WWW-Authenticate", `Basic realm="restricted", charset="UTF-8"`)
+	writeProblem(w, newProblem(r, http.StatusUnauthorized, "", "", ""))
+}
+
+// methodNotAllowedResponse answers requests to a route with a method it does not serve
+func methodNotAllowedResponse(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, newProblem(r, http.StatusMethodNotAllowed, "", "", r.Method+" is not allowed on "+r.URL.Path))
+}
 
-	writeJSONErorr(w, http.StatusUnauthorized, "unauthorized")
+// routeNotFoundResponse answers requests to paths that no route matches
+func routeNotFoundResponse(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, newProblem(r, http.StatusNotFound, "", "", "no route matches "+r.URL.Path))
 }
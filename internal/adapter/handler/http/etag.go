@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+var (
+	errInvalidIfMatch  = domain.NewError(domain.KindPreconditionFailed, "invalid_if_match", `If-Match must be a single entity tag such as "3"`)
+	errIfMatchRequired = domain.NewError(domain.KindPreconditionRequired, "if_match_required", `If-Match must name the version the change is based on, such as "3"`)
+)
+
+// etag returns the entity tag of a resource version
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", etag(version))
+}
+
+// notModified writes a 304 response and reports true when If-None-Match names the current version
+func notModified(w http.ResponseWriter, r *http.Request, version int64) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	// If-None-Match uses the weak comparison, so W/"3" matches "3"
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag(version) {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion returns the version named by the If-Match header. Writes are never unconditional,
+// a missing header or "*" fails so clients cannot overwrite changes they have not seen.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, errIfMatchRequired
+	}
+	// If-Match uses the strong comparison, weak tags never match
+	if len(header) < 3 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    int64
+		wantErr error
+	}{
+		{"version", `"3"`, 3, nil},
+		{"version with spaces", ` "12" `, 12, nil},
+		{"missing", "", 0, errIfMatchRequired},
+		{"any version", "*", 0, errIfMatchRequired},
+		{"weak tag", `W/"3"`, 0, errInvalidIfMatch},
+		{"unquoted", "3", 0, errInvalidIfMatch},
+		{"several tags", `"3", "4"`, 0, errInvalidIfMatch},
+		{"zero", `"0"`, 0, errInvalidIfMatch},
+		{"negative", `"-1"`, 0, errInvalidIfMatch},
+		{"not a number", `"abc"`, 0, errInvalidIfMatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+			got, err := ifMatchVersion(r)
+			if err != tt.wantErr || got != tt.want {
+				t.Errorf("ifMatchVersion() = %d, %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"no header", "", false},
+		{"current version", `"3"`, true},
+		{"weak current version", `W/"3"`, true},
+		{"one of several", `"1", "3"`, true},
+		{"any version", "*", true},
+		{"older version", `"2"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("If-None-Match", tt.header)
+			}
+			w := httptest.NewRecorder()
+			if got := notModified(w, r, 3); got != tt.want {
+				t.Errorf("notModified() = %v, want %v", got, tt.want)
+			}
+			if tt.want && w.Code != http.StatusNotModified {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNotModified)
+			}
+		})
+	}
+}
+
+// TestWritesRequireIfMatch sends changes without If-Match to the routes under ETag control
+func TestWritesRequireIfMatch(t *testing.T) {
+	admin := &domain.User{ID: 1, Role: domain.Admin}
+	router := newTestRouter(t, NewAuthHandler(&stubAuth{user: admin, twoFactorVerified: true}, &stubAPIKeys{user: admin}))
+
+	for _, path := range []string{"/v1/users/1", "/v1/books/1"} {
+		t.Run(path, func(t *testing.T) {
+			for _, header := range []string{"", "*"} {
+				req := httptest.NewRequest(http.MethodDelete, path, nil)
+				req.Header.Set("Authorization", "Bearer token")
+				if header != "" {
+					req.Header.Set("If-Match", header)
+				}
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if rec.Code != http.StatusPreconditionRequired || problemCode(t, rec) != errorCode(errIfMatchRequired) {
+					t.Errorf("If-Match %q: status = %d, body = %s, want 428", header, rec.Code, rec.Body)
+				}
+			}
+		})
+	}
+}
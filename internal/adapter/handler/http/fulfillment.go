@@ -0,0 +1,233 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type FulfillmentHandler struct {
+	service port.FulfillmentService
+	orders  port.OrderService
+}
+
+func NewFulfillmentHandler(service port.FulfillmentService, orders port.OrderService) *FulfillmentHandler {
+	return &FulfillmentHandler{
+		service: service,
+		orders:  orders,
+	}
+}
+
+type fulfillmentItemRequest struct {
+	OrderItemId int64 `json:"order_item_id" validate:"required,gt=0"`
+	Quantity    int   `json:"quantity" validate:"required,gt=0"`
+}
+
+type createFulfillmentRequest struct {
+	Items []fulfillmentItemRequest `json:"items" validate:"omitempty,max=100,dive"`
+}
+
+type updateFulfillmentStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=picked packed delivered cancelled"`
+}
+
+type shipFulfillmentRequest struct {
+	Carrier        string `json:"carrier" validate:"omitempty,max=50"`
+	TrackingNumber string `json:"tracking_number" validate:"omitempty,max=100"`
+}
+
+// canViewFulfillment reports whether the authenticated user may see the fulfillment, which is
+// when they may see its order
+func (fh *FulfillmentHandler) canViewFulfillment(r *http.Request, fulfillment *domain.Fulfillment) (bool, error) {
+	if isStaff(authUser(r)) {
+		return true, nil
+	}
+	order, err := fh.orders.GetOrder(r.Context(), fulfillment.OrderID)
+	if err != nil {
+		return false, err
+	}
+	return canViewOrder(r, order), nil
+}
+
+// viewableFulfillment loads a fulfillment for the caller, fulfillments of other users' orders are
+// reported as missing unless the caller is staff
+func (fh *FulfillmentHandler) viewableFulfillment(w http.ResponseWriter, r *http.Request) (*domain.Fulfillment, bool) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return nil, false
+	}
+	fulfillment, err := fh.service.GetFulfillment(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return nil, false
+	}
+	ok, err := fh.canViewFulfillment(r, fulfillment)
+	if err != nil {
+		errorResponse(w, r, err)
+		return nil, false
+	}
+	if !ok {
+		errorResponse(w, r, domain.ErrDataNotFound)
+		return nil, false
+	}
+	return fulfillment, true
+}
+
+func (fh *FulfillmentHandler) CreateFulfillment(w http.ResponseWriter, r *http.Request) {
+	orderID, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload createFulfillmentRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	fulfillment := &domain.Fulfillment{OrderID: orderID}
+	for _, item := range payload.Items {
+		fulfillment.Items = append(fulfillment.Items, domain.FulfillmentItem{
+			OrderItemID: item.OrderItemId,
+			Quantity:    item.Quantity,
+		})
+	}
+	fulfillment, err = fh.service.CreateFulfillment(r.Context(), fulfillment)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newFulfillmentResponse(fulfillment)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// ListOrderFulfillments lists the shipments of an order for its owner and staff
+func (fh *FulfillmentHandler) ListOrderFulfillments(w http.ResponseWriter, r *http.Request) {
+	orderID, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	order, err := fh.orders.GetOrder(r.Context(), orderID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if !canViewOrder(r, order) {
+		errorResponse(w, r, domain.ErrDataNotFound)
+		return
+	}
+
+	fulfillments, err := fh.service.ListOrderFulfillments(r.Context(), orderID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	fulfillmentsList := []fulfillmentResponse{}
+	for _, fulfillment := range fulfillments {
+		fulfillmentsList = append(fulfillmentsList, newFulfillmentResponse(&fulfillment))
+	}
+	if err := jsonResponse(w, http.StatusOK, fulfillmentsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (fh *FulfillmentHandler) GetFulfillment(w http.ResponseWriter, r *http.Request) {
+	fulfillment, ok := fh.viewableFulfillment(w, r)
+	if !ok {
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newFulfillmentResponse(fulfillment)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// GetTracking lists the tracking events of a fulfillment for the owner of the order and staff
+func (fh *FulfillmentHandler) GetTracking(w http.ResponseWriter, r *http.Request) {
+	fulfillment, ok := fh.viewableFulfillment(w, r)
+	if !ok {
+		return
+	}
+	events, err := fh.service.Tracking(r.Context(), fulfillment.ID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	eventsList := []trackingEventResponse{}
+	for _, event := range events {
+		eventsList = append(eventsList, newTrackingEventResponse(&event))
+	}
+	if err := jsonResponse(w, http.StatusOK, eventsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (fh *FulfillmentHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload updateFulfillmentStatusRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	fulfillment, err := fh.service.UpdateStatus(r.Context(), id, domain.FulfillmentStatus(payload.Status))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newFulfillmentResponse(fulfillment)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// Ship books a packed fulfillment with the carrier, or records a label bought elsewhere when a
+// tracking number is given
+func (fh *FulfillmentHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload shipFulfillmentRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	fulfillment, err := fh.service.Ship(r.Context(), id, payload.Carrier, payload.TrackingNumber)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newFulfillmentResponse(fulfillment)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (fh *FulfillmentHandler) GetPackingSlip(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	slip, err := fh.service.PackingSlip(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newPackingSlipResponse(slip)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
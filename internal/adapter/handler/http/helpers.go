@@ -1,9 +1,11 @@
 package http
 
 import (
+	"net"
 	"net/http"
 	"strconv"
 
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -15,3 +17,50 @@ func extractID(r *http.Request) (int64, error) {
 	}
 	return id, err
 }
+
+// clientIP returns the IP address of the client that sent the request
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// canManageUser reports whether the authenticated user may change the account with the id
+func canManageUser(r *http.Request, id int64) bool {
+	user := authUser(r)
+	return user != nil && (user.ID == id || user.Role == domain.Admin)
+}
+
+// extractPagination reads the skip and limit query parameters, limit defaults to 20 and is capped at 100
+func extractPagination(r *http.Request) (int64, int64) {
+	skip, err := strconv.ParseInt(r.URL.Query().Get("skip"), 10, 64)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+	limit, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return skip, limit
+}
+
+// isStaff reports whether the user is staff or an admin
+func isStaff(user *domain.User) bool {
+	return user != nil && (user.Role == domain.Staff || user.Role == domain.Admin)
+}
+
+// canViewOrder reports whether the authenticated user may see the order
+func canViewOrder(r *http.Request, order *domain.Order) bool {
+	user := authUser(r)
+	return user != nil && (user.ID == order.UserId || isStaff(user))
+}
+
+// includeDeleted reports whether soft-deleted rows were asked for, only staff may see them
+func includeDeleted(r *http.Request) bool {
+	return r.URL.Query().Get("include_deleted") == "true" && isStaff(authUser(r))
+}
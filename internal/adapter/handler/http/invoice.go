@@ -0,0 +1,53 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type InvoiceHandler struct {
+	service port.InvoiceService
+	orders  port.OrderService
+}
+
+func NewInvoiceHandler(service port.InvoiceService, orders port.OrderService) *InvoiceHandler {
+	return &InvoiceHandler{
+		service: service,
+		orders:  orders,
+	}
+}
+
+// GetInvoicePDF serves the PDF invoice of a paid order to its owner and staff
+func (ih *InvoiceHandler) GetInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	order, err := ih.orders.GetOrder(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	// Invoices of other users' orders are reported as missing unless the caller is staff
+	if !canViewOrder(r, order) {
+		errorResponse(w, r, domain.ErrDataNotFound)
+		return
+	}
+
+	invoice, file, info, err := ih.service.InvoicePDF(r.Context(), order.ID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", invoice.Number+".pdf"))
+	w.Header().Set("Cache-Control", "private")
+	http.ServeContent(w, r, invoice.Number+".pdf", info.ModifiedAt, file)
+}
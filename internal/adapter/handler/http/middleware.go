@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/go-chi/chi/middleware"
+	"golang.org/x/text/language"
+)
+
+type contextKey string
+
+const authIdentityKey contextKey = "auth_identity"
+
+// Authenticate is a middleware that requires a valid bearer token and stores the identity in the
+// request context. API keys are rejected, routes that accept them use AuthenticateScope.
+func (as *AuthHandler) Authenticate(next http.Handler) http.Handler {
+	return as.authenticate("", next)
+}
+
+// AuthenticateScope is a middleware like Authenticate that also accepts X-API-Key headers of
+// keys that were granted the scope
+func (as *AuthHandler) AuthenticateScope(scope domain.APIKeyScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return as.authenticate(scope, next)
+	}
+}
+
+// authenticate verifies the credentials of the request, API keys are only accepted with a scope
+func (as *AuthHandler) authenticate(scope domain.APIKeyScope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var identity *domain.Identity
+		var err error
+
+		if key := r.Header.Get("X-API-Key"); key != "" {
+			if scope == "" {
+				errorResponse(w, r, domain.ErrAPIKeyNotAllowed)
+				return
+			}
+			identity, err = as.apiKeyService.Authenticate(r.Context(), key)
+			if err == nil && !identity.HasScope(scope) {
+				err = fmt.Errorf("%w: %s", domain.ErrMissingScope, scope)
+			}
+		} else {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				unauthorizedErrorResponse(w, r, errors.New("authorization header is missing"))
+				return
+			}
+			parts := strings.Split(header, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				unauthorizedErrorResponse(w, r, errors.New("authorization header is malformed"))
+				return
+			}
+			identity, err = as.authService.Authenticate(r.Context(), parts[1])
+		}
+		if err != nil {
+			errorResponse(w, r, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), authIdentityKey, identity)
+		meta := domain.AuditMetaFrom(ctx)
+		meta.ActorID = identity.User.ID
+		ctx = domain.WithAuditMeta(ctx, meta)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireTwoFactor is a middleware that enforces the two-factor policy of the authenticated user's role
+func (as *AuthHandler) RequireTwoFactor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity := authIdentity(r)
+		if identity == nil {
+			unauthorizedErrorResponse(w, r, errors.New("user is not authenticated"))
+			return
+		}
+		if err := as.authService.AuthorizeTwoFactor(identity); err != nil {
+			errorResponse(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole is a middleware that only lets authenticated users with one of the roles through
+func RequireRole(roles ...domain.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := authUser(r)
+			if user == nil {
+				unauthorizedErrorResponse(w, r, errors.New("user is not authenticated"))
+				return
+			}
+			for _, role := range roles {
+				if user.Role == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			forbiddenResponse(w, r)
+		})
+	}
+}
+
+// authIdentity returns the authenticated identity stored in the request context
+func authIdentity(r *http.Request) *domain.Identity {
+	identity, _ := r.Context().Value(authIdentityKey).(*domain.Identity)
+	return identity
+}
+
+// authUser returns the authenticated user stored in the request context
+func authUser(r *http.Request) *domain.User {
+	if identity := authIdentity(r); identity != nil {
+		return identity.User
+	}
+	return nil
+}
+
+// AuditMeta is a middleware that stores the request ID and client IP in the context for the
+// audit log, Authenticate adds the actor
+func AuditMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := domain.WithAuditMeta(r.Context(), domain.AuditMeta{
+			RequestID: middleware.GetReqID(r.Context()),
+			IP:        clientIP(r),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Localize is a middleware that negotiates the locale of the response from the lang query
+// parameter or, when it is missing or invalid, the Accept-Language header and stores its fallback chain in the context.
+// Requests for unsupported locales get the default locale.
+func Localize(config *config.I18N) func(http.Handler) http.Handler {
+	var tags []language.Tag
+	var locales []string
+	for _, locale := range config.Locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+		locales = append(locales, domain.NormalizeLocale(locale))
+	}
+	matcher := language.NewMatcher(tags)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var wanted []language.Tag
+			if tag, err := language.Parse(r.URL.Query().Get("lang")); err == nil {
+				wanted = []language.Tag{tag}
+			} else {
+				wanted, _, _ = language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+			}
+
+			locale := config.DefaultLocale
+			if len(locales) > 0 {
+				// The matcher falls back to the first supported locale, the default one
+				_, index, _ := matcher.Match(wanted...)
+				locale = locales[index]
+			}
+			chain := domain.NewLocaleChain(locale, config.DefaultLocale)
+			w.Header().Set("Content-Language", chain.Locale())
+			w.Header().Add("Vary", "Accept-Language")
+			next.ServeHTTP(w, r.WithContext(domain.WithLocale(r.Context(), chain)))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+func TestLocalize(t *testing.T) {
+	i18n := &config.I18N{DefaultLocale: "en", Locales: []string{"en", "de", "pt", "pt-BR"}}
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           domain.LocaleChain
+	}{
+		{"nothing asked", "", "", domain.LocaleChain{"en"}},
+		{"accept language", "", "de", domain.LocaleChain{"de", "en"}},
+		{"preferred supported language", "", "ja, pt-BR;q=0.8, de;q=0.5", domain.LocaleChain{"pt-br", "pt", "en"}},
+		{"region of a supported language", "", "de-AT", domain.LocaleChain{"de", "en"}},
+		{"unsupported language", "", "ja", domain.LocaleChain{"en"}},
+		{"lang wins over accept language", "lang=pt-BR", "de", domain.LocaleChain{"pt-br", "pt", "en"}},
+		{"lang with underscores", "lang=pt_BR", "", domain.LocaleChain{"pt-br", "pt", "en"}},
+		{"unsupported lang", "lang=ja", "de", domain.LocaleChain{"en"}},
+		{"invalid lang falls back to accept language", "lang=not+a+locale", "de", domain.LocaleChain{"de", "en"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.LocaleChain
+			handler := Localize(i18n)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = domain.LocaleFrom(r.Context())
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/v1/books?"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("locale chain = %q, want %q", got, tt.want)
+			}
+			if language := w.Header().Get("Content-Language"); language != tt.want.Locale() {
+				t.Errorf("Content-Language = %q, want %q", language, tt.want.Locale())
+			}
+			if vary := w.Header().Values("Vary"); !slices.Contains(vary, "Accept-Language") {
+				t.Errorf("Vary = %q, want Accept-Language", vary)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/go-chi/chi/v5"
+)
+
+type OIDCHandler struct {
+	service port.OIDCService
+}
+
+func NewOIDCHandler(service port.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		service: service,
+	}
+}
+
+// oidcCookie keeps the state and PKCE verifier of a login in the browser that started it
+const oidcCookie = "oidc_login"
+
+// Login redirects the user to the identity provider and binds the login to the browser with a cookie
+func (oh *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	login, err := oh.service.AuthURL(r.Context(), chi.URLParam(r, "provider"))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	setOIDCCookie(w, r, login.Binding.State+"."+login.Binding.CodeVerifier, int(time.Until(login.ExpiresAt).Seconds()))
+	http.Redirect(w, r, login.URL, http.StatusFound)
+}
+
+// setOIDCCookie sets the login cookie for the login and callback paths of the provider, a
+// negative max age deletes it. Lax lets the browser send it on the redirect back from the provider.
+func setOIDCCookie(w http.ResponseWriter, r *http.Request, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookie,
+		Value:    value,
+		Path:     strings.TrimSuffix(r.URL.Path, "/callback"),
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// oidcBinding reads the binding of the login from the cookie, it is empty without a cookie
+func oidcBinding(r *http.Request) domain.OIDCBinding {
+	cookie, err := r.Cookie(oidcCookie)
+	if err != nil {
+		return domain.OIDCBinding{}
+	}
+	state, verifier, _ := strings.Cut(cookie.Value, ".")
+	return domain.OIDCBinding{State: state, CodeVerifier: verifier}
+}
+
+// Callback completes the login started by this browser and returns the same response as a password login
+func (oh *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	binding := oidcBinding(r)
+	// The login can only be completed once, whatever the outcome
+	setOIDCCookie(w, r, "", -1)
+
+	query := r.URL.Query()
+	if e := query.Get("error"); e != "" {
+		unauthorizedErrorResponse(w, r, errors.New("identity provider returned "+e))
+		return
+	}
+	state, code := query.Get("state"), query.Get("code")
+	if state == "" || code == "" {
+		badRequestResponse(w, r, errors.New("state and code are required"))
+		return
+	}
+
+	result, err := oh.service.Callback(r.Context(), chi.URLParam(r, "provider"), state, code, binding)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if result.TwoFactorRequired {
+		if err := jsonResponse(w, http.StatusOK, newTwoFactorChallengeResponse(result)); err != nil {
+			internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, result.AccessToken); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// bindingOIDCService is a port.OIDCService that starts logins with a fixed binding and
+// completes them only for that binding
+type bindingOIDCService struct {
+	binding domain.OIDCBinding
+}
+
+func (bs *bindingOIDCService) AuthURL(ctx context.Context, provider string) (*domain.OIDCLogin, error) {
+	return &domain.OIDCLogin{
+		URL:       "https://idp.example/authorize?state=" + bs.binding.State,
+		Binding:   bs.binding,
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil
+}
+
+func (bs *bindingOIDCService) Callback(ctx context.Context, provider, state, code string, binding domain.OIDCBinding) (*domain.LoginResult, error) {
+	if binding != bs.binding || state != binding.State {
+		return nil, domain.ErrInvalidOIDCState
+	}
+	return &domain.LoginResult{AccessToken: "access-token"}, nil
+}
+
+func newOIDCTestRouter() http.Handler {
+	handler := NewOIDCHandler(&bindingOIDCService{binding: domain.OIDCBinding{State: "the-state", CodeVerifier: "the-verifier"}})
+	r := chi.NewRouter()
+	r.Get("/v1/auth/oidc/{provider}", handler.Login)
+	r.Get("/v1/auth/oidc/{provider}/callback", handler.Callback)
+	return r
+}
+
+func TestOIDCLoginSetsCookie(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newOIDCTestRouter().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/auth/oidc/stub", nil))
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusFound)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies, want 1", len(cookies))
+	}
+	cookie := cookies[0]
+	if cookie.Name != oidcCookie || cookie.Value != "the-state.the-verifier" {
+		t.Errorf("cookie = %s=%s", cookie.Name, cookie.Value)
+	}
+	if !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/v1/auth/oidc/stub" {
+		t.Errorf("cookie attributes = %+v", cookie)
+	}
+	if cookie.MaxAge <= 0 || cookie.MaxAge > 600 {
+		t.Errorf("cookie max age = %d, want the lifetime of the login", cookie.MaxAge)
+	}
+}
+
+func TestOIDCCallbackChecksCookie(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		state  string
+		want   int
+	}{
+		{"cookie of the login", "the-state.the-verifier", "the-state", http.StatusOK},
+		{"no cookie", "", "the-state", http.StatusUnauthorized},
+		{"state of another login", "the-state.the-verifier", "other-state", http.StatusUnauthorized},
+		{"wrong verifier", "the-state.other-verifier", "the-state", http.StatusUnauthorized},
+		{"malformed cookie", "the-state", "the-state", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/auth/oidc/stub/callback?code=valid-code&state="+tt.state, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: oidcCookie, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			newOIDCTestRouter().ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			// The cookie is cleared whatever the outcome
+			cookies := rec.Result().Cookies()
+			if len(cookies) != 1 || cookies[0].Name != oidcCookie || cookies[0].MaxAge >= 0 {
+				t.Errorf("cookies = %+v, want the login cookie deleted", cookies)
+			}
+		})
+	}
+}
@@ -0,0 +1,587 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/go-chi/chi/v5"
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+// docsPath is where the OpenAPI document and the Swagger UI are served
+const docsPath = "/v1/docs"
+
+// apiSchema is an OpenAPI 3.0 schema object, only the keywords the generator needs
+type apiSchema struct {
+	Ref                  string                `json:"$ref,omitempty"`
+	AllOf                []*apiSchema          `json:"allOf,omitempty"`
+	OneOf                []*apiSchema          `json:"oneOf,omitempty"`
+	Type                 string                `json:"type,omitempty"`
+	Format               string                `json:"format,omitempty"`
+	Description          string                `json:"description,omitempty"`
+	Nullable             bool                  `json:"nullable,omitempty"`
+	Enum                 []any                 `json:"enum,omitempty"`
+	Pattern              string                `json:"pattern,omitempty"`
+	Minimum              *float64              `json:"minimum,omitempty"`
+	ExclusiveMinimum     bool                  `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64              `json:"maximum,omitempty"`
+	ExclusiveMaximum     bool                  `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int                  `json:"minLength,omitempty"`
+	MaxLength            *int                  `json:"maxLength,omitempty"`
+	MinItems             *int                  `json:"minItems,omitempty"`
+	MaxItems             *int                  `json:"maxItems,omitempty"`
+	Items                *apiSchema            `json:"items,omitempty"`
+	Properties           map[string]*apiSchema `json:"properties,omitempty"`
+	Required             []string              `json:"required,omitempty"`
+	AdditionalProperties *apiSchema            `json:"additionalProperties,omitempty"`
+}
+
+type apiMediaType struct {
+	Schema *apiSchema `json:"schema"`
+}
+
+type apiParameter struct {
+	Name        string     `json:"name"`
+	In          string     `json:"in"`
+	Description string     `json:"description,omitempty"`
+	Required    bool       `json:"required,omitempty"`
+	Schema      *apiSchema `json:"schema"`
+}
+
+type apiRequestBody struct {
+	Required bool                     `json:"required"`
+	Content  map[string]*apiMediaType `json:"content"`
+}
+
+type apiHeader struct {
+	Description string     `json:"description,omitempty"`
+	Schema      *apiSchema `json:"schema"`
+}
+
+type apiResponse struct {
+	Ref         string                   `json:"$ref,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Headers     map[string]*apiHeader    `json:"headers,omitempty"`
+	Content     map[string]*apiMediaType `json:"content,omitempty"`
+}
+
+type apiOperation struct {
+	OperationID string                  `json:"operationId"`
+	Summary     string                  `json:"summary"`
+	Description string                  `json:"description,omitempty"`
+	Tags        []string                `json:"tags"`
+	Parameters  []*apiParameter         `json:"parameters,omitempty"`
+	RequestBody *apiRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*apiResponse `json:"responses"`
+	Security    []map[string][]string   `json:"security,omitempty"`
+}
+
+type apiSecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type apiComponents struct {
+	Schemas         map[string]*apiSchema         `json:"schemas"`
+	Responses       map[string]*apiResponse       `json:"responses"`
+	SecuritySchemes map[string]*apiSecurityScheme `json:"securitySchemes"`
+}
+
+type openAPIDocument struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description"`
+	} `json:"info"`
+	Tags       []map[string]string                 `json:"tags"`
+	Paths      map[string]map[string]*apiOperation `json:"paths"`
+	Components apiComponents                       `json:"components"`
+}
+
+// access is who may call an endpoint
+type access int
+
+const (
+	public access = iota
+	// authenticated callers use a bearer token, or an API key when the endpoint has a scope
+	authenticated
+	// staff callers are staff or admins who passed the two-factor policy
+	staff
+	// admin callers are admins who passed the two-factor policy
+	admin
+)
+
+// endpoint describes a route of the router for the OpenAPI document. Request and Response are zero
+// values of the DTOs the handler decodes and encodes, their schemas are generated from the types.
+type endpoint struct {
+	Method  string
+	Path    string
+	ID      string
+	Tag     string
+	Summary string
+	Access  access
+	Scope   domain.APIKeyScope
+	Query   []*apiParameter
+	Request any
+	// RequestContent is the media type of request bodies that are not JSON
+	RequestContent string
+	Status         int
+	Response       any
+	// Content is the media type of responses that are not JSON, or an alternative to JSON
+	Content string
+	// Errors are the statuses of the problems the endpoint returns besides the ones its access,
+	// path parameters and request body imply
+	Errors []int
+	// IfMatch marks endpoints that take the version to change in the If-Match header
+	IfMatch bool
+	// ETag marks endpoints that return the version in the ETag header
+	ETag bool
+	// Headers describes other headers of successful responses by name
+	Headers map[string]string
+}
+
+// oneOf is the response of endpoints that answer with one of several DTOs
+type oneOf []any
+
+var pathParameterPattern = regexp.MustCompile(`\{([A-Za-z]+)\}`)
+
+// schemaGenerator turns Go types into schemas, named structs become components
+type schemaGenerator struct {
+	schemas map[string]*apiSchema
+}
+
+// componentName is the name of the component of a named type, names are capitalized so request
+// and response types read the same as in other generated specs
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// schemaOf returns the schema of a type. Request types take their constraints from the validate
+// tags, response types require every field that is always encoded.
+func (sg *schemaGenerator) schemaOf(t reflect.Type, request bool) *apiSchema {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return &apiSchema{Type: "string", Format: "date-time"}
+	case reflect.TypeOf(json.RawMessage{}):
+		return &apiSchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := sg.schemaOf(t.Elem(), request)
+		if s.Ref != "" {
+			return &apiSchema{AllOf: []*apiSchema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return &apiSchema{Type: "string"}
+	case reflect.Bool:
+		return &apiSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &apiSchema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &apiSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &apiSchema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &apiSchema{Type: "string", Format: "byte"}
+		}
+		return &apiSchema{Type: "array", Items: sg.schemaOf(t.Elem(), request)}
+	case reflect.Map:
+		return &apiSchema{Type: "object", AdditionalProperties: sg.schemaOf(t.Elem(), request)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return sg.structSchema(t, request)
+		}
+		name := componentName(t)
+		if _, ok := sg.schemas[name]; !ok {
+			// The placeholder stops recursive types from recursing forever
+			sg.schemas[name] = &apiSchema{}
+			*sg.schemas[name] = *sg.structSchema(t, request)
+		}
+		return &apiSchema{Ref: "#/components/schemas/" + name}
+	default:
+		return &apiSchema{}
+	}
+}
+
+// structSchema returns the object schema of a struct, fields of embedded structs are promoted as
+// encoding/json does
+func (sg *schemaGenerator) structSchema(t reflect.Type, request bool) *apiSchema {
+	s := &apiSchema{Type: "object", Properties: map[string]*apiSchema{}}
+	names := jsonNames(t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := sg.structSchema(field.Type, request)
+			for property, fieldSchema := range embedded.Properties {
+				s.Properties[property] = fieldSchema
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := sg.schemaOf(field.Type, request)
+		required := !request && !strings.Contains(options, "omitempty")
+		if request {
+			required = applyValidation(fieldSchema, field.Type, field.Tag.Get("validate"), names)
+		}
+		s.Properties[name] = fieldSchema
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	slices.Sort(s.Required)
+	return s
+}
+
+// applyValidation adds the constraints of a validate tag to a field schema and reports whether the
+// field is required
+func applyValidation(s *apiSchema, t reflect.Type, tag string, names map[string]string) bool {
+	if tag == "" {
+		return false
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "required_without":
+			s.Description = fmt.Sprintf("Required unless %s is given.", names[param])
+		case "dive":
+			// The rules after dive apply to the items of the slice
+			index := strings.Index(tag, "dive,")
+			if s.Items != nil && index >= 0 {
+				applyValidation(s.Items, t.Elem(), tag[index+len("dive,"):], names)
+			}
+			return required
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			applyBound(s, t, name, param)
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				if number, err := strconv.ParseFloat(value, 64); err == nil && t.Kind() != reflect.String {
+					s.Enum = append(s.Enum, number)
+				} else {
+					s.Enum = append(s.Enum, value)
+				}
+			}
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "datetime":
+			if param == "2006-01-02" {
+				s.Format = "date"
+			}
+		case "alphanum":
+			s.Pattern = "^[a-zA-Z0-9]+$"
+		case "bcp47_language_tag":
+			s.Description = "BCP 47 language tag such as en or pt-BR."
+		}
+	}
+	return required
+}
+
+// applyBound adds a size constraint, which bounds the length of strings, the number of items of
+// slices and the value of numbers
+func applyBound(s *apiSchema, t reflect.Type, rule, param string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		size := int(value)
+		switch rule {
+		case "gt":
+			size++
+		case "lt":
+			size--
+		}
+		lower, upper := &s.MinLength, &s.MaxLength
+		if t.Kind() != reflect.String {
+			lower, upper = &s.MinItems, &s.MaxItems
+		}
+		switch rule {
+		case "min", "gt", "gte":
+			*lower = &size
+		case "max", "lt", "lte":
+			*upper = &size
+		case "len":
+			*lower, *upper = &size, &size
+		}
+	default:
+		switch rule {
+		case "min", "gte":
+			s.Minimum = &value
+		case "gt":
+			s.Minimum, s.ExclusiveMinimum = &value, true
+		case "max", "lte":
+			s.Maximum = &value
+		case "lt":
+			s.Maximum, s.ExclusiveMaximum = &value, true
+		case "len":
+			s.Minimum, s.Maximum = &value, &value
+		}
+	}
+}
+
+// jsonNames maps the Go names of the fields of a struct to the names they are encoded as
+func jsonNames(t reflect.Type) map[string]string {
+	names := map[string]string{}
+	for _, field := range reflect.VisibleFields(t) {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		names[field.Name] = name
+	}
+	return names
+}
+
+// dataSchema wraps a response schema in the data envelope of jsonResponse
+func dataSchema(s *apiSchema) *apiSchema {
+	return &apiSchema{Type: "object", Properties: map[string]*apiSchema{"data": s}, Required: []string{"data"}}
+}
+
+// problemResponses are the shared responses of the problem statuses, detailed in problem+json
+var problemResponses = map[int]string{
+	http.StatusBadRequest:           "BadRequest",
+	http.StatusUnauthorized:         "Unauthorized",
+	http.StatusForbidden:            "Forbidden",
+	http.StatusNotFound:             "NotFound",
+	http.StatusConflict:             "Conflict",
+	http.StatusGone:                 "Gone",
+	http.StatusPreconditionFailed:   "PreconditionFailed",
+	http.StatusPreconditionRequired: "PreconditionRequired",
+	http.StatusLocked:               "Locked",
+	http.StatusTooManyRequests:      "TooManyRequests",
+	http.StatusInternalServerError:  "InternalServerError",
+}
+
+// operation returns the OpenAPI operation of the endpoint
+func (e *endpoint) operation(sg *schemaGenerator) *apiOperation {
+	op := &apiOperation{
+		OperationID: e.ID,
+		Summary:     e.Summary,
+		Tags:        []string{e.Tag},
+		Responses:   map[string]*apiResponse{},
+	}
+	problems := append([]int{http.StatusInternalServerError}, e.Errors...)
+
+	for _, match := range pathParameterPattern.FindAllStringSubmatch(e.Path, -1) {
+		p := &apiParameter{Name: match[1], In: "path", Required: true, Schema: &apiSchema{Type: "integer", Format: "int64"}}
+		switch match[1] {
+		case "provider":
+			p.Schema = &apiSchema{Type: "string"}
+			p.Description = "Name of a configured identity provider."
+		case "locale":
+			p.Schema = &apiSchema{Type: "string"}
+			p.Description = "BCP 47 language tag of the translation."
+		}
+		op.Parameters = append(op.Parameters, p)
+		problems = append(problems, http.StatusBadRequest, http.StatusNotFound)
+	}
+	op.Parameters = append(op.Parameters, e.Query...)
+	if e.IfMatch {
+		op.Parameters = append(op.Parameters, &apiParameter{
+			Name:        "If-Match",
+			In:          "header",
+			Description: `Version the change is based on, as returned in the ETag header, such as "3".`,
+			Required:    true,
+			Schema:      &apiSchema{Type: "string"},
+		})
+		problems = append(problems, http.StatusPreconditionFailed, http.StatusPreconditionRequired)
+	}
+
+	if e.RequestContent != "" {
+		op.RequestBody = &apiRequestBody{
+			Required: true,
+			Content:  map[string]*apiMediaType{e.RequestContent: {Schema: &apiSchema{Type: "string", Format: "binary"}}},
+		}
+		problems = append(problems, http.StatusBadRequest)
+	}
+	if e.Request != nil {
+		op.RequestBody = &apiRequestBody{
+			Required: true,
+			Content:  map[string]*apiMediaType{"application/json": {Schema: sg.schemaOf(reflect.TypeOf(e.Request), true)}},
+		}
+		problems = append(problems, http.StatusBadRequest)
+	}
+
+	switch e.Access {
+	case authenticated, staff, admin:
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+		if e.Scope != "" {
+			op.Security = append(op.Security, map[string][]string{"apiKeyAuth": {}})
+		}
+		// API keys are rejected by endpoints without a scope
+		problems = append(problems, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	var notes []string
+	switch e.Access {
+	case staff:
+		notes = append(notes, "Staff and admins only, the two-factor policy of the role applies.")
+	case admin:
+		notes = append(notes, "Admins only, the two-factor policy of the role applies.")
+	}
+	if e.Scope != "" {
+		notes = append(notes, fmt.Sprintf("API keys need the %s scope.", e.Scope))
+	} else if e.Access != public {
+		notes = append(notes, "API keys cannot call this endpoint.")
+	}
+	op.Description = strings.Join(notes, " ")
+
+	success := &apiResponse{Description: http.StatusText(e.Status), Content: map[string]*apiMediaType{}}
+	switch response := e.Response.(type) {
+	case nil:
+	case oneOf:
+		s := &apiSchema{}
+		for _, alternative := range response {
+			s.OneOf = append(s.OneOf, sg.schemaOf(reflect.TypeOf(alternative), false))
+		}
+		success.Content["application/json"] = &apiMediaType{Schema: dataSchema(s)}
+	default:
+		success.Content["application/json"] = &apiMediaType{Schema: dataSchema(sg.schemaOf(reflect.TypeOf(response), false))}
+	}
+	if e.Content != "" {
+		success.Content[e.Content] = &apiMediaType{Schema: &apiSchema{Type: "string", Format: "binary"}}
+	}
+	if e.ETag || len(e.Headers) > 0 {
+		success.Headers = map[string]*apiHeader{}
+	}
+	if e.ETag {
+		success.Headers["ETag"] = &apiHeader{Description: "Version of the resource.", Schema: &apiSchema{Type: "string"}}
+	}
+	for name, description := range e.Headers {
+		success.Headers[name] = &apiHeader{Description: description, Schema: &apiSchema{Type: "string"}}
+	}
+	op.Responses[strconv.Itoa(e.Status)] = success
+
+	slices.Sort(problems)
+	for _, status := range slices.Compact(problems) {
+		op.Responses[strconv.Itoa(status)] = &apiResponse{Ref: "#/components/responses/" + problemResponses[status]}
+	}
+	return op
+}
+
+// OpenAPI returns the OpenAPI 3 document of the /v1 API
+func OpenAPI() ([]byte, error) {
+	sg := &schemaGenerator{schemas: map[string]*apiSchema{}}
+
+	doc := &openAPIDocument{OpenAPI: "3.0.3", Paths: map[string]map[string]*apiOperation{}}
+	doc.Info.Title = "Book Store API"
+	doc.Info.Version = "1.0.0"
+	doc.Info.Description = "Successful responses wrap their payload in a data member. Errors are RFC 9457 problem " +
+		"details whose code identifies the error. Responses are localized with the lang query parameter or the " +
+		"Accept-Language header."
+
+	tags := map[string]bool{}
+	for _, e := range endpoints {
+		if doc.Paths[e.Path] == nil {
+			doc.Paths[e.Path] = map[string]*apiOperation{}
+		}
+		doc.Paths[e.Path][strings.ToLower(e.Method)] = e.operation(sg)
+		if !tags[e.Tag] {
+			tags[e.Tag] = true
+			doc.Tags = append(doc.Tags, map[string]string{"name": e.Tag})
+		}
+	}
+
+	problemSchema := sg.schemaOf(reflect.TypeOf(problem{}), false)
+	doc.Components.Responses = map[string]*apiResponse{}
+	for status, name := range problemResponses {
+		doc.Components.Responses[name] = &apiResponse{
+			Description: http.StatusText(status),
+			Content:     map[string]*apiMediaType{"application/problem+json": {Schema: problemSchema}},
+		}
+	}
+	doc.Components.Schemas = sg.schemas
+	doc.Components.SecuritySchemes = map[string]*apiSecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Access token returned by the login endpoints."},
+		"apiKeyAuth": {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "API key, limited to its scopes."},
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// mountDocs serves the OpenAPI document and the Swagger UI that renders it
+func mountDocs(router chi.Router) error {
+	document, err := OpenAPI()
+	if err != nil {
+		return err
+	}
+	router.Get(docsPath, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, docsPath+"/", http.StatusMovedPermanently)
+	})
+	router.Get(docsPath+"/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(document)
+	})
+	router.Get(docsPath+"/*", httpSwagger.Handler(httpSwagger.URL(docsPath+"/openapi.json")))
+	return nil
+}
+
+// UndocumentedRoutes compares the routes of the router with the OpenAPI document. It returns the
+// routes that have no operation and the operations that have no route, as "METHOD /path".
+func (r *Router) UndocumentedRoutes() (missing []string, stale []string, err error) {
+	documented := map[string]bool{}
+	for _, e := range endpoints {
+		documented[e.Method+" "+e.Path] = true
+	}
+
+	routed := map[string]bool{}
+	err = chi.Walk(r.Mux, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if route == docsPath || strings.HasPrefix(route, docsPath+"/") {
+			return nil
+		}
+		// Routes of subrouters mounted at "/" end in a slash the document leaves out
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		key := method + " " + route
+		routed[key] = true
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for key := range documented {
+		if !routed[key] {
+			stale = append(stale, key)
+		}
+	}
+	slices.Sort(missing)
+	slices.Sort(stale)
+	return missing, stale, nil
+}
@@ -0,0 +1,557 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+func queryParameter(name, description string, s *apiSchema) *apiParameter {
+	return &apiParameter{Name: name, In: "query", Description: description, Schema: s}
+}
+
+var (
+	stringSchema  = &apiSchema{Type: "string"}
+	integerSchema = &apiSchema{Type: "integer", Format: "int64"}
+
+	paginationQuery = []*apiParameter{
+		queryParameter("skip", "Number of items to skip.", &apiSchema{Type: "integer", Minimum: new(float64)}),
+		queryParameter("limit", "Number of items to return, 20 by default and at most 100.", &apiSchema{Type: "integer"}),
+	}
+	includeDeletedQuery = queryParameter("include_deleted", "Include soft-deleted rows, staff only.", &apiSchema{Type: "boolean"})
+)
+
+// endpoints documents every route of NewRouter, UndocumentedRoutes keeps the two in step
+var endpoints = []endpoint{
+	// Books
+	{
+		Method: http.MethodGet, Path: "/v1/books", ID: "listBooks", Tag: "Books", Summary: "List books",
+		Query:  append([]*apiParameter{includeDeletedQuery}, paginationQuery...),
+		Status: http.StatusOK, Response: []bookResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/books/search", ID: "searchBooks", Tag: "Books",
+		Summary: "Search books by text and filter them by their editions",
+		Query: append([]*apiParameter{
+			queryParameter("q", "Text matched against the name, authors and description, ISBNs match the edition.", stringSchema),
+			queryParameter("format", "Only books with an edition in the format.", &apiSchema{Type: "string", Enum: []any{domain.FormatHardcover, domain.FormatPaperback, domain.FormatEbook, domain.FormatAudiobook}}),
+			queryParameter("language", "Only books with an edition in the language.", stringSchema),
+			queryParameter("isbn", "Only the book with the edition of the ISBN.", stringSchema),
+			queryParameter("category_id", "Only books of the category.", integerSchema),
+			queryParameter("publisher_id", "Only books of the publisher.", integerSchema),
+			queryParameter("series_id", "Only books of the series.", integerSchema),
+		}, paginationQuery...),
+		Status: http.StatusOK, Response: []bookResponse{}, Errors: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/books/{id}", ID: "getBook", Tag: "Books", Summary: "Get a book",
+		Status: http.StatusOK, Response: bookResponse{}, ETag: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/books/{id}/editions/{editionId}/prices", ID: "getPriceTimeline", Tag: "Pricing",
+		Summary: "Get the current price of an edition and its scheduled changes",
+		Status:  http.StatusOK, Response: priceTimelineResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/books/{id}/editions", ID: "listEditions", Tag: "Editions", Summary: "List the editions of a book",
+		Status: http.StatusOK, Response: []editionResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/books/{id}/translations", ID: "listBookTranslations", Tag: "Translations",
+		Summary: "List the translations of a book",
+		Status:  http.StatusOK, Response: []bookTranslationResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/books/create", ID: "createBook", Tag: "Books", Summary: "Create a book",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: createBookRequest{},
+		Status: http.StatusCreated, Response: bookResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/books/{id}", ID: "deleteBook", Tag: "Books", Summary: "Soft-delete a book",
+		Access: staff, Scope: domain.ScopeBooksWrite, IfMatch: true, Status: http.StatusNoContent,
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/books/{id}", ID: "updateBook", Tag: "Books", Summary: "Update a book",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: updateBookRequest{}, IfMatch: true,
+		Status: http.StatusOK, Response: bookResponse{}, ETag: true, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/books/{id}/restore", ID: "restoreBook", Tag: "Books", Summary: "Restore a soft-deleted book",
+		Access: staff, Scope: domain.ScopeBooksWrite, Status: http.StatusOK, Response: bookResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/books/{id}/editions/{editionId}/price-schedules", ID: "listPriceSchedules", Tag: "Pricing",
+		Summary: "List the price schedules of an edition",
+		Access:  staff, Scope: domain.ScopeBooksWrite, Status: http.StatusOK, Response: []priceScheduleResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/books/{id}/editions/{editionId}/price-schedules", ID: "schedulePrice", Tag: "Pricing",
+		Summary: "Schedule a price for an edition",
+		Access:  staff, Scope: domain.ScopeBooksWrite, Request: priceScheduleRequest{},
+		Status: http.StatusCreated, Response: priceScheduleResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/books/{id}/editions/{editionId}/price-schedules/{scheduleId}", ID: "cancelPriceSchedule", Tag: "Pricing",
+		Summary: "Cancel a scheduled price",
+		Access:  staff, Scope: domain.ScopeBooksWrite, Status: http.StatusNoContent,
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/books/{id}/editions", ID: "createEdition", Tag: "Editions", Summary: "Add an edition to a book",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: createEditionRequest{},
+		Status: http.StatusCreated, Response: editionResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/books/{id}/editions/{editionId}", ID: "updateEdition", Tag: "Editions", Summary: "Update an edition",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: editionRequest{},
+		Status: http.StatusOK, Response: editionResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/books/{id}/editions/{editionId}/file", ID: "uploadEditionFile", Tag: "Editions",
+		Summary: "Upload the file of a digital edition, the body is the raw file",
+		Access:  staff, Scope: domain.ScopeBooksWrite, RequestContent: "application/octet-stream",
+		Query:  []*apiParameter{queryParameter("filename", "Name the file is downloaded as.", stringSchema)},
+		Status: http.StatusOK, Response: editionResponse{},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/books/{id}/translations/{locale}", ID: "setBookTranslation", Tag: "Translations",
+		Summary: "Create or replace the translation of a book",
+		Access:  staff, Scope: domain.ScopeBooksWrite, Request: bookTranslationRequest{},
+		Status: http.StatusOK, Response: bookTranslationResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/books/{id}/translations/{locale}", ID: "deleteBookTranslation", Tag: "Translations",
+		Summary: "Delete the translation of a book",
+		Access:  staff, Scope: domain.ScopeBooksWrite, Status: http.StatusNoContent,
+	},
+
+	// Categories
+	{
+		Method: http.MethodGet, Path: "/v1/categories", ID: "listCategories", Tag: "Categories", Summary: "List categories",
+		Status: http.StatusOK, Response: []categoryResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/categories/{id}/translations", ID: "listCategoryTranslations", Tag: "Translations",
+		Summary: "List the translations of a category",
+		Status:  http.StatusOK, Response: []categoryTranslationResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/categories", ID: "createCategory", Tag: "Categories", Summary: "Create a category",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: createCategoryRequest{},
+		Status: http.StatusCreated, Response: categoryResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/categories/{id}/translations/{locale}", ID: "setCategoryTranslation", Tag: "Translations",
+		Summary: "Create or replace the translation of a category",
+		Access:  staff, Scope: domain.ScopeBooksWrite, Request: categoryTranslationRequest{},
+		Status: http.StatusOK, Response: categoryTranslationResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/categories/{id}/translations/{locale}", ID: "deleteCategoryTranslation", Tag: "Translations",
+		Summary: "Delete the translation of a category",
+		Access:  staff, Scope: domain.ScopeBooksWrite, Status: http.StatusNoContent,
+	},
+
+	// Publishers
+	{
+		Method: http.MethodGet, Path: "/v1/publishers", ID: "listPublishers", Tag: "Publishers", Summary: "List publishers",
+		Status: http.StatusOK, Response: []publisherResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/publishers/{id}", ID: "getPublisher", Tag: "Publishers", Summary: "Get a publisher",
+		Status: http.StatusOK, Response: publisherResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/publishers/{id}/books", ID: "listPublisherBooks", Tag: "Publishers", Summary: "List the books of a publisher",
+		Query: paginationQuery, Status: http.StatusOK, Response: []bookResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/publishers", ID: "createPublisher", Tag: "Publishers", Summary: "Create a publisher",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: publisherRequest{},
+		Status: http.StatusCreated, Response: publisherResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/publishers/{id}", ID: "updatePublisher", Tag: "Publishers", Summary: "Update a publisher",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: publisherRequest{},
+		Status: http.StatusOK, Response: publisherResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/publishers/{id}", ID: "deletePublisher", Tag: "Publishers", Summary: "Delete a publisher",
+		Access: staff, Scope: domain.ScopeBooksWrite, Status: http.StatusNoContent,
+	},
+
+	// Series
+	{
+		Method: http.MethodGet, Path: "/v1/series", ID: "listSeries", Tag: "Series", Summary: "List series",
+		Status: http.StatusOK, Response: []seriesResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/series/{id}", ID: "getSeries", Tag: "Series", Summary: "Get a series and its volumes in order",
+		Status: http.StatusOK, Response: seriesResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/series", ID: "createSeries", Tag: "Series", Summary: "Create a series",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: seriesRequest{},
+		Status: http.StatusCreated, Response: seriesResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/series/{id}", ID: "updateSeries", Tag: "Series", Summary: "Update a series",
+		Access: staff, Scope: domain.ScopeBooksWrite, Request: seriesRequest{},
+		Status: http.StatusOK, Response: seriesResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/series/{id}", ID: "deleteSeries", Tag: "Series", Summary: "Delete a series",
+		Access: staff, Scope: domain.ScopeBooksWrite, Status: http.StatusNoContent,
+	},
+
+	// Promotions
+	{
+		Method: http.MethodGet, Path: "/v1/promotions/coupons", ID: "listCoupons", Tag: "Promotions", Summary: "List coupons",
+		Access: staff, Query: paginationQuery, Status: http.StatusOK, Response: []couponResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/promotions/coupons", ID: "createCoupon", Tag: "Promotions", Summary: "Create a coupon",
+		Access: staff, Request: createCouponRequest{},
+		Status: http.StatusCreated, Response: couponResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/promotions/coupons/{id}", ID: "deactivateCoupon", Tag: "Promotions", Summary: "Deactivate a coupon",
+		Access: staff, Status: http.StatusNoContent,
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/promotions/automatic", ID: "listPromotions", Tag: "Promotions", Summary: "List automatic promotions",
+		Access: staff, Query: paginationQuery, Status: http.StatusOK, Response: []promotionResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/promotions/automatic", ID: "createPromotion", Tag: "Promotions", Summary: "Create an automatic promotion",
+		Access: staff, Request: createPromotionRequest{},
+		Status: http.StatusCreated, Response: promotionResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/promotions/automatic/{id}", ID: "deactivatePromotion", Tag: "Promotions",
+		Summary: "Deactivate an automatic promotion",
+		Access:  staff, Status: http.StatusNoContent,
+	},
+
+	// Shipping
+	{
+		Method: http.MethodGet, Path: "/v1/shipping/zones", ID: "listShippingZones", Tag: "Shipping", Summary: "List shipping zones",
+		Access: staff, Status: http.StatusOK, Response: []shippingZoneResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/shipping/zones", ID: "createShippingZone", Tag: "Shipping", Summary: "Create a shipping zone",
+		Access: staff, Request: createShippingZoneRequest{},
+		Status: http.StatusCreated, Response: shippingZoneResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/shipping/methods", ID: "listShippingMethods", Tag: "Shipping", Summary: "List shipping methods",
+		Access: staff, Status: http.StatusOK, Response: []shippingMethodResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/shipping/methods", ID: "createShippingMethod", Tag: "Shipping", Summary: "Create a shipping method",
+		Access: staff, Request: createShippingMethodRequest{},
+		Status: http.StatusCreated, Response: shippingMethodResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/shipping/methods/{id}", ID: "disableShippingMethod", Tag: "Shipping", Summary: "Disable a shipping method",
+		Access: staff, Status: http.StatusNoContent,
+	},
+
+	// Users
+	{
+		Method: http.MethodPost, Path: "/v1/users/register", ID: "registerUser", Tag: "Users", Summary: "Register a user",
+		Request: registerRequestUser{}, Status: http.StatusOK, Response: userResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/users/verify-email", ID: "verifyEmail", Tag: "Users",
+		Summary: "Confirm an email change with the token sent to the new address",
+		Request: verifyEmailRequest{}, Status: http.StatusOK, Response: userResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/users/{id}/update", ID: "updateUser", Tag: "Users",
+		Summary: "Update the name and email of a user, a new email is confirmed by mail",
+		Access:  authenticated, Request: updateRequestUser{}, IfMatch: true,
+		Status: http.StatusOK, Response: userUpdateResponse{}, ETag: true, Errors: []int{http.StatusForbidden, http.StatusConflict},
+	},
+	{
+		Method: http.MethodPatch, Path: "/v1/users/{id}", ID: "patchUser", Tag: "Users", Summary: "Change some fields of a user",
+		Access: authenticated, Request: patchRequestUser{}, IfMatch: true,
+		Status: http.StatusOK, Response: userUpdateResponse{}, ETag: true, Errors: []int{http.StatusForbidden, http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/users/{id}", ID: "deleteUser", Tag: "Users", Summary: "Soft-delete a user",
+		Access: authenticated, IfMatch: true, Status: http.StatusNoContent, Errors: []int{http.StatusForbidden},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/users/{id}/restore", ID: "restoreUser", Tag: "Users", Summary: "Restore a soft-deleted user",
+		Access: staff, Status: http.StatusOK, Response: userResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/users/me/password", ID: "changePassword", Tag: "Users",
+		Summary: "Change the password, every other session is signed out",
+		Access:  authenticated, Request: changePasswordRequest{}, Status: http.StatusOK, Response: "",
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/users/me/addresses", ID: "listAddresses", Tag: "Addresses", Summary: "List the caller's addresses",
+		Access: authenticated, Status: http.StatusOK, Response: []addressResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/users/me/addresses", ID: "createAddress", Tag: "Addresses", Summary: "Add an address",
+		Access: authenticated, Request: addressRequest{}, Status: http.StatusCreated, Response: addressResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/users/me/addresses/{id}", ID: "getAddress", Tag: "Addresses", Summary: "Get an address",
+		Access: authenticated, Status: http.StatusOK, Response: addressResponse{},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/users/me/addresses/{id}", ID: "updateAddress", Tag: "Addresses", Summary: "Update an address",
+		Access: authenticated, Request: addressRequest{}, Status: http.StatusOK, Response: addressResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/users/me/addresses/{id}", ID: "deleteAddress", Tag: "Addresses", Summary: "Delete an address",
+		Access: authenticated, Status: http.StatusNoContent,
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/users/me/export", ID: "exportData", Tag: "Privacy", Summary: "Export the caller's personal data",
+		Access: authenticated, Scope: domain.ScopeUsersRead,
+		Query:  []*apiParameter{queryParameter("format", "zip returns the export as a ZIP archive instead of JSON.", &apiSchema{Type: "string", Enum: []any{"json", "zip"}})},
+		Status: http.StatusOK, Response: dataExportResponse{}, Content: "application/zip",
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/users/me/erasure", ID: "getErasure", Tag: "Privacy", Summary: "Get the scheduled erasure of the caller's account",
+		Access: authenticated, Status: http.StatusOK, Response: erasureResponse{}, Errors: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/users/me/erasure", ID: "requestErasure", Tag: "Privacy", Summary: "Schedule the erasure of the caller's account",
+		Access: authenticated, Status: http.StatusAccepted, Response: erasureResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/users/me/erasure", ID: "cancelErasure", Tag: "Privacy", Summary: "Cancel the scheduled erasure of the caller's account",
+		Access: authenticated, Status: http.StatusNoContent, Errors: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/users", ID: "listUsers", Tag: "Users", Summary: "List users",
+		Query:  append([]*apiParameter{includeDeletedQuery}, paginationQuery...),
+		Status: http.StatusOK, Response: []userResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/users/{id}", ID: "getUser", Tag: "Users", Summary: "Get a user",
+		Status: http.StatusOK, Response: userResponse{}, ETag: true,
+	},
+
+	// Authentication
+	{
+		Method: http.MethodPost, Path: "/v1/auth/login", ID: "login", Tag: "Authentication",
+		Summary: "Log in with email and password, accounts with two-factor authentication get a challenge instead of a token",
+		Request: loginRequestPayload{}, Status: http.StatusOK, Response: oneOf{"", twoFactorChallengeResponse{}},
+		Errors: []int{http.StatusUnauthorized, http.StatusLocked, http.StatusTooManyRequests},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/auth/forgot-password", ID: "forgotPassword", Tag: "Authentication",
+		Summary: "Send a password reset link, the response is the same for unknown emails",
+		Request: forgotPasswordRequestPayload{}, Status: http.StatusAccepted, Response: "",
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/auth/reset-password", ID: "resetPassword", Tag: "Authentication",
+		Summary: "Set a new password with a reset token",
+		Request: resetPasswordRequestPayload{}, Status: http.StatusOK, Response: "",
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/auth/2fa/verify", ID: "verifyTwoFactor", Tag: "Authentication",
+		Summary: "Exchange a login challenge and a two-factor code for an access token",
+		Request: verifyTwoFactorRequestPayload{}, Status: http.StatusOK, Response: "",
+		Errors: []int{http.StatusUnauthorized, http.StatusLocked},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/auth/oidc/{provider}", ID: "oidcLogin", Tag: "Authentication",
+		Summary: "Redirect to the identity provider to log in, the login is bound to the browser with a cookie",
+		Status:  http.StatusFound,
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/auth/oidc/{provider}/callback", ID: "oidcCallback", Tag: "Authentication",
+		Summary: "Complete a login at the identity provider in the browser that started it",
+		Query: []*apiParameter{
+			queryParameter("state", "State of the login, as sent to the identity provider.", stringSchema),
+			queryParameter("code", "Authorization code issued by the identity provider.", stringSchema),
+			queryParameter("error", "Error returned by the identity provider instead of a code.", stringSchema),
+		},
+		Status: http.StatusOK, Response: oneOf{"", twoFactorChallengeResponse{}}, Errors: []int{http.StatusUnauthorized},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/auth/2fa/enroll", ID: "enrollTwoFactor", Tag: "Authentication",
+		Summary: "Start enrolling in two-factor authentication",
+		Access:  authenticated, Status: http.StatusOK, Response: twoFactorEnrollmentResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/auth/2fa/confirm", ID: "confirmTwoFactor", Tag: "Authentication",
+		Summary: "Confirm the enrollment with a code, the response holds the recovery codes",
+		Access:  authenticated, Request: twoFactorCodeRequest{}, Status: http.StatusOK, Response: []string{},
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/auth/2fa/disable", ID: "disableTwoFactor", Tag: "Authentication",
+		Summary: "Disable two-factor authentication",
+		Access:  authenticated, Request: twoFactorCodeRequest{}, Status: http.StatusOK, Response: "",
+	},
+
+	// Orders
+	{
+		Method: http.MethodPost, Path: "/v1/orders", ID: "createOrder", Tag: "Orders", Summary: "Place an order",
+		Access: authenticated, Scope: domain.ScopeOrdersWrite, Request: createOrderRequest{},
+		Status: http.StatusCreated, Response: orderResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/orders/quote", ID: "quoteOrder", Tag: "Orders",
+		Summary: "Price an order with its discounts, shipping and taxes without placing it",
+		Access:  authenticated, Scope: domain.ScopeOrdersWrite, Request: createOrderRequest{},
+		Status: http.StatusOK, Response: orderResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/orders/shipping-quote", ID: "quoteShipping", Tag: "Orders",
+		Summary: "List the shipping methods available for an order and their cost",
+		Access:  authenticated, Scope: domain.ScopeOrdersWrite, Request: createOrderRequest{},
+		Status: http.StatusOK, Response: []shippingQuoteResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/orders", ID: "listOrders", Tag: "Orders", Summary: "List the caller's orders",
+		Access: authenticated, Scope: domain.ScopeOrdersRead, Query: paginationQuery,
+		Status: http.StatusOK, Response: []orderResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/orders/{id}", ID: "getOrder", Tag: "Orders", Summary: "Get an order",
+		Access: authenticated, Scope: domain.ScopeOrdersRead, Status: http.StatusOK, Response: orderResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/orders/{id}/invoice.pdf", ID: "getInvoicePDF", Tag: "Orders",
+		Summary: "Download the invoice of a paid order",
+		Access:  authenticated, Scope: domain.ScopeOrdersRead, Status: http.StatusOK, Content: "application/pdf",
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/orders/{id}/payment", ID: "markOrderPaid", Tag: "Orders",
+		Summary: "Record the payment of an order and issue its invoice",
+		Access:  staff, Status: http.StatusOK, Response: orderResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/orders/{id}/fulfillments", ID: "listOrderFulfillments", Tag: "Fulfillment",
+		Summary: "List the fulfillments of an order",
+		Access:  authenticated, Scope: domain.ScopeOrdersRead, Status: http.StatusOK, Response: []fulfillmentResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/orders/{id}/downloads", ID: "listOrderDownloads", Tag: "Downloads",
+		Summary: "List the downloads of the digital items of an order",
+		Access:  authenticated, Scope: domain.ScopeOrdersRead, Status: http.StatusOK, Response: []downloadGrantResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/orders/{id}/fulfillments", ID: "createFulfillment", Tag: "Fulfillment",
+		Summary: "Create a fulfillment for items of an order",
+		Access:  staff, Request: createFulfillmentRequest{},
+		Status: http.StatusCreated, Response: fulfillmentResponse{}, Errors: []int{http.StatusConflict},
+	},
+
+	// Downloads
+	{
+		Method: http.MethodPost, Path: "/v1/downloads/{id}/link", ID: "createDownloadLink", Tag: "Downloads",
+		Summary: "Create a signed link to download a purchased file",
+		Access:  authenticated, Scope: domain.ScopeOrdersRead,
+		Status: http.StatusCreated, Response: downloadLinkResponse{}, Errors: []int{http.StatusGone},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/downloads/{id}/file", ID: "downloadFile", Tag: "Downloads",
+		Summary: "Download a purchased file with a signed link, every request through the link is counted",
+		Query: []*apiParameter{
+			queryParameter("expires", "Expiry of the link as a Unix time.", integerSchema),
+			queryParameter("signature", "Signature of the link.", stringSchema),
+			queryParameter("resume", "Resume token of a counted download, only accepted with a Range that does not start at the first byte.", stringSchema),
+		},
+		Status: http.StatusOK, Content: "application/octet-stream", Errors: []int{http.StatusForbidden, http.StatusGone},
+		Headers: map[string]string{
+			resumeURLHeader: "URL that continues a counted download with range requests without counting it again.",
+		},
+	},
+
+	// Fulfillments
+	{
+		Method: http.MethodGet, Path: "/v1/fulfillments/{id}", ID: "getFulfillment", Tag: "Fulfillment", Summary: "Get a fulfillment",
+		Access: authenticated, Scope: domain.ScopeOrdersRead, Status: http.StatusOK, Response: fulfillmentResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/fulfillments/{id}/tracking", ID: "getTracking", Tag: "Fulfillment",
+		Summary: "Get the tracking events of a shipped fulfillment",
+		Access:  authenticated, Scope: domain.ScopeOrdersRead, Status: http.StatusOK, Response: []trackingEventResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/fulfillments/{id}/packing-slip", ID: "getPackingSlip", Tag: "Fulfillment",
+		Summary: "Get the packing slip of a fulfillment",
+		Access:  staff, Status: http.StatusOK, Response: packingSlipResponse{},
+	},
+	{
+		Method: http.MethodPut, Path: "/v1/fulfillments/{id}/status", ID: "updateFulfillmentStatus", Tag: "Fulfillment",
+		Summary: "Move a fulfillment to another status",
+		Access:  staff, Request: updateFulfillmentStatusRequest{},
+		Status: http.StatusOK, Response: fulfillmentResponse{}, Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/fulfillments/{id}/ship", ID: "shipFulfillment", Tag: "Fulfillment",
+		Summary: "Ship a fulfillment with its carrier and tracking number",
+		Access:  staff, Request: shipFulfillmentRequest{},
+		Status: http.StatusOK, Response: fulfillmentResponse{}, Errors: []int{http.StatusConflict},
+	},
+
+	// API keys
+	{
+		Method: http.MethodPost, Path: "/v1/api-keys", ID: "createAPIKey", Tag: "API keys",
+		Summary: "Create an API key, the key is only returned in this response",
+		Access:  authenticated, Request: createAPIKeyRequest{}, Status: http.StatusCreated, Response: apiKeyResponse{},
+		Errors: []int{http.StatusForbidden},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/api-keys", ID: "listAPIKeys", Tag: "API keys", Summary: "List the caller's API keys",
+		Access: authenticated, Status: http.StatusOK, Response: []apiKeyResponse{}, Errors: []int{http.StatusForbidden},
+	},
+	{
+		Method: http.MethodDelete, Path: "/v1/api-keys/{id}", ID: "revokeAPIKey", Tag: "API keys", Summary: "Revoke an API key",
+		Access: authenticated, Status: http.StatusNoContent, Errors: []int{http.StatusForbidden},
+	},
+
+	// Administration
+	{
+		Method: http.MethodGet, Path: "/v1/admin/audit", ID: "listAuditEntries", Tag: "Administration", Summary: "List audit log entries",
+		Access: admin,
+		Query: append([]*apiParameter{
+			queryParameter("action", "Only entries of the action, such as book.update.", stringSchema),
+			queryParameter("entity_type", "Only entries about entities of the type.", stringSchema),
+			queryParameter("actor_id", "Only changes made by the user.", integerSchema),
+			queryParameter("entity_id", "Only changes to the entity.", integerSchema),
+			queryParameter("from", "Only entries at or after the time.", &apiSchema{Type: "string", Format: "date-time"}),
+			queryParameter("to", "Only entries before the time.", &apiSchema{Type: "string", Format: "date-time"}),
+		}, paginationQuery...),
+		Status: http.StatusOK, Response: []auditEntryResponse{}, Errors: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/admin/books", ID: "adminListBooks", Tag: "Administration", Summary: "List books including deleted ones",
+		Access: admin, Query: append([]*apiParameter{includeDeletedQuery}, paginationQuery...),
+		Status: http.StatusOK, Response: []bookResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/admin/users", ID: "adminListUsers", Tag: "Administration", Summary: "List users including deleted ones",
+		Access: admin, Query: append([]*apiParameter{includeDeletedQuery}, paginationQuery...),
+		Status: http.StatusOK, Response: []userResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/admin/users/{id}/unlock", ID: "unlockUser", Tag: "Administration",
+		Summary: "Unlock an account locked by failed logins",
+		Access:  admin, Status: http.StatusOK, Response: "",
+	},
+	{
+		Method: http.MethodGet, Path: "/v1/admin/users/{id}/export", ID: "adminExportData", Tag: "Administration",
+		Summary: "Export the personal data of a user",
+		Access:  admin,
+		Query:   []*apiParameter{queryParameter("format", "zip returns the export as a ZIP archive instead of JSON.", &apiSchema{Type: "string", Enum: []any{"json", "zip"}})},
+		Status:  http.StatusOK, Response: dataExportResponse{}, Content: "application/zip",
+	},
+	{
+		Method: http.MethodPost, Path: "/v1/admin/users/{id}/erasure", ID: "adminRequestErasure", Tag: "Administration",
+		Summary: "Schedule the erasure of a user's account",
+		Access:  admin, Status: http.StatusAccepted, Response: erasureResponse{}, Errors: []int{http.StatusConflict},
+	},
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TestOpenAPIDocumentsEveryRoute walks the router and compares its routes with the document it
+// serves, every route needs an operation and every operation a route
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, docsPath+"/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s/openapi.json status = %d", docsPath, rec.Code)
+	}
+	var document struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &document); err != nil {
+		t.Fatalf("document is not JSON: %v", err)
+	}
+	documented := map[string]bool{}
+	for path, operations := range document.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	routed := map[string]bool{}
+	err := chi.Walk(router.Mux, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if route == docsPath || strings.HasPrefix(route, docsPath+"/") {
+			return nil
+		}
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		routed[method+" "+route] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chi.Walk() error = %v", err)
+	}
+	if len(routed) == 0 {
+		t.Fatal("the router has no routes")
+	}
+
+	for route := range routed {
+		if !documented[route] {
+			t.Errorf("%s is not documented", route)
+		}
+	}
+	for route := range documented {
+		if !routed[route] {
+			t.Errorf("%s is documented but has no handler", route)
+		}
+	}
+}
+
+func TestUndocumentedRoutes(t *testing.T) {
+	router := newTestRouter(t, nil)
+	missing, stale, err := router.UndocumentedRoutes()
+	if err != nil || len(missing) != 0 || len(stale) != 0 {
+		t.Fatalf("UndocumentedRoutes() = %q, %q, %v, want no routes", missing, stale, err)
+	}
+
+	router.Get("/v1/undocumented", func(w http.ResponseWriter, r *http.Request) {})
+	documented := endpoints
+	endpoints = append(slices.Clip(endpoints), endpoint{Method: http.MethodDelete, Path: "/v1/unrouted"})
+	t.Cleanup(func() { endpoints = documented })
+
+	missing, stale, err = router.UndocumentedRoutes()
+	if err != nil {
+		t.Fatalf("UndocumentedRoutes() error = %v", err)
+	}
+	if !slices.Equal(missing, []string{"GET /v1/undocumented"}) {
+		t.Errorf("missing = %q, want %q", missing, []string{"GET /v1/undocumented"})
+	}
+	if !slices.Equal(stale, []string{"DELETE /v1/unrouted"}) {
+		t.Errorf("stale = %q, want %q", stale, []string{"DELETE /v1/unrouted"})
+	}
+}
@@ -1,8 +1,10 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
 )
 
@@ -16,17 +18,186 @@ func NewOrderService(service port.OrderService) *OrderHandler {
 	}
 }
 
+// orderItemRequest orders an edition, books are only sold through their editions
+type orderItemRequest struct {
+	EditionId int64 `json:"edition_id" validate:"required,gt=0"`
+	Quantity  int   `json:"quantity" validate:"required,gt=0,lte=100"`
+}
+
 type createOrderRequest struct {
-	BookId int64 `json:"book_id"`
+	Items             []orderItemRequest `json:"items" validate:"required,max=50,dive"`
+	CouponCode        string             `json:"coupon_code" validate:"max=50"`
+	ShippingAddressId int64              `json:"shipping_address_id" validate:"omitempty,gt=0"`
+	BillingAddressId  int64              `json:"billing_address_id" validate:"omitempty,gt=0"`
+	ShippingMethodId  *int64             `json:"shipping_method_id" validate:"omitempty,gt=0"`
+}
+
+func (cr *createOrderRequest) toDomain(userID int64) *domain.Order {
+	order := &domain.Order{
+		UserId:           userID,
+		CouponCode:       cr.CouponCode,
+		ShippingMethodID: cr.ShippingMethodId,
+	}
+	for _, item := range cr.Items {
+		order.Items = append(order.Items, domain.OrderItem{EditionID: &item.EditionId, Quantity: item.Quantity})
+	}
+	return order
+}
+
+// readOrderRequest decodes and validates an order payload, it writes the error response itself
+func readOrderRequest(w http.ResponseWriter, r *http.Request) (*createOrderRequest, bool) {
+	var payload createOrderRequest
+	if !readValidated(w, r, &payload) {
+		return nil, false
+	}
+	return &payload, true
+}
+
+// errUnknownOrderItem is returned for orders that name a book or address that does not exist, the
+// order is invalid rather than the resource missing
+var errUnknownOrderItem = domain.NewError(domain.KindInvalid, "unknown_order_item", "book or address not found")
+
+func orderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrDataNotFound):
+		errorResponse(w, r, errUnknownOrderItem)
+	case errors.Is(err, domain.ErrNoDigitalFile):
+		badRequestResponse(w, r, err)
+	default:
+		errorResponse(w, r, err)
+	}
 }
 
 func (oh *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
-	var payload createBookRequest
-	err := readJSON(w, r, &payload)
+	payload, ok := readOrderRequest(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := oh.service.CreateOrder(r.Context(), payload.toDomain(authUser(r).ID), payload.ShippingAddressId, payload.BillingAddressId)
+	if err != nil {
+		orderError(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusCreated, newOrderResponse(order)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// QuoteOrder prices the items of an order with its coupon and the running promotions, explaining
+// every discount, and taxes it for the shipping address without placing the order
+func (oh *OrderHandler) QuoteOrder(w http.ResponseWriter, r *http.Request) {
+	payload, ok := readOrderRequest(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := oh.service.QuoteOrder(r.Context(), payload.toDomain(authUser(r).ID), payload.ShippingAddressId)
+	if err != nil {
+		orderError(w, r, err)
+		return
+	}
+
+	// A quote is an order that was not placed, it has no id yet
+	if err := jsonResponse(w, http.StatusOK, newOrderResponse(order)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// QuoteShipping lists the shipping methods available for the items of an order and the shipping
+// address with their cost, cheapest first
+func (oh *OrderHandler) QuoteShipping(w http.ResponseWriter, r *http.Request) {
+	payload, ok := readOrderRequest(w, r)
+	if !ok {
+		return
+	}
+
+	quotes, err := oh.service.QuoteShipping(r.Context(), payload.toDomain(authUser(r).ID), payload.ShippingAddressId)
+	if err != nil {
+		orderError(w, r, err)
+		return
+	}
+
+	quotesList := []shippingQuoteResponse{}
+	for _, quote := range quotes {
+		quotesList = append(quotesList, newShippingQuoteResponse(&quote))
+	}
+	if err := jsonResponse(w, http.StatusOK, quotesList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (oh *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	order, err := oh.service.GetOrder(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	// Orders of other users are reported as missing unless the caller is staff
+	if !canViewOrder(r, order) {
+		errorResponse(w, r, domain.ErrDataNotFound)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, newOrderResponse(order)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// ListsOrder lists every order for staff and the caller's own orders for everyone else
+func (oh *OrderHandler) ListsOrder(w http.ResponseWriter, r *http.Request) {
+	skip, limit := extractPagination(r)
+	user := authUser(r)
+
+	var orders []domain.Order
+	var err error
+	if isStaff(user) {
+		orders, err = oh.service.OrderLists(r.Context(), skip, limit)
+	} else {
+		orders, err = oh.service.ListUserOrders(r.Context(), user.ID, skip, limit)
+	}
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	ordersList := []orderResponse{}
+	for _, order := range orders {
+		ordersList = append(ordersList, newOrderResponse(&order))
+	}
+	if err := jsonResponse(w, http.StatusOK, ordersList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// MarkOrderPaid records the payment of an order for staff and issues its invoice
+func (oh *OrderHandler) MarkOrderPaid(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
 	if err != nil {
 		badRequestResponse(w, r, err)
 		return
 	}
+
+	order, err := oh.service.MarkOrderPaid(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, newOrderResponse(order)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
 }
-func (oh *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request)   {}
-func (oh *OrderHandler) ListsOrder(w http.ResponseWriter, r *http.Request) {}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/go-chi/chi/v5"
+)
+
+type PricingHandler struct {
+	service port.PricingService
+}
+
+func NewPricingHandler(service port.PricingService) *PricingHandler {
+	return &PricingHandler{
+		service: service,
+	}
+}
+
+type priceScheduleRequest struct {
+	Price    float64    `json:"price" validate:"required,gt=0"`
+	StartsAt time.Time  `json:"starts_at" validate:"required"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+// GetPriceTimeline returns the price history of an edition with the lowest price of the last 30
+// days and its upcoming scheduled prices
+func (ph *PricingHandler) GetPriceTimeline(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	editionID, err := extractEditionID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	timeline, err := ph.service.GetPriceTimeline(r.Context(), id, editionID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newPriceTimelineResponse(timeline)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PricingHandler) SchedulePrice(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	editionID, err := extractEditionID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	var payload priceScheduleRequest
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	schedule, err := ph.service.SchedulePrice(r.Context(), &domain.PriceSchedule{
+		BookID:    id,
+		EditionID: editionID,
+		Price:     payload.Price,
+		StartsAt:  payload.StartsAt,
+		EndsAt:    payload.EndsAt,
+		CreatedBy: authUser(r).ID,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newPriceScheduleResponse(schedule)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PricingHandler) ListPriceSchedules(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	editionID, err := extractEditionID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	schedules, err := ph.service.ListPriceSchedules(r.Context(), id, editionID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	schedulesList := []priceScheduleResponse{}
+	for _, schedule := range schedules {
+		schedulesList = append(schedulesList, newPriceScheduleResponse(&schedule))
+	}
+	if err := jsonResponse(w, http.StatusOK, schedulesList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// CancelPriceSchedule cancels a pending scheduled price, an active one is ended right away
+func (ph *PricingHandler) CancelPriceSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	editionID, err := extractEditionID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	scheduleID, err := strconv.ParseInt(chi.URLParam(r, "scheduleId"), 10, 64)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := ph.service.CancelPriceSchedule(r.Context(), id, editionID, scheduleID); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/go-chi/chi/v5"
+)
+
+type PrivacyHandler struct {
+	service port.PrivacyService
+}
+
+func NewPrivacyHandler(service port.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		service: service,
+	}
+}
+
+// privacyTarget returns the user whose data the request is about, admin routes name the
+// user in the path and every other route acts on the caller
+func privacyTarget(r *http.Request) (int64, error) {
+	if chi.URLParam(r, "id") == "" {
+		return authUser(r).ID, nil
+	}
+	return extractID(r)
+}
+
+// ExportData returns the caller's personal data as JSON, or as a ZIP archive of JSON files with ?format=zip
+func (ph *PrivacyHandler) ExportData(w http.ResponseWriter, r *http.Request) {
+	userID, err := privacyTarget(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	export, err := ph.service.ExportUserData(r.Context(), userID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	response := newDataExportResponse(export)
+
+	if r.URL.Query().Get("format") != "zip" {
+		if err := jsonResponse(w, http.StatusOK, response); err != nil {
+			internalServerError(w, r, err)
+			return
+		}
+		return
+	}
+
+	archive, err := zipDataExport(response)
+	if err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="personal-data-%d.zip"`, userID))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
+
+// zipDataExport writes every part of the export to its own JSON file in a ZIP archive
+func zipDataExport(export dataExportResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"addresses.json", export.Addresses},
+		{"orders.json", export.Orders},
+	}
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.GeneratedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (ph *PrivacyHandler) RequestErasure(w http.ResponseWriter, r *http.Request) {
+	// Api keys must not be able to schedule the erasure of their owner
+	if authIdentity(r).APIKey != nil {
+		forbiddenResponse(w, r)
+		return
+	}
+	userID, err := privacyTarget(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	request, err := ph.service.RequestErasure(r.Context(), userID, authUser(r).ID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusAccepted, newErasureResponse(request)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PrivacyHandler) GetErasure(w http.ResponseWriter, r *http.Request) {
+	request, err := ph.service.GetErasureRequest(r.Context(), authUser(r).ID)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, newErasureResponse(request)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PrivacyHandler) CancelErasure(w http.ResponseWriter, r *http.Request) {
+	if authIdentity(r).APIKey != nil {
+		forbiddenResponse(w, r)
+		return
+	}
+
+	if err := ph.service.CancelErasure(r.Context(), authUser(r).ID); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type dataExportResponse struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Profile     userResponse      `json:"profile"`
+	Addresses   []addressResponse `json:"addresses"`
+	Orders      []orderResponse   `json:"orders"`
+}
+
+func newDataExportResponse(export *domain.DataExport) dataExportResponse {
+	response := dataExportResponse{
+		GeneratedAt: export.GeneratedAt,
+		Profile:     newUserResponse(export.User),
+		Addresses:   []addressResponse{},
+		Orders:      []orderResponse{},
+	}
+	for _, address := range export.Addresses {
+		response.Addresses = append(response.Addresses, newAddressResponse(&address))
+	}
+	for _, order := range export.Orders {
+		response.Orders = append(response.Orders, newOrderResponse(&order))
+	}
+	return response
+}
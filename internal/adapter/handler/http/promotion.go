@@ -0,0 +1,164 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type PromotionHandler struct {
+	service port.PromotionService
+}
+
+func NewPromotionHandler(service port.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		service: service,
+	}
+}
+
+type createCouponRequest struct {
+	Code           string     `json:"code" validate:"required,max=50,alphanum"`
+	DiscountType   string     `json:"discount_type" validate:"required,oneof=percent fixed"`
+	Value          float64    `json:"value" validate:"required,gt=0"`
+	MinBasket      float64    `json:"min_basket" validate:"gte=0"`
+	MaxUses        int        `json:"max_uses" validate:"gte=0"`
+	MaxUsesPerUser int        `json:"max_uses_per_user" validate:"gte=0"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+}
+
+type createPromotionRequest struct {
+	Name         string     `json:"name" validate:"required,max=200"`
+	CategoryId   int64      `json:"category_id" validate:"required,gt=0"`
+	BuyQuantity  int        `json:"buy_quantity" validate:"required,gt=0"`
+	FreeQuantity int        `json:"free_quantity" validate:"required,gt=0"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+}
+
+// readValidated decodes and validates a payload, it writes the error response itself
+func readValidated(w http.ResponseWriter, r *http.Request, payload any) bool {
+	if err := readJSON(w, r, payload); err != nil {
+		badRequestResponse(w, r, err)
+		return false
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return false
+	}
+	return true
+}
+
+func (ph *PromotionHandler) CreateCoupon(w http.ResponseWriter, r *http.Request) {
+	var payload createCouponRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	coupon, err := ph.service.CreateCoupon(r.Context(), &domain.Coupon{
+		Code:           payload.Code,
+		DiscountType:   domain.DiscountType(payload.DiscountType),
+		Value:          payload.Value,
+		MinBasket:      payload.MinBasket,
+		MaxUses:        payload.MaxUses,
+		MaxUsesPerUser: payload.MaxUsesPerUser,
+		StartsAt:       payload.StartsAt,
+		EndsAt:         payload.EndsAt,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newCouponResponse(coupon)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PromotionHandler) ListCoupons(w http.ResponseWriter, r *http.Request) {
+	skip, limit := extractPagination(r)
+	coupons, err := ph.service.ListCoupons(r.Context(), skip, limit)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	couponsList := []couponResponse{}
+	for _, coupon := range coupons {
+		couponsList = append(couponsList, newCouponResponse(&coupon))
+	}
+	if err := jsonResponse(w, http.StatusOK, couponsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PromotionHandler) DeactivateCoupon(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := ph.service.DeactivateCoupon(r.Context(), id); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ph *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var payload createPromotionRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	promotion, err := ph.service.CreatePromotion(r.Context(), &domain.AutomaticPromotion{
+		Name:         payload.Name,
+		CategoryID:   payload.CategoryId,
+		BuyQuantity:  payload.BuyQuantity,
+		FreeQuantity: payload.FreeQuantity,
+		StartsAt:     payload.StartsAt,
+		EndsAt:       payload.EndsAt,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newPromotionResponse(promotion)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PromotionHandler) ListPromotions(w http.ResponseWriter, r *http.Request) {
+	skip, limit := extractPagination(r)
+	promotions, err := ph.service.ListPromotions(r.Context(), skip, limit)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	promotionsList := []promotionResponse{}
+	for _, promotion := range promotions {
+		promotionsList = append(promotionsList, newPromotionResponse(&promotion))
+	}
+	if err := jsonResponse(w, http.StatusOK, promotionsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PromotionHandler) DeactivatePromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := ph.service.DeactivatePromotion(r.Context(), id); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
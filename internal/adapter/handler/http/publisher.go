@@ -0,0 +1,140 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type PublisherHandler struct {
+	service port.PublisherService
+}
+
+func NewPublisherHandler(service port.PublisherService) *PublisherHandler {
+	return &PublisherHandler{
+		service: service,
+	}
+}
+
+type publisherRequest struct {
+	Name    string `json:"name" validate:"required,max=100"`
+	Website string `json:"website" validate:"omitempty,url,max=255"`
+}
+
+func (ph *PublisherHandler) CreatePublisher(w http.ResponseWriter, r *http.Request) {
+	var payload publisherRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	publisher, err := ph.service.CreatePublisher(r.Context(), &domain.Publisher{
+		Name:    payload.Name,
+		Website: payload.Website,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newPublisherResponse(publisher)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PublisherHandler) ListPublishers(w http.ResponseWriter, r *http.Request) {
+	publishers, err := ph.service.ListPublishers(r.Context())
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	publishersList := []publisherResponse{}
+	for _, publisher := range publishers {
+		publishersList = append(publishersList, newPublisherResponse(&publisher))
+	}
+	if err := jsonResponse(w, http.StatusOK, publishersList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PublisherHandler) GetPublisher(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	publisher, err := ph.service.GetPublisher(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newPublisherResponse(publisher)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// ListPublisherBooks lists the books of a publisher with pagination
+func (ph *PublisherHandler) ListPublisherBooks(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	skip, limit := extractPagination(r)
+	books, err := ph.service.ListPublisherBooks(r.Context(), id, skip, limit)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	booksList := []bookResponse{}
+	for _, book := range books {
+		booksList = append(booksList, newBookResponse(&book))
+	}
+	if err := jsonResponse(w, http.StatusOK, booksList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PublisherHandler) UpdatePublisher(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload publisherRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	publisher, err := ph.service.UpdatePublisher(r.Context(), &domain.Publisher{
+		ID:      id,
+		Name:    payload.Name,
+		Website: payload.Website,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newPublisherResponse(publisher)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (ph *PublisherHandler) DeletePublisher(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := ph.service.DeletePublisher(r.Context(), id); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,37 +1,722 @@
 package http
 
-import "github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+import (
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
 
 type bookResponse struct {
-	ID          int64   `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Author      string  `json:"author"`
-	Price       float64 `json:"price"`
-	Cover       string  `json:"cover"`
+	ID           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Author       string            `json:"author"`
+	Authors      []string          `json:"authors"`
+	Cover        string            `json:"cover"`
+	CategoryId   *int64            `json:"category_id"`
+	PublisherId  *int64            `json:"publisher_id"`
+	SeriesId     *int64            `json:"series_id"`
+	SeriesVolume *int              `json:"series_volume"`
+	Version      int64             `json:"version"`
+	DeletedAt    *time.Time        `json:"deleted_at,omitempty"`
+	Editions     []editionResponse `json:"editions,omitempty"`
+	Locale       string            `json:"locale,omitempty"`
 }
 
 func newBookResponse(book *domain.Book) bookResponse {
-	return bookResponse{
-		ID:          book.ID,
-		Name:        book.Name,
-		Author:      book.Author,
-		Description: book.Description,
-		Cover:       book.Cover,
-		Price:       book.Price,
+	response := bookResponse{
+		ID:           book.ID,
+		Name:         book.Name,
+		Author:       book.Author,
+		Authors:      book.Authors,
+		Description:  book.Description,
+		Cover:        book.Cover,
+		CategoryId:   book.CategoryID,
+		PublisherId:  book.PublisherID,
+		SeriesId:     book.SeriesID,
+		SeriesVolume: book.SeriesVolume,
+		Version:      book.Version,
+		DeletedAt:    book.DeletedAt,
+		Locale:       book.Locale,
+	}
+	for _, edition := range book.Editions {
+		response.Editions = append(response.Editions, newEditionResponse(&edition))
 	}
+	return response
 }
 
 type userResponse struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 func newUserResponse(user *domain.User) userResponse {
 	return userResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		Version:   user.Version,
+		DeletedAt: user.DeletedAt,
+	}
+}
+
+type userUpdateResponse struct {
+	userResponse
+	PendingEmail string `json:"pending_email,omitempty"`
+}
+
+func newUserUpdateResponse(update *domain.UserUpdate) userUpdateResponse {
+	return userUpdateResponse{
+		userResponse: newUserResponse(update.User),
+		PendingEmail: update.PendingEmail,
+	}
+}
+
+type twoFactorChallengeResponse struct {
+	ChallengeToken    string `json:"challenge_token"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+}
+
+func newTwoFactorChallengeResponse(result *domain.LoginResult) twoFactorChallengeResponse {
+	return twoFactorChallengeResponse{
+		ChallengeToken:    result.ChallengeToken,
+		TwoFactorRequired: result.TwoFactorRequired,
+	}
+}
+
+type twoFactorEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+func newTwoFactorEnrollmentResponse(enrollment *domain.TwoFactorEnrollment) twoFactorEnrollmentResponse {
+	return twoFactorEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	}
+}
+
+type apiKeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newAPIKeyResponse(key *domain.APIKey) apiKeyResponse {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+	return apiKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+type addressResponse struct {
+	ID                int64  `json:"id"`
+	Label             string `json:"label"`
+	FullName          string `json:"full_name"`
+	Line1             string `json:"line1"`
+	Line2             string `json:"line2"`
+	City              string `json:"city"`
+	Region            string `json:"region"`
+	PostalCode        string `json:"postal_code"`
+	Country           string `json:"country"`
+	Phone             string `json:"phone"`
+	IsDefaultShipping bool   `json:"is_default_shipping"`
+	IsDefaultBilling  bool   `json:"is_default_billing"`
+}
+
+func newAddressResponse(address *domain.Address) addressResponse {
+	return addressResponse{
+		ID:                address.ID,
+		Label:             address.Label,
+		FullName:          address.FullName,
+		Line1:             address.Line1,
+		Line2:             address.Line2,
+		City:              address.City,
+		Region:            address.Region,
+		PostalCode:        address.PostalCode,
+		Country:           address.Country,
+		Phone:             address.Phone,
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+	}
+}
+
+type orderItemResponse struct {
+	ID         int64   `json:"id"`
+	BookId     int64   `json:"book_id"`
+	EditionId  *int64  `json:"edition_id"`
+	Format     string  `json:"format,omitempty"`
+	CategoryId *int64  `json:"category_id"`
+	Class      string  `json:"product_class"`
+	Quantity   int     `json:"quantity"`
+	UnitPrice  float64 `json:"unit_price"`
+}
+
+func newOrderItemResponses(items []domain.OrderItem) []orderItemResponse {
+	responses := []orderItemResponse{}
+	for _, item := range items {
+		responses = append(responses, orderItemResponse{
+			ID:         item.ID,
+			BookId:     item.BookID,
+			EditionId:  item.EditionID,
+			Format:     string(item.Format),
+			CategoryId: item.CategoryID,
+			Class:      string(item.Class),
+			Quantity:   item.Quantity,
+			UnitPrice:  item.UnitPrice,
+		})
+	}
+	return responses
+}
+
+type orderResponse struct {
+	ID               int64                     `json:"id"`
+	UserId           int64                     `json:"user_id"`
+	BookId           int64                     `json:"book_id"`
+	Status           string                    `json:"status"`
+	Items            []orderItemResponse       `json:"items"`
+	ShippingAddress  *domain.AddressSnapshot   `json:"shipping_address"`
+	BillingAddress   *domain.AddressSnapshot   `json:"billing_address"`
+	CouponCode       string                    `json:"coupon_code,omitempty"`
+	Subtotal         float64                   `json:"subtotal"`
+	DiscountTotal    float64                   `json:"discount_total"`
+	ShippingMethodId *int64                    `json:"shipping_method_id"`
+	ShippingMethod   string                    `json:"shipping_method"`
+	ShippingCost     float64                   `json:"shipping_cost"`
+	TaxMode          string                    `json:"tax_mode,omitempty"`
+	TaxTotal         float64                   `json:"tax_total"`
+	TaxLines         []domain.TaxLine          `json:"tax_lines"`
+	Total            float64                   `json:"total"`
+	Promotions       []domain.AppliedPromotion `json:"promotions"`
+	PaidAt           *time.Time                `json:"paid_at"`
+	CreatedAt        time.Time                 `json:"created_at"`
+}
+
+func newOrderResponse(order *domain.Order) orderResponse {
+	promotions := order.Promotions
+	if promotions == nil {
+		promotions = []domain.AppliedPromotion{}
+	}
+	taxLines := order.TaxLines
+	if taxLines == nil {
+		taxLines = []domain.TaxLine{}
+	}
+	return orderResponse{
+		ID:               order.ID,
+		UserId:           order.UserId,
+		BookId:           order.BookId,
+		Status:           string(order.Status),
+		Items:            newOrderItemResponses(order.Items),
+		ShippingAddress:  order.ShippingAddress,
+		BillingAddress:   order.BillingAddress,
+		CouponCode:       order.CouponCode,
+		Subtotal:         order.Subtotal,
+		DiscountTotal:    order.DiscountTotal,
+		ShippingMethodId: order.ShippingMethodID,
+		ShippingMethod:   order.ShippingMethod,
+		ShippingCost:     order.ShippingCost,
+		TaxMode:          string(order.TaxMode),
+		TaxTotal:         order.TaxTotal,
+		TaxLines:         taxLines,
+		Total:            order.Total,
+		Promotions:       promotions,
+		PaidAt:           order.PaidAt,
+		CreatedAt:        order.CreatedAt,
+	}
+}
+
+type categoryResponse struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Locale string `json:"locale,omitempty"`
+}
+
+func newCategoryResponse(category *domain.Category) categoryResponse {
+	return categoryResponse{
+		ID:     category.ID,
+		Name:   category.Name,
+		Locale: category.Locale,
+	}
+}
+
+type bookTranslationResponse struct {
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newBookTranslationResponse(translation *domain.BookTranslation) bookTranslationResponse {
+	return bookTranslationResponse{
+		Locale:      translation.Locale,
+		Name:        translation.Name,
+		Description: translation.Description,
+		UpdatedAt:   translation.UpdatedAt,
+	}
+}
+
+type categoryTranslationResponse struct {
+	Locale    string    `json:"locale"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newCategoryTranslationResponse(translation *domain.CategoryTranslation) categoryTranslationResponse {
+	return categoryTranslationResponse{
+		Locale:    translation.Locale,
+		Name:      translation.Name,
+		UpdatedAt: translation.UpdatedAt,
+	}
+}
+
+type publisherResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Website   string    `json:"website,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newPublisherResponse(publisher *domain.Publisher) publisherResponse {
+	return publisherResponse{
+		ID:        publisher.ID,
+		Name:      publisher.Name,
+		Website:   publisher.Website,
+		CreatedAt: publisher.CreatedAt,
+	}
+}
+
+// seriesResponse lists the volumes of a series only when a single series is read
+type seriesResponse struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	PublisherId *int64         `json:"publisher_id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Volumes     []bookResponse `json:"volumes,omitempty"`
+}
+
+func newSeriesResponse(series *domain.Series) seriesResponse {
+	response := seriesResponse{
+		ID:          series.ID,
+		Name:        series.Name,
+		Description: series.Description,
+		PublisherId: series.PublisherID,
+		CreatedAt:   series.CreatedAt,
+	}
+	for _, book := range series.Volumes {
+		response.Volumes = append(response.Volumes, newBookResponse(&book))
+	}
+	return response
+}
+
+type couponResponse struct {
+	ID             int64      `json:"id"`
+	Code           string     `json:"code"`
+	DiscountType   string     `json:"discount_type"`
+	Value          float64    `json:"value"`
+	MinBasket      float64    `json:"min_basket"`
+	MaxUses        int        `json:"max_uses"`
+	MaxUsesPerUser int        `json:"max_uses_per_user"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func newCouponResponse(coupon *domain.Coupon) couponResponse {
+	return couponResponse{
+		ID:             coupon.ID,
+		Code:           coupon.Code,
+		DiscountType:   string(coupon.DiscountType),
+		Value:          coupon.Value,
+		MinBasket:      coupon.MinBasket,
+		MaxUses:        coupon.MaxUses,
+		MaxUsesPerUser: coupon.MaxUsesPerUser,
+		StartsAt:       coupon.StartsAt,
+		EndsAt:         coupon.EndsAt,
+		Active:         coupon.Active,
+		CreatedAt:      coupon.CreatedAt,
+	}
+}
+
+type promotionResponse struct {
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	CategoryId   int64      `json:"category_id"`
+	BuyQuantity  int        `json:"buy_quantity"`
+	FreeQuantity int        `json:"free_quantity"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	Active       bool       `json:"active"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func newPromotionResponse(promotion *domain.AutomaticPromotion) promotionResponse {
+	return promotionResponse{
+		ID:           promotion.ID,
+		Name:         promotion.Name,
+		CategoryId:   promotion.CategoryID,
+		BuyQuantity:  promotion.BuyQuantity,
+		FreeQuantity: promotion.FreeQuantity,
+		StartsAt:     promotion.StartsAt,
+		EndsAt:       promotion.EndsAt,
+		Active:       promotion.Active,
+		CreatedAt:    promotion.CreatedAt,
+	}
+}
+
+type erasureResponse struct {
+	RequestedAt  time.Time `json:"requested_at"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
+
+func newErasureResponse(request *domain.ErasureRequest) erasureResponse {
+	return erasureResponse{
+		RequestedAt:  request.RequestedAt,
+		ScheduledFor: request.ScheduledFor,
+	}
+}
+
+type auditEntryResponse struct {
+	ID         int64                         `json:"id"`
+	ActorID    *int64                        `json:"actor_id"`
+	Action     string                        `json:"action"`
+	EntityType string                        `json:"entity_type"`
+	EntityID   int64                         `json:"entity_id"`
+	Changes    map[string]domain.AuditChange `json:"changes"`
+	RequestID  string                        `json:"request_id"`
+	IP         string                        `json:"ip"`
+	CreatedAt  time.Time                     `json:"created_at"`
+}
+
+func newAuditEntryResponse(entry *domain.AuditEntry) auditEntryResponse {
+	return auditEntryResponse{
+		ID:         entry.ID,
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Changes:    entry.Changes,
+		RequestID:  entry.RequestID,
+		IP:         entry.IP,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+type priceChangeResponse struct {
+	Price     float64   `json:"price"`
+	Source    string    `json:"source"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type priceScheduleResponse struct {
+	ID            int64      `json:"id"`
+	BookID        int64      `json:"book_id"`
+	EditionID     int64      `json:"edition_id"`
+	Price         float64    `json:"price"`
+	PreviousPrice *float64   `json:"previous_price"`
+	StartsAt      time.Time  `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	Status        string     `json:"status"`
+	CreatedBy     int64      `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func newPriceScheduleResponse(schedule *domain.PriceSchedule) priceScheduleResponse {
+	return priceScheduleResponse{
+		ID:            schedule.ID,
+		BookID:        schedule.BookID,
+		EditionID:     schedule.EditionID,
+		Price:         schedule.Price,
+		PreviousPrice: schedule.PreviousPrice,
+		StartsAt:      schedule.StartsAt,
+		EndsAt:        schedule.EndsAt,
+		Status:        string(schedule.Status),
+		CreatedBy:     schedule.CreatedBy,
+		CreatedAt:     schedule.CreatedAt,
+	}
+}
+
+type priceTimelineResponse struct {
+	BookID            int64                   `json:"book_id"`
+	EditionID         int64                   `json:"edition_id"`
+	CurrentPrice      float64                 `json:"current_price"`
+	LowestPrice30Days float64                 `json:"lowest_price_30_days"`
+	LowestSince       time.Time               `json:"lowest_since"`
+	History           []priceChangeResponse   `json:"history"`
+	Schedules         []priceScheduleResponse `json:"schedules"`
+}
+
+func newPriceTimelineResponse(timeline *domain.PriceTimeline) priceTimelineResponse {
+	response := priceTimelineResponse{
+		BookID:            timeline.BookID,
+		EditionID:         timeline.EditionID,
+		CurrentPrice:      timeline.CurrentPrice,
+		LowestPrice30Days: timeline.LowestPrice,
+		LowestSince:       timeline.LowestSince,
+		History:           []priceChangeResponse{},
+		Schedules:         []priceScheduleResponse{},
+	}
+	for _, change := range timeline.History {
+		response.History = append(response.History, priceChangeResponse{
+			Price:     change.Price,
+			Source:    string(change.Source),
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	for _, schedule := range timeline.Schedules {
+		response.Schedules = append(response.Schedules, newPriceScheduleResponse(&schedule))
+	}
+	return response
+}
+
+type shippingZoneResponse struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Countries []string `json:"countries"`
+}
+
+func newShippingZoneResponse(zone *domain.ShippingZone) shippingZoneResponse {
+	return shippingZoneResponse{
+		ID:        zone.ID,
+		Name:      zone.Name,
+		Countries: zone.Countries,
+	}
+}
+
+type shippingMethodResponse struct {
+	ID             int64   `json:"id"`
+	ZoneId         int64   `json:"zone_id"`
+	Name           string  `json:"name"`
+	Type           string  `json:"rate_type"`
+	Price          float64 `json:"price"`
+	PerKg          float64 `json:"per_kg"`
+	FreeOver       float64 `json:"free_over"`
+	MaxWeightGrams int     `json:"max_weight_grams"`
+	Active         bool    `json:"active"`
+}
+
+func newShippingMethodResponse(method *domain.ShippingMethod) shippingMethodResponse {
+	return shippingMethodResponse{
+		ID:             method.ID,
+		ZoneId:         method.ZoneID,
+		Name:           method.Name,
+		Type:           string(method.Type),
+		Price:          method.Price,
+		PerKg:          method.PerKg,
+		FreeOver:       method.FreeOver,
+		MaxWeightGrams: method.MaxWeightGrams,
+		Active:         method.Active,
+	}
+}
+
+type shippingQuoteResponse struct {
+	MethodId    int64   `json:"method_id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"rate_type"`
+	Cost        float64 `json:"cost"`
+	Explanation string  `json:"explanation"`
+}
+
+func newShippingQuoteResponse(quote *domain.ShippingQuote) shippingQuoteResponse {
+	return shippingQuoteResponse{
+		MethodId:    quote.MethodID,
+		Name:        quote.Name,
+		Type:        string(quote.Type),
+		Cost:        quote.Cost,
+		Explanation: quote.Explanation,
+	}
+}
+
+type fulfillmentItemResponse struct {
+	OrderItemId int64 `json:"order_item_id"`
+	BookId      int64 `json:"book_id"`
+	Quantity    int   `json:"quantity"`
+}
+
+type fulfillmentResponse struct {
+	ID             int64                     `json:"id"`
+	OrderId        int64                     `json:"order_id"`
+	Status         string                    `json:"status"`
+	Carrier        string                    `json:"carrier"`
+	TrackingNumber string                    `json:"tracking_number"`
+	Items          []fulfillmentItemResponse `json:"items"`
+	ShippedAt      *time.Time                `json:"shipped_at"`
+	DeliveredAt    *time.Time                `json:"delivered_at"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+}
+
+func newFulfillmentResponse(fulfillment *domain.Fulfillment) fulfillmentResponse {
+	items := []fulfillmentItemResponse{}
+	for _, item := range fulfillment.Items {
+		items = append(items, fulfillmentItemResponse{
+			OrderItemId: item.OrderItemID,
+			BookId:      item.BookID,
+			Quantity:    item.Quantity,
+		})
+	}
+	return fulfillmentResponse{
+		ID:             fulfillment.ID,
+		OrderId:        fulfillment.OrderID,
+		Status:         string(fulfillment.Status),
+		Carrier:        fulfillment.Carrier,
+		TrackingNumber: fulfillment.TrackingNumber,
+		Items:          items,
+		ShippedAt:      fulfillment.ShippedAt,
+		DeliveredAt:    fulfillment.DeliveredAt,
+		CreatedAt:      fulfillment.CreatedAt,
+		UpdatedAt:      fulfillment.UpdatedAt,
+	}
+}
+
+type trackingEventResponse struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+func newTrackingEventResponse(event *domain.TrackingEvent) trackingEventResponse {
+	return trackingEventResponse{
+		Status:      string(event.Status),
+		Description: event.Description,
+		Location:    event.Location,
+		OccurredAt:  event.OccurredAt,
+	}
+}
+
+type packingSlipLineResponse struct {
+	BookId   int64  `json:"book_id"`
+	Name     string `json:"name"`
+	Author   string `json:"author"`
+	Quantity int    `json:"quantity"`
+}
+
+type packingSlipResponse struct {
+	FulfillmentId  int64                     `json:"fulfillment_id"`
+	OrderId        int64                     `json:"order_id"`
+	OrderedAt      time.Time                 `json:"ordered_at"`
+	ShipTo         *domain.AddressSnapshot   `json:"ship_to"`
+	ShippingMethod string                    `json:"shipping_method"`
+	Carrier        string                    `json:"carrier"`
+	TrackingNumber string                    `json:"tracking_number"`
+	Lines          []packingSlipLineResponse `json:"lines"`
+}
+
+func newPackingSlipResponse(slip *domain.PackingSlip) packingSlipResponse {
+	lines := []packingSlipLineResponse{}
+	for _, line := range slip.Lines {
+		lines = append(lines, packingSlipLineResponse{
+			BookId:   line.BookID,
+			Name:     line.Name,
+			Author:   line.Author,
+			Quantity: line.Quantity,
+		})
+	}
+	return packingSlipResponse{
+		FulfillmentId:  slip.FulfillmentID,
+		OrderId:        slip.OrderID,
+		OrderedAt:      slip.OrderedAt,
+		ShipTo:         slip.ShipTo,
+		ShippingMethod: slip.ShippingMethod,
+		Carrier:        slip.Carrier,
+		TrackingNumber: slip.TrackingNumber,
+		Lines:          lines,
+	}
+}
+
+type editionResponse struct {
+	ID            int64      `json:"id"`
+	BookId        int64      `json:"book_id"`
+	ISBN          string     `json:"isbn,omitempty"`
+	Format        string     `json:"format"`
+	Digital       bool       `json:"digital"`
+	Language      string     `json:"language"`
+	EditionNumber int        `json:"edition_number"`
+	PageCount     int        `json:"page_count,omitempty"`
+	PublishedOn   *time.Time `json:"published_on,omitempty"`
+	Price         float64    `json:"price"`
+	Stock         *int       `json:"stock"`
+	WeightGrams   int        `json:"weight_grams"`
+	FileName      string     `json:"file_name,omitempty"`
+	ContentType   string     `json:"content_type,omitempty"`
+	FileSize      int64      `json:"file_size,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func newEditionResponse(edition *domain.Edition) editionResponse {
+	return editionResponse{
+		ID:            edition.ID,
+		BookId:        edition.BookID,
+		ISBN:          edition.ISBN,
+		Format:        string(edition.Format),
+		Digital:       edition.Format.IsDigital(),
+		Language:      edition.Language,
+		EditionNumber: edition.EditionNumber,
+		PageCount:     edition.PageCount,
+		PublishedOn:   edition.PublishedOn,
+		Price:         edition.Price,
+		Stock:         edition.Stock,
+		WeightGrams:   edition.WeightGrams,
+		FileName:      edition.FileName,
+		ContentType:   edition.ContentType,
+		FileSize:      edition.FileSize,
+		UpdatedAt:     edition.UpdatedAt,
+	}
+}
+
+type downloadGrantResponse struct {
+	ID           int64     `json:"id"`
+	OrderItemId  int64     `json:"order_item_id"`
+	EditionId    int64     `json:"edition_id"`
+	MaxDownloads int       `json:"max_downloads"`
+	Downloads    int       `json:"downloads"`
+	Remaining    int       `json:"remaining"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func newDownloadGrantResponse(grant *domain.DownloadGrant) downloadGrantResponse {
+	return downloadGrantResponse{
+		ID:           grant.ID,
+		OrderItemId:  grant.OrderItemID,
+		EditionId:    grant.EditionID,
+		MaxDownloads: grant.MaxDownloads,
+		Downloads:    grant.Downloads,
+		Remaining:    grant.Remaining(),
+		CreatedAt:    grant.CreatedAt,
+	}
+}
+
+type downloadLinkResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newDownloadLinkResponse(link *domain.DownloadLink) downloadLinkResponse {
+	return downloadLinkResponse{
+		URL:       link.URL,
+		ExpiresAt: link.ExpiresAt,
 	}
 }
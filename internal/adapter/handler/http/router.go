@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
 )
@@ -13,30 +14,210 @@ type Router struct {
 	*chi.Mux
 }
 
-func NewRouter(config *config.HTTP, bookHandler BookHandler, userHandler UserHandler, authHandler AuthHandler) (*Router, error) {
+func NewRouter(config *config.HTTP, i18n *config.I18N, bookHandler BookHandler, userHandler UserHandler, authHandler AuthHandler, twoFactorHandler TwoFactorHandler, oidcHandler OIDCHandler, apiKeyHandler APIKeyHandler, orderHandler OrderHandler, addressHandler AddressHandler, privacyHandler PrivacyHandler, auditHandler AuditHandler, pricingHandler PricingHandler, categoryHandler CategoryHandler, promotionHandler PromotionHandler, shippingHandler ShippingHandler, fulfillmentHandler FulfillmentHandler, invoiceHandler InvoiceHandler, editionHandler EditionHandler, downloadHandler DownloadHandler, publisherHandler PublisherHandler, seriesHandler SeriesHandler, translationHandler TranslationHandler) (*Router, error) {
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
+	router.Use(AuditMeta)
+	router.Use(Localize(i18n))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Logger)
+	router.NotFound(routeNotFoundResponse)
+	router.MethodNotAllowed(methodNotAllowedResponse)
 
 	router.Route("/v1", func(r chi.Router) {
 		r.Route("/books", func(r chi.Router) {
-			r.Post("/create", bookHandler.CreateBook)
 			r.Get("/", bookHandler.ListBooks)
+			r.Get("/search", bookHandler.SearchBooks)
 			r.Get("/{id}", bookHandler.GetBookById)
-			r.Delete("/{id}", bookHandler.DeleteBook)
-			r.Put("/{id}", bookHandler.UpdateBook)
+			r.Get("/{id}/editions", editionHandler.ListEditions)
+			r.Get("/{id}/editions/{editionId}/prices", pricingHandler.GetPriceTimeline)
+			r.Get("/{id}/translations", translationHandler.ListBookTranslations)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.AuthenticateScope(domain.ScopeBooksWrite))
+				r.Use(RequireRole(domain.Staff, domain.Admin))
+				r.Use(authHandler.RequireTwoFactor)
+				r.Post("/create", bookHandler.CreateBook)
+				r.Delete("/{id}", bookHandler.DeleteBook)
+				r.Put("/{id}", bookHandler.UpdateBook)
+				r.Post("/{id}/restore", bookHandler.RestoreBook)
+				r.Post("/{id}/editions", editionHandler.CreateEdition)
+				r.Put("/{id}/editions/{editionId}", editionHandler.UpdateEdition)
+				r.Put("/{id}/editions/{editionId}/file", editionHandler.UploadFile)
+				r.Get("/{id}/editions/{editionId}/price-schedules", pricingHandler.ListPriceSchedules)
+				r.Post("/{id}/editions/{editionId}/price-schedules", pricingHandler.SchedulePrice)
+				r.Put("/{id}/translations/{locale}", translationHandler.SetBookTranslation)
+				r.Delete("/{id}/translations/{locale}", translationHandler.DeleteBookTranslation)
+				r.Delete("/{id}/editions/{editionId}/price-schedules/{scheduleId}", pricingHandler.CancelPriceSchedule)
+			})
+		})
+		r.Route("/categories", func(r chi.Router) {
+			r.Get("/", categoryHandler.ListCategories)
+			r.Get("/{id}/translations", translationHandler.ListCategoryTranslations)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.AuthenticateScope(domain.ScopeBooksWrite))
+				r.Use(RequireRole(domain.Staff, domain.Admin))
+				r.Use(authHandler.RequireTwoFactor)
+				r.Post("/", categoryHandler.CreateCategory)
+				r.Put("/{id}/translations/{locale}", translationHandler.SetCategoryTranslation)
+				r.Delete("/{id}/translations/{locale}", translationHandler.DeleteCategoryTranslation)
+			})
+		})
+		r.Route("/publishers", func(r chi.Router) {
+			r.Get("/", publisherHandler.ListPublishers)
+			r.Get("/{id}", publisherHandler.GetPublisher)
+			r.Get("/{id}/books", publisherHandler.ListPublisherBooks)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.AuthenticateScope(domain.ScopeBooksWrite))
+				r.Use(RequireRole(domain.Staff, domain.Admin))
+				r.Use(authHandler.RequireTwoFactor)
+				r.Post("/", publisherHandler.CreatePublisher)
+				r.Put("/{id}", publisherHandler.UpdatePublisher)
+				r.Delete("/{id}", publisherHandler.DeletePublisher)
+			})
+		})
+		r.Route("/series", func(r chi.Router) {
+			r.Get("/", seriesHandler.ListSeries)
+			r.Get("/{id}", seriesHandler.GetSeries)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.AuthenticateScope(domain.ScopeBooksWrite))
+				r.Use(RequireRole(domain.Staff, domain.Admin))
+				r.Use(authHandler.RequireTwoFactor)
+				r.Post("/", seriesHandler.CreateSeries)
+				r.Put("/{id}", seriesHandler.UpdateSeries)
+				r.Delete("/{id}", seriesHandler.DeleteSeries)
+			})
+		})
+		r.Route("/promotions", func(r chi.Router) {
+			r.Use(authHandler.Authenticate)
+			r.Use(RequireRole(domain.Staff, domain.Admin))
+			r.Use(authHandler.RequireTwoFactor)
+			r.Get("/coupons", promotionHandler.ListCoupons)
+			r.Post("/coupons", promotionHandler.CreateCoupon)
+			r.Delete("/coupons/{id}", promotionHandler.DeactivateCoupon)
+			r.Get("/automatic", promotionHandler.ListPromotions)
+			r.Post("/automatic", promotionHandler.CreatePromotion)
+			r.Delete("/automatic/{id}", promotionHandler.DeactivatePromotion)
+		})
+		r.Route("/shipping", func(r chi.Router) {
+			r.Use(authHandler.Authenticate)
+			r.Use(RequireRole(domain.Staff, domain.Admin))
+			r.Use(authHandler.RequireTwoFactor)
+			r.Get("/zones", shippingHandler.ListZones)
+			r.Post("/zones", shippingHandler.CreateZone)
+			r.Get("/methods", shippingHandler.ListMethods)
+			r.Post("/methods", shippingHandler.CreateMethod)
+			r.Delete("/methods/{id}", shippingHandler.DisableMethod)
 		})
 		r.Route("/users", func(r chi.Router) {
 			r.Post("/register", userHandler.RegisterUser)
-			r.Put("/{id}/update", userHandler.UpdateUser)
+			r.Post("/verify-email", userHandler.VerifyEmail)
+			r.With(authHandler.Authenticate, authHandler.RequireTwoFactor).Put("/{id}/update", userHandler.UpdateUser)
+			r.With(authHandler.Authenticate, authHandler.RequireTwoFactor).Patch("/{id}", userHandler.PatchUser)
+			r.With(authHandler.Authenticate, authHandler.RequireTwoFactor).Delete("/{id}", userHandler.DeleteUser)
+			r.With(authHandler.Authenticate, RequireRole(domain.Staff, domain.Admin), authHandler.RequireTwoFactor).
+				Post("/{id}/restore", userHandler.RestoreUser)
+			r.With(authHandler.Authenticate).Post("/me/password", userHandler.ChangePassword)
+			r.Route("/me/addresses", func(r chi.Router) {
+				r.Use(authHandler.Authenticate)
+				r.Get("/", addressHandler.ListAddresses)
+				r.Post("/", addressHandler.CreateAddress)
+				r.Get("/{id}", addressHandler.GetAddress)
+				r.Put("/{id}", addressHandler.UpdateAddress)
+				r.Delete("/{id}", addressHandler.DeleteAddress)
+			})
+			r.With(authHandler.AuthenticateScope(domain.ScopeUsersRead)).Get("/me/export", privacyHandler.ExportData)
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.Authenticate)
+				r.Get("/me/erasure", privacyHandler.GetErasure)
+				r.Post("/me/erasure", privacyHandler.RequestErasure)
+				r.Delete("/me/erasure", privacyHandler.CancelErasure)
+			})
 			r.Get("/", userHandler.ListUsers)
 			r.Get("/{id}", userHandler.GetUser)
 		})
 		r.Route("/auth", func(r chi.Router) {
 			r.Post("/login", authHandler.Login)
+			r.Post("/forgot-password", authHandler.ForgotPassword)
+			r.Post("/reset-password", authHandler.ResetPassword)
+			r.Post("/2fa/verify", authHandler.VerifyTwoFactor)
+			r.Get("/oidc/{provider}", oidcHandler.Login)
+			r.Get("/oidc/{provider}/callback", oidcHandler.Callback)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.Authenticate)
+				r.Post("/2fa/enroll", twoFactorHandler.Enroll)
+				r.Post("/2fa/confirm", twoFactorHandler.Confirm)
+				r.Post("/2fa/disable", twoFactorHandler.Disable)
+			})
+		})
+		r.Route("/orders", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.AuthenticateScope(domain.ScopeOrdersWrite))
+				r.Post("/", orderHandler.CreateOrder)
+				r.Post("/quote", orderHandler.QuoteOrder)
+				r.Post("/shipping-quote", orderHandler.QuoteShipping)
+			})
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.AuthenticateScope(domain.ScopeOrdersRead))
+				r.Get("/", orderHandler.ListsOrder)
+				r.Get("/{id}", orderHandler.GetOrder)
+				r.Get("/{id}/invoice.pdf", invoiceHandler.GetInvoicePDF)
+				r.Get("/{id}/fulfillments", fulfillmentHandler.ListOrderFulfillments)
+				r.Get("/{id}/downloads", downloadHandler.ListOrderDownloads)
+			})
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.Authenticate)
+				r.Use(RequireRole(domain.Staff, domain.Admin))
+				r.Use(authHandler.RequireTwoFactor)
+				r.Post("/{id}/payment", orderHandler.MarkOrderPaid)
+				r.Post("/{id}/fulfillments", fulfillmentHandler.CreateFulfillment)
+			})
+		})
+		r.Route("/downloads", func(r chi.Router) {
+			r.With(authHandler.AuthenticateScope(domain.ScopeOrdersRead)).Post("/{id}/link", downloadHandler.CreateLink)
+			r.Get("/{id}/file", downloadHandler.DownloadFile)
+		})
+		r.Route("/fulfillments", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.AuthenticateScope(domain.ScopeOrdersRead))
+				r.Get("/{id}", fulfillmentHandler.GetFulfillment)
+				r.Get("/{id}/tracking", fulfillmentHandler.GetTracking)
+			})
+			r.Group(func(r chi.Router) {
+				r.Use(authHandler.Authenticate)
+				r.Use(RequireRole(domain.Staff, domain.Admin))
+				r.Use(authHandler.RequireTwoFactor)
+				r.Get("/{id}/packing-slip", fulfillmentHandler.GetPackingSlip)
+				r.Put("/{id}/status", fulfillmentHandler.UpdateStatus)
+				r.Post("/{id}/ship", fulfillmentHandler.Ship)
+			})
+		})
+		r.Route("/api-keys", func(r chi.Router) {
+			r.Use(authHandler.Authenticate)
+			r.Use(authHandler.RequireTwoFactor)
+			r.Post("/", apiKeyHandler.CreateAPIKey)
+			r.Get("/", apiKeyHandler.ListAPIKeys)
+			r.Delete("/{id}", apiKeyHandler.RevokeAPIKey)
+		})
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authHandler.Authenticate)
+			r.Use(RequireRole(domain.Admin))
+			r.Use(authHandler.RequireTwoFactor)
+			r.Get("/audit", auditHandler.ListAuditEntries)
+			r.Get("/books", bookHandler.ListBooks)
+			r.Get("/users", userHandler.ListUsers)
+			r.Post("/users/{id}/unlock", authHandler.UnlockUser)
+			r.Get("/users/{id}/export", privacyHandler.ExportData)
+			r.Post("/users/{id}/erasure", privacyHandler.RequestErasure)
 		})
 	})
+	if err := mountDocs(router); err != nil {
+		return nil, err
+	}
 
 	return &Router{
 		Mux: router,
@@ -0,0 +1,222 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+func init() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// stubAPIKeys is a port.APIKeyService that authenticates any key with the scopes it was built with
+type stubAPIKeys struct {
+	port.APIKeyService
+	user   *domain.User
+	scopes []domain.APIKeyScope
+}
+
+func (sk *stubAPIKeys) Authenticate(ctx context.Context, key string) (*domain.Identity, error) {
+	return &domain.Identity{User: sk.user, APIKey: &domain.APIKey{ID: 1, UserID: sk.user.ID, Scopes: sk.scopes}}, nil
+}
+
+// stubAuth is a port.AuthService that authenticates any token as the user it was built with
+type stubAuth struct {
+	port.AuthService
+	user              *domain.User
+	twoFactorVerified bool
+}
+
+func (sa *stubAuth) Authenticate(ctx context.Context, token string) (*domain.Identity, error) {
+	return &domain.Identity{User: sa.user, TwoFactorVerified: sa.twoFactorVerified}, nil
+}
+
+func (sa *stubAuth) AuthorizeTwoFactor(identity *domain.Identity) error {
+	if identity.APIKey == nil && !identity.TwoFactorVerified {
+		return domain.ErrTwoFactorRequired
+	}
+	return nil
+}
+
+// newTestRouter builds the router with handlers without services, only requests rejected by
+// middleware or routing may be sent to it
+func newTestRouter(t *testing.T, authHandler *AuthHandler) *Router {
+	t.Helper()
+	if authHandler == nil {
+		authHandler = NewAuthHandler(&stubAuth{}, &stubAPIKeys{})
+	}
+	router, err := NewRouter(
+		&config.HTTP{}, &config.I18N{DefaultLocale: "en", Locales: []string{"en"}},
+		BookHandler{}, UserHandler{}, *authHandler, TwoFactorHandler{}, OIDCHandler{}, APIKeyHandler{},
+		OrderHandler{}, AddressHandler{}, PrivacyHandler{}, AuditHandler{}, PricingHandler{}, CategoryHandler{},
+		PromotionHandler{}, ShippingHandler{}, FulfillmentHandler{}, InvoiceHandler{}, EditionHandler{},
+		DownloadHandler{}, PublisherHandler{}, SeriesHandler{}, TranslationHandler{},
+	)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	return router
+}
+
+var pathParameter = regexp.MustCompile(`\{[^}]+\}`)
+
+// examplePath fills the parameters of a route pattern
+func examplePath(pattern string) string {
+	return pathParameter.ReplaceAllStringFunc(pattern, func(param string) string {
+		switch param {
+		case "{locale}":
+			return "en"
+		case "{provider}":
+			return "example"
+		}
+		return "1"
+	})
+}
+
+// errorCode returns the code of a domain error
+func errorCode(err error) string {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ""
+}
+
+func problemCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var p problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("response is not a problem: %s", rec.Body)
+	}
+	return p.Code
+}
+
+// TestAPIKeysDeniedByDefault sends an API key without scopes to every endpoint that needs
+// credentials. Endpoints with a scope have to ask for it, every other endpoint has to reject the key.
+func TestAPIKeysDeniedByDefault(t *testing.T) {
+	user := &domain.User{ID: 1, Role: domain.Admin}
+	router := newTestRouter(t, NewAuthHandler(&stubAuth{user: user}, &stubAPIKeys{user: user}))
+
+	for _, e := range endpoints {
+		if e.Access == public {
+			continue
+		}
+		t.Run(e.Method+" "+e.Path, func(t *testing.T) {
+			req := httptest.NewRequest(e.Method, examplePath(e.Path), strings.NewReader("{}"))
+			req.Header.Set("X-API-Key", "bs_test.secret")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			want := errorCode(domain.ErrAPIKeyNotAllowed)
+			if e.Scope != "" {
+				want = errorCode(domain.ErrMissingScope)
+			}
+			if rec.Code != http.StatusForbidden || problemCode(t, rec) != want {
+				t.Errorf("status = %d, body = %s, want 403 %s", rec.Code, rec.Body, want)
+			}
+		})
+	}
+}
+
+// TestSensitiveEndpointsRejectAPIKeys makes sure account, admin and key management endpoints
+// never get a scope an API key could be granted
+func TestSensitiveEndpointsRejectAPIKeys(t *testing.T) {
+	sensitive := regexp.MustCompile(`^/v1/(admin/|api-keys|auth/2fa/|users/(me/password|me/erasure|me/addresses|\{id\}))|^/v1/(promotions|shipping)/`)
+	for _, e := range endpoints {
+		if sensitive.MatchString(e.Path) && e.Access != public && e.Scope != "" {
+			t.Errorf("%s %s accepts API keys with the %s scope", e.Method, e.Path, e.Scope)
+		}
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	customer := &domain.User{ID: 1, Role: domain.Customer}
+
+	tests := []struct {
+		name   string
+		scopes []domain.APIKeyScope
+		method string
+		path   string
+		want   int
+	}{
+		{"orders read key cannot create orders", []domain.APIKeyScope{domain.ScopeOrdersRead}, http.MethodPost, "/v1/orders/", http.StatusForbidden},
+		{"orders read key cannot change the password", []domain.APIKeyScope{domain.ScopeOrdersRead}, http.MethodPost, "/v1/users/me/password", http.StatusForbidden},
+		{"orders read key cannot erase the account", []domain.APIKeyScope{domain.ScopeOrdersRead}, http.MethodPost, "/v1/users/me/erasure", http.StatusForbidden},
+		{"users read key cannot update the user", []domain.APIKeyScope{domain.ScopeUsersRead}, http.MethodPut, "/v1/users/1/update", http.StatusForbidden},
+		{"all scopes cannot create api keys", domain.APIKeyScopes, http.MethodPost, "/v1/api-keys/", http.StatusForbidden},
+		{"all scopes cannot read the audit log", domain.APIKeyScopes, http.MethodGet, "/v1/admin/audit", http.StatusForbidden},
+		{"books write key of a customer fails the role check", []domain.APIKeyScope{domain.ScopeBooksWrite}, http.MethodDelete, "/v1/books/1", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(t, NewAuthHandler(&stubAuth{user: customer}, &stubAPIKeys{user: customer, scopes: tt.scopes}))
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-API-Key", "bs_test.secret")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestBearerTokensReachUnscopedEndpoints(t *testing.T) {
+	admin := &domain.User{ID: 1, Role: domain.Admin}
+	router := newTestRouter(t, NewAuthHandler(&stubAuth{user: admin}, &stubAPIKeys{user: admin}))
+
+	// The token did not pass the second factor, the admin routes reject it after authenticating it
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/audit", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden || problemCode(t, rec) != errorCode(domain.ErrTwoFactorRequired) {
+		t.Errorf("status = %d, body = %s, want 403 %s", rec.Code, rec.Body, errorCode(domain.ErrTwoFactorRequired))
+	}
+}
+
+// TestUserChangesRequireTwoFactor makes sure an account cannot be changed with a token that did not
+// pass the second factor its role requires, an admin could otherwise take over other accounts
+func TestUserChangesRequireTwoFactor(t *testing.T) {
+	admin := &domain.User{ID: 1, Role: domain.Admin}
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPut, "/v1/users/2/update"},
+		{http.MethodPatch, "/v1/users/2"},
+		{http.MethodDelete, "/v1/users/2"},
+	}
+	for _, tt := range tests {
+		for _, verified := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s %s verified=%t", tt.method, tt.path, verified), func(t *testing.T) {
+				router := newTestRouter(t, NewAuthHandler(&stubAuth{user: admin, twoFactorVerified: verified}, &stubAPIKeys{user: admin}))
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader("{"))
+				req.Header.Set("Authorization", "Bearer token")
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				rejected := rec.Code == http.StatusForbidden && problemCode(t, rec) == errorCode(domain.ErrTwoFactorRequired)
+				if rejected == verified {
+					t.Errorf("status = %d, body = %s, want the second factor required = %t", rec.Code, rec.Body, !verified)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type SeriesHandler struct {
+	service port.SeriesService
+}
+
+func NewSeriesHandler(service port.SeriesService) *SeriesHandler {
+	return &SeriesHandler{
+		service: service,
+	}
+}
+
+type seriesRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=2000"`
+	PublisherId *int64 `json:"publisher_id" validate:"omitempty,gt=0"`
+}
+
+func (sh *SeriesHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	var payload seriesRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	series, err := sh.service.CreateSeries(r.Context(), &domain.Series{
+		Name:        payload.Name,
+		Description: payload.Description,
+		PublisherID: payload.PublisherId,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newSeriesResponse(series)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (sh *SeriesHandler) ListSeries(w http.ResponseWriter, r *http.Request) {
+	seriesList, err := sh.service.ListSeries(r.Context())
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	responses := []seriesResponse{}
+	for _, series := range seriesList {
+		responses = append(responses, newSeriesResponse(&series))
+	}
+	if err := jsonResponse(w, http.StatusOK, responses); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+// GetSeries returns a series with its volumes in reading order
+func (sh *SeriesHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	series, err := sh.service.GetSeries(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newSeriesResponse(series)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (sh *SeriesHandler) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload seriesRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	series, err := sh.service.UpdateSeries(r.Context(), &domain.Series{
+		ID:          id,
+		Name:        payload.Name,
+		Description: payload.Description,
+		PublisherID: payload.PublisherId,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newSeriesResponse(series)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (sh *SeriesHandler) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := sh.service.DeleteSeries(r.Context(), id); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type ShippingHandler struct {
+	service port.ShippingService
+}
+
+func NewShippingHandler(service port.ShippingService) *ShippingHandler {
+	return &ShippingHandler{
+		service: service,
+	}
+}
+
+type createShippingZoneRequest struct {
+	Name      string   `json:"name" validate:"required,max=100"`
+	Countries []string `json:"countries" validate:"required,min=1,dive,len=2|eq=*"`
+}
+
+type createShippingMethodRequest struct {
+	ZoneId         int64   `json:"zone_id" validate:"required,gt=0"`
+	Name           string  `json:"name" validate:"required,max=100"`
+	Type           string  `json:"rate_type" validate:"required,oneof=flat weight free_over pickup"`
+	Price          float64 `json:"price" validate:"gte=0"`
+	PerKg          float64 `json:"per_kg" validate:"gte=0"`
+	FreeOver       float64 `json:"free_over" validate:"gte=0"`
+	MaxWeightGrams int     `json:"max_weight_grams" validate:"gte=0"`
+}
+
+func (sh *ShippingHandler) CreateZone(w http.ResponseWriter, r *http.Request) {
+	var payload createShippingZoneRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	zone, err := sh.service.CreateZone(r.Context(), &domain.ShippingZone{
+		Name:      payload.Name,
+		Countries: payload.Countries,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newShippingZoneResponse(zone)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (sh *ShippingHandler) ListZones(w http.ResponseWriter, r *http.Request) {
+	zones, err := sh.service.ListZones(r.Context())
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	zonesList := []shippingZoneResponse{}
+	for _, zone := range zones {
+		zonesList = append(zonesList, newShippingZoneResponse(&zone))
+	}
+	if err := jsonResponse(w, http.StatusOK, zonesList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (sh *ShippingHandler) CreateMethod(w http.ResponseWriter, r *http.Request) {
+	var payload createShippingMethodRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	method, err := sh.service.CreateMethod(r.Context(), &domain.ShippingMethod{
+		ZoneID:         payload.ZoneId,
+		Name:           payload.Name,
+		Type:           domain.ShippingRateType(payload.Type),
+		Price:          payload.Price,
+		PerKg:          payload.PerKg,
+		FreeOver:       payload.FreeOver,
+		MaxWeightGrams: payload.MaxWeightGrams,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusCreated, newShippingMethodResponse(method)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (sh *ShippingHandler) ListMethods(w http.ResponseWriter, r *http.Request) {
+	methods, err := sh.service.ListMethods(r.Context())
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	methodsList := []shippingMethodResponse{}
+	for _, method := range methods {
+		methodsList = append(methodsList, newShippingMethodResponse(&method))
+	}
+	if err := jsonResponse(w, http.StatusOK, methodsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (sh *ShippingHandler) DisableMethod(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := sh.service.DisableMethod(r.Context(), id); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/go-chi/chi/v5"
+)
+
+type TranslationHandler struct {
+	service port.TranslationService
+}
+
+func NewTranslationHandler(service port.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		service: service,
+	}
+}
+
+type bookTranslationRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+type categoryTranslationRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// extractLocale returns the locale of a /{id}/translations/{locale} route, it must be a BCP 47 tag
+func extractLocale(r *http.Request) (string, error) {
+	locale := chi.URLParam(r, "locale")
+	if err := Validate.Var(locale, "bcp47_language_tag"); err != nil {
+		return "", errors.New("invalid locale, expected a BCP 47 language tag")
+	}
+	return locale, nil
+}
+
+func (th *TranslationHandler) ListBookTranslations(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	translations, err := th.service.ListBookTranslations(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	translationsList := []bookTranslationResponse{}
+	for _, translation := range translations {
+		translationsList = append(translationsList, newBookTranslationResponse(&translation))
+	}
+	if err := jsonResponse(w, http.StatusOK, translationsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (th *TranslationHandler) SetBookTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	locale, err := extractLocale(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload bookTranslationRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	translation, err := th.service.SetBookTranslation(r.Context(), &domain.BookTranslation{
+		BookID:      id,
+		Locale:      locale,
+		Name:        payload.Name,
+		Description: payload.Description,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newBookTranslationResponse(translation)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (th *TranslationHandler) DeleteBookTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	locale, err := extractLocale(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := th.service.DeleteBookTranslation(r.Context(), id, locale); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (th *TranslationHandler) ListCategoryTranslations(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	translations, err := th.service.ListCategoryTranslations(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	translationsList := []categoryTranslationResponse{}
+	for _, translation := range translations {
+		translationsList = append(translationsList, newCategoryTranslationResponse(&translation))
+	}
+	if err := jsonResponse(w, http.StatusOK, translationsList); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (th *TranslationHandler) SetCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	locale, err := extractLocale(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload categoryTranslationRequest
+	if !readValidated(w, r, &payload) {
+		return
+	}
+
+	translation, err := th.service.SetCategoryTranslation(r.Context(), &domain.CategoryTranslation{
+		CategoryID: id,
+		Locale:     locale,
+		Name:       payload.Name,
+	})
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newCategoryTranslationResponse(translation)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (th *TranslationHandler) DeleteCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	locale, err := extractLocale(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := th.service.DeleteCategoryTranslation(r.Context(), id, locale); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type TwoFactorHandler struct {
+	service port.TwoFactorService
+}
+
+func NewTwoFactorHandler(service port.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		service: service,
+	}
+}
+
+func (th *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	enrollment, err := th.service.Enroll(r.Context(), authUser(r))
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, newTwoFactorEnrollmentResponse(enrollment)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+func (th *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	var payload twoFactorCodeRequest
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	codes, err := th.service.Confirm(r.Context(), authUser(r), payload.Code)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, codes); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (th *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	var payload twoFactorCodeRequest
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	if err := th.service.Disable(r.Context(), authUser(r), payload.Code); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, "two-factor authentication has been disabled"); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
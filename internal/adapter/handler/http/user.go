@@ -1,6 +1,8 @@
 package http
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
@@ -31,12 +33,7 @@ func (us *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	}
 	err := Validate.Struct(payload)
 	if err != nil {
-		validationsMessages, err := validationErrors(err)
-		if err != nil {
-			internalServerError(w, r, err)
-			return
-		}
-		badRequestResponseWithTags(w, r, validationsMessages)
+		validationFailedResponse(w, r, err)
 		return
 	}
 	user := domain.User{
@@ -47,14 +44,8 @@ func (us *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 
 	_, err = us.service.Register(r.Context(), &user)
 	if err != nil {
-		switch err {
-		case domain.ErrConflictingData:
-			jsonResponse(w, http.StatusConflict, "email used before")
-			return
-		default:
-			internalServerError(w, r, err)
-			return
-		}
+		us.updateError(w, r, err)
+		return
 	}
 	if err = jsonResponse(w, http.StatusOK, newUserResponse(&user)); err != nil {
 		internalServerError(w, r, err)
@@ -63,9 +54,8 @@ func (us *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 }
 
 type updateRequestUser struct {
-	Email    string `json:"email" validate:"required,email"`
-	Name     string `json:"name" validate:"required,max=100"`
-	Password string `json:"password" validate:"required,max=100,min=3"`
+	Email string `json:"email" validate:"required,email"`
+	Name  string `json:"name" validate:"required,max=100"`
 }
 
 func (uh *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
@@ -75,42 +65,178 @@ func (uh *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := Validate.Struct(payload); err != nil {
-		messages, err := validationErrors(err)
-		if err != nil {
-			internalServerError(w, r, err)
-			return
-		}
-		badRequestResponseWithTags(w, r, messages)
+		validationFailedResponse(w, r, err)
 		return
 	}
 
 	id, err := extractID(r)
 	if err != nil {
-		internalServerError(w, r, err)
+		badRequestResponse(w, r, err)
+		return
+	}
+	if !canManageUser(r, id) {
+		forbiddenResponse(w, r)
+		return
+	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		errorResponse(w, r, err)
 		return
 	}
 	user := &domain.User{
-		ID:       id,
-		Name:     payload.Name,
-		Email:    payload.Email,
-		Password: payload.Password,
+		ID:      id,
+		Name:    payload.Name,
+		Email:   payload.Email,
+		Version: version,
+	}
+
+	update, err := uh.service.UpdateUser(r.Context(), user)
+	if err != nil {
+		uh.updateError(w, r, err)
+		return
+	}
+	setETag(w, update.User.Version)
+	if err := jsonResponse(w, http.StatusOK, newUserUpdateResponse(update)); err != nil {
+		internalServerError(w, r, err)
+		return
 	}
+}
+
+// patchRequestUser holds the fields of a JSON merge patch (RFC 7396) of a user.
+// Every field is required on the user so it can be replaced but not removed with null.
+type patchRequestUser struct {
+	Email *string `json:"email" validate:"omitempty,email"`
+	Name  *string `json:"name" validate:"omitempty,min=1,max=100"`
+}
 
-	_, err = uh.service.UpdateUser(r.Context(), user)
+func (uh *UserHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
 	if err != nil {
-		switch err {
-		case domain.ErrConflictingData:
-			err := jsonResponse(w, http.StatusConflict, "email used before")
-			if err != nil {
-				internalServerError(w, r, err)
-				return
-			}
+		badRequestResponse(w, r, err)
+		return
+	}
+	if !canManageUser(r, id) {
+		forbiddenResponse(w, r)
+		return
+	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	var raw map[string]json.RawMessage
+	if err := readJSON(w, r, &raw); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	var payload patchRequestUser
+	for field, value := range raw {
+		if string(value) == "null" {
+			badRequestResponse(w, r, fmt.Errorf("%s cannot be removed", field))
 			return
+		}
+		var target any
+		switch field {
+		case "name":
+			target = &payload.Name
+		case "email":
+			target = &payload.Email
 		default:
-			internalServerError(w, r, err)
+			badRequestResponse(w, r, fmt.Errorf("unknown field %q", field))
+			return
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			badRequestResponse(w, r, fmt.Errorf("invalid value for %s: %w", field, err))
 			return
 		}
 	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	update, err := uh.service.PatchUser(r.Context(), id, &domain.UserPatch{
+		Name:    payload.Name,
+		Email:   payload.Email,
+		Version: version,
+	})
+	if err != nil {
+		uh.updateError(w, r, err)
+		return
+	}
+	setETag(w, update.User.Version)
+	if err := jsonResponse(w, http.StatusOK, newUserUpdateResponse(update)); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+func (uh *UserHandler) updateError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == domain.ErrConflictingData {
+		err = domain.ErrEmailTaken
+	}
+	errorResponse(w, r, err)
+}
+
+// errWrongPassword is returned when the current password given to change it does not match, the
+// session stays valid so it is not reported as invalid credentials
+var errWrongPassword = domain.NewError(domain.KindInvalid, "wrong_password", "current password is incorrect")
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,max=100,min=3"`
+}
+
+func (uh *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var payload changePasswordRequest
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	identity := authIdentity(r)
+	token, err := uh.service.ChangePassword(r.Context(), identity.User.ID, identity.TwoFactorVerified, payload.CurrentPassword, payload.NewPassword)
+	if err != nil {
+		if err == domain.ErrInvalidCredentials {
+			err = errWrongPassword
+		}
+		errorResponse(w, r, err)
+		return
+	}
+
+	// Every other session was signed out, the caller continues with the new token
+	if err := jsonResponse(w, http.StatusOK, token); err != nil {
+		internalServerError(w, r, err)
+		return
+	}
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (uh *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var payload verifyEmailRequest
+	if err := readJSON(w, r, &payload); err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if err := Validate.Struct(payload); err != nil {
+		validationFailedResponse(w, r, err)
+		return
+	}
+
+	user, err := uh.service.VerifyEmailChange(r.Context(), payload.Token)
+	if err != nil {
+		uh.updateError(w, r, err)
+		return
+	}
+
 	if err := jsonResponse(w, http.StatusOK, newUserResponse(user)); err != nil {
 		internalServerError(w, r, err)
 		return
@@ -119,20 +245,12 @@ func (uh *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 func (uh *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 
-	users, err := uh.service.ListUsers(r.Context(), 0, 10)
+	skip, limit := extractPagination(r)
+	users, err := uh.service.ListUsers(r.Context(), skip, limit, includeDeleted(r))
 
 	if err != nil {
-		switch err {
-		case domain.ErrDataNotFound:
-			if err := jsonResponse(w, http.StatusOK, err); err != nil {
-				internalServerError(w, r, err)
-				return
-			}
-			return
-		default:
-			internalServerError(w, r, err)
-			return
-		}
+		errorResponse(w, r, err)
+		return
 	}
 
 	var usersList []userResponse
@@ -149,22 +267,61 @@ func (uh *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 
 	id, err := extractID(r)
 	if err != nil {
-		internalServerError(w, r, err)
+		badRequestResponse(w, r, err)
 		return
 	}
 
 	user, err := uh.service.GetUser(r.Context(), id)
 	if err != nil {
-		switch err {
-		case domain.ErrDataNotFound:
-			badRequestResponse(w, r, err)
-			return
-		default:
-			internalServerError(w, r, err)
-			return
-		}
+		errorResponse(w, r, err)
+		return
+	}
+
+	setETag(w, user.Version)
+	if notModified(w, r, user.Version) {
+		return
+	}
+	if err := jsonResponse(w, http.StatusOK, newUserResponse(user)); err != nil {
+		internalServerError(w, r, err)
+		return
 	}
+}
 
+func (uh *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+	if !canManageUser(r, id) {
+		forbiddenResponse(w, r)
+		return
+	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+
+	if err := uh.service.DeleteUser(r.Context(), id, version); err != nil {
+		errorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (uh *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	id, err := extractID(r)
+	if err != nil {
+		badRequestResponse(w, r, err)
+		return
+	}
+
+	user, err := uh.service.RestoreUser(r.Context(), id)
+	if err != nil {
+		errorResponse(w, r, err)
+		return
+	}
 	if err := jsonResponse(w, http.StatusOK, newUserResponse(user)); err != nil {
 		internalServerError(w, r, err)
 		return
@@ -1,29 +1,142 @@
 package http
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
 
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/nl"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	fr_translations "github.com/go-playground/validator/v10/translations/fr"
+	it_translations "github.com/go-playground/validator/v10/translations/it"
+	nl_translations "github.com/go-playground/validator/v10/translations/nl"
+	pt_translations "github.com/go-playground/validator/v10/translations/pt"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
 )
 
 var Validate *validator.Validate
 
+// translators holds the validation messages of every language that has them, English is the
+// fallback for the others
+var translators *ut.UniversalTranslator
+
+// embeddedField names embedded structs in validation namespaces, their fields are promoted to the
+// parent in JSON and so are their paths
+const embeddedField = "-"
+
 func init() {
 	Validate = validator.New(validator.WithRequiredStructEnabled())
+	Validate.RegisterTagNameFunc(jsonFieldName)
+
+	languages := []struct {
+		locale   locales.Translator
+		register func(*validator.Validate, ut.Translator) error
+	}{
+		{en.New(), en_translations.RegisterDefaultTranslations},
+		{es.New(), es_translations.RegisterDefaultTranslations},
+		{fr.New(), fr_translations.RegisterDefaultTranslations},
+		{it.New(), it_translations.RegisterDefaultTranslations},
+		{nl.New(), nl_translations.RegisterDefaultTranslations},
+		{pt.New(), pt_translations.RegisterDefaultTranslations},
+		{pt_BR.New(), pt_BR_translations.RegisterDefaultTranslations},
+	}
+	translators = ut.New(languages[0].locale)
+	for _, language := range languages {
+		if err := translators.AddTranslator(language.locale, true); err != nil {
+			panic(err)
+		}
+		trans, _ := translators.GetTranslator(language.locale.Locale())
+		if err := language.register(Validate, trans); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// translator returns the validation translator for the locale chain of the request, pt-br is
+// looked up as pt_BR and then as pt
+func translator(r *http.Request) ut.Translator {
+	var names []string
+	for _, locale := range domain.LocaleFrom(r.Context()) {
+		language, region, found := strings.Cut(locale, "-")
+		if found {
+			names = append(names, language+"_"+strings.ToUpper(region))
+		}
+		names = append(names, language)
+	}
+	if trans, found := translators.FindTranslator(names...); found {
+		return trans
+	}
+	return translators.GetFallback()
+}
+
+// jsonFieldName names a field by its JSON key so validation errors match the request body
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch {
+	case name == "-":
+		return ""
+	case name != "":
+		return name
+	case field.Anonymous:
+		return embeddedField
+	}
+	return field.Name
 }
 
-func validationErrors(err error) ([]byte, error) {
-	validationErrors := make(map[string]string)
-	for _, err := range err.(validator.ValidationErrors) {
-		field := err.Field()
-		tag := err.Tag()
-		validationErrors[field] = fmt.Sprintf("Validation failed on '%s' tag", tag)
+// fieldError describes why a field of a request failed validation. Code is the failed validation
+// tag and Param its parameter, such as 100 for max=100.
+type fieldError struct {
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// fieldPath returns the JSON path of a field error such as items[0].quantity, dropping the name
+// of the validated struct and of embedded structs
+func fieldPath(fe validator.FieldError) string {
+	segments := strings.Split(fe.Namespace(), ".")[1:]
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment != embeddedField {
+			path = append(path, segment)
+		}
 	}
-	errorJSON, err := json.Marshal(validationErrors)
+	return strings.Join(path, ".")
+}
 
-	if err != nil {
+// fieldErrors maps the JSON path of every invalid field to its error, with the message in the
+// language of the request. Errors other than validation errors are returned as they are.
+func fieldErrors(r *http.Request, err error) (map[string]fieldError, error) {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
 		return nil, err
 	}
-	return errorJSON, nil
+	trans := translator(r)
+	fields := make(map[string]fieldError, len(errs))
+	for _, fe := range errs {
+		message := fe.Translate(trans)
+		// Tags without a translation translate to the raw error
+		if message == fe.Error() {
+			message = fmt.Sprintf("%s failed the %s validation", fe.Field(), fe.Tag())
+		}
+		fields[fieldPath(fe)] = fieldError{
+			Code:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: message,
+		}
+	}
+	return fields, nil
 }
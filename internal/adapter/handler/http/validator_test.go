@@ -0,0 +1,161 @@
+package http
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+func TestTranslator(t *testing.T) {
+	tests := []struct {
+		name  string
+		chain domain.LocaleChain
+		want  string
+	}{
+		{"not localized", nil, "en"},
+		{"default locale", domain.LocaleChain{"en"}, "en"},
+		{"language", domain.LocaleChain{"fr", "en"}, "fr"},
+		{"region", domain.LocaleChain{"pt-br", "pt", "en"}, "pt_BR"},
+		{"region without messages falls back to its language", domain.LocaleChain{"es-mx", "es", "en"}, "es"},
+		{"language without messages", domain.LocaleChain{"de", "en"}, "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.chain != nil {
+				r = r.WithContext(domain.WithLocale(r.Context(), tt.chain))
+			}
+			if got := translator(r).Locale(); got != tt.want {
+				t.Errorf("translator() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	type address struct {
+		City    string `json:"city" validate:"required"`
+		Country string `json:"country" validate:"required,len=2"`
+	}
+	type line struct {
+		Quantity int `json:"quantity" validate:"gt=0"`
+	}
+	type paging struct {
+		Limit int `json:"limit" validate:"max=100"`
+	}
+	type request struct {
+		paging
+		Name     string   `json:"name,omitempty" validate:"max=5"`
+		Version  string   `json:"version" validate:"omitempty,semver"`
+		Address  address  `json:"address"`
+		Lines    []line   `json:"lines" validate:"dive"`
+		Internal string   `json:"-" validate:"required"`
+		Tags     []string `validate:"max=1"`
+	}
+	valid := request{
+		Address:  address{City: "Lisbon", Country: "PT"},
+		Lines:    []line{{Quantity: 1}},
+		Internal: "set",
+	}
+
+	tests := []struct {
+		name   string
+		locale domain.LocaleChain
+		modify func(*request)
+		want   map[string]fieldError
+	}{
+		{
+			name:   "valid",
+			modify: func(*request) {},
+			want:   map[string]fieldError{},
+		},
+		{
+			name:   "message carries the param",
+			modify: func(r *request) { r.Name = "Too long" },
+			want:   map[string]fieldError{"name": {Code: "max", Param: "5", Message: "name must be a maximum of 5 characters in length"}},
+		},
+		{
+			name:   "nested field",
+			modify: func(r *request) { r.Address.Country = "PRT" },
+			want:   map[string]fieldError{"address.country": {Code: "len", Param: "2", Message: "country must be 2 characters in length"}},
+		},
+		{
+			name:   "slice element",
+			modify: func(r *request) { r.Lines = append(r.Lines, line{}) },
+			want:   map[string]fieldError{"lines[1].quantity": {Code: "gt", Param: "0", Message: "quantity must be greater than 0"}},
+		},
+		{
+			name:   "embedded field is promoted",
+			modify: func(r *request) { r.Limit = 101 },
+			want:   map[string]fieldError{"limit": {Code: "max", Param: "100", Message: "limit must be 100 or less"}},
+		},
+		{
+			name:   "field without a JSON name keeps its Go name",
+			modify: func(r *request) { r.Tags = []string{"a", "b"} },
+			want:   map[string]fieldError{"Tags": {Code: "max", Param: "1", Message: "Tags must contain at maximum 1 item"}},
+		},
+		{
+			name:   "tag without a message",
+			modify: func(r *request) { r.Version = "one" },
+			want:   map[string]fieldError{"version": {Code: "semver", Message: "version failed the semver validation"}},
+		},
+		{
+			name:   "several fields",
+			modify: func(r *request) { r.Address = address{} },
+			want: map[string]fieldError{
+				"address.city":    {Code: "required", Message: "city is a required field"},
+				"address.country": {Code: "required", Message: "country is a required field"},
+			},
+		},
+		{
+			name:   "localized message",
+			locale: domain.LocaleChain{"pt-br", "pt", "en"},
+			modify: func(r *request) { r.Address.City = "" },
+			want:   map[string]fieldError{"address.city": {Code: "required", Message: "city é um campo obrigatório"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := valid
+			payload.Lines = slices.Clone(valid.Lines)
+			tt.modify(&payload)
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.locale != nil {
+				r = r.WithContext(domain.WithLocale(r.Context(), tt.locale))
+			}
+
+			err := Validate.Struct(payload)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate.Struct() error = %v", err)
+				}
+				return
+			}
+			got, err := fieldErrors(r, err)
+			if err != nil {
+				t.Fatalf("fieldErrors() error = %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("fieldErrors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldErrorsOfOtherErrors(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	failure := errors.New("not a validation error")
+
+	fields, err := fieldErrors(r, failure)
+	if err != failure || fields != nil {
+		t.Errorf("fieldErrors() = %v, %v, want nil, %v", fields, err, failure)
+	}
+	if _, err := fieldErrors(r, Validate.Struct(42)); err == nil {
+		t.Error("fieldErrors() of an invalid validation error error = nil")
+	}
+}
@@ -0,0 +1,133 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Page size and margins in points, A4 portrait
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 50.0
+)
+
+// pdfWriter lays out text on pages and writes them as a PDF using the standard Helvetica fonts,
+// which every reader has, so no font has to be embedded
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+func newPDFWriter() *pdfWriter {
+	pw := &pdfWriter{}
+	pw.newPage()
+	return pw
+}
+
+func (pw *pdfWriter) newPage() {
+	pw.page = &bytes.Buffer{}
+	pw.pages = append(pw.pages, pw.page)
+}
+
+// text writes s with its baseline starting at x, y measured from the top of the page
+func (pw *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(pw.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, pageHeight-y, escapePDF(s))
+}
+
+// textRight writes s so that it ends at x
+func (pw *pdfWriter) textRight(x, y, size float64, bold bool, s string) {
+	pw.text(x-textWidth(s, size), y, size, bold, s)
+}
+
+// rule draws a thin horizontal line across the page at y
+func (pw *pdfWriter) rule(y float64) {
+	fmt.Fprintf(pw.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, pageHeight-y, pageWidth-margin, pageHeight-y)
+}
+
+// bytes assembles the document, the objects are the catalog, the page tree, the two fonts and a
+// page with its content stream for every page
+func (pw *pdfWriter) bytes() []byte {
+	var objects []string
+	pageIDs := make([]string, len(pw.pages))
+	for i := range pw.pages {
+		pageIDs[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageIDs, " "), len(pw.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range pw.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// escapePDF escapes a string for a PDF literal. Characters outside Latin-1 have no glyph in the
+// standard fonts and are replaced.
+func escapePDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// textWidth estimates the width of s in Helvetica, exact for digits and punctuation which is what
+// gets right aligned
+func textWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			units += 556
+		case r == '.' || r == ',' || r == ' ':
+			units += 278
+		case r == '%':
+			units += 889
+		case r == '-':
+			units += 333
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		default:
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
@@ -0,0 +1,140 @@
+package invoice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// Columns of the line item table, amounts are right aligned at their x
+const (
+	colDescription = margin
+	colQuantity    = 330.0
+	colUnitPrice   = 400.0
+	colTax         = 470.0
+	colAmount      = pageWidth - margin
+)
+
+// PDFRenderer renders invoices as single or multi page A4 PDFs
+type PDFRenderer struct{}
+
+func NewPDFRenderer() *PDFRenderer {
+	return &PDFRenderer{}
+}
+
+func money(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+// addressLines returns the printed lines of an address, nil addresses print nothing
+func addressLines(address *domain.AddressSnapshot) []string {
+	if address == nil {
+		return nil
+	}
+	lines := []string{address.FullName, address.Line1}
+	if address.Line2 != "" {
+		lines = append(lines, address.Line2)
+	}
+	city := strings.TrimSpace(strings.Join([]string{address.PostalCode, address.City}, " "))
+	if address.Region != "" {
+		city += ", " + address.Region
+	}
+	return append(lines, city, address.Country)
+}
+
+func (pr *PDFRenderer) Render(document *domain.InvoiceDocument) ([]byte, error) {
+	pw := newPDFWriter()
+	invoice, order := document.Invoice, document.Order
+
+	pw.text(margin, 70, 22, true, "INVOICE")
+	pw.textRight(colAmount, 60, 10, false, "Invoice "+invoice.Number)
+	pw.textRight(colAmount, 74, 10, false, "Date "+invoice.IssuedAt.Format("2006-01-02"))
+	pw.textRight(colAmount, 88, 10, false, fmt.Sprintf("Order %d of %s", order.ID, order.CreatedAt.Format("2006-01-02")))
+
+	y := 130.0
+	blocks := []struct {
+		x     float64
+		title string
+		lines []string
+	}{
+		{margin, "From", append([]string{document.Seller.Name}, document.Seller.Address...)},
+		{220, "Bill to", addressLines(order.BillingAddress)},
+		{390, "Ship to", addressLines(order.ShippingAddress)},
+	}
+	bottom := y
+	for _, block := range blocks {
+		if len(block.lines) == 0 {
+			continue
+		}
+		pw.text(block.x, y, 10, true, block.title)
+		lineY := y
+		for _, line := range block.lines {
+			lineY += 13
+			pw.text(block.x, lineY, 9, false, line)
+		}
+		bottom = max(bottom, lineY)
+	}
+
+	y = bottom + 40
+	header := func() {
+		pw.text(colDescription, y, 9, true, "Description")
+		pw.textRight(colQuantity, y, 9, true, "Qty")
+		pw.textRight(colUnitPrice, y, 9, true, "Unit price")
+		pw.textRight(colTax, y, 9, true, "Tax")
+		pw.textRight(colAmount, y, 9, true, "Amount")
+		pw.rule(y + 5)
+		y += 20
+	}
+	header()
+	for _, line := range document.Invoice.Lines {
+		if y > pageHeight-margin-120 {
+			pw.newPage()
+			y = margin + 20
+			header()
+		}
+		pw.text(colDescription, y, 9, false, truncate(line.Description, 50))
+		pw.textRight(colQuantity, y, 9, false, fmt.Sprint(line.Quantity))
+		pw.textRight(colUnitPrice, y, 9, false, money(line.UnitPrice))
+		if line.TaxName != "" {
+			pw.textRight(colTax, y, 9, false, fmt.Sprintf("%s %.1f%%", line.TaxName, line.TaxRate*100))
+		}
+		pw.textRight(colAmount, y, 9, false, money(line.Amount))
+		y += 15
+	}
+	pw.rule(y - 5)
+
+	y += 10
+	totals := [][2]string{{"Subtotal", money(order.Subtotal)}}
+	if order.DiscountTotal > 0 {
+		totals = append(totals, [2]string{"Discount", "-" + money(order.DiscountTotal)})
+	}
+	if order.ShippingMethodID != nil {
+		totals = append(totals, [2]string{"Shipping (" + order.ShippingMethod + ")", money(order.ShippingCost)})
+	}
+	taxLabel := "Tax"
+	if order.TaxMode == domain.TaxInclusive {
+		taxLabel = "Included tax"
+	}
+	totals = append(totals, [2]string{taxLabel, money(order.TaxTotal)})
+	for _, total := range totals {
+		pw.text(colUnitPrice, y, 10, false, total[0])
+		pw.textRight(colAmount, y, 10, false, total[1])
+		y += 15
+	}
+	pw.text(colUnitPrice, y+3, 11, true, "Total")
+	pw.textRight(colAmount, y+3, 11, true, money(order.Total))
+
+	if document.Seller.TaxID != "" {
+		pw.text(margin, pageHeight-margin, 8, false, fmt.Sprintf("%s, tax id %s", document.Seller.Name, document.Seller.TaxID))
+	}
+	return pw.bytes(), nil
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,21 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// LogMailer writes emails to the log instead of sending them, useful for local development
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs an email message
+func (lm *LogMailer) Send(ctx context.Context, mail *domain.Mail) error {
+	slog.Info("sending email", "to", mail.To, "subject", mail.Subject, "body", mail.Body)
+	return nil
+}
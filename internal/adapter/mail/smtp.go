@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// SMTPMailer sends emails through an SMTP server
+type SMTPMailer struct {
+	config *config.Mail
+}
+
+func NewSMTPMailer(config *config.Mail) *SMTPMailer {
+	return &SMTPMailer{
+		config: config,
+	}
+}
+
+// sendTimeout bounds a send when the context has no deadline
+const sendTimeout = 30 * time.Second
+
+// Send sends an email message, the connection is closed when the context is done
+func (sm *SMTPMailer) Send(ctx context.Context, mail *domain.Mail) error {
+	addr := fmt.Sprintf("%s:%s", sm.config.Host, sm.config.Port)
+
+	var auth smtp.Auth
+	if sm.config.Username != "" {
+		auth = smtp.PlainAuth("", sm.config.Username, sm.config.Password, sm.config.Host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", sm.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mail.Subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
+	msg.WriteString(mail.Body)
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sendTimeout)
+		defer cancel()
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Unblock reads and writes as soon as the context is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	return sm.send(conn, auth, mail.To, msg.String())
+}
+
+// send runs the SMTP conversation of smtp.SendMail over an open connection
+func (sm *SMTPMailer) send(conn net.Conn, auth smtp.Auth, to, msg string) error {
+	client, err := smtp.NewClient(conn, sm.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: sm.config.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(sm.config.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,272 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeProvider is a local OpenID provider that issues ID tokens signed with its own key
+type fakeProvider struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+	kid string
+	// tokenKid overrides the key id in the header of issued tokens
+	tokenKid string
+
+	// claims returns the claims of the ID token issued for a token request
+	claims func(form url.Values) jwt.MapClaims
+	// lastForm is the last token request
+	lastForm url.Values
+	// jwksRequests counts fetches of the key set
+	jwksRequests int
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := &fakeProvider{key: key, kid: "key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 fp.URL,
+			"authorization_endpoint": fp.URL + "/authorize",
+			"token_endpoint":         fp.URL + "/token",
+			"jwks_uri":               fp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fp.jwksRequests++
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": fp.kid,
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(fp.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(fp.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		fp.lastForm = r.PostForm
+		if r.PostForm.Get("code") != "valid-code" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, fp.claims(r.PostForm))
+		token.Header["kid"] = fp.kid
+		if fp.tokenKid != "" {
+			token.Header["kid"] = fp.tokenKid
+		}
+		signed, err := token.SignedString(fp.key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+	})
+	fp.Server = httptest.NewServer(mux)
+	t.Cleanup(fp.Close)
+	return fp
+}
+
+// validClaims returns the claims of a valid ID token for the nonce
+func (fp *fakeProvider) validClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            fp.URL,
+		"aud":            "client-id",
+		"sub":            "subject-1",
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "reader@example.com",
+		"email_verified": true,
+		"name":           "Reader",
+	}
+}
+
+func (fp *fakeProvider) provider() *Provider {
+	return NewProvider(&config.OIDCProvider{
+		Name:         "fake",
+		IssuerURL:    fp.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost/v1/auth/oidc/fake/callback",
+	})
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	fp := newFakeProvider(t)
+
+	authURL, err := fp.provider().AuthCodeURL(context.Background(), "the-state", "the-nonce", "the-challenge")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Scheme + "://" + u.Host + u.Path; got != fp.URL+"/authorize" {
+		t.Errorf("endpoint = %s, want the discovered authorization endpoint", got)
+	}
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             "client-id",
+		"redirect_uri":          "http://localhost/v1/auth/oidc/fake/callback",
+		"scope":                 "openid email profile",
+		"state":                 "the-state",
+		"nonce":                 "the-nonce",
+		"code_challenge":        "the-challenge",
+		"code_challenge_method": "S256",
+	}
+	for key, value := range want {
+		if got := u.Query().Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	fp := newFakeProvider(t)
+	p := NewProvider(&config.OIDCProvider{Name: "fake", IssuerURL: fp.URL + "/other", ClientID: "client-id"})
+
+	if _, err := p.AuthCodeURL(context.Background(), "state", "nonce", "challenge"); err == nil {
+		t.Error("AuthCodeURL() accepted a provider with another issuer")
+	}
+}
+
+func TestExchange(t *testing.T) {
+	fp := newFakeProvider(t)
+	fp.claims = func(form url.Values) jwt.MapClaims { return fp.validClaims("the-nonce") }
+	p := fp.provider()
+
+	claims, err := p.Exchange(context.Background(), "valid-code", "the-verifier", "the-nonce")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if claims.Subject != "subject-1" || claims.Email != "reader@example.com" || !claims.EmailVerified || claims.Name != "Reader" {
+		t.Errorf("Exchange() claims = %+v", claims)
+	}
+
+	for key, value := range map[string]string{
+		"grant_type":    "authorization_code",
+		"code":          "valid-code",
+		"code_verifier": "the-verifier",
+		"client_id":     "client-id",
+		"client_secret": "client-secret",
+		"redirect_uri":  "http://localhost/v1/auth/oidc/fake/callback",
+	} {
+		if got := fp.lastForm.Get(key); got != value {
+			t.Errorf("token request %s = %q, want %q", key, got, value)
+		}
+	}
+
+	// The key set is cached between exchanges
+	if _, err := p.Exchange(context.Background(), "valid-code", "the-verifier", "the-nonce"); err != nil {
+		t.Fatalf("second Exchange() error = %v", err)
+	}
+	if fp.jwksRequests != 1 {
+		t.Errorf("key set fetched %d times, want 1", fp.jwksRequests)
+	}
+}
+
+func TestExchangeEmailVerifiedString(t *testing.T) {
+	fp := newFakeProvider(t)
+	fp.claims = func(form url.Values) jwt.MapClaims {
+		claims := fp.validClaims("the-nonce")
+		claims["email_verified"] = "true"
+		return claims
+	}
+
+	claims, err := fp.provider().Exchange(context.Background(), "valid-code", "the-verifier", "the-nonce")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if !claims.EmailVerified {
+		t.Error("email_verified sent as a string was not accepted")
+	}
+}
+
+func TestExchangeRejectsInvalidTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		code   string
+		mutate func(fp *fakeProvider, claims jwt.MapClaims)
+	}{
+		{"rejected code", "wrong-code", nil},
+		{"wrong nonce", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) { claims["nonce"] = "other-nonce" }},
+		{"missing nonce", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) { delete(claims, "nonce") }},
+		{"wrong audience", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) { claims["aud"] = "other-client" }},
+		{"wrong issuer", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) { claims["iss"] = "https://attacker.example" }},
+		{"expired", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) {
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		}},
+		{"no expiry", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) { delete(claims, "exp") }},
+		{"no subject", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) { delete(claims, "sub") }},
+		{"unknown key", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) { fp.tokenKid = "key-2" }},
+		{"signed with another key", "valid-code", func(fp *fakeProvider, claims jwt.MapClaims) { fp.key = otherKey }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := newFakeProvider(t)
+			p := fp.provider()
+			// The provider discovers its metadata and keys before the token is tampered with
+			if _, err := p.getDiscovery(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := p.getKey(context.Background(), p.discovery, fp.kid); err != nil {
+				t.Fatal(err)
+			}
+
+			fp.claims = func(form url.Values) jwt.MapClaims {
+				claims := fp.validClaims("the-nonce")
+				if tt.mutate != nil {
+					tt.mutate(fp, claims)
+				}
+				return claims
+			}
+			if _, err := p.Exchange(context.Background(), tt.code, "the-verifier", "the-nonce"); err == nil {
+				t.Error("Exchange() accepted the token")
+			}
+		})
+	}
+}
+
+func TestExchangeUnsignedToken(t *testing.T) {
+	fp := newFakeProvider(t)
+	p := fp.provider()
+	d, err := p.getDiscovery(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, fp.validClaims("the-nonce"))
+	unsigned, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.verifyIDToken(context.Background(), d, unsigned, "the-nonce"); err == nil || !strings.Contains(err.Error(), "signing method") {
+		t.Errorf("verifyIDToken() of an unsigned token = %v, want a signing method error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// BlobStorage keeps blobs as files below a root directory
+type BlobStorage struct {
+	root string
+}
+
+func NewBlobStorage(root string) (*BlobStorage, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+	return &BlobStorage{
+		root: root,
+	}, nil
+}
+
+// path maps a key to a file below the root, keys that would escape it are refused
+func (bs *BlobStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(bs.root, filepath.FromSlash(clean)), nil
+}
+
+// Put writes the blob to a temporary file first and renames it, readers never see half a blob
+func (bs *BlobStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	name, err := bs.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".blob-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (bs *BlobStorage) Open(ctx context.Context, key string) (io.ReadSeekCloser, *domain.BlobInfo, error) {
+	name, err := bs.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, domain.ErrDataNotFound
+		}
+		return nil, nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, &domain.BlobInfo{
+		Key:        key,
+		Size:       stat.Size(),
+		ModifiedAt: stat.ModTime(),
+	}, nil
+}
+
+func (bs *BlobStorage) Delete(ctx context.Context, key string) error {
+	name, err := bs.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+type loginAttempt struct {
+	failures    int64
+	expiresAt   time.Time
+	lockedUntil time.Time
+}
+
+// LoginAttemptRepository keeps failed login attempts in memory, it is meant for
+// single instance deployments and local development
+type LoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]*loginAttempt
+}
+
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		attempts: make(map[string]*loginAttempt),
+	}
+}
+
+// get returns the attempt of a key, dropping it when both the failures and the lock expired
+func (lr *LoginAttemptRepository) get(key string, now time.Time) *loginAttempt {
+	attempt, ok := lr.attempts[key]
+	if !ok {
+		return nil
+	}
+	if now.After(attempt.expiresAt) {
+		attempt.failures = 0
+	}
+	if attempt.failures == 0 && !now.Before(attempt.lockedUntil) {
+		delete(lr.attempts, key)
+		return nil
+	}
+	return attempt
+}
+
+// GetLoginAttempts returns the failed attempts recorded for a key
+func (lr *LoginAttemptRepository) GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	attempt := lr.get(key, time.Now())
+	if attempt == nil {
+		return &domain.LoginAttempts{}, nil
+	}
+	return &domain.LoginAttempts{
+		Failures:    attempt.failures,
+		LockedUntil: attempt.lockedUntil,
+	}, nil
+}
+
+// RecordLoginFailure increments the failures of a key
+func (lr *LoginAttemptRepository) RecordLoginFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempts, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	now := time.Now()
+	attempt := lr.get(key, now)
+	if attempt == nil {
+		attempt = &loginAttempt{}
+		lr.attempts[key] = attempt
+	}
+	if attempt.failures == 0 {
+		attempt.expiresAt = now.Add(window)
+	}
+	attempt.failures++
+
+	return &domain.LoginAttempts{
+		Failures:    attempt.failures,
+		LockedUntil: attempt.lockedUntil,
+	}, nil
+}
+
+// LockLogin locks a key until the given time and clears its failures
+func (lr *LoginAttemptRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	lr.attempts[key] = &loginAttempt{lockedUntil: until}
+	return nil
+}
+
+// ResetLoginAttempts clears the failures and lock of a key
+func (lr *LoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	delete(lr.attempts, key)
+	return nil
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+type oidcState struct {
+	request   domain.OIDCAuthRequest
+	expiresAt time.Time
+}
+
+// OIDCStateRepository keeps pending OpenID Connect logins in memory
+type OIDCStateRepository struct {
+	mu     sync.Mutex
+	states map[string]oidcState
+}
+
+func NewOIDCStateRepository() *OIDCStateRepository {
+	return &OIDCStateRepository{
+		states: make(map[string]oidcState),
+	}
+}
+
+// SaveAuthRequest stores an authorization request until it expires
+func (or *OIDCStateRepository) SaveAuthRequest(ctx context.Context, request *domain.OIDCAuthRequest, ttl time.Duration) error {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	now := time.Now()
+	for state, s := range or.states {
+		if now.After(s.expiresAt) {
+			delete(or.states, state)
+		}
+	}
+	or.states[request.State] = oidcState{
+		request:   *request,
+		expiresAt: now.Add(ttl),
+	}
+	return nil
+}
+
+// TakeAuthRequest returns and deletes the authorization request of a state
+func (or *OIDCStateRepository) TakeAuthRequest(ctx context.Context, state string) (*domain.OIDCAuthRequest, error) {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+
+	s, ok := or.states[state]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	delete(or.states, state)
+	if time.Now().After(s.expiresAt) {
+		return nil, domain.ErrDataNotFound
+	}
+	return &s.request, nil
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
@@ -40,12 +41,25 @@ func New(ctx context.Context, config *config.DB) (*DB, error) {
 	}, nil
 }
 
-// ErrorCode returns the error code of the given error
+// ErrorCode returns the error code of the given error, or an empty string if it is not a postgres error
 func (db *DB) ErrorCode(err error) string {
-	pgErr := err.(*pgconn.PgError)
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
 	return pgErr.Code
 }
 
+// ConstraintName returns the constraint violated by the given error, or an empty string if it is
+// not a postgres error
+func (db *DB) ConstraintName(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	return pgErr.ConstraintName
+}
+
 func (db *DB) Close() {
 	db.Pool.Close()
 }
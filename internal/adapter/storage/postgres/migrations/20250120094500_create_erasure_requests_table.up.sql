@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS erasure_requests (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    requested_by BIGINT NOT NULL REFERENCES users(id),
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX erasure_requests_pending ON erasure_requests (user_id) WHERE completed_at IS NULL AND cancelled_at IS NULL;
+CREATE INDEX erasure_requests_scheduled_for ON erasure_requests (scheduled_for) WHERE completed_at IS NULL AND cancelled_at IS NULL;
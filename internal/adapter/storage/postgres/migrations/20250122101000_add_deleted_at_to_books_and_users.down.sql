@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_deleted_at;
+DROP INDEX IF EXISTS books_deleted_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE books DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX books_deleted_at ON books (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
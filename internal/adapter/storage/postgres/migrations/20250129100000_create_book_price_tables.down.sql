@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "price_schedules";
+DROP TABLE IF EXISTS "book_price_history";
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS book_price_history (
+    id BIGSERIAL PRIMARY KEY,
+    book_id BIGINT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    price NUMERIC(10,2) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX book_price_history_book_id_changed_at ON book_price_history (book_id, changed_at);
+
+-- The current prices are the starting point of the history
+INSERT INTO book_price_history (book_id, price, source)
+SELECT id, price, 'manual' FROM books;
+
+CREATE TABLE IF NOT EXISTS price_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    book_id BIGINT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    price NUMERIC(10,2) NOT NULL,
+    previous_price NUMERIC(10,2),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX price_schedules_book_id ON price_schedules (book_id);
+CREATE INDEX price_schedules_due ON price_schedules (starts_at) WHERE status IN ('pending', 'active');
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS "automatic_promotions";
+DROP TABLE IF EXISTS "coupon_redemptions";
+DROP TABLE IF EXISTS "coupons";
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS promotions,
+    DROP COLUMN IF EXISTS total,
+    DROP COLUMN IF EXISTS discount_total,
+    DROP COLUMN IF EXISTS subtotal,
+    DROP COLUMN IF EXISTS coupon_code;
+
+DROP TABLE IF EXISTS "order_items";
+
+ALTER TABLE books DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS "categories";
@@ -0,0 +1,70 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id BIGSERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    book_id BIGINT NOT NULL REFERENCES books(id),
+    category_id BIGINT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price NUMERIC(10, 2) NOT NULL
+);
+
+CREATE INDEX order_items_order_id ON order_items (order_id);
+CREATE INDEX order_items_book_id ON order_items (book_id);
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS coupon_code VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS subtotal NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS discount_total NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS total NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS promotions JSONB NOT NULL DEFAULT '[]';
+
+-- Orders placed before orders had items were for a single book, its current price is the best
+-- price still known
+INSERT INTO order_items (order_id, book_id, quantity, unit_price)
+SELECT orders.id, orders.book_id, 1, books.price FROM orders JOIN books ON books.id = orders.book_id;
+
+UPDATE orders SET subtotal = books.price, total = books.price FROM books WHERE books.id = orders.book_id;
+
+CREATE TABLE IF NOT EXISTS coupons (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    discount_type VARCHAR(20) NOT NULL,
+    value NUMERIC(10, 2) NOT NULL,
+    min_basket NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    max_uses_per_user INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMPTZ,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    coupon_id BIGINT NOT NULL REFERENCES coupons(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    order_id INTEGER NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    amount NUMERIC(10, 2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX coupon_redemptions_coupon_id_user_id ON coupon_redemptions (coupon_id, user_id);
+
+CREATE TABLE IF NOT EXISTS automatic_promotions (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    category_id BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    buy_quantity INTEGER NOT NULL CHECK (buy_quantity > 0),
+    free_quantity INTEGER NOT NULL CHECK (free_quantity > 0),
+    starts_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMPTZ,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
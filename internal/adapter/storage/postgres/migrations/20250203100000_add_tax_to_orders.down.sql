@@ -0,0 +1,8 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS tax_lines,
+    DROP COLUMN IF EXISTS tax_total,
+    DROP COLUMN IF EXISTS tax_mode;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS product_class;
+
+ALTER TABLE books DROP COLUMN IF EXISTS product_class;
@@ -0,0 +1,8 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS product_class VARCHAR(20) NOT NULL DEFAULT 'printed';
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_class VARCHAR(20) NOT NULL DEFAULT 'printed';
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS tax_mode VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tax_total NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_lines JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,11 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS shipping_cost,
+    DROP COLUMN IF EXISTS shipping_method,
+    DROP COLUMN IF EXISTS shipping_method_id;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS weight_grams;
+
+ALTER TABLE books DROP COLUMN IF EXISTS weight_grams;
+
+DROP TABLE IF EXISTS "shipping_methods";
+DROP TABLE IF EXISTS "shipping_zones";
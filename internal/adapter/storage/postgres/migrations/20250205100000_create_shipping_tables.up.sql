@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS shipping_zones (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    countries VARCHAR(2)[] NOT NULL
+);
+
+CREATE INDEX shipping_zones_countries ON shipping_zones USING GIN (countries);
+
+CREATE TABLE IF NOT EXISTS shipping_methods (
+    id BIGSERIAL PRIMARY KEY,
+    zone_id BIGINT NOT NULL REFERENCES shipping_zones(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    rate_type VARCHAR(20) NOT NULL,
+    price NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    per_kg NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    free_over NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    max_weight_grams INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX shipping_methods_zone_id ON shipping_methods (zone_id);
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS weight_grams INTEGER NOT NULL DEFAULT 0 CHECK (weight_grams >= 0);
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS weight_grams INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS shipping_method_id BIGINT REFERENCES shipping_methods(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS shipping_method VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_cost NUMERIC(10, 2) NOT NULL DEFAULT 0;
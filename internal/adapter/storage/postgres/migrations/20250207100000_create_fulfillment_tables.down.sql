@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "tracking_events";
+DROP TABLE IF EXISTS "fulfillment_items";
+DROP TABLE IF EXISTS "fulfillments";
+
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
@@ -0,0 +1,35 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'placed';
+
+CREATE TABLE IF NOT EXISTS fulfillments (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    carrier VARCHAR(50) NOT NULL DEFAULT '',
+    tracking_number VARCHAR(100) NOT NULL DEFAULT '',
+    shipped_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX fulfillments_order_id ON fulfillments (order_id);
+CREATE INDEX fulfillments_in_transit ON fulfillments (carrier) WHERE status IN ('shipped', 'in_transit', 'exception');
+
+CREATE TABLE IF NOT EXISTS fulfillment_items (
+    fulfillment_id BIGINT NOT NULL REFERENCES fulfillments(id) ON DELETE CASCADE,
+    order_item_id BIGINT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (fulfillment_id, order_item_id)
+);
+
+CREATE INDEX fulfillment_items_order_item_id ON fulfillment_items (order_item_id);
+
+CREATE TABLE IF NOT EXISTS tracking_events (
+    id BIGSERIAL PRIMARY KEY,
+    fulfillment_id BIGINT NOT NULL REFERENCES fulfillments(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    location VARCHAR(100) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (fulfillment_id, status, occurred_at)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    year INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL UNIQUE REFERENCES orders(id),
+    year INTEGER NOT NULL,
+    sequence INTEGER NOT NULL,
+    number VARCHAR(30) NOT NULL UNIQUE,
+    blob_key VARCHAR(255) NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (year, sequence)
+);
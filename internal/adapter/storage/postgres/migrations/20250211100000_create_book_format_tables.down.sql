@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "download_grants";
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS format_type,
+    DROP COLUMN IF EXISTS format_id;
+
+DROP TABLE IF EXISTS "book_formats";
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS book_formats (
+    id BIGSERIAL PRIMARY KEY,
+    book_id BIGINT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    format_type VARCHAR(20) NOT NULL,
+    price NUMERIC(10, 2) NOT NULL CHECK (price > 0),
+    stock INTEGER CHECK (stock >= 0),
+    weight_grams INTEGER NOT NULL DEFAULT 0,
+    file_key VARCHAR(255) NOT NULL DEFAULT '',
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    file_size BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (book_id, format_type)
+);
+
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS format_id BIGINT REFERENCES book_formats(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS format_type VARCHAR(20) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS download_grants (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id BIGINT NOT NULL UNIQUE REFERENCES order_items(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format_id BIGINT NOT NULL REFERENCES book_formats(id) ON DELETE CASCADE,
+    max_downloads INTEGER NOT NULL,
+    downloads INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX download_grants_order_id ON download_grants (order_id);
@@ -0,0 +1,19 @@
+ALTER TABLE books DROP COLUMN IF EXISTS authors;
+
+ALTER TABLE download_grants RENAME COLUMN edition_id TO format_id;
+ALTER TABLE order_items RENAME COLUMN edition_id TO format_id;
+
+DROP INDEX IF EXISTS editions_language;
+
+ALTER TABLE editions
+    DROP CONSTRAINT IF EXISTS editions_book_id_format_type_language_edition_number_key,
+    DROP COLUMN IF EXISTS published_on,
+    DROP COLUMN IF EXISTS page_count,
+    DROP COLUMN IF EXISTS edition_number,
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS isbn;
+
+ALTER SEQUENCE editions_id_seq RENAME TO book_formats_id_seq;
+ALTER TABLE editions RENAME CONSTRAINT editions_pkey TO book_formats_pkey;
+ALTER TABLE editions RENAME TO book_formats;
+ALTER TABLE book_formats ADD CONSTRAINT book_formats_book_id_format_type_key UNIQUE (book_id, format_type);
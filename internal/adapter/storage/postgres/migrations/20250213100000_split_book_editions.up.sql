@@ -0,0 +1,20 @@
+ALTER TABLE book_formats RENAME TO editions;
+ALTER TABLE editions RENAME CONSTRAINT book_formats_pkey TO editions_pkey;
+ALTER SEQUENCE book_formats_id_seq RENAME TO editions_id_seq;
+
+ALTER TABLE editions
+    DROP CONSTRAINT IF EXISTS book_formats_book_id_format_type_key,
+    ADD COLUMN IF NOT EXISTS isbn VARCHAR(13) UNIQUE,
+    ADD COLUMN IF NOT EXISTS language VARCHAR(35) NOT NULL DEFAULT 'en',
+    ADD COLUMN IF NOT EXISTS edition_number INTEGER NOT NULL DEFAULT 1 CHECK (edition_number > 0),
+    ADD COLUMN IF NOT EXISTS page_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS published_on DATE,
+    ADD CONSTRAINT editions_book_id_format_type_language_edition_number_key UNIQUE (book_id, format_type, language, edition_number);
+
+CREATE INDEX IF NOT EXISTS editions_language ON editions (language);
+
+ALTER TABLE order_items RENAME COLUMN format_id TO edition_id;
+ALTER TABLE download_grants RENAME COLUMN format_id TO edition_id;
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS authors TEXT[] NOT NULL DEFAULT '{}';
+UPDATE books SET authors = ARRAY[author] WHERE author <> '';
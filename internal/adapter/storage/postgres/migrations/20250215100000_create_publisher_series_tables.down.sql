@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS books_publisher_id;
+
+ALTER TABLE books
+    DROP CONSTRAINT IF EXISTS books_series_id_series_volume_key,
+    DROP COLUMN IF EXISTS series_volume,
+    DROP COLUMN IF EXISTS series_id,
+    DROP COLUMN IF EXISTS publisher_id;
+
+DROP TABLE IF EXISTS "series";
+DROP TABLE IF EXISTS "publishers";
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS publishers (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    website VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS series (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    publisher_id BIGINT REFERENCES publishers(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS publisher_id BIGINT,
+    ADD COLUMN IF NOT EXISTS series_id BIGINT,
+    ADD COLUMN IF NOT EXISTS series_volume INTEGER CHECK (series_volume > 0),
+    ADD CONSTRAINT books_publisher_id_fkey FOREIGN KEY (publisher_id) REFERENCES publishers(id) ON DELETE SET NULL,
+    ADD CONSTRAINT books_series_id_fkey FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE SET NULL,
+    ADD CONSTRAINT books_series_id_series_volume_key UNIQUE (series_id, series_volume);
+
+CREATE INDEX IF NOT EXISTS books_publisher_id ON books (publisher_id);
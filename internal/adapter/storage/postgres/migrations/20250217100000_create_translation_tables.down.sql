@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "category_translations";
+DROP TABLE IF EXISTS "book_translations";
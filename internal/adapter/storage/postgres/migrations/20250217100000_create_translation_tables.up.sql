@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS book_translations (
+    book_id BIGINT NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (book_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS category_translations (
+    category_id BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (category_id, locale)
+);
@@ -0,0 +1,2 @@
+-- The redacted personal data cannot be restored
+SELECT 1;
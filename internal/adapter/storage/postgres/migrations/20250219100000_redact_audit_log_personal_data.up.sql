@@ -0,0 +1,50 @@
+-- Redacts the personal data already written to the audit log, new entries are redacted when
+-- they are recorded. Erasure cannot remove entries from the append-only log later.
+CREATE OR REPLACE FUNCTION audit_log_redact(value JSONB, personal BOOLEAN) RETURNS JSONB AS $$
+DECLARE
+    field TEXT;
+    nested JSONB;
+    result JSONB;
+BEGIN
+    IF value IS NULL OR jsonb_typeof(value) = 'null' THEN
+        RETURN value;
+    END IF;
+    IF personal THEN
+        RETURN '"[redacted]"'::JSONB;
+    END IF;
+    IF jsonb_typeof(value) = 'array' THEN
+        SELECT COALESCE(jsonb_agg(audit_log_redact(element, FALSE)), '[]'::JSONB) INTO result
+        FROM jsonb_array_elements(value) AS element;
+        RETURN result;
+    END IF;
+    IF jsonb_typeof(value) <> 'object' THEN
+        RETURN value;
+    END IF;
+    result := '{}'::JSONB;
+    FOR field, nested IN SELECT * FROM jsonb_each(value) LOOP
+        result := result || jsonb_build_object(field, audit_log_redact(nested,
+            lower(replace(field, '_', '')) IN ('email', 'fullname', 'line1', 'line2', 'postalcode', 'phone', 'subject')));
+    END LOOP;
+    RETURN result;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+ALTER TABLE audit_log DISABLE TRIGGER audit_log_append_only;
+
+-- The top level of changes maps field names to their before and after values
+UPDATE audit_log SET changes = (
+    SELECT COALESCE(jsonb_object_agg(field, jsonb_build_object(
+        'before', audit_log_redact(change->'before', personal),
+        'after', audit_log_redact(change->'after', personal)
+    )), '{}'::JSONB)
+    FROM (
+        SELECT field, change,
+            field IN ('email', 'full_name', 'line1', 'line2', 'postal_code', 'phone', 'subject')
+                OR (entity_type = 'user' AND field = 'name') AS personal
+        FROM jsonb_each(changes) AS c(field, change)
+    ) AS fields
+);
+
+ALTER TABLE audit_log ENABLE TRIGGER audit_log_append_only;
+
+DROP FUNCTION audit_log_redact(JSONB, BOOLEAN);
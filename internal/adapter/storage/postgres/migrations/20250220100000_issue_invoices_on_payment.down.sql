@@ -0,0 +1,2 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS lines;
+ALTER TABLE orders DROP COLUMN IF EXISTS paid_at;
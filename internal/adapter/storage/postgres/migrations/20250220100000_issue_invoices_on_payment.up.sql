@@ -0,0 +1,52 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS paid_at TIMESTAMPTZ;
+
+-- Orders that were invoiced before payments were recorded count as paid
+UPDATE orders SET paid_at = invoices.issued_at
+FROM invoices
+WHERE invoices.order_id = orders.id AND orders.paid_at IS NULL;
+
+-- Tax lines name the position of their item in the order instead of the book, the first item of
+-- the book is the best guess for existing lines
+UPDATE orders SET tax_lines = (
+    SELECT COALESCE(jsonb_agg(tax.line || jsonb_build_object('item', (
+        SELECT items.position
+        FROM (
+            SELECT book_id, row_number() OVER (ORDER BY id) - 1 AS position
+            FROM order_items
+            WHERE order_items.order_id = orders.id
+        ) AS items
+        WHERE items.book_id = (tax.line->>'book_id')::BIGINT
+        ORDER BY items.position
+        LIMIT 1
+    )) ORDER BY tax.ordinality), '[]'::JSONB)
+    FROM jsonb_array_elements(orders.tax_lines) WITH ORDINALITY AS tax(line, ordinality)
+)
+WHERE jsonb_array_length(tax_lines) > 0;
+
+-- The lines of an invoice are copied from the order when it is issued
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS lines JSONB NOT NULL DEFAULT '[]';
+
+UPDATE invoices SET lines = (
+    SELECT COALESCE(jsonb_agg(jsonb_strip_nulls(jsonb_build_object(
+        'description', COALESCE(books.name || ', ' || books.author, 'Book #' || items.book_id)
+            || COALESCE(' (' || NULLIF(items.format_type, '') || ')', ''),
+        'quantity', items.quantity,
+        'unit_price', items.unit_price,
+        'tax_name', tax.line->>'name',
+        'tax_rate', (tax.line->>'rate')::NUMERIC,
+        'tax_amount', (tax.line->>'amount')::NUMERIC,
+        'amount', round(items.unit_price * items.quantity, 2)
+    )) ORDER BY items.position), '[]'::JSONB)
+    FROM (
+        SELECT *, row_number() OVER (ORDER BY id) - 1 AS position
+        FROM order_items
+        WHERE order_items.order_id = invoices.order_id
+    ) AS items
+    JOIN orders ON orders.id = items.order_id
+    LEFT JOIN books ON books.id = items.book_id
+    LEFT JOIN LATERAL (
+        SELECT line FROM jsonb_array_elements(orders.tax_lines) AS line
+        WHERE (line->>'item')::INT = items.position
+        LIMIT 1
+    ) AS tax ON TRUE
+);
@@ -0,0 +1,40 @@
+-- Books take the price, class and weight of their first edition back, the default editions stay
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS price NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS product_class VARCHAR(20) NOT NULL DEFAULT 'printed',
+    ADD COLUMN IF NOT EXISTS weight_grams INTEGER NOT NULL DEFAULT 0 CHECK (weight_grams >= 0);
+
+UPDATE books SET
+    price = first.price,
+    product_class = CASE WHEN first.format_type IN ('ebook', 'audiobook') THEN 'ebook' ELSE 'printed' END,
+    weight_grams = first.weight_grams
+FROM (
+    SELECT DISTINCT ON (book_id) book_id, price, format_type, weight_grams
+    FROM editions
+    ORDER BY book_id, id
+) AS first
+WHERE first.book_id = books.id;
+
+ALTER TABLE books ALTER COLUMN price DROP DEFAULT;
+
+-- Only the schedules and the history of the first edition of every book are kept
+DROP INDEX IF EXISTS price_schedules_edition_id;
+DELETE FROM price_schedules
+WHERE edition_id <> (SELECT MIN(id) FROM editions WHERE editions.book_id = price_schedules.book_id);
+ALTER TABLE price_schedules DROP COLUMN IF EXISTS edition_id;
+
+DROP INDEX IF EXISTS edition_price_history_edition_id_changed_at;
+ALTER TABLE edition_price_history ADD COLUMN IF NOT EXISTS book_id BIGINT REFERENCES books(id) ON DELETE CASCADE;
+UPDATE edition_price_history SET book_id = editions.book_id
+FROM editions
+WHERE editions.id = edition_price_history.edition_id;
+DELETE FROM edition_price_history
+WHERE edition_id <> (SELECT MIN(id) FROM editions WHERE editions.book_id = edition_price_history.book_id);
+ALTER TABLE edition_price_history
+    DROP COLUMN IF EXISTS edition_id,
+    ALTER COLUMN book_id SET NOT NULL;
+
+ALTER SEQUENCE edition_price_history_id_seq RENAME TO book_price_history_id_seq;
+ALTER TABLE edition_price_history RENAME CONSTRAINT edition_price_history_pkey TO book_price_history_pkey;
+ALTER TABLE edition_price_history RENAME TO book_price_history;
+CREATE INDEX book_price_history_book_id_changed_at ON book_price_history (book_id, changed_at);
@@ -0,0 +1,74 @@
+-- Books are only sold through their editions. Every book without an edition gets a default one
+-- with the price, class and weight it was sold at. Stock was never counted per book, so printed
+-- default editions start with a stock of zero until staff count it.
+CREATE TEMPORARY TABLE default_editions (
+    book_id BIGINT PRIMARY KEY,
+    edition_id BIGINT NOT NULL
+);
+
+WITH inserted AS (
+    INSERT INTO editions (book_id, format_type, price, stock, weight_grams)
+    SELECT id,
+        CASE WHEN product_class = 'ebook' THEN 'ebook' ELSE 'paperback' END,
+        price,
+        CASE WHEN product_class = 'ebook' THEN NULL ELSE 0 END,
+        CASE WHEN product_class = 'ebook' THEN 0 ELSE weight_grams END
+    FROM books
+    WHERE NOT EXISTS (SELECT 1 FROM editions WHERE editions.book_id = books.id)
+    RETURNING id, book_id
+)
+INSERT INTO default_editions (book_id, edition_id)
+SELECT book_id, id FROM inserted;
+
+-- Items ordered at the price of their book were ordered from its default edition
+UPDATE order_items SET edition_id = default_editions.edition_id
+FROM default_editions
+WHERE order_items.book_id = default_editions.book_id AND order_items.edition_id IS NULL;
+
+-- The price history follows the price to the editions. Books that already had editions keep the
+-- history of their own price on their first edition, the edition the down migration takes the
+-- price back from, so the lowest recent price of the book is not lost.
+CREATE TEMPORARY TABLE first_editions AS
+SELECT book_id, MIN(id) AS edition_id FROM editions GROUP BY book_id;
+
+ALTER TABLE book_price_history RENAME TO edition_price_history;
+ALTER TABLE edition_price_history RENAME CONSTRAINT book_price_history_pkey TO edition_price_history_pkey;
+ALTER SEQUENCE book_price_history_id_seq RENAME TO edition_price_history_id_seq;
+
+ALTER TABLE edition_price_history ADD COLUMN IF NOT EXISTS edition_id BIGINT REFERENCES editions(id) ON DELETE CASCADE;
+
+UPDATE edition_price_history SET edition_id = first_editions.edition_id
+FROM first_editions
+WHERE edition_price_history.book_id = first_editions.book_id;
+
+ALTER TABLE edition_price_history
+    DROP COLUMN IF EXISTS book_id,
+    ALTER COLUMN edition_id SET NOT NULL;
+
+-- Default editions were sold at the price their history ends with, the other editions start
+-- theirs at their current price
+INSERT INTO edition_price_history (edition_id, price, source)
+SELECT id, price, 'manual' FROM editions
+WHERE NOT EXISTS (SELECT 1 FROM default_editions WHERE default_editions.edition_id = editions.id);
+
+CREATE INDEX edition_price_history_edition_id_changed_at ON edition_price_history (edition_id, changed_at);
+
+-- Scheduled prices change the price of an edition, schedules of books that already had editions
+-- change the price of their first edition. An active schedule only restores its previous price
+-- while the edition still has the scheduled one.
+ALTER TABLE price_schedules ADD COLUMN IF NOT EXISTS edition_id BIGINT REFERENCES editions(id) ON DELETE CASCADE;
+
+UPDATE price_schedules SET edition_id = first_editions.edition_id
+FROM first_editions
+WHERE price_schedules.book_id = first_editions.book_id;
+
+ALTER TABLE price_schedules ALTER COLUMN edition_id SET NOT NULL;
+
+CREATE INDEX price_schedules_edition_id ON price_schedules (edition_id);
+
+DROP TABLE default_editions, first_editions;
+
+ALTER TABLE books
+    DROP COLUMN IF EXISTS price,
+    DROP COLUMN IF EXISTS product_class,
+    DROP COLUMN IF EXISTS weight_grams;
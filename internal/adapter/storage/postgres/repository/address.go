@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const addressColumns = "id,user_id,label,full_name,line1,line2,city,region,postal_code,country,phone,is_default_shipping,is_default_billing,created_at,updated_at"
+
+type AddressRepository struct {
+	db *postgres.DB
+}
+
+func NewAddressRepository(db *postgres.DB) *AddressRepository {
+	return &AddressRepository{
+		db: db,
+	}
+}
+
+func scanAddress(row pgx.Row, address *domain.Address) error {
+	return row.Scan(
+		&address.ID,
+		&address.UserID,
+		&address.Label,
+		&address.FullName,
+		&address.Line1,
+		&address.Line2,
+		&address.City,
+		&address.Region,
+		&address.PostalCode,
+		&address.Country,
+		&address.Phone,
+		&address.IsDefaultShipping,
+		&address.IsDefaultBilling,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+}
+
+// clearDefaults unsets the defaults of the user's other addresses that the address takes over
+func (ar *AddressRepository) clearDefaults(ctx context.Context, tx pgx.Tx, address *domain.Address) error {
+	for column, isDefault := range map[string]bool{
+		"is_default_shipping": address.IsDefaultShipping,
+		"is_default_billing":  address.IsDefaultBilling,
+	} {
+		if !isDefault {
+			continue
+		}
+		sql, args, err := ar.db.QueryBuilder.Update("addresses").
+			Set(column, false).
+			Where(sq.Eq{"user_id": address.UserID, column: true}).
+			Where(sq.NotEq{"id": address.ID}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateAddress creates a new address in the database
+func (ar *AddressRepository) CreateAddress(ctx context.Context, address *domain.Address) (*domain.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := ar.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ar.clearDefaults(ctx, tx, address); err != nil {
+		return nil, err
+	}
+
+	sql, args, err := ar.db.QueryBuilder.Insert("addresses").
+		Columns("user_id", "label", "full_name", "line1", "line2", "city", "region", "postal_code", "country", "phone", "is_default_shipping", "is_default_billing").
+		Values(address.UserID, address.Label, address.FullName, address.Line1, address.Line2, address.City, address.Region, address.PostalCode, address.Country, address.Phone, address.IsDefaultShipping, address.IsDefaultBilling).
+		Suffix("RETURNING " + addressColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanAddress(tx.QueryRow(ctx, sql, args...), address); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// GetAddressById gets an address of a user by id from the database
+func (ar *AddressRepository) GetAddressById(ctx context.Context, userID, id int64) (*domain.Address, error) {
+	return ar.getAddress(ctx, sq.Eq{"user_id": userID, "id": id})
+}
+
+// GetDefaultAddress gets the default shipping or billing address of a user from the database
+func (ar *AddressRepository) GetDefaultAddress(ctx context.Context, userID int64, shipping bool) (*domain.Address, error) {
+	column := "is_default_billing"
+	if shipping {
+		column = "is_default_shipping"
+	}
+	return ar.getAddress(ctx, sq.Eq{"user_id": userID, column: true})
+}
+
+func (ar *AddressRepository) getAddress(ctx context.Context, where sq.Eq) (*domain.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := ar.db.QueryBuilder.Select(addressColumns).From("addresses").Where(where).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var address domain.Address
+	if err := scanAddress(ar.db.QueryRow(ctx, sql, args...), &address); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+// ListAddresses lists the addresses of a user from the database
+func (ar *AddressRepository) ListAddresses(ctx context.Context, userID int64) ([]domain.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := ar.db.QueryBuilder.Select(addressColumns).From("addresses").Where(sq.Eq{"user_id": userID}).OrderBy("id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := ar.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []domain.Address
+	for rows.Next() {
+		var address domain.Address
+		if err := scanAddress(rows, &address); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+// UpdateAddress updates an address of a user in the database
+func (ar *AddressRepository) UpdateAddress(ctx context.Context, address *domain.Address) (*domain.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := ar.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ar.clearDefaults(ctx, tx, address); err != nil {
+		return nil, err
+	}
+
+	sql, args, err := ar.db.QueryBuilder.Update("addresses").
+		Set("label", address.Label).
+		Set("full_name", address.FullName).
+		Set("line1", address.Line1).
+		Set("line2", address.Line2).
+		Set("city", address.City).
+		Set("region", address.Region).
+		Set("postal_code", address.PostalCode).
+		Set("country", address.Country).
+		Set("phone", address.Phone).
+		Set("is_default_shipping", address.IsDefaultShipping).
+		Set("is_default_billing", address.IsDefaultBilling).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": address.ID, "user_id": address.UserID}).
+		Suffix("RETURNING " + addressColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanAddress(tx.QueryRow(ctx, sql, args...), address); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// DeleteAddress deletes an address of a user from the database
+func (ar *AddressRepository) DeleteAddress(ctx context.Context, userID, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := ar.db.QueryBuilder.Delete("addresses").Where(sq.Eq{"id": id, "user_id": userID}).ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := ar.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const apiKeyColumns = "id,user_id,name,prefix,secret_hash,scopes,last_used_at,expires_at,revoked_at,created_at"
+
+type APIKeyRepository struct {
+	db *postgres.DB
+}
+
+func NewAPIKeyRepository(db *postgres.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+func scanAPIKey(row pgx.Row, key *domain.APIKey) error {
+	var scopes []string
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.SecretHash,
+		&scopes,
+		&key.LastUsedAt,
+		&key.ExpiresAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	key.Scopes = make([]domain.APIKeyScope, len(scopes))
+	for i, scope := range scopes {
+		key.Scopes[i] = domain.APIKeyScope(scope)
+	}
+	return nil
+}
+
+// CreateAPIKey creates a new api key in the database
+func (ar *APIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	query := ar.db.QueryBuilder.Insert("api_keys").
+		Columns("user_id", "name", "prefix", "secret_hash", "scopes", "expires_at").
+		Values(key.UserID, key.Name, key.Prefix, key.SecretHash, scopes, key.ExpiresAt).
+		Suffix("RETURNING " + apiKeyColumns)
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanAPIKey(ar.db.QueryRow(ctx, sql, args...), key); err != nil {
+		if errCode := ar.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetAPIKeyByPrefix gets an api key by its prefix from the database
+func (ar *APIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	return ar.getAPIKey(ctx, sq.Eq{"prefix": prefix})
+}
+
+// GetAPIKeyById gets an api key by id from the database
+func (ar *APIKeyRepository) GetAPIKeyById(ctx context.Context, id int64) (*domain.APIKey, error) {
+	return ar.getAPIKey(ctx, sq.Eq{"id": id})
+}
+
+func (ar *APIKeyRepository) getAPIKey(ctx context.Context, where sq.Eq) (*domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := ar.db.QueryBuilder.Select(apiKeyColumns).From("api_keys").Where(where)
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var key domain.APIKey
+	if err := scanAPIKey(ar.db.QueryRow(ctx, sql, args...), &key); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListAPIKeys lists the api keys of a user from the database
+func (ar *APIKeyRepository) ListAPIKeys(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := ar.db.QueryBuilder.Select(apiKeyColumns).From("api_keys").Where(sq.Eq{"user_id": userID}).OrderBy("id")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := ar.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		var key domain.APIKey
+		if err := scanAPIKey(rows, &key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// TouchAPIKey updates the last used time, at most once a minute to keep writes down on busy keys
+func (ar *APIKeyRepository) TouchAPIKey(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := ar.db.QueryBuilder.Update("api_keys").
+		Set("last_used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Or{
+			sq.Eq{"last_used_at": nil},
+			sq.Expr("last_used_at < NOW() - INTERVAL '1 minute'"),
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = ar.db.Exec(ctx, sql, args...)
+	return err
+}
+
+// RevokeAPIKey revokes an api key in the database
+func (ar *APIKeyRepository) RevokeAPIKey(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := ar.db.QueryBuilder.Update("api_keys").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "revoked_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = ar.db.Exec(ctx, sql, args...)
+	return err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const auditEntryColumns = "id,actor_id,action,entity_type,entity_id,changes,request_id,ip,created_at"
+
+type AuditRepository struct {
+	db *postgres.DB
+}
+
+func NewAuditRepository(db *postgres.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func scanAuditEntry(row pgx.Row, entry *domain.AuditEntry) error {
+	return row.Scan(
+		&entry.ID,
+		&entry.ActorID,
+		&entry.Action,
+		&entry.EntityType,
+		&entry.EntityID,
+		&entry.Changes,
+		&entry.RequestID,
+		&entry.IP,
+		&entry.CreatedAt,
+	)
+}
+
+// CreateAuditEntry appends an entry to the audit log
+func (ar *AuditRepository) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := ar.db.QueryBuilder.Insert("audit_log").
+		Columns("actor_id", "action", "entity_type", "entity_id", "changes", "request_id", "ip").
+		Values(entry.ActorID, entry.Action, entry.EntityType, entry.EntityID, entry.Changes, entry.RequestID, entry.IP).
+		Suffix("RETURNING " + auditEntryColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanAuditEntry(ar.db.QueryRow(ctx, sql, args...), entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ListAuditEntries lists the audit entries matching the filter, newest first
+func (ar *AuditRepository) ListAuditEntries(ctx context.Context, filter *domain.AuditFilter) ([]domain.AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := ar.db.QueryBuilder.Select(auditEntryColumns).
+		From("audit_log").
+		OrderBy("id DESC").
+		Offset(uint64(filter.Skip)).
+		Limit(uint64(filter.Limit))
+	if filter.ActorID != 0 {
+		query = query.Where(sq.Eq{"actor_id": filter.ActorID})
+	}
+	if filter.Action != "" {
+		query = query.Where(sq.Eq{"action": filter.Action})
+	}
+	if filter.EntityType != "" {
+		query = query.Where(sq.Eq{"entity_type": filter.EntityType})
+	}
+	if filter.EntityID != 0 {
+		query = query.Where(sq.Eq{"entity_id": filter.EntityID})
+	}
+	if !filter.From.IsZero() {
+		query = query.Where(sq.GtOrEq{"created_at": filter.From})
+	}
+	if !filter.To.IsZero() {
+		query = query.Where(sq.Lt{"created_at": filter.To})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := ar.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.AuditEntry
+	for rows.Next() {
+		var entry domain.AuditEntry
+		if err := scanAuditEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -12,6 +13,11 @@ import (
 
 var QueryTimeOutDuration = time.Second * 5
 
+const bookColumns = "id,name,author,authors,description,cover,category_id,publisher_id,series_id,series_volume,version,deleted_at"
+
+// notDeleted excludes soft-deleted rows
+var notDeleted = sq.Eq{"deleted_at": nil}
+
 type BookRepository struct {
 	db *postgres.DB
 }
@@ -22,65 +28,92 @@ func NewBookRepository(db *postgres.DB) *BookRepository {
 	}
 }
 
+func scanBook(row pgx.Row, book *domain.Book) error {
+	return row.Scan(
+		&book.ID,
+		&book.Name,
+		&book.Author,
+		&book.Authors,
+		&book.Description,
+		&book.Cover,
+		&book.CategoryID,
+		&book.PublisherID,
+		&book.SeriesID,
+		&book.SeriesVolume,
+		&book.Version,
+		&book.DeletedAt,
+	)
+}
+
 func (br *BookRepository) CreateBook(ctx context.Context, book *domain.Book) (*domain.Book, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
 	query := br.db.QueryBuilder.Insert("books").
-		Columns("name", "author", "price", "description", "cover").
-		Values(book.Name, book.Author, book.Price, book.Description, book.Cover).
-		Suffix("RETURNING id,name,author,price,description,cover")
+		Columns("name", "author", "authors", "description", "cover", "category_id", "publisher_id", "series_id", "series_volume").
+		Values(book.Name, book.Author, book.Authors, book.Description, book.Cover, book.CategoryID,
+			book.PublisherID, book.SeriesID, book.SeriesVolume).
+		Suffix("RETURNING " + bookColumns)
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
-	err = br.db.QueryRow(ctx, sql, args...).Scan(
-		&book.ID,
-		&book.Name,
-		&book.Author,
-		&book.Price,
-		&book.Description,
-		&book.Cover,
-	)
+
+	err = scanBook(br.db.QueryRow(ctx, sql, args...), book)
 	if err != nil {
+		switch br.db.ErrorCode(err) {
+		case "23505":
+			return nil, domain.ErrConflictingData
+		case "23503":
+			return nil, br.unknownReference(err)
+		}
 		return nil, err
 	}
 	return book, nil
 }
 
+// unknownReference maps a foreign key violation on a book to the missing entity
+func (br *BookRepository) unknownReference(err error) error {
+	switch br.db.ConstraintName(err) {
+	case "books_publisher_id_fkey":
+		return domain.ErrUnknownPublisher
+	case "books_series_id_fkey":
+		return domain.ErrUnknownSeries
+	default:
+		return domain.ErrUnknownCategory
+	}
+}
+
 func (br *BookRepository) GetBookById(ctx context.Context, id int64) (*domain.Book, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
-	var book domain.Book
+	book := &domain.Book{}
 
-	query := br.db.QueryBuilder.Select("id,name,author,price,description,cover").From("books").Where(sq.Eq{"id": id})
+	query := br.db.QueryBuilder.Select(bookColumns).From("books").Where(sq.Eq{"id": id}).Where(notDeleted)
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
-	err = br.db.QueryRow(ctx, sql, args...).Scan(
-		&book.ID,
-		&book.Name,
-		&book.Author,
-		&book.Price,
-		&book.Description,
-		&book.Cover,
-	)
+	err = scanBook(br.db.QueryRow(ctx, sql, args...), book)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, domain.ErrDataNotFound
 		}
 		return nil, err
 	}
-	return &book, nil
+	return book, nil
 }
 
-func (br *BookRepository) ListBooks(ctx context.Context, skip, limit int64) ([]domain.Book, error) {
+// ListBooks lists books, soft-deleted books are only included when includeDeleted is set
+func (br *BookRepository) ListBooks(ctx context.Context, skip, limit int64, includeDeleted bool) ([]domain.Book, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
-	query := br.db.QueryBuilder.Select(" id,name,author,price,description,cover").From("books").OrderBy("id").Limit(uint64(limit))
+	query := br.db.QueryBuilder.Select(bookColumns).From("books").OrderBy("id").Offset(uint64(skip)).Limit(uint64(limit))
+	if !includeDeleted {
+		query = query.Where(notDeleted)
+	}
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
@@ -90,17 +123,10 @@ func (br *BookRepository) ListBooks(ctx context.Context, skip, limit int64) ([]d
 		return nil, err
 	}
 	defer rows.Close()
-	var book domain.Book
 	var books []domain.Book
 	for rows.Next() {
-		err := rows.Scan(
-			&book.ID,
-			&book.Name,
-			&book.Author,
-			&book.Price,
-			&book.Description,
-			&book.Cover,
-		)
+		var book domain.Book
+		err := scanBook(rows, &book)
 		if err != nil {
 			return nil, err
 		}
@@ -110,6 +136,158 @@ func (br *BookRepository) ListBooks(ctx context.Context, skip, limit int64) ([]d
 	return books, nil
 }
 
+// SearchBooks lists the books matching the search with their matching editions. The text is
+// looked up in the name, authors and description of the books and in their translations.
+func (br *BookRepository) SearchBooks(ctx context.Context, search *domain.BookSearch) ([]domain.Book, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := br.db.QueryBuilder.Select(bookColumns).
+		From("books").
+		Where(notDeleted).
+		OrderBy("id").
+		Offset(uint64(search.Skip)).
+		Limit(uint64(search.Limit))
+	if search.Text != "" {
+		pattern := "%" + likeEscaper.Replace(search.Text) + "%"
+		query = query.Where(`(name ILIKE ? OR array_to_string(authors, ' ') ILIKE ? OR description ILIKE ?
+			OR EXISTS (SELECT 1 FROM book_translations t WHERE t.book_id = books.id AND (t.name ILIKE ? OR t.description ILIKE ?)))`,
+			pattern, pattern, pattern, pattern, pattern)
+	}
+	if search.CategoryID != 0 {
+		query = query.Where(sq.Eq{"category_id": search.CategoryID})
+	}
+	if search.PublisherID != 0 {
+		query = query.Where(sq.Eq{"publisher_id": search.PublisherID})
+	}
+	if search.SeriesID != 0 {
+		query = query.Where(sq.Eq{"series_id": search.SeriesID})
+	}
+	if filter := editionConditions(&search.EditionFilter); len(filter) > 0 {
+		// The subquery keeps ? placeholders, the outer query numbers them
+		sub, args, err := sq.Select("1").From("editions").
+			Where("editions.book_id = books.id").
+			Where(filter).
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(sq.Expr("EXISTS ("+sub+")", args...))
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := br.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []domain.Book
+	for rows.Next() {
+		var book domain.Book
+		if err := scanBook(rows, &book); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return books, br.loadEditions(ctx, books, &search.EditionFilter)
+}
+
+// ListSeriesVolumes lists the books of a series in reading order, books without a volume number
+// come last
+func (br *BookRepository) ListSeriesVolumes(ctx context.Context, seriesID int64) ([]domain.Book, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := br.db.QueryBuilder.Select(bookColumns).
+		From("books").
+		Where(sq.Eq{"series_id": seriesID}).
+		Where(notDeleted).
+		OrderBy("series_volume NULLS LAST", "id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := br.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []domain.Book
+	for rows.Next() {
+		var book domain.Book
+		if err := scanBook(rows, &book); err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	return books, rows.Err()
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// editionConditions are the conditions of an edition filter on the editions table
+func editionConditions(filter *domain.EditionFilter) sq.Eq {
+	conditions := sq.Eq{}
+	if filter.Format != "" {
+		conditions["editions.format_type"] = filter.Format
+	}
+	if filter.Language != "" {
+		conditions["editions.language"] = filter.Language
+	}
+	if filter.ISBN != "" {
+		conditions["editions.isbn"] = filter.ISBN
+	}
+	return conditions
+}
+
+// loadEditions sets the editions passing the filter on the books with one query
+func (br *BookRepository) loadEditions(ctx context.Context, books []domain.Book, filter *domain.EditionFilter) error {
+	if len(books) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(books))
+	byID := make(map[int64]*domain.Book, len(books))
+	for i := range books {
+		ids[i] = books[i].ID
+		byID[books[i].ID] = &books[i]
+	}
+
+	sql, args, err := br.db.QueryBuilder.Select(editionColumns).
+		From("editions").
+		Where(sq.Eq{"book_id": ids}).
+		OrderBy("book_id", "id").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := br.db.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var edition domain.Edition
+		if err := scanEdition(rows, &edition); err != nil {
+			return err
+		}
+		if book := byID[edition.BookID]; book != nil && filter.Matches(&edition) {
+			book.Editions = append(book.Editions, edition)
+		}
+	}
+	return rows.Err()
+}
+
+// UpdateBook updates a book, a non-zero version must match the stored one
 func (br *BookRepository) UpdateBook(ctx context.Context, book *domain.Book) (*domain.Book, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
@@ -117,45 +295,112 @@ func (br *BookRepository) UpdateBook(ctx context.Context, book *domain.Book) (*d
 	query := br.db.QueryBuilder.Update("books").
 		Set("name", book.Name).
 		Set("author", book.Author).
-		Set("price", book.Price).
+		Set("authors", book.Authors).
 		Set("description", book.Description).
 		Set("cover", book.Cover).
+		Set("category_id", book.CategoryID).
+		Set("publisher_id", book.PublisherID).
+		Set("series_id", book.SeriesID).
+		Set("series_volume", book.SeriesVolume).
+		Set("version", sq.Expr("version + 1")).
 		Where(sq.Eq{"id": book.ID}).
-		Suffix("RETURNING id,name,author,price,description,cover")
+		Where(notDeleted).
+		Suffix("RETURNING " + bookColumns)
+	// A non-zero version turns the update into a compare-and-swap against the stored version
+	if book.Version != 0 {
+		query = query.Where(sq.Eq{"version": book.Version})
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
-	err = br.db.QueryRow(ctx, sql, args...).Scan(
-		&book.ID,
-		&book.Name,
-		&book.Author,
-		&book.Price,
-		&book.Description,
-		&book.Cover,
-	)
+	version := book.Version
+	err = scanBook(br.db.QueryRow(ctx, sql, args...), book)
 	if err != nil {
-		if errCode := br.db.ErrorCode(err); errCode == "23505" {
+		if err == pgx.ErrNoRows {
+			return nil, missingOrConflict(ctx, br.db, "books", book.ID, version)
+		}
+		switch br.db.ErrorCode(err) {
+		case "23505":
 			return nil, domain.ErrConflictingData
+		case "23503":
+			return nil, br.unknownReference(err)
 		}
 		return nil, err
 	}
 	return book, nil
 }
 
-func (br *BookRepository) DeleteBook(ctx context.Context, id int64) error {
+// DeleteBook soft-deletes a book, it is hard-deleted by PurgeBooks once the retention window has passed.
+// A non-zero version must match the stored one.
+func (br *BookRepository) DeleteBook(ctx context.Context, id, version int64) error {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
-	query := br.db.QueryBuilder.Delete("books").Where(sq.Eq{"id": id})
+	query := br.db.QueryBuilder.Update("books").
+		Set("deleted_at", sq.Expr("NOW()")).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id}).
+		Where(notDeleted)
+	if version != 0 {
+		query = query.Where(sq.Eq{"version": version})
+	}
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return err
 	}
-	_, err = br.db.Exec(ctx, sql, args...)
+	tag, err := br.db.Exec(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return missingOrConflict(ctx, br.db, "books", id, version)
+	}
 	return nil
 }
+
+// RestoreBook undoes the soft delete of a book
+func (br *BookRepository) RestoreBook(ctx context.Context, id int64) (*domain.Book, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := br.db.QueryBuilder.Update("books").
+		Set("deleted_at", nil).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("RETURNING " + bookColumns)
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	book := &domain.Book{}
+	if err := scanBook(br.db.QueryRow(ctx, sql, args...), book); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return book, nil
+}
+
+// PurgeBooks hard-deletes the books soft-deleted before the given time that no order references
+func (br *BookRepository) PurgeBooks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := br.db.QueryBuilder.Delete("books").
+		Where(sq.Lt{"deleted_at": deletedBefore}).
+		Where("NOT EXISTS (SELECT 1 FROM orders WHERE orders.book_id = books.id)").
+		Where("NOT EXISTS (SELECT 1 FROM order_items WHERE order_items.book_id = books.id)")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	tag, err := br.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
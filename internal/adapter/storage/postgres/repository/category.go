@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+const categoryColumns = "id,name,created_at"
+
+type CategoryRepository struct {
+	db *postgres.DB
+}
+
+func NewCategoryRepository(db *postgres.DB) *CategoryRepository {
+	return &CategoryRepository{
+		db: db,
+	}
+}
+
+func (cr *CategoryRepository) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := cr.db.QueryBuilder.Insert("categories").
+		Columns("name").
+		Values(category.Name).
+		Suffix("RETURNING " + categoryColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := cr.db.QueryRow(ctx, sql, args...).Scan(&category.ID, &category.Name, &category.CreatedAt); err != nil {
+		if errCode := cr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+func (cr *CategoryRepository) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := cr.db.QueryBuilder.Select(categoryColumns).From("categories").OrderBy("name").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := cr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		var category domain.Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.CreatedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
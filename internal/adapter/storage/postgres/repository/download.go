@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const downloadGrantColumns = "id,order_id,order_item_id,user_id,edition_id,max_downloads,downloads,created_at"
+
+// createGrantsSQL grants the downloads of the digital items of a paid order, items that already
+// have a grant keep it
+const createGrantsSQL = `INSERT INTO download_grants (order_id, order_item_id, user_id, edition_id, max_downloads)
+SELECT o.id, oi.id, o.user_id, oi.edition_id, $2
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+JOIN editions e ON e.id = oi.edition_id
+WHERE oi.order_id = $1 AND o.paid_at IS NOT NULL AND e.format_type IN ('ebook', 'audiobook')
+ON CONFLICT (order_item_id) DO NOTHING`
+
+type DownloadRepository struct {
+	db *postgres.DB
+}
+
+func NewDownloadRepository(db *postgres.DB) *DownloadRepository {
+	return &DownloadRepository{
+		db: db,
+	}
+}
+
+func scanDownloadGrant(row pgx.Row, grant *domain.DownloadGrant) error {
+	return row.Scan(
+		&grant.ID,
+		&grant.OrderID,
+		&grant.OrderItemID,
+		&grant.UserID,
+		&grant.EditionID,
+		&grant.MaxDownloads,
+		&grant.Downloads,
+		&grant.CreatedAt,
+	)
+}
+
+func (dr *DownloadRepository) CreateOrderGrants(ctx context.Context, orderID int64, maxDownloads int) ([]domain.DownloadGrant, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	if _, err := dr.db.Exec(ctx, createGrantsSQL, orderID, maxDownloads); err != nil {
+		return nil, err
+	}
+
+	sql, args, err := dr.db.QueryBuilder.Select(downloadGrantColumns).From("download_grants").Where(sq.Eq{"order_id": orderID}).OrderBy("id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := dr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grants []domain.DownloadGrant
+	for rows.Next() {
+		var grant domain.DownloadGrant
+		if err := scanDownloadGrant(rows, &grant); err != nil {
+			return nil, err
+		}
+		grants = append(grants, grant)
+	}
+	return grants, rows.Err()
+}
+
+func (dr *DownloadRepository) GetGrant(ctx context.Context, id int64) (*domain.DownloadGrant, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := dr.db.QueryBuilder.Select(downloadGrantColumns).From("download_grants").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var grant domain.DownloadGrant
+	if err := scanDownloadGrant(dr.db.QueryRow(ctx, sql, args...), &grant); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// UseDownload counts a download in a single statement, so concurrent downloads cannot go over
+// the limit
+func (dr *DownloadRepository) UseDownload(ctx context.Context, id int64) (*domain.DownloadGrant, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := dr.db.QueryBuilder.Update("download_grants").
+		Set("downloads", sq.Expr("downloads + 1")).
+		Where(sq.And{sq.Eq{"id": id}, sq.Expr("downloads < max_downloads")}).
+		Suffix("RETURNING " + downloadGrantColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var grant domain.DownloadGrant
+	if err := scanDownloadGrant(dr.db.QueryRow(ctx, sql, args...), &grant); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDownloadLimit
+		}
+		return nil, err
+	}
+	return &grant, nil
+}
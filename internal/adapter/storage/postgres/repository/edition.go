@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// editionColumns reads a missing ISBN, which is stored as NULL to keep ISBNs unique, as empty
+const editionColumns = "id,book_id,COALESCE(isbn, ''),format_type,language,edition_number,page_count,published_on,price,stock,weight_grams,file_key,file_name,content_type,file_size,created_at,updated_at"
+
+type EditionRepository struct {
+	db *postgres.DB
+}
+
+func NewEditionRepository(db *postgres.DB) *EditionRepository {
+	return &EditionRepository{
+		db: db,
+	}
+}
+
+func scanEdition(row pgx.Row, edition *domain.Edition) error {
+	return row.Scan(
+		&edition.ID,
+		&edition.BookID,
+		&edition.ISBN,
+		&edition.Format,
+		&edition.Language,
+		&edition.EditionNumber,
+		&edition.PageCount,
+		&edition.PublishedOn,
+		&edition.Price,
+		&edition.Stock,
+		&edition.WeightGrams,
+		&edition.FileKey,
+		&edition.FileName,
+		&edition.ContentType,
+		&edition.FileSize,
+		&edition.CreatedAt,
+		&edition.UpdatedAt,
+	)
+}
+
+// CreateEdition creates an edition, its price is the first entry of its price history
+func (er *EditionRepository) CreateEdition(ctx context.Context, edition *domain.Edition) (*domain.Edition, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := er.db.QueryBuilder.Insert("editions").
+		Columns("book_id", "isbn", "format_type", "language", "edition_number", "page_count", "published_on", "price", "stock", "weight_grams").
+		Values(edition.BookID, sq.Expr("NULLIF(?, '')", edition.ISBN), edition.Format, edition.Language, edition.EditionNumber,
+			edition.PageCount, edition.PublishedOn, edition.Price, edition.Stock, edition.WeightGrams).
+		Suffix("RETURNING " + editionColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := er.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := scanEdition(tx.QueryRow(ctx, sql, args...), edition); err != nil {
+		switch er.db.ErrorCode(err) {
+		case "23505":
+			return nil, domain.ErrConflictingData
+		case "23503":
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	if err := recordPrice(ctx, tx, edition.ID, edition.Price, domain.PriceSourceManual); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return edition, nil
+}
+
+func (er *EditionRepository) GetEdition(ctx context.Context, id int64) (*domain.Edition, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := er.db.QueryBuilder.Select(editionColumns).From("editions").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var edition domain.Edition
+	if err := scanEdition(er.db.QueryRow(ctx, sql, args...), &edition); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &edition, nil
+}
+
+func (er *EditionRepository) ListBookEditions(ctx context.Context, bookID int64) ([]domain.Edition, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := er.db.QueryBuilder.Select(editionColumns).From("editions").Where(sq.Eq{"book_id": bookID}).OrderBy("id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := er.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var editions []domain.Edition
+	for rows.Next() {
+		var edition domain.Edition
+		if err := scanEdition(rows, &edition); err != nil {
+			return nil, err
+		}
+		editions = append(editions, edition)
+	}
+	return editions, rows.Err()
+}
+
+// UpdateEdition updates an edition and records a price change in its price history
+func (er *EditionRepository) UpdateEdition(ctx context.Context, edition *domain.Edition) (*domain.Edition, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := er.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	edition, err = er.updateEdition(ctx, tx, edition.ID, map[string]any{
+		"isbn":           sq.Expr("NULLIF(?, '')", edition.ISBN),
+		"language":       edition.Language,
+		"edition_number": edition.EditionNumber,
+		"page_count":     edition.PageCount,
+		"published_on":   edition.PublishedOn,
+		"price":          edition.Price,
+		"stock":          edition.Stock,
+		"weight_grams":   edition.WeightGrams,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The price history only grows when the price actually changed
+	if err := recordPrice(ctx, tx, edition.ID, edition.Price, domain.PriceSourceManual); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return edition, nil
+}
+
+func (er *EditionRepository) SetEditionFile(ctx context.Context, edition *domain.Edition) (*domain.Edition, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	return er.updateEdition(ctx, er.db, edition.ID, map[string]any{
+		"file_key":     edition.FileKey,
+		"file_name":    edition.FileName,
+		"content_type": edition.ContentType,
+		"file_size":    edition.FileSize,
+	})
+}
+
+func (er *EditionRepository) updateEdition(ctx context.Context, q querier, id int64, fields map[string]any) (*domain.Edition, error) {
+	sql, args, err := er.db.QueryBuilder.Update("editions").
+		SetMap(fields).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + editionColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var edition domain.Edition
+	if err := scanEdition(q.QueryRow(ctx, sql, args...), &edition); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if er.db.ErrorCode(err) == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return &edition, nil
+}
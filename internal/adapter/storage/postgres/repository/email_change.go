@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type EmailChangeRepository struct {
+	db *postgres.DB
+}
+
+func NewEmailChangeRepository(db *postgres.DB) *EmailChangeRepository {
+	return &EmailChangeRepository{
+		db: db,
+	}
+}
+
+// CreateEmailChange stores a new email change in the database
+func (er *EmailChangeRepository) CreateEmailChange(ctx context.Context, change *domain.EmailChange) (*domain.EmailChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := er.db.QueryBuilder.Insert("email_changes").
+		Columns("user_id", "new_email", "token_hash", "expires_at").
+		Values(change.UserID, change.NewEmail, change.TokenHash, change.ExpiresAt).
+		Suffix("RETURNING id,created_at")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	err = er.db.QueryRow(ctx, sql, args...).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// GetEmailChangeByTokenHash gets an email change by its token hash from the database
+func (er *EmailChangeRepository) GetEmailChangeByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := er.db.QueryBuilder.Select("id,user_id,new_email,token_hash,expires_at,used_at,created_at").
+		From("email_changes").
+		Where(sq.Eq{"token_hash": tokenHash})
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var change domain.EmailChange
+	err = er.db.QueryRow(ctx, sql, args...).Scan(
+		&change.ID,
+		&change.UserID,
+		&change.NewEmail,
+		&change.TokenHash,
+		&change.ExpiresAt,
+		&change.UsedAt,
+		&change.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ConfirmEmailChange consumes the change and updates the user's email
+func (er *EmailChangeRepository) ConfirmEmailChange(ctx context.Context, change *domain.EmailChange) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := er.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Consuming every pending change of the user also invalidates older verification links
+	sql, args, err := er.db.QueryBuilder.Update("email_changes").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": change.UserID, "used_at": nil}).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	claimed := false
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		claimed = claimed || id == change.ID
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, domain.ErrInvalidEmailToken
+	}
+
+	sql, args, err = er.db.QueryBuilder.Update("users").
+		Set("email", change.NewEmail).
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": change.UserID}).
+		Suffix("RETURNING id,name,email,role,token_version,version").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var user domain.User
+	err = tx.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.TokenVersion, &user.Version)
+	if err != nil {
+		if errCode := er.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const erasureRequestColumns = "id,user_id,requested_by,requested_at,scheduled_for,completed_at,cancelled_at"
+
+// personalDataTables hold nothing but personal data of a user and are emptied on erasure
+var personalDataTables = []string{
+	"addresses",
+	"api_keys",
+	"user_two_factors",
+	"user_recovery_codes",
+	"user_identities",
+	"password_resets",
+	"email_changes",
+}
+
+// erasedAddressFields are removed from the address snapshots of erased users' orders, the
+// city, region and country stay for accounting and tax reporting
+const erasedAddressFields = "ARRAY['full_name','line1','line2','postal_code','phone']"
+
+type ErasureRepository struct {
+	db *postgres.DB
+}
+
+func NewErasureRepository(db *postgres.DB) *ErasureRepository {
+	return &ErasureRepository{
+		db: db,
+	}
+}
+
+func scanErasureRequest(row pgx.Row, request *domain.ErasureRequest) error {
+	return row.Scan(
+		&request.ID,
+		&request.UserID,
+		&request.RequestedBy,
+		&request.RequestedAt,
+		&request.ScheduledFor,
+		&request.CompletedAt,
+		&request.CancelledAt,
+	)
+}
+
+// CreateErasureRequest creates a new erasure request in the database
+func (er *ErasureRepository) CreateErasureRequest(ctx context.Context, request *domain.ErasureRequest) (*domain.ErasureRequest, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := er.db.QueryBuilder.Insert("erasure_requests").
+		Columns("user_id", "requested_by", "requested_at", "scheduled_for").
+		Values(request.UserID, request.RequestedBy, request.RequestedAt, request.ScheduledFor).
+		Suffix("RETURNING " + erasureRequestColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanErasureRequest(er.db.QueryRow(ctx, sql, args...), request); err != nil {
+		if errCode := er.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrErasurePending
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetPendingErasureRequest gets the pending erasure request of a user from the database
+func (er *ErasureRepository) GetPendingErasureRequest(ctx context.Context, userID int64) (*domain.ErasureRequest, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := er.db.QueryBuilder.Select(erasureRequestColumns).
+		From("erasure_requests").
+		Where(sq.Eq{"user_id": userID, "completed_at": nil, "cancelled_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var request domain.ErasureRequest
+	if err := scanErasureRequest(er.db.QueryRow(ctx, sql, args...), &request); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// CancelErasureRequest marks a pending erasure request as cancelled in the database
+func (er *ErasureRepository) CancelErasureRequest(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := er.db.QueryBuilder.Update("erasure_requests").
+		Set("cancelled_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "completed_at": nil, "cancelled_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := er.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+// ListDueErasureRequests lists the pending erasure requests scheduled at or before the given time
+func (er *ErasureRepository) ListDueErasureRequests(ctx context.Context, before time.Time) ([]domain.ErasureRequest, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := er.db.QueryBuilder.Select(erasureRequestColumns).
+		From("erasure_requests").
+		Where(sq.Eq{"completed_at": nil, "cancelled_at": nil}).
+		Where(sq.LtOrEq{"scheduled_for": before}).
+		OrderBy("scheduled_for").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := er.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []domain.ErasureRequest
+	for rows.Next() {
+		var request domain.ErasureRequest
+		if err := scanErasureRequest(rows, &request); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
+
+// EraseUser anonymizes the user's profile, deletes their personal data and strips the address
+// snapshots of their orders, the orders themselves are kept for accounting
+func (er *ErasureRepository) EraseUser(ctx context.Context, request *domain.ErasureRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := er.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// The password is not a valid bcrypt hash so no password can ever match it
+	queries := []sq.Sqlizer{
+		er.db.QueryBuilder.Update("users").
+			Set("name", "Erased user").
+			Set("email", sq.Expr("'erased-' || id || '@erased.invalid'")).
+			Set("password", "!").
+			Set("role", domain.Customer).
+			Set("token_version", sq.Expr("token_version + 1")).
+			Set("version", sq.Expr("version + 1")).
+			Set("updated_at", sq.Expr("NOW()")).
+			Where(sq.Eq{"id": request.UserID}),
+		er.db.QueryBuilder.Update("orders").
+			Set("shipping_address", sq.Expr("shipping_address - "+erasedAddressFields)).
+			Set("billing_address", sq.Expr("billing_address - "+erasedAddressFields)).
+			Where(sq.Eq{"user_id": request.UserID}),
+		er.db.QueryBuilder.Update("erasure_requests").
+			Set("completed_at", sq.Expr("NOW()")).
+			Where(sq.Eq{"id": request.ID}),
+	}
+	for _, table := range personalDataTables {
+		queries = append(queries, er.db.QueryBuilder.Delete(table).Where(sq.Eq{"user_id": request.UserID}))
+	}
+
+	for _, query := range queries {
+		sql, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
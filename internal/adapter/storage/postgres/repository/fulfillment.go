@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	fulfillmentColumns   = "id,order_id,status,carrier,tracking_number,shipped_at,delivered_at,created_at,updated_at"
+	trackingEventColumns = "id,fulfillment_id,status,description,location,occurred_at"
+)
+
+// remainingItemsSQL selects how much of every printed item of an order is not in a fulfillment yet
+const remainingItemsSQL = `SELECT oi.id, oi.book_id, oi.quantity - COALESCE(SUM(fi.quantity) FILTER (WHERE f.status <> 'cancelled'), 0)
+FROM order_items oi
+LEFT JOIN fulfillment_items fi ON fi.order_item_id = oi.id
+LEFT JOIN fulfillments f ON f.id = fi.fulfillment_id
+WHERE oi.order_id = $1 AND oi.product_class = 'printed'
+GROUP BY oi.id
+ORDER BY oi.id`
+
+type FulfillmentRepository struct {
+	db *postgres.DB
+}
+
+func NewFulfillmentRepository(db *postgres.DB) *FulfillmentRepository {
+	return &FulfillmentRepository{
+		db: db,
+	}
+}
+
+func scanFulfillment(row pgx.Row, fulfillment *domain.Fulfillment) error {
+	return row.Scan(
+		&fulfillment.ID,
+		&fulfillment.OrderID,
+		&fulfillment.Status,
+		&fulfillment.Carrier,
+		&fulfillment.TrackingNumber,
+		&fulfillment.ShippedAt,
+		&fulfillment.DeliveredAt,
+		&fulfillment.CreatedAt,
+		&fulfillment.UpdatedAt,
+	)
+}
+
+// CreateFulfillment inserts a fulfillment with its items in one transaction. The order row is
+// locked while the quantities left to fulfill are checked so concurrent fulfillments cannot ship
+// an item twice.
+func (fr *FulfillmentRepository) CreateFulfillment(ctx context.Context, fulfillment *domain.Fulfillment) (*domain.Fulfillment, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := fr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var orderID int64
+	if err := tx.QueryRow(ctx, "SELECT id FROM orders WHERE id = $1 FOR UPDATE", fulfillment.OrderID).Scan(&orderID); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	items, err := fr.allocateItems(ctx, tx, fulfillment)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, args, err := fr.db.QueryBuilder.Insert("fulfillments").
+		Columns("order_id", "status").
+		Values(fulfillment.OrderID, domain.FulfillmentPending).
+		Suffix("RETURNING " + fulfillmentColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanFulfillment(tx.QueryRow(ctx, sql, args...), fulfillment); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		sql, args, err := fr.db.QueryBuilder.Insert("fulfillment_items").
+			Columns("fulfillment_id", "order_item_id", "quantity").
+			Values(fulfillment.ID, item.OrderItemID, item.Quantity).
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return nil, err
+		}
+	}
+	fulfillment.Items = items
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return fulfillment, nil
+}
+
+// allocateItems checks the items of a new fulfillment against what is left of the order, merging
+// repeated items. Without items everything that is left is taken.
+func (fr *FulfillmentRepository) allocateItems(ctx context.Context, tx pgx.Tx, fulfillment *domain.Fulfillment) ([]domain.FulfillmentItem, error) {
+	rows, err := tx.Query(ctx, remainingItemsSQL, fulfillment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	var remaining []domain.FulfillmentItem
+	for rows.Next() {
+		var item domain.FulfillmentItem
+		if err := rows.Scan(&item.OrderItemID, &item.BookID, &item.Quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		remaining = append(remaining, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var items []domain.FulfillmentItem
+	if len(fulfillment.Items) == 0 {
+		for _, item := range remaining {
+			if item.Quantity > 0 {
+				items = append(items, item)
+			}
+		}
+	} else {
+		left := make(map[int64]*domain.FulfillmentItem, len(remaining))
+		for i := range remaining {
+			left[remaining[i].OrderItemID] = &remaining[i]
+		}
+		index := make(map[int64]int)
+		for _, requested := range fulfillment.Items {
+			item, ok := left[requested.OrderItemID]
+			if !ok {
+				return nil, fmt.Errorf("%w: item %d is not a printed item of the order", domain.ErrInvalidFulfillment, requested.OrderItemID)
+			}
+			if requested.Quantity <= 0 || requested.Quantity > item.Quantity {
+				return nil, fmt.Errorf("%w: %d of item %d left to fulfill", domain.ErrInvalidFulfillment, item.Quantity, item.OrderItemID)
+			}
+			item.Quantity -= requested.Quantity
+			if i, ok := index[item.OrderItemID]; ok {
+				items[i].Quantity += requested.Quantity
+				continue
+			}
+			index[item.OrderItemID] = len(items)
+			items = append(items, domain.FulfillmentItem{
+				OrderItemID: item.OrderItemID,
+				BookID:      item.BookID,
+				Quantity:    requested.Quantity,
+			})
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: nothing left to fulfill", domain.ErrInvalidFulfillment)
+	}
+	return items, nil
+}
+
+func (fr *FulfillmentRepository) GetFulfillment(ctx context.Context, id int64) (*domain.Fulfillment, error) {
+	fulfillments, err := fr.listFulfillments(ctx, sq.Eq{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	if len(fulfillments) == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+	return &fulfillments[0], nil
+}
+
+func (fr *FulfillmentRepository) ListOrderFulfillments(ctx context.Context, orderID int64) ([]domain.Fulfillment, error) {
+	return fr.listFulfillments(ctx, sq.Eq{"order_id": orderID})
+}
+
+// ListCarrierShipments selects the fulfillments of a carrier that were shipped but not delivered
+func (fr *FulfillmentRepository) ListCarrierShipments(ctx context.Context, carrier string) ([]domain.Fulfillment, error) {
+	return fr.listFulfillments(ctx, sq.Eq{
+		"carrier": carrier,
+		"status":  []domain.FulfillmentStatus{domain.FulfillmentShipped, domain.FulfillmentInTransit, domain.FulfillmentException},
+	})
+}
+
+func (fr *FulfillmentRepository) listFulfillments(ctx context.Context, where sq.Sqlizer) ([]domain.Fulfillment, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := fr.db.QueryBuilder.Select(fulfillmentColumns).From("fulfillments").Where(where).OrderBy("id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := fr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fulfillments []domain.Fulfillment
+	for rows.Next() {
+		var fulfillment domain.Fulfillment
+		if err := scanFulfillment(rows, &fulfillment); err != nil {
+			return nil, err
+		}
+		fulfillments = append(fulfillments, fulfillment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := fr.loadFulfillmentItems(ctx, fulfillments); err != nil {
+		return nil, err
+	}
+	return fulfillments, nil
+}
+
+// loadFulfillmentItems fills in the items of the fulfillments with a single query
+func (fr *FulfillmentRepository) loadFulfillmentItems(ctx context.Context, fulfillments []domain.Fulfillment) error {
+	if len(fulfillments) == 0 {
+		return nil
+	}
+	index := make(map[int64]int, len(fulfillments))
+	ids := make([]int64, 0, len(fulfillments))
+	for i, fulfillment := range fulfillments {
+		index[fulfillment.ID] = i
+		ids = append(ids, fulfillment.ID)
+	}
+
+	sql, args, err := fr.db.QueryBuilder.Select("fi.fulfillment_id", "fi.order_item_id", "oi.book_id", "fi.quantity").
+		From("fulfillment_items fi").
+		Join("order_items oi ON oi.id = fi.order_item_id").
+		Where(sq.Eq{"fi.fulfillment_id": ids}).
+		OrderBy("fi.order_item_id").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := fr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fulfillmentID int64
+		var item domain.FulfillmentItem
+		if err := rows.Scan(&fulfillmentID, &item.OrderItemID, &item.BookID, &item.Quantity); err != nil {
+			return err
+		}
+		fulfillment := &fulfillments[index[fulfillmentID]]
+		fulfillment.Items = append(fulfillment.Items, item)
+	}
+	return rows.Err()
+}
+
+// UpdateFulfillment stores the changes of a fulfillment if its status is still from, so two
+// updates racing each other cannot both apply
+func (fr *FulfillmentRepository) UpdateFulfillment(ctx context.Context, fulfillment *domain.Fulfillment, from domain.FulfillmentStatus) (*domain.Fulfillment, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := fr.db.QueryBuilder.Update("fulfillments").
+		Set("status", fulfillment.Status).
+		Set("carrier", fulfillment.Carrier).
+		Set("tracking_number", fulfillment.TrackingNumber).
+		Set("shipped_at", fulfillment.ShippedAt).
+		Set("delivered_at", fulfillment.DeliveredAt).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": fulfillment.ID, "status": from}).
+		Suffix("RETURNING " + fulfillmentColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	items := fulfillment.Items
+	if err := scanFulfillment(fr.db.QueryRow(ctx, sql, args...), fulfillment); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	fulfillment.Items = items
+	return fulfillment, nil
+}
+
+func (fr *FulfillmentRepository) AddTrackingEvents(ctx context.Context, events []domain.TrackingEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := fr.db.QueryBuilder.Insert("tracking_events").
+		Columns("fulfillment_id", "status", "description", "location", "occurred_at").
+		Suffix("ON CONFLICT (fulfillment_id, status, occurred_at) DO NOTHING")
+	for _, event := range events {
+		query = query.Values(event.FulfillmentID, event.Status, event.Description, event.Location, event.OccurredAt)
+	}
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = fr.db.Exec(ctx, sql, args...)
+	return err
+}
+
+func (fr *FulfillmentRepository) ListTrackingEvents(ctx context.Context, fulfillmentID int64) ([]domain.TrackingEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := fr.db.QueryBuilder.Select(trackingEventColumns).
+		From("tracking_events").
+		Where(sq.Eq{"fulfillment_id": fulfillmentID}).
+		OrderBy("occurred_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := fr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.TrackingEvent
+	for rows.Next() {
+		var event domain.TrackingEvent
+		if err := rows.Scan(&event.ID, &event.FulfillmentID, &event.Status, &event.Description, &event.Location, &event.OccurredAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const invoiceColumns = "id,order_id,year,sequence,number,blob_key,lines,issued_at"
+
+// nextInvoiceSQL takes the next number of a year. The sequence row stays locked until the
+// transaction ends, so invoices of the same year are numbered one after the other and a rolled
+// back invoice gives its number back.
+const nextInvoiceSQL = `INSERT INTO invoice_sequences (year, last_number) VALUES ($1, 1)
+ON CONFLICT (year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+RETURNING last_number`
+
+type InvoiceRepository struct {
+	db *postgres.DB
+}
+
+func NewInvoiceRepository(db *postgres.DB) *InvoiceRepository {
+	return &InvoiceRepository{
+		db: db,
+	}
+}
+
+func scanInvoice(row pgx.Row, invoice *domain.Invoice) error {
+	return row.Scan(
+		&invoice.ID,
+		&invoice.OrderID,
+		&invoice.Year,
+		&invoice.Sequence,
+		&invoice.Number,
+		&invoice.BlobKey,
+		&invoice.Lines,
+		&invoice.IssuedAt,
+	)
+}
+
+// CreateInvoice numbers and inserts an invoice in one transaction
+func (ir *InvoiceRepository) CreateInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := ir.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.QueryRow(ctx, nextInvoiceSQL, invoice.Year).Scan(&invoice.Sequence); err != nil {
+		return nil, err
+	}
+	invoice.Number = domain.InvoiceNumber(invoice.Year, invoice.Sequence)
+	invoice.BlobKey = domain.InvoiceBlobKey(invoice.Number)
+
+	sql, args, err := ir.db.QueryBuilder.Insert("invoices").
+		Columns("order_id", "year", "sequence", "number", "blob_key", "lines", "issued_at").
+		Values(invoice.OrderID, invoice.Year, invoice.Sequence, invoice.Number, invoice.BlobKey, invoice.Lines, invoice.IssuedAt).
+		Suffix("RETURNING " + invoiceColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanInvoice(tx.QueryRow(ctx, sql, args...), invoice); err != nil {
+		if errCode := ir.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (ir *InvoiceRepository) GetOrderInvoice(ctx context.Context, orderID int64) (*domain.Invoice, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := ir.db.QueryBuilder.Select(invoiceColumns).From("invoices").Where(sq.Eq{"order_id": orderID}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var invoice domain.Invoice
+	if err := scanInvoice(ir.db.QueryRow(ctx, sql, args...), &invoice); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
@@ -9,6 +10,11 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+const (
+	orderColumns     = "id,user_id,book_id,status,shipping_address,billing_address,coupon_code,subtotal,discount_total,shipping_method_id,shipping_method,shipping_cost,tax_mode,tax_total,total,tax_lines,promotions,paid_at,created_at"
+	orderItemColumns = "id,order_id,book_id,edition_id,format_type,category_id,product_class,weight_grams,quantity,unit_price"
+)
+
 type OrderRepository struct {
 	db *postgres.DB
 }
@@ -19,48 +25,179 @@ func NewOrderReposiotory(db *postgres.DB) *OrderRepository {
 	}
 }
 
+func scanOrder(row pgx.Row, order *domain.Order) error {
+	return row.Scan(
+		&order.ID,
+		&order.UserId,
+		&order.BookId,
+		&order.Status,
+		&order.ShippingAddress,
+		&order.BillingAddress,
+		&order.CouponCode,
+		&order.Subtotal,
+		&order.DiscountTotal,
+		&order.ShippingMethodID,
+		&order.ShippingMethod,
+		&order.ShippingCost,
+		&order.TaxMode,
+		&order.TaxTotal,
+		&order.Total,
+		&order.TaxLines,
+		&order.Promotions,
+		&order.PaidAt,
+		&order.CreatedAt,
+	)
+}
+
+// CreateOrder inserts an order with its items and coupon redemption in one transaction. The coupon
+// row is locked while its usage limits are checked so concurrent orders cannot overspend it.
 func (or *OrderRepository) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
-	query := or.db.QueryBuilder.Insert("orders").Columns("user_id", "book_id").Values(&order.UserId, &order.BookId).Suffix("RETURNING id,user_id,book_id")
+	tx, err := or.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if order.Redemption != nil {
+		if err := checkCouponLimits(ctx, tx, order.Redemption); err != nil {
+			return nil, err
+		}
+	}
 
+	query := or.db.QueryBuilder.Insert("orders").
+		Columns("user_id", "book_id", "shipping_address", "billing_address", "coupon_code", "subtotal", "discount_total", "shipping_method_id", "shipping_method", "shipping_cost", "tax_mode", "tax_total", "total", "tax_lines", "promotions").
+		Values(order.UserId, order.BookId, order.ShippingAddress, order.BillingAddress, order.CouponCode, order.Subtotal, order.DiscountTotal, order.ShippingMethodID, order.ShippingMethod, order.ShippingCost, order.TaxMode, order.TaxTotal, order.Total, order.TaxLines, order.Promotions).
+		Suffix("RETURNING " + orderColumns)
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
-	err = or.db.QueryRow(ctx, sql, args...).Scan(&order.ID, &order.UserId, &order.BookId)
-	if err != nil {
+	if err := scanOrder(tx.QueryRow(ctx, sql, args...), order); err != nil {
+		return nil, err
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.OrderID = order.ID
+		sql, args, err := or.db.QueryBuilder.Insert("order_items").
+			Columns("order_id", "book_id", "edition_id", "format_type", "category_id", "product_class", "weight_grams", "quantity", "unit_price").
+			Values(item.OrderID, item.BookID, item.EditionID, item.Format, item.CategoryID, item.Class, item.WeightGrams, item.Quantity, item.UnitPrice).
+			Suffix("RETURNING id").
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.QueryRow(ctx, sql, args...).Scan(&item.ID); err != nil {
+			return nil, err
+		}
+		if err := takeStock(ctx, tx, item); err != nil {
+			return nil, err
+		}
+	}
+
+	if redemption := order.Redemption; redemption != nil {
+		redemption.OrderID = order.ID
+		sql, args, err := or.db.QueryBuilder.Insert("coupon_redemptions").
+			Columns("coupon_id", "user_id", "order_id", "amount").
+			Values(redemption.CouponID, redemption.UserID, redemption.OrderID, redemption.Amount).
+			Suffix("RETURNING id,created_at").
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.QueryRow(ctx, sql, args...).Scan(&redemption.ID, &redemption.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
 	return order, nil
 }
 
+// checkCouponLimits locks the coupon of a redemption and fails when redeeming it once more would
+// exceed one of its usage limits
+func checkCouponLimits(ctx context.Context, tx pgx.Tx, redemption *domain.CouponRedemption) error {
+	var maxUses, maxUsesPerUser int
+	err := tx.QueryRow(ctx, "SELECT max_uses, max_uses_per_user FROM coupons WHERE id = $1 AND active FOR UPDATE", redemption.CouponID).
+		Scan(&maxUses, &maxUsesPerUser)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrCouponNotApplicable
+		}
+		return err
+	}
+	usage, err := couponUsage(ctx, tx, redemption.CouponID, redemption.UserID)
+	if err != nil {
+		return err
+	}
+	if (maxUses > 0 && usage.Total >= maxUses) || (maxUsesPerUser > 0 && usage.ByUser >= maxUsesPerUser) {
+		return domain.ErrCouponExhausted
+	}
+	return nil
+}
+
+// takeStock takes the ordered quantity of a physical edition out of its stock, failing with
+// ErrOutOfStock when there is not enough of it. Digital editions have no stock.
+func takeStock(ctx context.Context, tx pgx.Tx, item *domain.OrderItem) error {
+	if item.EditionID == nil || item.Format.IsDigital() {
+		return nil
+	}
+	tag, err := tx.Exec(ctx, "UPDATE editions SET stock = stock - $1, updated_at = now() WHERE id = $2 AND stock >= $1", item.Quantity, *item.EditionID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrOutOfStock
+	}
+	return nil
+}
+
 func (or *OrderRepository) GetOrderById(ctx context.Context, id int64) (*domain.Order, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
-	query := or.db.QueryBuilder.Select("id,user_id,book_id").From("orders").Where(sq.Eq{"id": id})
+	query := or.db.QueryBuilder.Select(orderColumns).From("orders").Where(sq.Eq{"id": id})
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
 	var order domain.Order
-	err = or.db.QueryRow(ctx, sql, args...).Scan(&order.ID, &order.UserId, &order.BookId)
-	if err != nil {
+	if err := scanOrder(or.db.QueryRow(ctx, sql, args...), &order); err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, domain.ErrDataNotFound
 		}
 		return nil, err
 	}
-	return &order, nil
+	orders := []domain.Order{order}
+	if err := or.loadOrderItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
 }
+
 func (or *OrderRepository) OrderLists(ctx context.Context, skip, limit int64) ([]domain.Order, error) {
+	return or.listOrders(ctx, nil, skip, limit)
+}
+
+// ListUserOrders lists the orders of a user from the database
+func (or *OrderRepository) ListUserOrders(ctx context.Context, userID, skip, limit int64) ([]domain.Order, error) {
+	return or.listOrders(ctx, sq.Eq{"user_id": userID}, skip, limit)
+}
+
+func (or *OrderRepository) listOrders(ctx context.Context, where sq.Sqlizer, skip, limit int64) ([]domain.Order, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
-	query := or.db.QueryBuilder.Select("id,user_id,book_id").Options("id").Limit(uint64(limit))
+	query := or.db.QueryBuilder.Select(orderColumns).From("orders").OrderBy("id").Offset(uint64(skip)).Limit(uint64(limit))
+	if where != nil {
+		query = query.Where(where)
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -74,12 +211,107 @@ func (or *OrderRepository) OrderLists(ctx context.Context, skip, limit int64) ([
 
 	defer rows.Close()
 	var ordersList []domain.Order
-	var order domain.Order
 	for rows.Next() {
-		if err := rows.Scan(&order.ID, &order.UserId, &order.BookId); err != nil {
+		var order domain.Order
+		if err := scanOrder(rows, &order); err != nil {
 			return nil, err
 		}
 		ordersList = append(ordersList, order)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := or.loadOrderItems(ctx, ordersList); err != nil {
+		return nil, err
+	}
 	return ordersList, nil
 }
+
+// UpdateOrderStatus stores the status of an order in the database
+func (or *OrderRepository) UpdateOrderStatus(ctx context.Context, id int64, status domain.OrderStatus) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := or.db.QueryBuilder.Update("orders").
+		Set("status", status).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := or.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+// MarkOrderPaid stores the payment time of an order that was not paid yet in the database
+func (or *OrderRepository) MarkOrderPaid(ctx context.Context, id int64, paidAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := or.db.QueryBuilder.Update("orders").
+		Set("paid_at", paidAt).
+		Where(sq.Eq{"id": id, "paid_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := or.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+	// Either the order does not exist or it was paid before
+	var exists bool
+	if err := or.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)", id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrDataNotFound
+	}
+	return domain.ErrOrderAlreadyPaid
+}
+
+// loadOrderItems fills in the items of the orders with a single query
+func (or *OrderRepository) loadOrderItems(ctx context.Context, orders []domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	index := make(map[int64]int, len(orders))
+	ids := make([]int64, 0, len(orders))
+	for i, order := range orders {
+		index[order.ID] = i
+		ids = append(ids, order.ID)
+	}
+
+	sql, args, err := or.db.QueryBuilder.Select(orderItemColumns).
+		From("order_items").
+		Where(sq.Eq{"order_id": ids}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := or.db.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.OrderItem
+		if err := rows.Scan(&item.ID, &item.OrderID, &item.BookID, &item.EditionID, &item.Format, &item.CategoryID, &item.Class, &item.WeightGrams, &item.Quantity, &item.UnitPrice); err != nil {
+			return err
+		}
+		order := &orders[index[item.OrderID]]
+		order.Items = append(order.Items, item)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type PasswordResetRepository struct {
+	db *postgres.DB
+}
+
+func NewPasswordResetRepository(db *postgres.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+// CreatePasswordReset stores a new password reset in the database
+func (pr *PasswordResetRepository) CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) (*domain.PasswordReset, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := pr.db.QueryBuilder.Insert("password_resets").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(reset.UserID, reset.TokenHash, reset.ExpiresAt).
+		Suffix("RETURNING id,created_at")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	err = pr.db.QueryRow(ctx, sql, args...).Scan(&reset.ID, &reset.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return reset, nil
+}
+
+// GetPasswordResetByTokenHash gets a password reset by its token hash from the database
+func (pr *PasswordResetRepository) GetPasswordResetByTokenHash(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := pr.db.QueryBuilder.Select("id,user_id,token_hash,expires_at,used_at,created_at").
+		From("password_resets").
+		Where(sq.Eq{"token_hash": tokenHash})
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var reset domain.PasswordReset
+	err = pr.db.QueryRow(ctx, sql, args...).Scan(
+		&reset.ID,
+		&reset.UserID,
+		&reset.TokenHash,
+		&reset.ExpiresAt,
+		&reset.UsedAt,
+		&reset.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &reset, nil
+}
+
+// ResetPassword consumes the reset, updates the password and bumps the user's token version
+func (pr *PasswordResetRepository) ResetPassword(ctx context.Context, reset *domain.PasswordReset, hashedPassword string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := pr.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Claim the token, the conditions guard against two concurrent redemptions
+	sql, args, err := pr.db.QueryBuilder.Update("password_resets").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": reset.ID, "used_at": nil}).
+		Where(sq.Expr("expires_at > NOW()")).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrInvalidResetToken
+	}
+
+	sql, args, err = pr.db.QueryBuilder.Update("users").
+		Set("password", hashedPassword).
+		Set("token_version", sq.Expr("token_version + 1")).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": reset.UserID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	// Any other outstanding resets for the user are no longer valid
+	sql, args, err = pr.db.QueryBuilder.Update("password_resets").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": reset.UserID, "used_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
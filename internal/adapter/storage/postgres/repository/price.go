@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	priceChangeColumns   = "id,edition_id,price,source,changed_at"
+	priceScheduleColumns = "id,book_id,edition_id,price,previous_price,starts_at,ends_at,status,created_by,created_at"
+)
+
+// recordPriceSQL appends a price change unless the price equals the last recorded one
+const recordPriceSQL = `INSERT INTO edition_price_history (edition_id, price, source)
+SELECT $1, $2, $3
+WHERE $2::numeric IS DISTINCT FROM (
+    SELECT price FROM edition_price_history WHERE edition_id = $1 ORDER BY changed_at DESC, id DESC LIMIT 1
+)`
+
+// recordPrice adds a price change to the history of an edition within the transaction that changed it
+func recordPrice(ctx context.Context, tx pgx.Tx, editionID int64, price float64, source domain.PriceSource) error {
+	_, err := tx.Exec(ctx, recordPriceSQL, editionID, price, source)
+	return err
+}
+
+type PriceRepository struct {
+	db *postgres.DB
+}
+
+func NewPriceRepository(db *postgres.DB) *PriceRepository {
+	return &PriceRepository{
+		db: db,
+	}
+}
+
+func scanPriceSchedule(row pgx.Row, schedule *domain.PriceSchedule) error {
+	return row.Scan(
+		&schedule.ID,
+		&schedule.BookID,
+		&schedule.EditionID,
+		&schedule.Price,
+		&schedule.PreviousPrice,
+		&schedule.StartsAt,
+		&schedule.EndsAt,
+		&schedule.Status,
+		&schedule.CreatedBy,
+		&schedule.CreatedAt,
+	)
+}
+
+// ListPriceHistory lists the price changes of an edition, oldest first
+func (pr *PriceRepository) ListPriceHistory(ctx context.Context, editionID int64) ([]domain.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Select(priceChangeColumns).
+		From("edition_price_history").
+		Where(sq.Eq{"edition_id": editionID}).
+		OrderBy("changed_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := pr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []domain.PriceChange
+	for rows.Next() {
+		var change domain.PriceChange
+		if err := rows.Scan(&change.ID, &change.EditionID, &change.Price, &change.Source, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// GetLowestPrice gets the lowest price of an edition since the given time, including the price
+// that was already in effect at that time
+func (pr *PriceRepository) GetLowestPrice(ctx context.Context, editionID int64, since time.Time) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	const query = `SELECT MIN(price) FROM edition_price_history
+WHERE edition_id = $1 AND (
+    changed_at >= $2 OR id = (
+        SELECT id FROM edition_price_history WHERE edition_id = $1 AND changed_at < $2 ORDER BY changed_at DESC, id DESC LIMIT 1
+    )
+)`
+	var lowest *float64
+	if err := pr.db.QueryRow(ctx, query, editionID, since).Scan(&lowest); err != nil {
+		return 0, err
+	}
+	if lowest == nil {
+		return 0, domain.ErrDataNotFound
+	}
+	return *lowest, nil
+}
+
+// CreatePriceSchedule creates a scheduled price, the edition row is locked so concurrent
+// schedules of the same edition cannot both pass the overlap check
+func (pr *PriceRepository) CreatePriceSchedule(ctx context.Context, schedule *domain.PriceSchedule) (*domain.PriceSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := pr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	const lockSQL = `SELECT editions.book_id FROM editions
+JOIN books ON books.id = editions.book_id AND books.deleted_at IS NULL
+WHERE editions.id = $1
+FOR UPDATE OF editions`
+	err = tx.QueryRow(ctx, lockSQL, schedule.EditionID).Scan(&schedule.BookID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	const overlapSQL = `SELECT EXISTS (
+    SELECT 1 FROM price_schedules
+    WHERE edition_id = $1 AND status IN ('pending', 'active')
+    AND tstzrange(starts_at, ends_at) && tstzrange($2::timestamptz, $3::timestamptz)
+)`
+	var overlaps bool
+	if err := tx.QueryRow(ctx, overlapSQL, schedule.EditionID, schedule.StartsAt, schedule.EndsAt).Scan(&overlaps); err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, domain.ErrPriceScheduleOverlap
+	}
+
+	sql, args, err := pr.db.QueryBuilder.Insert("price_schedules").
+		Columns("book_id", "edition_id", "price", "starts_at", "ends_at", "created_by").
+		Values(schedule.BookID, schedule.EditionID, schedule.Price, schedule.StartsAt, schedule.EndsAt, schedule.CreatedBy).
+		Suffix("RETURNING " + priceScheduleColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanPriceSchedule(tx.QueryRow(ctx, sql, args...), schedule); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetPriceSchedule gets a scheduled price of an edition by id
+func (pr *PriceRepository) GetPriceSchedule(ctx context.Context, editionID, id int64) (*domain.PriceSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Select(priceScheduleColumns).
+		From("price_schedules").
+		Where(sq.Eq{"id": id, "edition_id": editionID}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var schedule domain.PriceSchedule
+	if err := scanPriceSchedule(pr.db.QueryRow(ctx, sql, args...), &schedule); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ListPriceSchedules lists the scheduled prices of an edition ordered by start
+func (pr *PriceRepository) ListPriceSchedules(ctx context.Context, editionID int64, openOnly bool) ([]domain.PriceSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := pr.db.QueryBuilder.Select(priceScheduleColumns).
+		From("price_schedules").
+		Where(sq.Eq{"edition_id": editionID}).
+		OrderBy("starts_at", "id")
+	if openOnly {
+		query = query.Where(sq.Eq{"status": []domain.PriceScheduleStatus{domain.PriceSchedulePending, domain.PriceScheduleActive}})
+	}
+	return pr.listPriceSchedules(ctx, query)
+}
+
+// ListDuePriceSchedules lists the pending schedules that should have started and the active
+// schedules that should have ended by now
+func (pr *PriceRepository) ListDuePriceSchedules(ctx context.Context, now time.Time) ([]domain.PriceSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := pr.db.QueryBuilder.Select(priceScheduleColumns).
+		From("price_schedules").
+		Where(sq.Or{
+			sq.And{sq.Eq{"status": domain.PriceSchedulePending}, sq.LtOrEq{"starts_at": now}},
+			sq.And{sq.Eq{"status": domain.PriceScheduleActive}, sq.LtOrEq{"ends_at": now}},
+		}).
+		OrderBy("starts_at", "id")
+	return pr.listPriceSchedules(ctx, query)
+}
+
+func (pr *PriceRepository) listPriceSchedules(ctx context.Context, query sq.SelectBuilder) ([]domain.PriceSchedule, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := pr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []domain.PriceSchedule
+	for rows.Next() {
+		var schedule domain.PriceSchedule
+		if err := scanPriceSchedule(rows, &schedule); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// CancelPriceSchedule marks a pending scheduled price as cancelled
+func (pr *PriceRepository) CancelPriceSchedule(ctx context.Context, id int64) error {
+	return pr.updatePriceSchedule(ctx, pr.db.QueryBuilder.Update("price_schedules").
+		Set("status", domain.PriceScheduleCancelled).
+		Where(sq.Eq{"id": id, "status": domain.PriceSchedulePending}))
+}
+
+// EndPriceSchedule moves the end of an active scheduled price
+func (pr *PriceRepository) EndPriceSchedule(ctx context.Context, id int64, at time.Time) error {
+	return pr.updatePriceSchedule(ctx, pr.db.QueryBuilder.Update("price_schedules").
+		Set("ends_at", at).
+		Where(sq.Eq{"id": id, "status": domain.PriceScheduleActive}))
+}
+
+func (pr *PriceRepository) updatePriceSchedule(ctx context.Context, query sq.UpdateBuilder) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := pr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+// runPriceSchedule locks a due schedule in the status and the price of its edition, and changes
+// both as the schedule decides. Schedules of deleted editions or books are cancelled and reported
+// as not found.
+func (pr *PriceRepository) runPriceSchedule(ctx context.Context, id int64, status domain.PriceScheduleStatus) (*domain.PriceSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := pr.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := pr.db.QueryBuilder.Select(priceScheduleColumns).
+		From("price_schedules").
+		Where(sq.Eq{"id": id, "status": status}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var schedule domain.PriceSchedule
+	if err := scanPriceSchedule(tx.QueryRow(ctx, sql, args...), &schedule); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	const lockSQL = `SELECT editions.price FROM editions
+JOIN books ON books.id = editions.book_id AND books.deleted_at IS NULL
+WHERE editions.id = $1
+FOR UPDATE OF editions`
+	var price float64
+	deleted := false
+	if err := tx.QueryRow(ctx, lockSQL, schedule.EditionID).Scan(&price); err != nil {
+		if err != pgx.ErrNoRows {
+			return nil, err
+		}
+		deleted = true
+	}
+
+	run := schedule.Run(price, deleted)
+	if run.Price != nil {
+		if err := pr.setEditionPrice(ctx, tx, schedule.EditionID, *run.Price, run.Source); err != nil {
+			return nil, err
+		}
+	}
+	sql, args, err = pr.db.QueryBuilder.Update("price_schedules").
+		Set("previous_price", run.PreviousPrice).
+		Set("status", run.Status).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + priceScheduleColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanPriceSchedule(tx.QueryRow(ctx, sql, args...), &schedule); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	if deleted {
+		return nil, domain.ErrDataNotFound
+	}
+	return &schedule, nil
+}
+
+// setEditionPrice changes the price of an edition and records it in the price history
+func (pr *PriceRepository) setEditionPrice(ctx context.Context, tx pgx.Tx, editionID int64, price float64, source domain.PriceSource) error {
+	sql, args, err := pr.db.QueryBuilder.Update("editions").
+		Set("price", price).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": editionID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+	return recordPrice(ctx, tx, editionID, price, source)
+}
+
+// ApplyPriceSchedule sets the edition's price to the scheduled price, a schedule without an end
+// is a permanent change and completes right away
+func (pr *PriceRepository) ApplyPriceSchedule(ctx context.Context, id int64) (*domain.PriceSchedule, error) {
+	return pr.runPriceSchedule(ctx, id, domain.PriceSchedulePending)
+}
+
+// RevertPriceSchedule restores the price from before the schedule. When staff changed the price
+// while the schedule was active their price is kept.
+func (pr *PriceRepository) RevertPriceSchedule(ctx context.Context, id int64) (*domain.PriceSchedule, error) {
+	return pr.runPriceSchedule(ctx, id, domain.PriceScheduleActive)
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	couponColumns    = "id,code,discount_type,value,min_basket,max_uses,max_uses_per_user,starts_at,ends_at,active,created_at"
+	promotionColumns = "id,name,category_id,buy_quantity,free_quantity,starts_at,ends_at,active,created_at"
+)
+
+type PromotionRepository struct {
+	db *postgres.DB
+}
+
+func NewPromotionRepository(db *postgres.DB) *PromotionRepository {
+	return &PromotionRepository{
+		db: db,
+	}
+}
+
+func scanCoupon(row pgx.Row, coupon *domain.Coupon) error {
+	return row.Scan(
+		&coupon.ID,
+		&coupon.Code,
+		&coupon.DiscountType,
+		&coupon.Value,
+		&coupon.MinBasket,
+		&coupon.MaxUses,
+		&coupon.MaxUsesPerUser,
+		&coupon.StartsAt,
+		&coupon.EndsAt,
+		&coupon.Active,
+		&coupon.CreatedAt,
+	)
+}
+
+func scanPromotion(row pgx.Row, promotion *domain.AutomaticPromotion) error {
+	return row.Scan(
+		&promotion.ID,
+		&promotion.Name,
+		&promotion.CategoryID,
+		&promotion.BuyQuantity,
+		&promotion.FreeQuantity,
+		&promotion.StartsAt,
+		&promotion.EndsAt,
+		&promotion.Active,
+		&promotion.CreatedAt,
+	)
+}
+
+func (pr *PromotionRepository) CreateCoupon(ctx context.Context, coupon *domain.Coupon) (*domain.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Insert("coupons").
+		Columns("code", "discount_type", "value", "min_basket", "max_uses", "max_uses_per_user", "starts_at", "ends_at").
+		Values(coupon.Code, coupon.DiscountType, coupon.Value, coupon.MinBasket, coupon.MaxUses, coupon.MaxUsesPerUser, coupon.StartsAt, coupon.EndsAt).
+		Suffix("RETURNING " + couponColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanCoupon(pr.db.QueryRow(ctx, sql, args...), coupon); err != nil {
+		if errCode := pr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return coupon, nil
+}
+
+func (pr *PromotionRepository) GetCouponByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Select(couponColumns).From("coupons").Where(sq.Eq{"code": code}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var coupon domain.Coupon
+	if err := scanCoupon(pr.db.QueryRow(ctx, sql, args...), &coupon); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (pr *PromotionRepository) ListCoupons(ctx context.Context, skip, limit int64) ([]domain.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Select(couponColumns).From("coupons").OrderBy("id").Offset(uint64(skip)).Limit(uint64(limit)).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := pr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coupons []domain.Coupon
+	for rows.Next() {
+		var coupon domain.Coupon
+		if err := scanCoupon(rows, &coupon); err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, coupon)
+	}
+	return coupons, rows.Err()
+}
+
+func (pr *PromotionRepository) DeactivateCoupon(ctx context.Context, id int64) (*domain.Coupon, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Update("coupons").
+		Set("active", false).
+		Where(sq.Eq{"id": id, "active": true}).
+		Suffix("RETURNING " + couponColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var coupon domain.Coupon
+	if err := scanCoupon(pr.db.QueryRow(ctx, sql, args...), &coupon); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// GetCouponUsage counts the redemptions of a coupon in total and by the user
+func (pr *PromotionRepository) GetCouponUsage(ctx context.Context, couponID, userID int64) (domain.CouponUsage, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	return couponUsage(ctx, pr.db, couponID, userID)
+}
+
+// couponUsageSQL counts all redemptions of a coupon and those of one user
+const couponUsageSQL = `SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $2) FROM coupon_redemptions WHERE coupon_id = $1`
+
+// querier is what pgx pools and transactions have in common for reading rows
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func couponUsage(ctx context.Context, q querier, couponID, userID int64) (domain.CouponUsage, error) {
+	var usage domain.CouponUsage
+	err := q.QueryRow(ctx, couponUsageSQL, couponID, userID).Scan(&usage.Total, &usage.ByUser)
+	return usage, err
+}
+
+func (pr *PromotionRepository) CreatePromotion(ctx context.Context, promotion *domain.AutomaticPromotion) (*domain.AutomaticPromotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Insert("automatic_promotions").
+		Columns("name", "category_id", "buy_quantity", "free_quantity", "starts_at", "ends_at").
+		Values(promotion.Name, promotion.CategoryID, promotion.BuyQuantity, promotion.FreeQuantity, promotion.StartsAt, promotion.EndsAt).
+		Suffix("RETURNING " + promotionColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanPromotion(pr.db.QueryRow(ctx, sql, args...), promotion); err != nil {
+		if errCode := pr.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrUnknownCategory
+		}
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (pr *PromotionRepository) ListPromotions(ctx context.Context, skip, limit int64) ([]domain.AutomaticPromotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	return pr.listPromotions(ctx, pr.db.QueryBuilder.Select(promotionColumns).
+		From("automatic_promotions").
+		OrderBy("id").
+		Offset(uint64(skip)).
+		Limit(uint64(limit)))
+}
+
+// ListActivePromotions lists the active automatic promotions whose validity window contains now
+func (pr *PromotionRepository) ListActivePromotions(ctx context.Context, now time.Time) ([]domain.AutomaticPromotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	return pr.listPromotions(ctx, pr.db.QueryBuilder.Select(promotionColumns).
+		From("automatic_promotions").
+		Where(sq.Eq{"active": true}).
+		Where(sq.LtOrEq{"starts_at": now}).
+		Where(sq.Or{sq.Eq{"ends_at": nil}, sq.Gt{"ends_at": now}}).
+		OrderBy("id"))
+}
+
+func (pr *PromotionRepository) listPromotions(ctx context.Context, query sq.SelectBuilder) ([]domain.AutomaticPromotion, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := pr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []domain.AutomaticPromotion
+	for rows.Next() {
+		var promotion domain.AutomaticPromotion
+		if err := scanPromotion(rows, &promotion); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
+
+func (pr *PromotionRepository) DeactivatePromotion(ctx context.Context, id int64) (*domain.AutomaticPromotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Update("automatic_promotions").
+		Set("active", false).
+		Where(sq.Eq{"id": id, "active": true}).
+		Suffix("RETURNING " + promotionColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var promotion domain.AutomaticPromotion
+	if err := scanPromotion(pr.db.QueryRow(ctx, sql, args...), &promotion); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
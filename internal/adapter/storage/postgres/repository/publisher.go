@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const publisherColumns = "id,name,website,created_at,updated_at"
+
+type PublisherRepository struct {
+	db *postgres.DB
+}
+
+func NewPublisherRepository(db *postgres.DB) *PublisherRepository {
+	return &PublisherRepository{
+		db: db,
+	}
+}
+
+func scanPublisher(row pgx.Row, publisher *domain.Publisher) error {
+	return row.Scan(
+		&publisher.ID,
+		&publisher.Name,
+		&publisher.Website,
+		&publisher.CreatedAt,
+		&publisher.UpdatedAt,
+	)
+}
+
+func (pr *PublisherRepository) CreatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Insert("publishers").
+		Columns("name", "website").
+		Values(publisher.Name, publisher.Website).
+		Suffix("RETURNING " + publisherColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanPublisher(pr.db.QueryRow(ctx, sql, args...), publisher); err != nil {
+		if errCode := pr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return publisher, nil
+}
+
+func (pr *PublisherRepository) GetPublisher(ctx context.Context, id int64) (*domain.Publisher, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Select(publisherColumns).From("publishers").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var publisher domain.Publisher
+	if err := scanPublisher(pr.db.QueryRow(ctx, sql, args...), &publisher); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &publisher, nil
+}
+
+func (pr *PublisherRepository) ListPublishers(ctx context.Context) ([]domain.Publisher, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Select(publisherColumns).From("publishers").OrderBy("name").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := pr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var publishers []domain.Publisher
+	for rows.Next() {
+		var publisher domain.Publisher
+		if err := scanPublisher(rows, &publisher); err != nil {
+			return nil, err
+		}
+		publishers = append(publishers, publisher)
+	}
+	return publishers, rows.Err()
+}
+
+func (pr *PublisherRepository) UpdatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Update("publishers").
+		Set("name", publisher.Name).
+		Set("website", publisher.Website).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": publisher.ID}).
+		Suffix("RETURNING " + publisherColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanPublisher(pr.db.QueryRow(ctx, sql, args...), publisher); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if errCode := pr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return publisher, nil
+}
+
+func (pr *PublisherRepository) DeletePublisher(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := pr.db.QueryBuilder.Delete("publishers").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := pr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
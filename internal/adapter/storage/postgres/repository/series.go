@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const seriesColumns = "id,name,description,publisher_id,created_at,updated_at"
+
+type SeriesRepository struct {
+	db *postgres.DB
+}
+
+func NewSeriesRepository(db *postgres.DB) *SeriesRepository {
+	return &SeriesRepository{
+		db: db,
+	}
+}
+
+func scanSeries(row pgx.Row, series *domain.Series) error {
+	return row.Scan(
+		&series.ID,
+		&series.Name,
+		&series.Description,
+		&series.PublisherID,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+}
+
+func (sr *SeriesRepository) CreateSeries(ctx context.Context, series *domain.Series) (*domain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Insert("series").
+		Columns("name", "description", "publisher_id").
+		Values(series.Name, series.Description, series.PublisherID).
+		Suffix("RETURNING " + seriesColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanSeries(sr.db.QueryRow(ctx, sql, args...), series); err != nil {
+		if errCode := sr.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrUnknownPublisher
+		}
+		return nil, err
+	}
+	return series, nil
+}
+
+func (sr *SeriesRepository) GetSeries(ctx context.Context, id int64) (*domain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Select(seriesColumns).From("series").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var series domain.Series
+	if err := scanSeries(sr.db.QueryRow(ctx, sql, args...), &series); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (sr *SeriesRepository) ListSeries(ctx context.Context) ([]domain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Select(seriesColumns).From("series").OrderBy("name", "id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := sr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seriesList []domain.Series
+	for rows.Next() {
+		var series domain.Series
+		if err := scanSeries(rows, &series); err != nil {
+			return nil, err
+		}
+		seriesList = append(seriesList, series)
+	}
+	return seriesList, rows.Err()
+}
+
+func (sr *SeriesRepository) UpdateSeries(ctx context.Context, series *domain.Series) (*domain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Update("series").
+		Set("name", series.Name).
+		Set("description", series.Description).
+		Set("publisher_id", series.PublisherID).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": series.ID}).
+		Suffix("RETURNING " + seriesColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanSeries(sr.db.QueryRow(ctx, sql, args...), series); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if errCode := sr.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrUnknownPublisher
+		}
+		return nil, err
+	}
+	return series, nil
+}
+
+func (sr *SeriesRepository) DeleteSeries(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Delete("series").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := sr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	shippingZoneColumns   = "id,name,countries"
+	shippingMethodColumns = "id,zone_id,name,rate_type,price,per_kg,free_over,max_weight_grams,active"
+)
+
+type ShippingRepository struct {
+	db *postgres.DB
+}
+
+func NewShippingRepository(db *postgres.DB) *ShippingRepository {
+	return &ShippingRepository{
+		db: db,
+	}
+}
+
+func scanShippingMethod(row pgx.Row, method *domain.ShippingMethod) error {
+	return row.Scan(
+		&method.ID,
+		&method.ZoneID,
+		&method.Name,
+		&method.Type,
+		&method.Price,
+		&method.PerKg,
+		&method.FreeOver,
+		&method.MaxWeightGrams,
+		&method.Active,
+	)
+}
+
+func (sr *ShippingRepository) CreateZone(ctx context.Context, zone *domain.ShippingZone) (*domain.ShippingZone, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Insert("shipping_zones").
+		Columns("name", "countries").
+		Values(zone.Name, zone.Countries).
+		Suffix("RETURNING " + shippingZoneColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := sr.db.QueryRow(ctx, sql, args...).Scan(&zone.ID, &zone.Name, &zone.Countries); err != nil {
+		if errCode := sr.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return zone, nil
+}
+
+func (sr *ShippingRepository) ListZones(ctx context.Context) ([]domain.ShippingZone, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Select(shippingZoneColumns).From("shipping_zones").OrderBy("id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := sr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var zones []domain.ShippingZone
+	for rows.Next() {
+		var zone domain.ShippingZone
+		if err := rows.Scan(&zone.ID, &zone.Name, &zone.Countries); err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+	return zones, rows.Err()
+}
+
+func (sr *ShippingRepository) CreateMethod(ctx context.Context, method *domain.ShippingMethod) (*domain.ShippingMethod, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Insert("shipping_methods").
+		Columns("zone_id", "name", "rate_type", "price", "per_kg", "free_over", "max_weight_grams").
+		Values(method.ZoneID, method.Name, method.Type, method.Price, method.PerKg, method.FreeOver, method.MaxWeightGrams).
+		Suffix("RETURNING " + shippingMethodColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanShippingMethod(sr.db.QueryRow(ctx, sql, args...), method); err != nil {
+		if errCode := sr.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return method, nil
+}
+
+func (sr *ShippingRepository) ListMethods(ctx context.Context) ([]domain.ShippingMethod, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	return sr.listMethods(ctx, sr.db.QueryBuilder.Select(shippingMethodColumns).From("shipping_methods").OrderBy("zone_id", "id"))
+}
+
+func (sr *ShippingRepository) DisableMethod(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := sr.db.QueryBuilder.Update("shipping_methods").
+		Set("active", false).
+		Where(sq.Eq{"id": id, "active": true}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := sr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+// ListMethodsForCountry lists the active methods of the zones listing the country. The zones
+// listing AnyCountry are only used when no zone lists the country itself.
+func (sr *ShippingRepository) ListMethodsForCountry(ctx context.Context, country string) ([]domain.ShippingMethod, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	const zonesSQL = `SELECT id FROM shipping_zones WHERE ? = ANY(countries)
+UNION ALL
+SELECT id FROM shipping_zones WHERE ? = ANY(countries)
+    AND NOT EXISTS (SELECT 1 FROM shipping_zones WHERE ? = ANY(countries))`
+	query := sr.db.QueryBuilder.Select(shippingMethodColumns).
+		From("shipping_methods").
+		Where(sq.Eq{"active": true}).
+		Where(sq.Expr("zone_id IN ("+zonesSQL+")", country, domain.AnyCountry, country)).
+		OrderBy("id")
+	return sr.listMethods(ctx, query)
+}
+
+func (sr *ShippingRepository) listMethods(ctx context.Context, query sq.SelectBuilder) ([]domain.ShippingMethod, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := sr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var methods []domain.ShippingMethod
+	for rows.Next() {
+		var method domain.ShippingMethod
+		if err := scanShippingMethod(rows, &method); err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+	return methods, rows.Err()
+}
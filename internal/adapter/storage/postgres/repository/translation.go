@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	bookTranslationColumns     = "book_id,locale,name,description,updated_at"
+	categoryTranslationColumns = "category_id,locale,name,updated_at"
+)
+
+type TranslationRepository struct {
+	db *postgres.DB
+}
+
+func NewTranslationRepository(db *postgres.DB) *TranslationRepository {
+	return &TranslationRepository{
+		db: db,
+	}
+}
+
+func scanBookTranslation(row pgx.Row, translation *domain.BookTranslation) error {
+	return row.Scan(
+		&translation.BookID,
+		&translation.Locale,
+		&translation.Name,
+		&translation.Description,
+		&translation.UpdatedAt,
+	)
+}
+
+func scanCategoryTranslation(row pgx.Row, translation *domain.CategoryTranslation) error {
+	return row.Scan(
+		&translation.CategoryID,
+		&translation.Locale,
+		&translation.Name,
+		&translation.UpdatedAt,
+	)
+}
+
+func (tr *TranslationRepository) UpsertBookTranslation(ctx context.Context, translation *domain.BookTranslation) (*domain.BookTranslation, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := tr.db.QueryBuilder.Insert("book_translations").
+		Columns("book_id", "locale", "name", "description").
+		Values(translation.BookID, translation.Locale, translation.Name, translation.Description).
+		Suffix(`ON CONFLICT (book_id, locale) DO UPDATE
+			SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = now()
+			RETURNING ` + bookTranslationColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanBookTranslation(tr.db.QueryRow(ctx, sql, args...), translation); err != nil {
+		if errCode := tr.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return translation, nil
+}
+
+func (tr *TranslationRepository) ListBookTranslations(ctx context.Context, bookID int64) ([]domain.BookTranslation, error) {
+	return tr.listBookTranslations(ctx, sq.Eq{"book_id": bookID})
+}
+
+func (tr *TranslationRepository) FindBookTranslations(ctx context.Context, bookIDs []int64, locales []string) ([]domain.BookTranslation, error) {
+	return tr.listBookTranslations(ctx, sq.Eq{"book_id": bookIDs, "locale": locales})
+}
+
+func (tr *TranslationRepository) listBookTranslations(ctx context.Context, where sq.Sqlizer) ([]domain.BookTranslation, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := tr.db.QueryBuilder.Select(bookTranslationColumns).
+		From("book_translations").
+		Where(where).
+		OrderBy("book_id", "locale").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []domain.BookTranslation
+	for rows.Next() {
+		var translation domain.BookTranslation
+		if err := scanBookTranslation(rows, &translation); err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+func (tr *TranslationRepository) DeleteBookTranslation(ctx context.Context, bookID int64, locale string) error {
+	return tr.deleteTranslation(ctx, "book_translations", sq.Eq{"book_id": bookID, "locale": locale})
+}
+
+func (tr *TranslationRepository) UpsertCategoryTranslation(ctx context.Context, translation *domain.CategoryTranslation) (*domain.CategoryTranslation, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := tr.db.QueryBuilder.Insert("category_translations").
+		Columns("category_id", "locale", "name").
+		Values(translation.CategoryID, translation.Locale, translation.Name).
+		Suffix(`ON CONFLICT (category_id, locale) DO UPDATE
+			SET name = EXCLUDED.name, updated_at = now()
+			RETURNING ` + categoryTranslationColumns).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := scanCategoryTranslation(tr.db.QueryRow(ctx, sql, args...), translation); err != nil {
+		if errCode := tr.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return translation, nil
+}
+
+func (tr *TranslationRepository) ListCategoryTranslations(ctx context.Context, categoryID int64) ([]domain.CategoryTranslation, error) {
+	return tr.listCategoryTranslations(ctx, sq.Eq{"category_id": categoryID})
+}
+
+func (tr *TranslationRepository) FindCategoryTranslations(ctx context.Context, categoryIDs []int64, locales []string) ([]domain.CategoryTranslation, error) {
+	return tr.listCategoryTranslations(ctx, sq.Eq{"category_id": categoryIDs, "locale": locales})
+}
+
+func (tr *TranslationRepository) listCategoryTranslations(ctx context.Context, where sq.Sqlizer) ([]domain.CategoryTranslation, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := tr.db.QueryBuilder.Select(categoryTranslationColumns).
+		From("category_translations").
+		Where(where).
+		OrderBy("category_id", "locale").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tr.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []domain.CategoryTranslation
+	for rows.Next() {
+		var translation domain.CategoryTranslation
+		if err := scanCategoryTranslation(rows, &translation); err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+func (tr *TranslationRepository) DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error {
+	return tr.deleteTranslation(ctx, "category_translations", sq.Eq{"category_id": categoryID, "locale": locale})
+}
+
+func (tr *TranslationRepository) deleteTranslation(ctx context.Context, table string, where sq.Eq) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := tr.db.QueryBuilder.Delete(table).Where(where).ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := tr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
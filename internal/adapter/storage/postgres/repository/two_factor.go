@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type TwoFactorRepository struct {
+	db *postgres.DB
+}
+
+func NewTwoFactorRepository(db *postgres.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		db: db,
+	}
+}
+
+// GetTwoFactor gets the second factor of a user from the database
+func (tr *TwoFactorRepository) GetTwoFactor(ctx context.Context, userID int64) (*domain.TwoFactor, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := tr.db.QueryBuilder.Select("user_id,secret,confirmed_at,last_used_step").
+		From("user_two_factors").
+		Where(sq.Eq{"user_id": userID})
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var twoFactor domain.TwoFactor
+	err = tr.db.QueryRow(ctx, sql, args...).Scan(
+		&twoFactor.UserID,
+		&twoFactor.Secret,
+		&twoFactor.ConfirmedAt,
+		&twoFactor.LastUsedStep,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// SaveTwoFactor inserts a pending second factor, replacing a previous unconfirmed one
+func (tr *TwoFactorRepository) SaveTwoFactor(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := tr.db.QueryBuilder.Insert("user_two_factors").
+		Columns("user_id", "secret").
+		Values(twoFactor.UserID, twoFactor.Secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0 WHERE user_two_factors.confirmed_at IS NULL")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = tr.db.Exec(ctx, sql, args...)
+	return err
+}
+
+// ConfirmTwoFactor enables the second factor and stores fresh recovery codes in a single transaction
+func (tr *TwoFactorRepository) ConfirmTwoFactor(ctx context.Context, userID, step int64, recoveryCodeHashes []string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := tr.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := tr.db.QueryBuilder.Update("user_two_factors").
+		Set("confirmed_at", sq.Expr("NOW()")).
+		Set("last_used_step", step).
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	sql, args, err = tr.db.QueryBuilder.Delete("user_recovery_codes").Where(sq.Eq{"user_id": userID}).ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	insert := tr.db.QueryBuilder.Insert("user_recovery_codes").Columns("user_id", "code_hash")
+	for _, hash := range recoveryCodeHashes {
+		insert = insert.Values(userID, hash)
+	}
+	sql, args, err = insert.ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseTwoFactorStep records the step of an accepted code, older or equal steps are rejected as replays
+func (tr *TwoFactorRepository) UseTwoFactorStep(ctx context.Context, userID, step int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := tr.db.QueryBuilder.Update("user_two_factors").
+		Set("last_used_step", step).
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Lt{"last_used_step": step}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := tr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrInvalidTwoFactor
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used
+func (tr *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	sql, args, err := tr.db.QueryBuilder.Update("user_recovery_codes").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "code_hash": codeHash, "used_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	tag, err := tr.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+// DeleteTwoFactor deletes the second factor and recovery codes of a user
+func (tr *TwoFactorRepository) DeleteTwoFactor(ctx context.Context, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	tx, err := tr.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, table := range []string{"user_recovery_codes", "user_two_factors"} {
+		sql, args, err := tr.db.QueryBuilder.Delete(table).Where(sq.Eq{"user_id": userID}).ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
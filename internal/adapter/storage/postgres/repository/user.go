@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
@@ -20,7 +21,7 @@ func (ur *UserRepository) GetUserById(ctx context.Context, id int64) (*domain.Us
 
 	var user domain.User
 
-	query := ur.db.QueryBuilder.Select("id,name,email").From("users").Where(sq.Eq{"id": id})
+	query := ur.db.QueryBuilder.Select("id,name,email,role,token_version,version").From("users").Where(sq.Eq{"id": id}).Where(notDeleted)
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
@@ -29,6 +30,9 @@ func (ur *UserRepository) GetUserById(ctx context.Context, id int64) (*domain.Us
 		&user.ID,
 		&user.Name,
 		&user.Email,
+		&user.Role,
+		&user.TokenVersion,
+		&user.Version,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -53,13 +57,13 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*d
 
 	query := ur.db.QueryBuilder.Insert("users").
 		Columns("name", "email", "password").
-		Values(user.Name, user.Email, user.Password).Suffix("RETURNING id,name,email")
+		Values(user.Name, user.Email, user.Password).Suffix("RETURNING id,name,email,role,version")
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = ur.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email)
+	err = ur.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.Version)
 	if err != nil {
 		if errCode := ur.db.ErrorCode(err); errCode == "23505" {
 			return nil, domain.ErrConflictingData
@@ -74,7 +78,7 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*d
 func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
-	query := ur.db.QueryBuilder.Select("id,name,email,password").From("users").Where(sq.Eq{"email": email})
+	query := ur.db.QueryBuilder.Select("id,name,email,password,role,token_version,version").From("users").Where(sq.Eq{"email": email}).Where(notDeleted)
 
 	sql, args, err := query.ToSql()
 
@@ -82,7 +86,7 @@ func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*do
 		return nil, err
 	}
 	var user domain.User
-	err = ur.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email, &user.Password)
+	err = ur.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.TokenVersion, &user.Version)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, domain.ErrDataNotFound
@@ -93,11 +97,14 @@ func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*do
 	return &user, nil
 }
 
-// ListUsers lists all users from the database
-func (ur *UserRepository) ListUsers(ctx context.Context, skip, limit int64) ([]domain.User, error) {
+// ListUsers lists users from the database, soft-deleted users are only included when includeDeleted is set
+func (ur *UserRepository) ListUsers(ctx context.Context, skip, limit int64, includeDeleted bool) ([]domain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
-	query := ur.db.QueryBuilder.Select("id,name,email").From("users").OrderBy("id").Limit(uint64(limit))
+	query := ur.db.QueryBuilder.Select("id,name,email,role,version,deleted_at").From("users").OrderBy("id").Offset(uint64(skip)).Limit(uint64(limit))
+	if !includeDeleted {
+		query = query.Where(notDeleted)
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -109,9 +116,9 @@ func (ur *UserRepository) ListUsers(ctx context.Context, skip, limit int64) ([]d
 	}
 	defer rows.Close()
 	var usersList []domain.User
-	var user domain.User
 	for rows.Next() {
-		err := rows.Scan(&user.ID, &user.Name, &user.Email)
+		var user domain.User
+		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.Version, &user.DeletedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -120,26 +127,31 @@ func (ur *UserRepository) ListUsers(ctx context.Context, skip, limit int64) ([]d
 	return usersList, nil
 }
 
-// UpdateUser updates a user by ID in the database
+// UpdateUser updates a user's name by ID in the database, a non-zero version must match the stored one
 func (ur *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
 	query := ur.db.QueryBuilder.Update("users").
 		Set("name", user.Name).
-		Set("email", user.Email).
-		Set("password", user.Password).
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", sq.Expr("NOW()")).
 		Where(sq.Eq{"id": user.ID}).
-		Suffix("RETURNING id,name,email")
+		Where(notDeleted).
+		Suffix("RETURNING id,name,email,role,token_version,version")
+	if user.Version != 0 {
+		query = query.Where(sq.Eq{"version": user.Version})
+	}
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = ur.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email)
+	version := user.Version
+	err = ur.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.TokenVersion, &user.Version)
 	if err != nil {
-		if errCode := ur.db.ErrorCode(err); errCode == "23505" {
-			return nil, domain.ErrConflictingData
+		if err == pgx.ErrNoRows {
+			return nil, missingOrConflict(ctx, ur.db, "users", user.ID, version)
 		}
 		return nil, err
 	}
@@ -147,19 +159,109 @@ func (ur *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*d
 	return user, nil
 }
 
-// DeleteUser deletes a user by ID from the database
-func (ur *UserRepository) DeleteUser(ctx context.Context, id int64) error {
+// UpdatePassword updates a user's password and bumps the token version so existing tokens stop working
+func (ur *UserRepository) UpdatePassword(ctx context.Context, id int64, hashedPassword string) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
 	defer cancel()
 
-	query := ur.db.QueryBuilder.Delete("users").Where(sq.Eq{"id": id})
+	query := ur.db.QueryBuilder.Update("users").
+		Set("password", hashedPassword).
+		Set("token_version", sq.Expr("token_version + 1")).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Where(notDeleted).
+		Suffix("RETURNING id,name,email,role,token_version,version")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var user domain.User
+	err = ur.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.TokenVersion, &user.Version)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// DeleteUser soft-deletes a user and ends their sessions, the user is hard-deleted by PurgeUsers
+// once the retention window has passed. A non-zero version must match the stored one.
+func (ur *UserRepository) DeleteUser(ctx context.Context, id, version int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := ur.db.QueryBuilder.Update("users").
+		Set("deleted_at", sq.Expr("NOW()")).
+		Set("token_version", sq.Expr("token_version + 1")).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id}).
+		Where(notDeleted)
+	if version != 0 {
+		query = query.Where(sq.Eq{"version": version})
+	}
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return err
 	}
-	_, err = ur.db.Exec(ctx, sql, args...)
+	tag, err := ur.db.Exec(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return missingOrConflict(ctx, ur.db, "users", id, version)
+	}
 	return nil
 }
+
+// RestoreUser undoes the soft delete of a user
+func (ur *UserRepository) RestoreUser(ctx context.Context, id int64) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := ur.db.QueryBuilder.Update("users").
+		Set("deleted_at", nil).
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("RETURNING id,name,email,role,token_version,version")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var user domain.User
+	err = ur.db.QueryRow(ctx, sql, args...).Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.TokenVersion, &user.Version)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PurgeUsers hard-deletes the users soft-deleted before the given time that no order or erasure
+// request references, their remaining personal data is removed by the cascading foreign keys
+func (ur *UserRepository) PurgeUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := ur.db.QueryBuilder.Delete("users").
+		Where(sq.Lt{"deleted_at": deletedBefore}).
+		Where("NOT EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM erasure_requests WHERE erasure_requests.user_id = users.id OR erasure_requests.requested_by = users.id)")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	tag, err := ur.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
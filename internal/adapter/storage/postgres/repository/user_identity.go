@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+type UserIdentityRepository struct {
+	db *postgres.DB
+}
+
+func NewUserIdentityRepository(db *postgres.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{
+		db: db,
+	}
+}
+
+// GetUserIdentity gets the identity of a provider subject from the database
+func (ir *UserIdentityRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := ir.db.QueryBuilder.Select("id,user_id,provider,subject,email,created_at").
+		From("user_identities").
+		Where(sq.Eq{"provider": provider, "subject": subject})
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var identity domain.UserIdentity
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// CreateUserIdentity creates a new identity in the database
+func (ir *UserIdentityRepository) CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeOutDuration)
+	defer cancel()
+
+	query := ir.db.QueryBuilder.Insert("user_identities").
+		Columns("user_id", "provider", "subject", "email").
+		Values(identity.UserID, identity.Provider, identity.Subject, identity.Email).
+		Suffix("RETURNING id,created_at")
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	err = ir.db.QueryRow(ctx, sql, args...).Scan(&identity.ID, &identity.CreatedAt)
+	if err != nil {
+		if errCode := ir.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return identity, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/storage/postgres"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// missingOrConflict tells apart why a write conditioned on the row's version matched no row,
+// the row is either gone or was changed by someone else in the meantime
+func missingOrConflict(ctx context.Context, db *postgres.DB, table string, id, version int64) error {
+	if version == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	sql, args, err := db.QueryBuilder.Select("1").From(table).Where(sq.Eq{"id": id}).Where(notDeleted).ToSql()
+	if err != nil {
+		return err
+	}
+	var exists int
+	if err := db.QueryRow(ctx, sql, args...).Scan(&exists); err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrDataNotFound
+		}
+		return err
+	}
+	return domain.ErrVersionConflict
+}
@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginAttemptRepository keeps failed login attempts in redis so they are shared between instances
+type LoginAttemptRepository struct {
+	client *Redis
+}
+
+func NewLoginAttemptRepository(client *Redis) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		client: client,
+	}
+}
+
+func failuresKey(key string) string {
+	return "login:failures:" + key
+}
+
+func lockKey(key string) string {
+	return "login:lock:" + key
+}
+
+// GetLoginAttempts returns the failed attempts recorded for a key
+func (lr *LoginAttemptRepository) GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	var attempts domain.LoginAttempts
+
+	failures, err := lr.client.Get(ctx, failuresKey(key)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	attempts.Failures = failures
+
+	lockedUntil, err := lr.client.Get(ctx, lockKey(key)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	if lockedUntil > 0 {
+		attempts.LockedUntil = time.Unix(lockedUntil, 0)
+	}
+
+	return &attempts, nil
+}
+
+// RecordLoginFailure increments the failures of a key, the window starts with the first failure
+func (lr *LoginAttemptRepository) RecordLoginFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempts, error) {
+	failures, err := lr.client.Incr(ctx, failuresKey(key)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if failures == 1 {
+		if err := lr.client.Expire(ctx, failuresKey(key), window).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	attempts, err := lr.GetLoginAttempts(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	attempts.Failures = failures
+	return attempts, nil
+}
+
+// LockLogin locks a key until the given time and clears its failures
+func (lr *LoginAttemptRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	_, err := lr.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, lockKey(key), until.Unix(), time.Until(until))
+		pipe.Del(ctx, failuresKey(key))
+		return nil
+	})
+	return err
+}
+
+// ResetLoginAttempts clears the failures and lock of a key
+func (lr *LoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	return lr.client.Del(ctx, failuresKey(key), lockKey(key)).Err()
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// OIDCStateRepository keeps pending OpenID Connect logins in redis
+type OIDCStateRepository struct {
+	client *Redis
+}
+
+func NewOIDCStateRepository(client *Redis) *OIDCStateRepository {
+	return &OIDCStateRepository{
+		client: client,
+	}
+}
+
+func oidcStateKey(state string) string {
+	return "oidc:state:" + state
+}
+
+// SaveAuthRequest stores an authorization request until it expires
+func (or *OIDCStateRepository) SaveAuthRequest(ctx context.Context, request *domain.OIDCAuthRequest, ttl time.Duration) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return or.client.Set(ctx, oidcStateKey(request.State), data, ttl).Err()
+}
+
+// TakeAuthRequest returns and deletes the authorization request of a state
+func (or *OIDCStateRepository) TakeAuthRequest(ctx context.Context, state string) (*domain.OIDCAuthRequest, error) {
+	data, err := or.client.GetDel(ctx, oidcStateKey(state)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	var request domain.OIDCAuthRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
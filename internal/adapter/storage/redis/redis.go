@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/adapter/config"
+	"github.com/redis/go-redis/v9"
+)
+
+type Redis struct {
+	*redis.Client
+}
+
+func New(ctx context.Context, config *config.Redis) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Addr,
+		Password: config.Password,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	return &Redis{
+		client,
+	}, nil
+}
+
+func (r *Redis) Close() error {
+	return r.Client.Close()
+}
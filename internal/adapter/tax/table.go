@@ -0,0 +1,90 @@
+package tax
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// DefaultRates are the rates the table calculator starts with. Review them with your accountant
+// before going live, rates change.
+var DefaultRates = []domain.TaxRate{
+	{Country: "DE", Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0.07},
+	{Country: "DE", Class: domain.ProductClassEbook, Name: "VAT", Rate: 0.07},
+	{Country: "FR", Class: domain.ProductClassPrinted, Name: "TVA", Rate: 0.055},
+	{Country: "FR", Class: domain.ProductClassEbook, Name: "TVA", Rate: 0.055},
+	{Country: "NL", Class: domain.ProductClassPrinted, Name: "BTW", Rate: 0.09},
+	{Country: "NL", Class: domain.ProductClassEbook, Name: "BTW", Rate: 0.09},
+	{Country: "GB", Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0},
+	{Country: "GB", Class: domain.ProductClassEbook, Name: "VAT", Rate: 0},
+	{Country: "SA", Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0.15},
+	{Country: "SA", Class: domain.ProductClassEbook, Name: "VAT", Rate: 0.15},
+	{Country: "AE", Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0.05},
+	{Country: "AE", Class: domain.ProductClassEbook, Name: "VAT", Rate: 0.05},
+	{Country: "US", Region: "NY", Class: domain.ProductClassPrinted, Name: "Sales tax", Rate: 0.04},
+	{Country: "US", Region: "NY", Class: domain.ProductClassEbook, Name: "Sales tax", Rate: 0},
+}
+
+// TableCalculator taxes orders with a fixed table of rates. A rate for the region of the address
+// wins over a rate for its whole country, lines without a matching rate are not taxed.
+type TableCalculator struct {
+	rates []domain.TaxRate
+}
+
+func NewTableCalculator(rates []domain.TaxRate) *TableCalculator {
+	return &TableCalculator{
+		rates: rates,
+	}
+}
+
+// Calculate taxes every line of the request with the rate of its product class
+func (tc *TableCalculator) Calculate(ctx context.Context, request *domain.TaxRequest) (*domain.TaxResult, error) {
+	result := &domain.TaxResult{
+		Lines: []domain.TaxLine{},
+	}
+	for _, line := range request.Lines {
+		rate, ok := tc.lookup(request.Country, request.Region, line.Class)
+		if !ok {
+			continue
+		}
+
+		taxLine := domain.TaxLine{
+			Item:    line.Item,
+			BookID:  line.BookID,
+			Class:   line.Class,
+			Name:    rate.Name,
+			Rate:    rate.Rate,
+			Taxable: line.Amount,
+		}
+		if request.Mode == domain.TaxInclusive {
+			// The amount already contains the tax, only the net part is taxable
+			taxLine.Taxable = domain.RoundPrice(line.Amount / (1 + rate.Rate))
+			taxLine.Amount = domain.RoundPrice(line.Amount - taxLine.Taxable)
+		} else {
+			taxLine.Amount = domain.RoundPrice(line.Amount * rate.Rate)
+		}
+		result.Lines = append(result.Lines, taxLine)
+		result.Total += taxLine.Amount
+	}
+	result.Total = domain.RoundPrice(result.Total)
+	return result, nil
+}
+
+func (tc *TableCalculator) lookup(country, region string, class domain.ProductClass) (domain.TaxRate, bool) {
+	var countryRate *domain.TaxRate
+	for i, rate := range tc.rates {
+		if !strings.EqualFold(rate.Country, country) || rate.Class != class {
+			continue
+		}
+		if rate.Region == "" {
+			countryRate = &tc.rates[i]
+		} else if region != "" && strings.EqualFold(rate.Region, region) {
+			return rate, true
+		}
+	}
+	if countryRate == nil {
+		return domain.TaxRate{}, false
+	}
+	return *countryRate, true
+}
@@ -0,0 +1,142 @@
+package tax
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+func TestTableCalculatorCalculate(t *testing.T) {
+	rates := []domain.TaxRate{
+		{Country: "DE", Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0.07},
+		{Country: "DE", Class: domain.ProductClassEbook, Name: "VAT", Rate: 0.19},
+		{Country: "FR", Class: domain.ProductClassPrinted, Name: "TVA", Rate: 0.055},
+		// The region rate comes first to check it wins regardless of the order of the table
+		{Country: "US", Region: "NY", Class: domain.ProductClassPrinted, Name: "NY sales tax", Rate: 0.04},
+		{Country: "US", Class: domain.ProductClassPrinted, Name: "Sales tax", Rate: 0.06},
+		{Country: "CA", Region: "ON", Class: domain.ProductClassPrinted, Name: "HST", Rate: 0.13},
+	}
+	printed := func(item int, amount float64) domain.TaxableLine {
+		return domain.TaxableLine{Item: item, BookID: int64(item + 1), Class: domain.ProductClassPrinted, Amount: amount}
+	}
+	ebook := func(item int, amount float64) domain.TaxableLine {
+		return domain.TaxableLine{Item: item, BookID: int64(item + 1), Class: domain.ProductClassEbook, Amount: amount}
+	}
+
+	tests := []struct {
+		name      string
+		request   domain.TaxRequest
+		wantLines []domain.TaxLine
+		wantTotal float64
+	}{
+		{
+			name:    "exclusive amounts get the tax added",
+			request: domain.TaxRequest{Country: "DE", Mode: domain.TaxExclusive, Lines: []domain.TaxableLine{printed(0, 20), ebook(1, 10)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0.07, Taxable: 20, Amount: 1.4},
+				{Item: 1, BookID: 2, Class: domain.ProductClassEbook, Name: "VAT", Rate: 0.19, Taxable: 10, Amount: 1.9},
+			},
+			wantTotal: 3.3,
+		},
+		{
+			name:    "inclusive amounts have the tax extracted",
+			request: domain.TaxRequest{Country: "DE", Mode: domain.TaxInclusive, Lines: []domain.TaxableLine{printed(0, 10.70)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0.07, Taxable: 10, Amount: 0.7},
+			},
+			wantTotal: 0.7,
+		},
+		{
+			name:    "exclusive tax is rounded to cents",
+			request: domain.TaxRequest{Country: "DE", Mode: domain.TaxExclusive, Lines: []domain.TaxableLine{printed(0, 9.99)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0.07, Taxable: 9.99, Amount: 0.7},
+			},
+			wantTotal: 0.7,
+		},
+		{
+			name:    "inclusive net and tax are rounded to cents and add up to the amount",
+			request: domain.TaxRequest{Country: "DE", Mode: domain.TaxInclusive, Lines: []domain.TaxableLine{printed(0, 9.99)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "VAT", Rate: 0.07, Taxable: 9.34, Amount: 0.65},
+			},
+			wantTotal: 0.65,
+		},
+		{
+			name:    "total is the sum of the rounded lines",
+			request: domain.TaxRequest{Country: "FR", Lines: []domain.TaxableLine{printed(0, 1.15), printed(1, 1.15), printed(2, 1.15)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "TVA", Rate: 0.055, Taxable: 1.15, Amount: 0.06},
+				{Item: 1, BookID: 2, Class: domain.ProductClassPrinted, Name: "TVA", Rate: 0.055, Taxable: 1.15, Amount: 0.06},
+				{Item: 2, BookID: 3, Class: domain.ProductClassPrinted, Name: "TVA", Rate: 0.055, Taxable: 1.15, Amount: 0.06},
+			},
+			wantTotal: 0.18,
+		},
+		{
+			name:    "region rate wins over the country rate",
+			request: domain.TaxRequest{Country: "US", Region: "NY", Lines: []domain.TaxableLine{printed(0, 10)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "NY sales tax", Rate: 0.04, Taxable: 10, Amount: 0.4},
+			},
+			wantTotal: 0.4,
+		},
+		{
+			name:    "country and region are matched case-insensitively",
+			request: domain.TaxRequest{Country: "us", Region: "ny", Lines: []domain.TaxableLine{printed(0, 10)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "NY sales tax", Rate: 0.04, Taxable: 10, Amount: 0.4},
+			},
+			wantTotal: 0.4,
+		},
+		{
+			name:    "region without a rate falls back to the country rate",
+			request: domain.TaxRequest{Country: "US", Region: "OR", Lines: []domain.TaxableLine{printed(0, 10)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "Sales tax", Rate: 0.06, Taxable: 10, Amount: 0.6},
+			},
+			wantTotal: 0.6,
+		},
+		{
+			name:    "address without a region gets the country rate",
+			request: domain.TaxRequest{Country: "US", Lines: []domain.TaxableLine{printed(0, 10)}},
+			wantLines: []domain.TaxLine{
+				{Item: 0, BookID: 1, Class: domain.ProductClassPrinted, Name: "Sales tax", Rate: 0.06, Taxable: 10, Amount: 0.6},
+			},
+			wantTotal: 0.6,
+		},
+		{
+			name:      "region without a rate and no country rate is not taxed",
+			request:   domain.TaxRequest{Country: "CA", Region: "QC", Lines: []domain.TaxableLine{printed(0, 10)}},
+			wantLines: []domain.TaxLine{},
+		},
+		{
+			name:      "country without rates is not taxed",
+			request:   domain.TaxRequest{Country: "JP", Lines: []domain.TaxableLine{printed(0, 10)}},
+			wantLines: []domain.TaxLine{},
+		},
+		{
+			name:    "class without a rate is not taxed",
+			request: domain.TaxRequest{Country: "FR", Lines: []domain.TaxableLine{ebook(0, 10), printed(1, 10)}},
+			wantLines: []domain.TaxLine{
+				{Item: 1, BookID: 2, Class: domain.ProductClassPrinted, Name: "TVA", Rate: 0.055, Taxable: 10, Amount: 0.55},
+			},
+			wantTotal: 0.55,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTableCalculator(rates).Calculate(context.Background(), &tt.request)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Lines, tt.wantLines) {
+				t.Errorf("lines = %+v, want %+v", got.Lines, tt.wantLines)
+			}
+			if got.Total != tt.wantTotal {
+				t.Errorf("total = %v, want %v", got.Total, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Address is an entry in a user's address book
+type Address struct {
+	ID                int64
+	UserID            int64
+	Label             string
+	FullName          string
+	Line1             string
+	Line2             string
+	City              string
+	Region            string
+	PostalCode        string
+	Country           string
+	Phone             string
+	IsDefaultShipping bool
+	IsDefaultBilling  bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// AddressSnapshot is a copy of an address stored on an order, later edits
+// of the address book do not change it
+type AddressSnapshot struct {
+	FullName   string `json:"full_name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country"`
+	Phone      string `json:"phone,omitempty"`
+}
+
+// Snapshot returns the copy of the address to store on an order
+func (a *Address) Snapshot() *AddressSnapshot {
+	return &AddressSnapshot{
+		FullName:   a.FullName,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Phone:      a.Phone,
+	}
+}
+
+type addressRule struct {
+	postalCode     *regexp.Regexp
+	regionRequired bool
+}
+
+// addressRules are the country specific rules, countries without a rule only need the common fields
+var addressRules = map[string]addressRule{
+	"US": {regexp.MustCompile(`^\d{5}(-\d{4})?$`), true},
+	"CA": {regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`), true},
+	"AU": {regexp.MustCompile(`^\d{4}$`), true},
+	"IN": {regexp.MustCompile(`^\d{6}$`), true},
+	"GB": {regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`), false},
+	"DE": {regexp.MustCompile(`^\d{5}$`), false},
+	"FR": {regexp.MustCompile(`^\d{5}$`), false},
+	"NL": {regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`), false},
+	"JP": {regexp.MustCompile(`^\d{3}-?\d{4}$`), true},
+	"SA": {regexp.MustCompile(`^\d{5}(-\d{4})?$`), false},
+	"EG": {regexp.MustCompile(`^\d{5}$`), false},
+	"SD": {regexp.MustCompile(`^\d{5}$`), false},
+	"AE": {nil, false},
+}
+
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Normalize trims the fields and upper-cases the country and postal code
+func (a *Address) Normalize() {
+	a.Label = strings.TrimSpace(a.Label)
+	a.FullName = strings.TrimSpace(a.FullName)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+	a.City = strings.TrimSpace(a.City)
+	a.Region = strings.TrimSpace(a.Region)
+	a.PostalCode = strings.ToUpper(strings.TrimSpace(a.PostalCode))
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	a.Phone = strings.TrimSpace(a.Phone)
+}
+
+// Validate checks the address against the rules of its country
+func (a *Address) Validate() error {
+	if !countryCode.MatchString(a.Country) {
+		return fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidAddress)
+	}
+	if a.FullName == "" || a.Line1 == "" || a.City == "" {
+		return fmt.Errorf("%w: full name, line1 and city are required", ErrInvalidAddress)
+	}
+
+	rule, ok := addressRules[a.Country]
+	if !ok {
+		return nil
+	}
+	if rule.regionRequired && a.Region == "" {
+		return fmt.Errorf("%w: region is required for %s", ErrInvalidAddress, a.Country)
+	}
+	if rule.postalCode != nil && !rule.postalCode.MatchString(a.PostalCode) {
+		return fmt.Errorf("%w: postal code is not valid for %s", ErrInvalidAddress, a.Country)
+	}
+	return nil
+}
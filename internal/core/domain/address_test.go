@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddressValidate(t *testing.T) {
+	address := func(country, region, postalCode string) Address {
+		return Address{FullName: "Paul Atreides", Line1: "1 Arrakeen Way", City: "Arrakeen", Country: country, Region: region, PostalCode: postalCode}
+	}
+
+	tests := []struct {
+		name    string
+		address Address
+		wantErr error
+	}{
+		{"US zip code", address("US", "CA", "94103"), nil},
+		{"US zip+4 code", address("US", "CA", "94103-1234"), nil},
+		{"US short zip code", address("US", "CA", "9410"), ErrInvalidAddress},
+		{"US without a region", address("US", "", "94103"), ErrInvalidAddress},
+		{"CA postal code", address("CA", "ON", "K1A 0B1"), nil},
+		{"CA postal code without a space", address("CA", "ON", "K1A0B1"), nil},
+		{"CA digits only", address("CA", "ON", "12345"), ErrInvalidAddress},
+		{"GB postcode", address("GB", "", "SW1A 1AA"), nil},
+		{"GB short postcode", address("GB", "", "M1 1AE"), nil},
+		{"GB invalid postcode", address("GB", "", "12345"), ErrInvalidAddress},
+		{"NL postcode", address("NL", "", "1012 AB"), nil},
+		{"NL postcode without letters", address("NL", "", "1012"), ErrInvalidAddress},
+		{"JP postal code", address("JP", "Tokyo", "100-0001"), nil},
+		{"JP without a region", address("JP", "", "100-0001"), ErrInvalidAddress},
+		{"DE missing postal code", address("DE", "", ""), ErrInvalidAddress},
+		{"AE without a postal code", address("AE", "", ""), nil},
+		{"unknown country only needs the common fields", address("ZZ", "", ""), nil},
+		{"lower-case country", address("us", "CA", "94103"), ErrInvalidAddress},
+		{"country name", address("USA", "CA", "94103"), ErrInvalidAddress},
+		{"missing country", address("", "", ""), ErrInvalidAddress},
+		{"missing full name", Address{Line1: "1 Arrakeen Way", City: "Arrakeen", Country: "DE", PostalCode: "10115"}, ErrInvalidAddress},
+		{"missing line1", Address{FullName: "Paul Atreides", City: "Arrakeen", Country: "DE", PostalCode: "10115"}, ErrInvalidAddress},
+		{"missing city", Address{FullName: "Paul Atreides", Line1: "1 Arrakeen Way", Country: "DE", PostalCode: "10115"}, ErrInvalidAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.address.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddressNormalizeBeforeValidate(t *testing.T) {
+	address := Address{FullName: " Paul Atreides ", Line1: " 1 Arrakeen Way", City: "Arrakeen ", Country: " ca", Region: " ON ", PostalCode: " k1a 0b1 "}
+	address.Normalize()
+	if address.Country != "CA" || address.PostalCode != "K1A 0B1" || address.Region != "ON" || address.FullName != "Paul Atreides" {
+		t.Errorf("Normalize() = %+v", address)
+	}
+	if err := address.Validate(); err != nil {
+		t.Errorf("Validate() of the normalized address error = %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// APIKeyScope is a permission granted to an API key
+type APIKeyScope string
+
+const (
+	ScopeBooksRead   APIKeyScope = "books:read"
+	ScopeBooksWrite  APIKeyScope = "books:write"
+	ScopeOrdersRead  APIKeyScope = "orders:read"
+	ScopeOrdersWrite APIKeyScope = "orders:write"
+	ScopeUsersRead   APIKeyScope = "users:read"
+)
+
+// APIKeyScopes are all scopes an API key can be granted
+var APIKeyScopes = []APIKeyScope{
+	ScopeBooksRead,
+	ScopeBooksWrite,
+	ScopeOrdersRead,
+	ScopeOrdersWrite,
+	ScopeUsersRead,
+}
+
+// APIKey is a credential owned by a user for machine clients. The key is
+// "<prefix>.<secret>", the prefix identifies the key and only the hash of the secret is stored.
+type APIKey struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Prefix     string
+	SecretHash string
+	Scopes     []APIKeyScope
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// IsActive reports whether the key is neither revoked nor expired
+func (ak *APIKey) IsActive(now time.Time) bool {
+	if ak.RevokedAt != nil {
+		return false
+	}
+	return ak.ExpiresAt == nil || now.Before(*ak.ExpiresAt)
+}
+
+// HasScope reports whether the key was granted the scope
+func (ak *APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(ak.Scopes, scope)
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Audited entity types
+const (
+	AuditEntityBook        = "book"
+	AuditEntityUser        = "user"
+	AuditEntityAddress     = "address"
+	AuditEntityOrder       = "order"
+	AuditEntityAPIKey      = "api_key"
+	AuditEntityTwoFactor   = "two_factor"
+	AuditEntityCategory    = "category"
+	AuditEntityCoupon      = "coupon"
+	AuditEntityPromotion   = "promotion"
+	AuditEntityShipping    = "shipping"
+	AuditEntityFulfillment = "fulfillment"
+	AuditEntityPublisher   = "publisher"
+	AuditEntitySeries      = "series"
+)
+
+// Audited actions, named <entity>.<verb>
+const (
+	AuditBookCreate                = "book.create"
+	AuditBookUpdate                = "book.update"
+	AuditBookDelete                = "book.delete"
+	AuditBookRestore               = "book.restore"
+	AuditBookPriceSchedule         = "book.price_schedule"
+	AuditBookPriceScheduleCancel   = "book.price_schedule_cancel"
+	AuditBookPriceApply            = "book.price_apply"
+	AuditBookPriceRevert           = "book.price_revert"
+	AuditUserRegister              = "user.register"
+	AuditUserUpdate                = "user.update"
+	AuditUserEmailChange           = "user.email_change"
+	AuditUserPasswordChange        = "user.password_change"
+	AuditUserPasswordReset         = "user.password_reset"
+	AuditUserUnlock                = "user.unlock"
+	AuditUserIdentityLink          = "user.identity_link"
+	AuditUserDelete                = "user.delete"
+	AuditUserRestore               = "user.restore"
+	AuditUserErasureRequest        = "user.erasure_request"
+	AuditUserErasureCancel         = "user.erasure_cancel"
+	AuditUserErase                 = "user.erase"
+	AuditAddressCreate             = "address.create"
+	AuditAddressUpdate             = "address.update"
+	AuditAddressDelete             = "address.delete"
+	AuditOrderCreate               = "order.create"
+	AuditOrderPay                  = "order.pay"
+	AuditOrderInvoice              = "order.invoice"
+	AuditAPIKeyCreate              = "api_key.create"
+	AuditAPIKeyRevoke              = "api_key.revoke"
+	AuditTwoFactorEnable           = "two_factor.enable"
+	AuditTwoFactorDisable          = "two_factor.disable"
+	AuditCategoryCreate            = "category.create"
+	AuditCouponCreate              = "coupon.create"
+	AuditCouponDeactivate          = "coupon.deactivate"
+	AuditPromotionCreate           = "promotion.create"
+	AuditPromotionDeactivate       = "promotion.deactivate"
+	AuditShippingZoneCreate        = "shipping.zone_create"
+	AuditShippingMethodCreate      = "shipping.method_create"
+	AuditShippingMethodDisable     = "shipping.method_disable"
+	AuditFulfillmentCreate         = "fulfillment.create"
+	AuditFulfillmentStatus         = "fulfillment.status"
+	AuditFulfillmentShip           = "fulfillment.ship"
+	AuditBookEditionCreate         = "book.edition_create"
+	AuditBookEditionUpdate         = "book.edition_update"
+	AuditBookEditionUpload         = "book.edition_upload"
+	AuditPublisherCreate           = "publisher.create"
+	AuditPublisherUpdate           = "publisher.update"
+	AuditPublisherDelete           = "publisher.delete"
+	AuditSeriesCreate              = "series.create"
+	AuditSeriesUpdate              = "series.update"
+	AuditSeriesDelete              = "series.delete"
+	AuditBookTranslate             = "book.translate"
+	AuditBookTranslationDelete     = "book.translation_delete"
+	AuditCategoryTranslate         = "category.translate"
+	AuditCategoryTranslationDelete = "category.translation_delete"
+)
+
+// AuditChange is the value of a field before and after a change, nil when the field did not exist
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEntry records a change made to an entity, entries are never updated or deleted
+type AuditEntry struct {
+	ID         int64
+	ActorID    *int64
+	Action     string
+	EntityType string
+	EntityID   int64
+	Changes    map[string]AuditChange
+	RequestID  string
+	IP         string
+	CreatedAt  time.Time
+}
+
+// AuditFilter narrows down a listing of the audit log, zero fields do not filter
+type AuditFilter struct {
+	ActorID    int64
+	Action     string
+	EntityType string
+	EntityID   int64
+	From       time.Time
+	To         time.Time
+	Skip       int64
+	Limit      int64
+}
+
+// AuditMeta describes who made a change and through which request, changes made by
+// background jobs have no actor
+type AuditMeta struct {
+	ActorID   int64
+	RequestID string
+	IP        string
+}
+
+type auditMetaKey struct{}
+
+// WithAuditMeta returns a context carrying the audit metadata of the current request
+func WithAuditMeta(ctx context.Context, meta AuditMeta) context.Context {
+	return context.WithValue(ctx, auditMetaKey{}, meta)
+}
+
+// AuditMetaFrom returns the audit metadata stored in the context, or the zero value
+func AuditMetaFrom(ctx context.Context) AuditMeta {
+	meta, _ := ctx.Value(auditMetaKey{}).(AuditMeta)
+	return meta
+}
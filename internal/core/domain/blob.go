@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// BlobInfo describes a stored blob
+type BlobInfo struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
@@ -1,10 +1,66 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Book is the work, sold through its editions which carry the price, stock and weight. Author is
+// the authors joined for display, SeriesVolume the place of the book in the reading order of its
+// series and Locale the locale its name was localized to.
 type Book struct {
-	ID          int64
-	Name        string
-	Description string
-	Author      string
-	Price       float64
-	Cover       string
+	ID           int64
+	Name         string
+	Description  string
+	Author       string
+	Authors      []string
+	Cover        string
+	CategoryID   *int64
+	PublisherID  *int64
+	SeriesID     *int64
+	SeriesVolume *int
+	Version      int64
+	DeletedAt    *time.Time
+	Editions     []Edition
+	Locale       string
+}
+
+// NormalizeAuthors trims the authors of the book and sets Author from them, a book given only an
+// Author has it as its single author
+func (b *Book) NormalizeAuthors() {
+	var authors []string
+	for _, author := range b.Authors {
+		if author = strings.TrimSpace(author); author != "" {
+			authors = append(authors, author)
+		}
+	}
+	if len(authors) == 0 && strings.TrimSpace(b.Author) != "" {
+		authors = []string{strings.TrimSpace(b.Author)}
+	}
+	b.Authors = authors
+	b.Author = strings.Join(authors, ", ")
+}
+
+// ValidateSeries checks that a volume number is only given for a book in a series
+func (b *Book) ValidateSeries() error {
+	if b.SeriesVolume != nil && b.SeriesID == nil {
+		return fmt.Errorf("%w: a volume number needs a series", ErrInvalidSeries)
+	}
+	if b.SeriesVolume != nil && *b.SeriesVolume <= 0 {
+		return fmt.Errorf("%w: volume numbers start at 1", ErrInvalidSeries)
+	}
+	return nil
+}
+
+// BookSearch narrows down a book search, zero fields do not filter. Books match when one of their
+// editions passes the edition filter and only those editions are returned with them.
+type BookSearch struct {
+	Text        string
+	CategoryID  int64
+	PublisherID int64
+	SeriesID    int64
+	EditionFilter
+	Skip  int64
+	Limit int64
 }
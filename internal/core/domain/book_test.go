@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeAuthors(t *testing.T) {
+	tests := []struct {
+		name        string
+		book        Book
+		wantAuthors []string
+		wantAuthor  string
+	}{
+		{"single author", Book{Author: " Frank Herbert "}, []string{"Frank Herbert"}, "Frank Herbert"},
+		{"authors win over author", Book{Author: "Someone", Authors: []string{"Terry Pratchett", "Neil Gaiman"}}, []string{"Terry Pratchett", "Neil Gaiman"}, "Terry Pratchett, Neil Gaiman"},
+		{"blank authors are dropped", Book{Authors: []string{" Ursula K. Le Guin ", "", "  "}}, []string{"Ursula K. Le Guin"}, "Ursula K. Le Guin"},
+		{"only blank authors fall back to author", Book{Author: "Iain Banks", Authors: []string{" "}}, []string{"Iain Banks"}, "Iain Banks"},
+		{"no author", Book{}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.book.NormalizeAuthors()
+			if !slices.Equal(tt.book.Authors, tt.wantAuthors) || tt.book.Author != tt.wantAuthor {
+				t.Errorf("authors, author = %q, %q, want %q, %q", tt.book.Authors, tt.book.Author, tt.wantAuthors, tt.wantAuthor)
+			}
+		})
+	}
+}
+
+func TestValidateSeries(t *testing.T) {
+	series := int64(1)
+	volume := func(v int) *int { return &v }
+
+	tests := []struct {
+		name    string
+		book    Book
+		wantErr error
+	}{
+		{"not in a series", Book{}, nil},
+		{"series without a volume", Book{SeriesID: &series}, nil},
+		{"volume of a series", Book{SeriesID: &series, SeriesVolume: volume(3)}, nil},
+		{"volume without a series", Book{SeriesVolume: volume(3)}, ErrInvalidSeries},
+		{"volume zero", Book{SeriesID: &series, SeriesVolume: volume(0)}, ErrInvalidSeries},
+		{"negative volume", Book{SeriesID: &series, SeriesVolume: volume(-1)}, ErrInvalidSeries},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.book.ValidateSeries(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateSeries() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Category groups books, promotions can target all books of a category
+type Category struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+	// Locale is the locale the name was localized to
+	Locale string
+}
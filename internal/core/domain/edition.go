@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FormatType is the kind of product an edition is sold as
+type FormatType string
+
+const (
+	FormatHardcover FormatType = "hardcover"
+	FormatPaperback FormatType = "paperback"
+	FormatEbook     FormatType = "ebook"
+	FormatAudiobook FormatType = "audiobook"
+)
+
+// IsDigital reports whether the format is a file that is downloaded instead of shipped
+func (ft FormatType) IsDigital() bool {
+	return ft == FormatEbook || ft == FormatAudiobook
+}
+
+// Class is the product class the format is taxed and shipped as
+func (ft FormatType) Class() ProductClass {
+	if ft.IsDigital() {
+		return ProductClassEbook
+	}
+	return ProductClassPrinted
+}
+
+// digitalContentTypes are the files that can be sold for each digital format
+var digitalContentTypes = map[FormatType][]string{
+	FormatEbook:     {"application/epub+zip", "application/pdf"},
+	FormatAudiobook: {"audio/mpeg", "audio/mp4", "application/zip"},
+}
+
+// Edition is a sellable edition of a book, the book being the work. Every edition has its own
+// ISBN, format, language, price and stock. Physical editions have a stock, digital editions have
+// none and a file instead.
+type Edition struct {
+	ID            int64
+	BookID        int64
+	ISBN          string
+	Format        FormatType
+	Language      string
+	EditionNumber int
+	PageCount     int
+	PublishedOn   *time.Time
+	Price         float64
+	Stock         *int
+	WeightGrams   int
+	FileKey       string
+	FileName      string
+	ContentType   string
+	FileSize      int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Validate normalizes the ISBN and language of the edition and checks that the stock and weight
+// fit its format
+func (e *Edition) Validate() error {
+	if e.ISBN != "" {
+		isbn, err := NormalizeISBN(e.ISBN)
+		if err != nil {
+			return err
+		}
+		e.ISBN = isbn
+	}
+	e.Language = strings.ToLower(strings.TrimSpace(e.Language))
+	if e.EditionNumber == 0 {
+		e.EditionNumber = 1
+	}
+	if e.Price <= 0 {
+		return fmt.Errorf("%w: the price must be positive", ErrInvalidEdition)
+	}
+	if e.Format.IsDigital() {
+		if e.Stock != nil || e.WeightGrams != 0 {
+			return fmt.Errorf("%w: %s has no stock and no weight", ErrInvalidEdition, e.Format)
+		}
+		return nil
+	}
+	if e.Stock == nil || *e.Stock < 0 {
+		return fmt.Errorf("%w: %s needs a stock of zero or more", ErrInvalidEdition, e.Format)
+	}
+	return nil
+}
+
+// AcceptsFile reports whether a file of the content type can be sold in the edition's format
+func (e *Edition) AcceptsFile(contentType string) bool {
+	return slices.Contains(digitalContentTypes[e.Format], contentType)
+}
+
+// EditionFileKey is where the file of a digital edition is stored
+func EditionFileKey(bookID, editionID int64) string {
+	return fmt.Sprintf("books/%d/editions/%d", bookID, editionID)
+}
+
+// NormalizeISBN strips the hyphens and spaces of an ISBN-10 or ISBN-13 and checks its check digit
+func NormalizeISBN(isbn string) (string, error) {
+	isbn = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+	invalid := fmt.Errorf("%w: %q is not a valid ISBN", ErrInvalidEdition, isbn)
+
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, r := range isbn {
+			digit := int(r - '0')
+			switch {
+			case r == 'X' && i == 9:
+				digit = 10
+			case r < '0' || r > '9':
+				return "", invalid
+			}
+			sum += digit * (10 - i)
+		}
+		if sum%11 != 0 {
+			return "", invalid
+		}
+	case 13:
+		sum := 0
+		for i, r := range isbn {
+			if r < '0' || r > '9' {
+				return "", invalid
+			}
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += int(r-'0') * weight
+		}
+		if sum%10 != 0 {
+			return "", invalid
+		}
+	default:
+		return "", invalid
+	}
+	return isbn, nil
+}
+
+// EditionFilter narrows the editions a book search returns, zero fields match everything
+type EditionFilter struct {
+	Format   FormatType
+	Language string
+	ISBN     string
+}
+
+// Matches reports whether the edition passes the filter
+func (ef *EditionFilter) Matches(edition *Edition) bool {
+	return (ef.Format == "" || edition.Format == ef.Format) &&
+		(ef.Language == "" || edition.Language == ef.Language) &&
+		(ef.ISBN == "" || edition.ISBN == ef.ISBN)
+}
+
+// DownloadGrant lets the buyer of a digital item download its file a limited number of times
+type DownloadGrant struct {
+	ID           int64
+	OrderID      int64
+	OrderItemID  int64
+	UserID       int64
+	EditionID    int64
+	MaxDownloads int
+	Downloads    int
+	CreatedAt    time.Time
+}
+
+// Remaining is how many downloads are left
+func (dg *DownloadGrant) Remaining() int {
+	return max(dg.MaxDownloads-dg.Downloads, 0)
+}
+
+// DownloadLink is a signed URL for downloading the file of a grant until it expires
+type DownloadLink struct {
+	GrantID   int64
+	URL       string
+	ExpiresAt time.Time
+}
+
+// DownloadRequest asks for the file of a grant with the expiry and signature of a download link,
+// or with the resume token of a download that was already counted
+type DownloadRequest struct {
+	GrantID   int64
+	Expires   int64
+	Signature string
+	Resume    string
+	// Continues is set for range requests that do not start at the first byte of the file
+	Continues bool
+}
+
+// Download is an opened download. ResumeURL continues it with range requests without counting it
+// again until ResumeExpiresAt, it is empty for downloads that were resumed.
+type Download struct {
+	Edition         *Edition
+	ResumeURL       string
+	ResumeExpiresAt time.Time
+}
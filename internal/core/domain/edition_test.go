@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEditionFilterMatches(t *testing.T) {
+	edition := &Edition{Format: FormatEbook, Language: "de", ISBN: "9780306406157"}
+
+	tests := []struct {
+		name   string
+		filter EditionFilter
+		want   bool
+	}{
+		{"no filter", EditionFilter{}, true},
+		{"format", EditionFilter{Format: FormatEbook}, true},
+		{"other format", EditionFilter{Format: FormatHardcover}, false},
+		{"language", EditionFilter{Language: "de"}, true},
+		{"other language", EditionFilter{Language: "en"}, false},
+		{"isbn", EditionFilter{ISBN: "9780306406157"}, true},
+		{"other isbn", EditionFilter{ISBN: "9781861972712"}, false},
+		{"every field", EditionFilter{Format: FormatEbook, Language: "de", ISBN: "9780306406157"}, true},
+		{"one field off", EditionFilter{Format: FormatEbook, Language: "en", ISBN: "9780306406157"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(edition); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		isbn    string
+		want    string
+		wantErr bool
+	}{
+		{"978-0-306-40615-7", "9780306406157", false},
+		{"978 0 306 40615 7", "9780306406157", false},
+		{"0-306-40615-2", "0306406152", false},
+		{"0-8044-2957-x", "080442957X", false},
+		{"978-0-306-40615-8", "", true},
+		{"0-306-40615-3", "", true},
+		{"X-306-40615-2", "", true},
+		{"dune", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.isbn, func(t *testing.T) {
+			got, err := NormalizeISBN(tt.isbn)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("NormalizeISBN() = %q, %v, want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidEdition) {
+				t.Errorf("NormalizeISBN() error = %v, want %v", err, ErrInvalidEdition)
+			}
+		})
+	}
+}
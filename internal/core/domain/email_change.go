@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// EmailChange is a pending change of a user's email, applied once the new address is verified
+type EmailChange struct {
+	ID        int64
+	UserID    int64
+	NewEmail  string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// IsUsable reports whether the verification token can still be redeemed
+func (ec *EmailChange) IsUsable(now time.Time) bool {
+	return ec.UsedAt == nil && now.Before(ec.ExpiresAt)
+}
+
+// UserPatch is a partial update of a user, nil fields are left unchanged.
+// A non-zero Version must match the user's current version.
+type UserPatch struct {
+	Name    *string
+	Email   *string
+	Version int64
+}
+
+// UserUpdate is the result of updating a user, an email change is only pending until verified
+type UserUpdate struct {
+	User         *User
+	PendingEmail string
+}
@@ -1,11 +1,97 @@
 package domain
 
-import "errors"
+// ErrorKind is the class of failure an error belongs to, adapters map it to their own status codes
+type ErrorKind string
+
+const (
+	KindInvalid            ErrorKind = "invalid"
+	KindUnauthorized       ErrorKind = "unauthorized"
+	KindForbidden          ErrorKind = "forbidden"
+	KindNotFound           ErrorKind = "not_found"
+	KindConflict           ErrorKind = "conflict"
+	KindPreconditionFailed ErrorKind = "precondition_failed"
+	// KindPreconditionRequired is a change that has to name the version it is based on
+	KindPreconditionRequired ErrorKind = "precondition_required"
+	KindGone                 ErrorKind = "gone"
+	KindLocked               ErrorKind = "locked"
+	KindRateLimited          ErrorKind = "rate_limited"
+	KindInternal             ErrorKind = "internal"
+)
+
+// Error is a failure of the domain. The code identifies the error for clients and stays the same
+// when the message is reworded or wrapped with more detail.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(kind ErrorKind, code, message string) error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// NewError returns an error of the kind with a code and message of its own, for failures that only
+// an adapter knows about
+func NewError(kind ErrorKind, code, message string) error {
+	return newError(kind, code, message)
+}
 
 var (
-	ErrDataNotFound       = errors.New("data not found")
-	ErrConflictingData    = errors.New("data conflicts with existing data in unique column")
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrInternal           = errors.New("internal error")
-	ErrTokenCreation      = errors.New("error creating token")
+	ErrDataNotFound          = newError(KindNotFound, "not_found", "data not found")
+	ErrConflictingData       = newError(KindConflict, "conflicting_data", "data conflicts with existing data in unique column")
+	ErrEmailTaken            = newError(KindConflict, "email_taken", "email used before")
+	ErrInvalidCredentials    = newError(KindUnauthorized, "invalid_credentials", "invalid email or password")
+	ErrInternal              = newError(KindInternal, "internal", "internal error")
+	ErrTokenCreation         = newError(KindInternal, "token_creation", "error creating token")
+	ErrInvalidToken          = newError(KindUnauthorized, "invalid_token", "invalid or expired token")
+	ErrInvalidResetToken     = newError(KindInvalid, "invalid_reset_token", "invalid or expired password reset token")
+	ErrAccountLocked         = newError(KindLocked, "account_locked", "account is temporarily locked due to too many failed login attempts")
+	ErrTooManyAttempts       = newError(KindRateLimited, "too_many_attempts", "too many failed login attempts, try again later")
+	ErrForbidden             = newError(KindForbidden, "forbidden", "user is not allowed to perform this action")
+	ErrInvalidTwoFactor      = newError(KindInvalid, "invalid_two_factor", "invalid two-factor authentication code")
+	ErrTwoFactorEnabled      = newError(KindConflict, "two_factor_enabled", "two-factor authentication is already enabled")
+	ErrTwoFactorDisabled     = newError(KindInvalid, "two_factor_disabled", "two-factor authentication is not enabled")
+	ErrTwoFactorRequired     = newError(KindForbidden, "two_factor_required", "two-factor authentication is required for this action")
+	ErrUnknownProvider       = newError(KindNotFound, "unknown_provider", "unknown identity provider")
+	ErrInvalidOIDCState      = newError(KindUnauthorized, "invalid_oidc_state", "invalid or expired login state")
+	ErrOIDCExchange          = newError(KindUnauthorized, "oidc_exchange", "identity provider rejected the login")
+	ErrUnverifiedEmail       = newError(KindUnauthorized, "unverified_email", "identity provider did not verify the email address")
+	ErrInvalidAPIKey         = newError(KindUnauthorized, "invalid_api_key", "invalid, expired or revoked api key")
+	ErrInvalidScope          = newError(KindInvalid, "invalid_scope", "invalid api key scope")
+	ErrMissingScope          = newError(KindForbidden, "missing_scope", "api key is missing the required scope")
+	ErrAPIKeyNotAllowed      = newError(KindForbidden, "api_key_not_allowed", "api keys cannot be used for this endpoint")
+	ErrInvalidEmailToken     = newError(KindInvalid, "invalid_email_token", "invalid or expired email verification token")
+	ErrInvalidAddress        = newError(KindInvalid, "invalid_address", "invalid address")
+	ErrNoShippingAddress     = newError(KindInvalid, "no_shipping_address", "no shipping address given and no default shipping address set")
+	ErrErasurePending        = newError(KindConflict, "erasure_pending", "an erasure of this account is already scheduled")
+	ErrVersionConflict       = newError(KindPreconditionFailed, "version_conflict", "the resource was changed by someone else, reload it and try again")
+	ErrInvalidPriceSchedule  = newError(KindInvalid, "invalid_price_schedule", "invalid price schedule")
+	ErrPriceScheduleOverlap  = newError(KindConflict, "price_schedule_overlap", "the price schedule overlaps another scheduled price of the edition")
+	ErrUnknownCategory       = newError(KindInvalid, "unknown_category", "category does not exist")
+	ErrEmptyOrder            = newError(KindInvalid, "empty_order", "an order needs at least one item")
+	ErrInvalidCoupon         = newError(KindInvalid, "invalid_coupon", "invalid coupon")
+	ErrInvalidPromotion      = newError(KindInvalid, "invalid_promotion", "invalid promotion")
+	ErrCouponNotApplicable   = newError(KindInvalid, "coupon_not_applicable", "the coupon cannot be applied to this order")
+	ErrCouponExhausted       = newError(KindConflict, "coupon_exhausted", "the coupon has reached its usage limit")
+	ErrInvalidShippingZone   = newError(KindInvalid, "invalid_shipping_zone", "invalid shipping zone")
+	ErrInvalidShippingMethod = newError(KindInvalid, "invalid_shipping_method", "invalid shipping method")
+	ErrNoShippingMethod      = newError(KindInvalid, "no_shipping_method", "the shipping method is not available for this order")
+	ErrInvalidFulfillment    = newError(KindInvalid, "invalid_fulfillment", "invalid fulfillment")
+	ErrFulfillmentStatus     = newError(KindConflict, "fulfillment_status", "the fulfillment cannot change to this status")
+	ErrUnknownTracking       = newError(KindNotFound, "unknown_tracking", "the carrier does not know this tracking number")
+	ErrInvalidEdition        = newError(KindInvalid, "invalid_edition", "invalid edition")
+	ErrOutOfStock            = newError(KindConflict, "out_of_stock", "not enough stock left for this order")
+	ErrNoDigitalFile         = newError(KindNotFound, "no_digital_file", "the file of this edition has not been uploaded yet")
+	ErrDownloadLimit         = newError(KindGone, "download_limit", "the download limit of this purchase has been reached")
+	ErrInvalidDownloadLink   = newError(KindForbidden, "invalid_download_link", "invalid or expired download link")
+	ErrUnknownPublisher      = newError(KindInvalid, "unknown_publisher", "publisher does not exist")
+	ErrUnknownSeries         = newError(KindInvalid, "unknown_series", "series does not exist")
+	ErrInvalidSeries         = newError(KindInvalid, "invalid_series", "invalid series")
+	ErrInvalidTranslation    = newError(KindInvalid, "invalid_translation", "invalid translation")
+	ErrOrderNotPaid          = newError(KindConflict, "order_not_paid", "the order has not been paid yet")
+	ErrOrderAlreadyPaid      = newError(KindConflict, "order_already_paid", "the payment of this order has already been recorded")
 )
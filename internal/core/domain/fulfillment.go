@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// OrderStatus is how far an order got, it follows the fulfillments of the order
+type OrderStatus string
+
+const (
+	OrderPlaced           OrderStatus = "placed"
+	OrderProcessing       OrderStatus = "processing"
+	OrderPartiallyShipped OrderStatus = "partially_shipped"
+	OrderShipped          OrderStatus = "shipped"
+	OrderDelivered        OrderStatus = "delivered"
+)
+
+// FulfillmentStatus is the state of a shipment, from picking in the warehouse to delivery
+type FulfillmentStatus string
+
+const (
+	FulfillmentPending   FulfillmentStatus = "pending"
+	FulfillmentPicked    FulfillmentStatus = "picked"
+	FulfillmentPacked    FulfillmentStatus = "packed"
+	FulfillmentShipped   FulfillmentStatus = "shipped"
+	FulfillmentInTransit FulfillmentStatus = "in_transit"
+	// FulfillmentException is reported by the carrier when a parcel is held up, it may still arrive
+	FulfillmentException FulfillmentStatus = "exception"
+	FulfillmentDelivered FulfillmentStatus = "delivered"
+	FulfillmentCancelled FulfillmentStatus = "cancelled"
+)
+
+var fulfillmentTransitions = map[FulfillmentStatus][]FulfillmentStatus{
+	FulfillmentPending:   {FulfillmentPicked, FulfillmentCancelled},
+	FulfillmentPicked:    {FulfillmentPacked, FulfillmentCancelled},
+	FulfillmentPacked:    {FulfillmentShipped, FulfillmentCancelled},
+	FulfillmentShipped:   {FulfillmentInTransit, FulfillmentException, FulfillmentDelivered},
+	FulfillmentInTransit: {FulfillmentException, FulfillmentDelivered},
+	FulfillmentException: {FulfillmentInTransit, FulfillmentDelivered},
+}
+
+// CanTransition reports whether a fulfillment may go from status s to next
+func (s FulfillmentStatus) CanTransition(next FulfillmentStatus) bool {
+	return slices.Contains(fulfillmentTransitions[s], next)
+}
+
+// HasShipped reports whether the parcel has left the warehouse
+func (s FulfillmentStatus) HasShipped() bool {
+	switch s {
+	case FulfillmentShipped, FulfillmentInTransit, FulfillmentException, FulfillmentDelivered:
+		return true
+	}
+	return false
+}
+
+// FulfillmentItem is the quantity of an order item that goes into a shipment
+type FulfillmentItem struct {
+	OrderItemID int64
+	BookID      int64
+	Quantity    int
+}
+
+// Fulfillment is one shipment of an order, an order can be split over several of them. Only
+// printed items are fulfilled.
+type Fulfillment struct {
+	ID             int64
+	OrderID        int64
+	Status         FulfillmentStatus
+	Carrier        string
+	TrackingNumber string
+	Items          []FulfillmentItem
+	ShippedAt      *time.Time
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TrackingEvent is a step of a shipment reported by the carrier or recorded by staff
+type TrackingEvent struct {
+	ID            int64
+	FulfillmentID int64
+	Status        FulfillmentStatus
+	Description   string
+	Location      string
+	OccurredAt    time.Time
+}
+
+// Parcel is what a carrier needs to book a shipment
+type Parcel struct {
+	FulfillmentID int64
+	OrderID       int64
+	ShipTo        *AddressSnapshot
+	WeightGrams   int
+}
+
+// PackingSlipLine is a book to put in the parcel
+type PackingSlipLine struct {
+	BookID   int64
+	Name     string
+	Author   string
+	Quantity int
+}
+
+// PackingSlip lists the content of a shipment for the warehouse and the customer
+type PackingSlip struct {
+	FulfillmentID  int64
+	OrderID        int64
+	OrderedAt      time.Time
+	ShipTo         *AddressSnapshot
+	ShippingMethod string
+	Carrier        string
+	TrackingNumber string
+	Lines          []PackingSlipLine
+}
+
+// FulfillmentOrderStatus derives the status of an order from its fulfillments. Cancelled
+// fulfillments do not count and orders without printed items stay placed.
+func FulfillmentOrderStatus(order *Order, fulfillments []Fulfillment) OrderStatus {
+	ordered := 0
+	for _, item := range order.Items {
+		if item.Class == ProductClassPrinted {
+			ordered += item.Quantity
+		}
+	}
+
+	started, shipped, delivered := false, 0, 0
+	for _, fulfillment := range fulfillments {
+		if fulfillment.Status == FulfillmentCancelled {
+			continue
+		}
+		started = true
+		for _, item := range fulfillment.Items {
+			if fulfillment.Status.HasShipped() {
+				shipped += item.Quantity
+			}
+			if fulfillment.Status == FulfillmentDelivered {
+				delivered += item.Quantity
+			}
+		}
+	}
+
+	switch {
+	case ordered == 0:
+		return OrderPlaced
+	case delivered >= ordered:
+		return OrderDelivered
+	case shipped >= ordered:
+		return OrderShipped
+	case shipped > 0:
+		return OrderPartiallyShipped
+	case started:
+		return OrderProcessing
+	default:
+		return OrderPlaced
+	}
+}
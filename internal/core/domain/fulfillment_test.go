@@ -0,0 +1,63 @@
+package domain
+
+import "testing"
+
+func TestFulfillmentStatusCanTransition(t *testing.T) {
+	tests := []struct {
+		from FulfillmentStatus
+		to   FulfillmentStatus
+		want bool
+	}{
+		{FulfillmentPending, FulfillmentPicked, true},
+		{FulfillmentPending, FulfillmentShipped, false},
+		{FulfillmentPacked, FulfillmentShipped, true},
+		{FulfillmentPacked, FulfillmentCancelled, true},
+		{FulfillmentShipped, FulfillmentCancelled, false},
+		{FulfillmentException, FulfillmentInTransit, true},
+		{FulfillmentInTransit, FulfillmentShipped, false},
+		{FulfillmentDelivered, FulfillmentInTransit, false},
+		{FulfillmentCancelled, FulfillmentPending, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransition(tt.to); got != tt.want {
+				t.Errorf("CanTransition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFulfillmentOrderStatus(t *testing.T) {
+	order := &Order{Items: []OrderItem{
+		{ID: 1, Class: ProductClassPrinted, Quantity: 2},
+		{ID: 2, Class: ProductClassPrinted, Quantity: 1},
+		{ID: 3, Class: ProductClassEbook, Quantity: 1},
+	}}
+	all := []FulfillmentItem{{OrderItemID: 1, Quantity: 2}, {OrderItemID: 2, Quantity: 1}}
+	first := []FulfillmentItem{{OrderItemID: 1, Quantity: 2}}
+	second := []FulfillmentItem{{OrderItemID: 2, Quantity: 1}}
+
+	tests := []struct {
+		name         string
+		order        *Order
+		fulfillments []Fulfillment
+		want         OrderStatus
+	}{
+		{"no fulfillments", order, nil, OrderPlaced},
+		{"only e-books", &Order{Items: []OrderItem{{Class: ProductClassEbook, Quantity: 1}}}, nil, OrderPlaced},
+		{"picking", order, []Fulfillment{{Status: FulfillmentPicked, Items: all}}, OrderProcessing},
+		{"cancelled fulfillments do not count", order, []Fulfillment{{Status: FulfillmentCancelled, Items: all}}, OrderPlaced},
+		{"one of two parcels shipped", order, []Fulfillment{{Status: FulfillmentShipped, Items: first}, {Status: FulfillmentPacked, Items: second}}, OrderPartiallyShipped},
+		{"held up parcels have shipped", order, []Fulfillment{{Status: FulfillmentException, Items: all}}, OrderShipped},
+		{"one of two parcels delivered", order, []Fulfillment{{Status: FulfillmentDelivered, Items: first}, {Status: FulfillmentInTransit, Items: second}}, OrderShipped},
+		{"every parcel delivered", order, []Fulfillment{{Status: FulfillmentDelivered, Items: first}, {Status: FulfillmentDelivered, Items: second}}, OrderDelivered},
+		{"delivered after a cancelled attempt", order, []Fulfillment{{Status: FulfillmentCancelled, Items: all}, {Status: FulfillmentDelivered, Items: all}}, OrderDelivered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FulfillmentOrderStatus(tt.order, tt.fulfillments); got != tt.want {
+				t.Errorf("FulfillmentOrderStatus() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
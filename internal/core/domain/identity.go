@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// UserIdentity links a user to an account at an external identity provider
+type UserIdentity struct {
+	ID        int64
+	UserID    int64
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+// OIDCAuthRequest is the state kept between redirecting to the identity provider and its callback
+type OIDCAuthRequest struct {
+	Provider     string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// OIDCBinding binds a login to the browser that started it. It is kept in a short-lived cookie
+// and has to match the state of the callback and the stored PKCE verifier.
+type OIDCBinding struct {
+	State        string
+	CodeVerifier string
+}
+
+// OIDCLogin is a started login, the user is redirected to the URL with the binding set in a cookie
+type OIDCLogin struct {
+	URL       string
+	Binding   OIDCBinding
+	ExpiresAt time.Time
+}
+
+// OIDCClaims are the verified claims of an ID token
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Invoice is the numbered invoice of a paid order. Numbers run from 1 every year without gaps.
+// The lines are copied from the order items when the invoice is issued, so the PDF can always be
+// rendered again and later changes of the books do not change it.
+type Invoice struct {
+	ID       int64
+	OrderID  int64
+	Year     int
+	Sequence int
+	Number   string
+	BlobKey  string
+	Lines    []InvoiceLine
+	IssuedAt time.Time
+}
+
+// InvoiceNumber formats the number of the sequence-th invoice of a year
+func InvoiceNumber(year, sequence int) string {
+	return fmt.Sprintf("INV-%d-%06d", year, sequence)
+}
+
+// InvoiceBlobKey is where the PDF of an invoice is stored
+func InvoiceBlobKey(number string) string {
+	return fmt.Sprintf("invoices/%s.pdf", number)
+}
+
+// InvoiceSeller is the business that issues the invoices
+type InvoiceSeller struct {
+	Name    string
+	Address []string
+	TaxID   string
+}
+
+// InvoiceLine is an item of the order as it is printed on the invoice
+type InvoiceLine struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	TaxName     string  `json:"tax_name,omitempty"`
+	TaxRate     float64 `json:"tax_rate,omitempty"`
+	TaxAmount   float64 `json:"tax_amount,omitempty"`
+	Amount      float64 `json:"amount"`
+}
+
+// InvoiceDocument is everything printed on an invoice
+type InvoiceDocument struct {
+	Invoice *Invoice
+	Seller  InvoiceSeller
+	Order   *Order
+}
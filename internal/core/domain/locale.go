@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// LocaleChain is the ordered list of locales content is looked up in, most specific first. The
+// last locale is the default locale the base content of books and categories is written in.
+type LocaleChain []string
+
+// NewLocaleChain returns the fallback chain of a BCP 47 locale: the locale, its parents and then
+// the default locale, so pt-BR falls back to pt and then to the default
+func NewLocaleChain(locale, defaultLocale string) LocaleChain {
+	var chain LocaleChain
+	add := func(locale string) {
+		for _, l := range chain {
+			if l == locale {
+				return
+			}
+		}
+		chain = append(chain, locale)
+	}
+	locale = NormalizeLocale(locale)
+	for locale != "" {
+		add(locale)
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	add(NormalizeLocale(defaultLocale))
+	return chain
+}
+
+// NormalizeLocale lowercases a locale and uses hyphens as separators, pt_BR becomes pt-br
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// Locale is the most specific locale of the chain
+func (lc LocaleChain) Locale() string {
+	if len(lc) == 0 {
+		return ""
+	}
+	return lc[0]
+}
+
+// Default is the locale base content is written in
+func (lc LocaleChain) Default() string {
+	if len(lc) == 0 {
+		return ""
+	}
+	return lc[len(lc)-1]
+}
+
+type localeKey struct{}
+
+// WithLocale returns a context carrying the locale chain negotiated for the current request
+func WithLocale(ctx context.Context, chain LocaleChain) context.Context {
+	return context.WithValue(ctx, localeKey{}, chain)
+}
+
+// LocaleFrom returns the locale chain stored in the context, or nil when content is not localized
+func LocaleFrom(ctx context.Context) LocaleChain {
+	chain, _ := ctx.Value(localeKey{}).(LocaleChain)
+	return chain
+}
+
+// BookTranslation is the name and description of a book in a locale, an empty description falls
+// back to the next locale of the chain
+type BookTranslation struct {
+	BookID      int64
+	Locale      string
+	Name        string
+	Description string
+	UpdatedAt   time.Time
+}
+
+// CategoryTranslation is the name of a category in a locale
+type CategoryTranslation struct {
+	CategoryID int64
+	Locale     string
+	Name       string
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewLocaleChain(t *testing.T) {
+	tests := []struct {
+		name          string
+		locale        string
+		defaultLocale string
+		want          LocaleChain
+	}{
+		{"default locale", "en", "en", LocaleChain{"en"}},
+		{"language", "de", "en", LocaleChain{"de", "en"}},
+		{"region falls back to its language", "pt-BR", "en", LocaleChain{"pt-br", "pt", "en"}},
+		{"underscores are separators", "pt_BR", "en", LocaleChain{"pt-br", "pt", "en"}},
+		{"script and region", "zh-Hant-TW", "en", LocaleChain{"zh-hant-tw", "zh-hant", "zh", "en"}},
+		{"region of the default language", "en-GB", "en", LocaleChain{"en-gb", "en"}},
+		{"default locale with a region", "fr", "EN_us", LocaleChain{"fr", "en-us"}},
+		{"no locale", " ", "en", LocaleChain{"en"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewLocaleChain(tt.locale, tt.defaultLocale)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NewLocaleChain(%q, %q) = %q, want %q", tt.locale, tt.defaultLocale, got, tt.want)
+			}
+			if got.Locale() != tt.want[0] || got.Default() != tt.want[len(tt.want)-1] {
+				t.Errorf("Locale(), Default() = %q, %q, want %q, %q", got.Locale(), got.Default(), tt.want[0], tt.want[len(tt.want)-1])
+			}
+		})
+	}
+}
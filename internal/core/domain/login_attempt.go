@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// LoginAttempts is the failed login state tracked for an account or an IP address
+type LoginAttempts struct {
+	Failures    int64
+	LockedUntil time.Time
+}
+
+// IsLocked reports whether the key is locked at the given time
+func (la *LoginAttempts) IsLocked(now time.Time) bool {
+	return now.Before(la.LockedUntil)
+}
+
+// LockoutPolicy configures brute-force protection for logins
+type LockoutPolicy struct {
+	// MaxAccountFailures is the number of failures after which an account is locked
+	MaxAccountFailures int64
+	// MaxIPFailures is the number of failures after which an IP address is blocked
+	MaxIPFailures int64
+	// FailureWindow is how long failures are remembered
+	FailureWindow time.Duration
+	// LockoutDuration is how long an account or IP address stays locked
+	LockoutDuration time.Duration
+	// BaseDelay is the delay after the first failure, doubled on every further failure
+	BaseDelay time.Duration
+	// MaxDelay caps the progressive delay
+	MaxDelay time.Duration
+}
+
+// Delay returns the progressive delay to apply after the given number of failures
+func (lp LockoutPolicy) Delay(failures int64) time.Duration {
+	if failures <= 0 || lp.BaseDelay <= 0 {
+		return 0
+	}
+	delay := lp.BaseDelay
+	for i := int64(1); i < failures && delay < lp.MaxDelay; i++ {
+		delay *= 2
+	}
+	if lp.MaxDelay > 0 && delay > lp.MaxDelay {
+		delay = lp.MaxDelay
+	}
+	return delay
+}
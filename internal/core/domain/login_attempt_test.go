@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutPolicyDelay(t *testing.T) {
+	policy := LockoutPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		name     string
+		policy   LockoutPolicy
+		failures int64
+		want     time.Duration
+	}{
+		{"no failures", policy, 0, 0},
+		{"negative failures", policy, -1, 0},
+		{"first failure", policy, 1, 100 * time.Millisecond},
+		{"second failure doubles", policy, 2, 200 * time.Millisecond},
+		{"fourth failure", policy, 4, 800 * time.Millisecond},
+		{"capped at max", policy, 5, time.Second},
+		{"many failures stay capped", policy, 1000, time.Second},
+		{"no base delay", LockoutPolicy{MaxDelay: time.Second}, 3, 0},
+		{"max below base delay", LockoutPolicy{BaseDelay: 2 * time.Second, MaxDelay: time.Second}, 1, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Delay(tt.failures); got != tt.want {
+				t.Errorf("Delay(%d) = %s, want %s", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginAttemptsIsLocked(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		lockedUntil time.Time
+		want        bool
+	}{
+		{"never locked", time.Time{}, false},
+		{"locked in the future", now.Add(time.Minute), true},
+		{"lock expired", now.Add(-time.Minute), false},
+		{"lock ends now", now, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := &LoginAttempts{LockedUntil: tt.lockedUntil}
+			if got := attempts.IsLocked(now); got != tt.want {
+				t.Errorf("IsLocked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,49 @@
 package domain
 
+import "time"
+
+// OrderItem is a line of an order, the unit price, class and weight are copied from the ordered
+// edition and the category from its book when the order is placed. EditionID is only nil for
+// items whose edition was deleted since.
+type OrderItem struct {
+	ID          int64
+	OrderID     int64
+	BookID      int64
+	EditionID   *int64
+	Format      FormatType
+	CategoryID  *int64
+	Class       ProductClass
+	WeightGrams int
+	Quantity    int
+	UnitPrice   float64
+}
+
 type Order struct {
 	ID     int64
 	UserId int64
-	BookId int64
+	// BookId is the book of the first item, kept for orders placed before orders had items
+	BookId          int64
+	Status          OrderStatus
+	Items           []OrderItem
+	ShippingAddress *AddressSnapshot
+	BillingAddress  *AddressSnapshot
+	CouponCode      string
+	Subtotal        float64
+	DiscountTotal   float64
+	// ShippingMethodID is nil when nothing has to be shipped
+	ShippingMethodID *int64
+	ShippingMethod   string
+	ShippingCost     float64
+	TaxMode          TaxMode
+	TaxTotal         float64
+	Total            float64
+	// TaxLines is the tax of every item, computed for the shipping address
+	TaxLines []TaxLine
+	// Promotions explains the discounts that were applied to the order
+	Promotions []AppliedPromotion
+	// Redemption is the coupon use stored with the order, nil when no coupon applied
+	Redemption *CouponRedemption
+	// PaidAt is nil until the payment of the order is recorded, the invoice is issued then
+	PaidAt    *time.Time
+	CreatedAt time.Time
 }
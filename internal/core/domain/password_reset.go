@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// PasswordReset is a single-use, time-limited password reset request.
+// Only the hash of the token is stored, the plain token is sent to the user.
+type PasswordReset struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// IsUsable reports whether the reset token can still be redeemed
+func (pr *PasswordReset) IsUsable(now time.Time) bool {
+	return pr.UsedAt == nil && now.Before(pr.ExpiresAt)
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// PriceSource tells what caused a price change
+type PriceSource string
+
+const (
+	PriceSourceManual    PriceSource = "manual"
+	PriceSourceScheduled PriceSource = "scheduled"
+	PriceSourceReverted  PriceSource = "reverted"
+)
+
+// PriceChange is an entry in the price history of an edition, the price is in effect from
+// ChangedAt until the next change
+type PriceChange struct {
+	ID        int64
+	EditionID int64
+	Price     float64
+	Source    PriceSource
+	ChangedAt time.Time
+}
+
+// PriceScheduleStatus is the state of a scheduled price
+type PriceScheduleStatus string
+
+const (
+	PriceSchedulePending   PriceScheduleStatus = "pending"
+	PriceScheduleActive    PriceScheduleStatus = "active"
+	PriceScheduleCompleted PriceScheduleStatus = "completed"
+	PriceScheduleCancelled PriceScheduleStatus = "cancelled"
+)
+
+// PriceSchedule is a future price of an edition. The scheduler applies it at StartsAt and, when
+// EndsAt is set, restores the previous price at EndsAt.
+type PriceSchedule struct {
+	ID            int64
+	BookID        int64
+	EditionID     int64
+	Price         float64
+	PreviousPrice *float64
+	StartsAt      time.Time
+	EndsAt        *time.Time
+	Status        PriceScheduleStatus
+	CreatedBy     int64
+	CreatedAt     time.Time
+}
+
+// Validate checks that the schedule has a positive price and lies in the future
+func (ps *PriceSchedule) Validate(now time.Time) error {
+	if ps.Price <= 0 {
+		return fmt.Errorf("%w: price must be greater than zero", ErrInvalidPriceSchedule)
+	}
+	if !ps.StartsAt.After(now) {
+		return fmt.Errorf("%w: starts_at must be in the future", ErrInvalidPriceSchedule)
+	}
+	if ps.EndsAt != nil && !ps.EndsAt.After(ps.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPriceSchedule)
+	}
+	return nil
+}
+
+// PriceScheduleRun is what running a due schedule changes
+type PriceScheduleRun struct {
+	// Status is the status the schedule moves to
+	Status PriceScheduleStatus
+	// PreviousPrice is the price the edition had before the schedule was applied
+	PreviousPrice *float64
+	// Price is the new price of the edition, nil when the edition keeps its price
+	Price  *float64
+	Source PriceSource
+}
+
+// Run decides what running the due schedule does to an edition with the current price. A pending
+// schedule is applied and remembers the current price, without an end it is a permanent change
+// that completes right away. An active schedule restores the previous price unless staff changed
+// the price while it was active. Schedules of deleted editions or books are cancelled.
+func (ps *PriceSchedule) Run(current float64, deleted bool) PriceScheduleRun {
+	if deleted {
+		return PriceScheduleRun{Status: PriceScheduleCancelled, PreviousPrice: ps.PreviousPrice}
+	}
+	if ps.Status == PriceScheduleActive {
+		run := PriceScheduleRun{Status: PriceScheduleCompleted, PreviousPrice: ps.PreviousPrice}
+		if ps.PreviousPrice != nil && current == ps.Price {
+			run.Price, run.Source = ps.PreviousPrice, PriceSourceReverted
+		}
+		return run
+	}
+
+	price := ps.Price
+	run := PriceScheduleRun{Status: PriceScheduleActive, PreviousPrice: &current, Price: &price, Source: PriceSourceScheduled}
+	if ps.EndsAt == nil {
+		run.Status = PriceScheduleCompleted
+	}
+	return run
+}
+
+// PriceTimeline is the price history of an edition with its upcoming prices
+type PriceTimeline struct {
+	BookID       int64
+	EditionID    int64
+	CurrentPrice float64
+	LowestPrice  float64
+	LowestSince  time.Time
+	History      []PriceChange
+	Schedules    []PriceSchedule
+}
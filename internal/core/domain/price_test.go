@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPriceScheduleRun(t *testing.T) {
+	price := func(p float64) *float64 { return &p }
+	ends := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		schedule PriceSchedule
+		current  float64
+		deleted  bool
+		want     PriceScheduleRun
+	}{
+		{
+			name:     "pending schedule with an end is applied",
+			schedule: PriceSchedule{Price: 8, EndsAt: &ends, Status: PriceSchedulePending},
+			current:  10,
+			want:     PriceScheduleRun{Status: PriceScheduleActive, PreviousPrice: price(10), Price: price(8), Source: PriceSourceScheduled},
+		},
+		{
+			name:     "pending schedule without an end completes right away",
+			schedule: PriceSchedule{Price: 12, Status: PriceSchedulePending},
+			current:  10,
+			want:     PriceScheduleRun{Status: PriceScheduleCompleted, PreviousPrice: price(10), Price: price(12), Source: PriceSourceScheduled},
+		},
+		{
+			name:     "active schedule is reverted",
+			schedule: PriceSchedule{Price: 8, PreviousPrice: price(10), EndsAt: &ends, Status: PriceScheduleActive},
+			current:  8,
+			want:     PriceScheduleRun{Status: PriceScheduleCompleted, PreviousPrice: price(10), Price: price(10), Source: PriceSourceReverted},
+		},
+		{
+			name:     "price changed by staff while active is kept",
+			schedule: PriceSchedule{Price: 8, PreviousPrice: price(10), EndsAt: &ends, Status: PriceScheduleActive},
+			current:  9,
+			want:     PriceScheduleRun{Status: PriceScheduleCompleted, PreviousPrice: price(10)},
+		},
+		{
+			name:     "pending schedule of a deleted book is cancelled",
+			schedule: PriceSchedule{Price: 8, EndsAt: &ends, Status: PriceSchedulePending},
+			deleted:  true,
+			want:     PriceScheduleRun{Status: PriceScheduleCancelled},
+		},
+		{
+			name:     "active schedule of a deleted book is cancelled",
+			schedule: PriceSchedule{Price: 8, PreviousPrice: price(10), EndsAt: &ends, Status: PriceScheduleActive},
+			deleted:  true,
+			want:     PriceScheduleRun{Status: PriceScheduleCancelled, PreviousPrice: price(10)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.schedule.Run(tt.current, tt.deleted)
+			if got.Status != tt.want.Status || got.Source != tt.want.Source ||
+				!equalPrice(got.Price, tt.want.Price) || !equalPrice(got.PreviousPrice, tt.want.PreviousPrice) {
+				t.Errorf("Run() = %s, want %s", formatRun(got), formatRun(tt.want))
+			}
+		})
+	}
+}
+
+func equalPrice(a, b *float64) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func formatRun(run PriceScheduleRun) string {
+	format := func(p *float64) string {
+		if p == nil {
+			return "nil"
+		}
+		return fmt.Sprint(*p)
+	}
+	return fmt.Sprintf("{%s previous=%s price=%s %s}", run.Status, format(run.PreviousPrice), format(run.Price), run.Source)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// DataExport is the personal data held about a user
+type DataExport struct {
+	User        *User
+	Addresses   []Address
+	Orders      []Order
+	GeneratedAt time.Time
+}
+
+// ErasureRequest is a request to erase a user's personal data, the row is kept after the
+// erasure as the record of who asked for it and when it was carried out
+type ErasureRequest struct {
+	ID           int64
+	UserID       int64
+	RequestedBy  int64
+	RequestedAt  time.Time
+	ScheduledFor time.Time
+	CompletedAt  *time.Time
+	CancelledAt  *time.Time
+}
+
+// IsPending reports whether the erasure has neither been carried out nor cancelled
+func (er *ErasureRequest) IsPending() bool {
+	return er.CompletedAt == nil && er.CancelledAt == nil
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// DiscountType tells how the value of a coupon is applied
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent"
+	DiscountFixed   DiscountType = "fixed"
+)
+
+// Coupon is a discount code entered at checkout. Zero usage limits and a nil EndsAt mean no limit.
+type Coupon struct {
+	ID             int64
+	Code           string
+	DiscountType   DiscountType
+	Value          float64
+	MinBasket      float64
+	MaxUses        int
+	MaxUsesPerUser int
+	StartsAt       time.Time
+	EndsAt         *time.Time
+	Active         bool
+	CreatedAt      time.Time
+}
+
+// NormalizeCouponCode makes coupon codes case-insensitive
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks that the coupon describes a usable discount
+func (c *Coupon) Validate() error {
+	switch c.DiscountType {
+	case DiscountPercent:
+		if c.Value <= 0 || c.Value > 100 {
+			return fmt.Errorf("%w: a percentage must be between 0 and 100", ErrInvalidCoupon)
+		}
+	case DiscountFixed:
+		if c.Value <= 0 {
+			return fmt.Errorf("%w: value must be greater than zero", ErrInvalidCoupon)
+		}
+	default:
+		return fmt.Errorf("%w: unknown discount type %q", ErrInvalidCoupon, c.DiscountType)
+	}
+	if c.MinBasket < 0 || c.MaxUses < 0 || c.MaxUsesPerUser < 0 {
+		return fmt.Errorf("%w: limits cannot be negative", ErrInvalidCoupon)
+	}
+	if c.EndsAt != nil && !c.EndsAt.After(c.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidCoupon)
+	}
+	return nil
+}
+
+// CouponUsage is how often a coupon was redeemed in total and by the user placing the order
+type CouponUsage struct {
+	Total  int
+	ByUser int
+}
+
+// CouponRedemption records the use of a coupon by an order
+type CouponRedemption struct {
+	ID        int64
+	CouponID  int64
+	UserID    int64
+	OrderID   int64
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// AutomaticPromotion applies without a code: for every BuyQuantity books of the category in the
+// basket, FreeQuantity more books of the category are free, the cheapest ones first
+type AutomaticPromotion struct {
+	ID           int64
+	Name         string
+	CategoryID   int64
+	BuyQuantity  int
+	FreeQuantity int
+	StartsAt     time.Time
+	EndsAt       *time.Time
+	Active       bool
+	CreatedAt    time.Time
+}
+
+// Validate checks that the promotion can be evaluated
+func (ap *AutomaticPromotion) Validate() error {
+	if ap.BuyQuantity <= 0 || ap.FreeQuantity <= 0 {
+		return fmt.Errorf("%w: buy and free quantities must be greater than zero", ErrInvalidPromotion)
+	}
+	if ap.EndsAt != nil && !ap.EndsAt.After(ap.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPromotion)
+	}
+	return nil
+}
+
+// ActiveAt reports whether the promotion applies at the given time
+func (ap *AutomaticPromotion) ActiveAt(now time.Time) bool {
+	return ap.Active && !now.Before(ap.StartsAt) && (ap.EndsAt == nil || now.Before(*ap.EndsAt))
+}
+
+// BasketLine is a priced line of a cart or order
+type BasketLine struct {
+	BookID     int64
+	CategoryID *int64
+	Quantity   int
+	UnitPrice  float64
+}
+
+// Basket is what promotions are evaluated against
+type Basket struct {
+	UserID     int64
+	Lines      []BasketLine
+	CouponCode string
+}
+
+// Subtotal is the price of the basket before discounts
+func (b *Basket) Subtotal() float64 {
+	var subtotal float64
+	for _, line := range b.Lines {
+		subtotal += float64(line.Quantity) * line.UnitPrice
+	}
+	return RoundPrice(subtotal)
+}
+
+// PromotionKind tells whether a discount came from a coupon or an automatic promotion
+type PromotionKind string
+
+const (
+	PromotionCoupon    PromotionKind = "coupon"
+	PromotionAutomatic PromotionKind = "automatic"
+)
+
+// AppliedPromotion is a discount that was applied, with a human readable explanation
+type AppliedPromotion struct {
+	Kind        PromotionKind `json:"kind"`
+	ID          int64         `json:"id"`
+	Name        string        `json:"name"`
+	Amount      float64       `json:"amount"`
+	Explanation string        `json:"explanation"`
+}
+
+// PromotionResult is the outcome of evaluating promotions against a basket. CouponRejection
+// says why the coupon of the basket was not applied.
+type PromotionResult struct {
+	Subtotal        float64
+	Discount        float64
+	Total           float64
+	Applied         []AppliedPromotion
+	CouponRejection string
+}
+
+// AppliedCoupon returns the coupon discount of the result, nil when no coupon applied
+func (pr *PromotionResult) AppliedCoupon() *AppliedPromotion {
+	for i := range pr.Applied {
+		if pr.Applied[i].Kind == PromotionCoupon {
+			return &pr.Applied[i]
+		}
+	}
+	return nil
+}
+
+// RoundPrice rounds an amount to cents
+func RoundPrice(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCouponValidate(t *testing.T) {
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		coupon Coupon
+		valid  bool
+	}{
+		{"percent", Coupon{DiscountType: DiscountPercent, Value: 100, StartsAt: start}, true},
+		{"fixed", Coupon{DiscountType: DiscountFixed, Value: 250, StartsAt: start}, true},
+		{"percent above 100", Coupon{DiscountType: DiscountPercent, Value: 101, StartsAt: start}, false},
+		{"zero value", Coupon{DiscountType: DiscountFixed, StartsAt: start}, false},
+		{"unknown type", Coupon{DiscountType: "bogo", Value: 1, StartsAt: start}, false},
+		{"negative limit", Coupon{DiscountType: DiscountFixed, Value: 1, MaxUses: -1, StartsAt: start}, false},
+		{"ends before it starts", Coupon{DiscountType: DiscountFixed, Value: 1, StartsAt: start, EndsAt: &before}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.coupon.Validate()
+			if (err == nil) != tt.valid || (err != nil && !errors.Is(err, ErrInvalidCoupon)) {
+				t.Errorf("Validate() error = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestAutomaticPromotionActiveAt(t *testing.T) {
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name      string
+		promotion AutomaticPromotion
+		at        time.Time
+		want      bool
+	}{
+		{"at the start", AutomaticPromotion{Active: true, StartsAt: start, EndsAt: &end}, start, true},
+		{"before the start", AutomaticPromotion{Active: true, StartsAt: start}, start.Add(-time.Second), false},
+		{"at the end", AutomaticPromotion{Active: true, StartsAt: start, EndsAt: &end}, end, false},
+		{"without an end", AutomaticPromotion{Active: true, StartsAt: start}, end.AddDate(1, 0, 0), true},
+		{"deactivated", AutomaticPromotion{StartsAt: start}, start, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.ActiveAt(tt.at); got != tt.want {
+				t.Errorf("ActiveAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Publisher publishes books and series
+type Publisher struct {
+	ID        int64
+	Name      string
+	Website   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Series is a run of books read in the order of their volume numbers. Volumes are only loaded
+// when a single series is read.
+type Series struct {
+	ID          int64
+	Name        string
+	Description string
+	PublisherID *int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Volumes     []Book
+}
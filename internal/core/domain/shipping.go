@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// AnyCountry in the countries of a zone makes it the fallback for countries no other zone lists
+const AnyCountry = "*"
+
+// ShippingZone groups the countries that share shipping methods
+type ShippingZone struct {
+	ID        int64
+	Name      string
+	Countries []string
+}
+
+// Normalize upper cases the country codes of the zone
+func (sz *ShippingZone) Normalize() {
+	sz.Name = strings.TrimSpace(sz.Name)
+	for i, country := range sz.Countries {
+		sz.Countries[i] = strings.ToUpper(strings.TrimSpace(country))
+	}
+	slices.Sort(sz.Countries)
+	sz.Countries = slices.Compact(sz.Countries)
+}
+
+// ShippingRateType tells how the cost of a shipping method is computed
+type ShippingRateType string
+
+const (
+	// ShippingFlat costs Price
+	ShippingFlat ShippingRateType = "flat"
+	// ShippingWeight costs Price plus PerKg for every started kilogram
+	ShippingWeight ShippingRateType = "weight"
+	// ShippingFreeOver costs Price, or nothing when the order is worth at least FreeOver
+	ShippingFreeOver ShippingRateType = "free_over"
+	// ShippingPickup is collected in store and costs nothing
+	ShippingPickup ShippingRateType = "pickup"
+)
+
+// ShippingMethod is a way of delivering orders to the countries of a zone. A zero MaxWeightGrams
+// means no weight limit.
+type ShippingMethod struct {
+	ID             int64
+	ZoneID         int64
+	Name           string
+	Type           ShippingRateType
+	Price          float64
+	PerKg          float64
+	FreeOver       float64
+	MaxWeightGrams int
+	Active         bool
+}
+
+// Validate checks that the method has what its rate type needs
+func (sm *ShippingMethod) Validate() error {
+	if sm.Price < 0 || sm.PerKg < 0 || sm.FreeOver < 0 || sm.MaxWeightGrams < 0 {
+		return fmt.Errorf("%w: amounts cannot be negative", ErrInvalidShippingMethod)
+	}
+	switch sm.Type {
+	case ShippingFlat, ShippingPickup:
+	case ShippingWeight:
+		if sm.PerKg == 0 {
+			return fmt.Errorf("%w: a weight-based method needs per_kg", ErrInvalidShippingMethod)
+		}
+	case ShippingFreeOver:
+		if sm.FreeOver == 0 {
+			return fmt.Errorf("%w: a free over threshold method needs free_over", ErrInvalidShippingMethod)
+		}
+	default:
+		return fmt.Errorf("%w: unknown rate type %q", ErrInvalidShippingMethod, sm.Type)
+	}
+	return nil
+}
+
+// Shipment is what is shipped, Subtotal is the value of the order after discounts
+type Shipment struct {
+	Country     string
+	Subtotal    float64
+	WeightGrams int
+}
+
+// ShippingQuote is the cost of shipping a shipment with a method
+type ShippingQuote struct {
+	MethodID    int64
+	Name        string
+	Type        ShippingRateType
+	Cost        float64
+	Explanation string
+}
+
+// Quote computes the cost of the shipment, it returns false when the shipment is too heavy
+func (sm *ShippingMethod) Quote(shipment Shipment) (ShippingQuote, bool) {
+	if sm.MaxWeightGrams > 0 && shipment.WeightGrams > sm.MaxWeightGrams {
+		return ShippingQuote{}, false
+	}
+
+	quote := ShippingQuote{
+		MethodID: sm.ID,
+		Name:     sm.Name,
+		Type:     sm.Type,
+	}
+	switch sm.Type {
+	case ShippingFlat:
+		quote.Cost = sm.Price
+		quote.Explanation = "flat rate"
+	case ShippingWeight:
+		kilograms := math.Ceil(float64(shipment.WeightGrams) / 1000)
+		quote.Cost = sm.Price + kilograms*sm.PerKg
+		quote.Explanation = fmt.Sprintf("%.2f plus %.2f per kg for %g kg", sm.Price, sm.PerKg, kilograms)
+	case ShippingFreeOver:
+		if shipment.Subtotal >= sm.FreeOver {
+			quote.Explanation = fmt.Sprintf("free for orders of %.2f or more", sm.FreeOver)
+		} else {
+			quote.Cost = sm.Price
+			quote.Explanation = fmt.Sprintf("free from %.2f, %.2f to go", sm.FreeOver, sm.FreeOver-shipment.Subtotal)
+		}
+	case ShippingPickup:
+		quote.Explanation = "collect in store"
+	}
+	quote.Cost = RoundPrice(quote.Cost)
+	return quote, true
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestShippingMethodValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  ShippingMethod
+		wantErr error
+	}{
+		{"flat", ShippingMethod{Type: ShippingFlat, Price: 4.95}, nil},
+		{"free flat", ShippingMethod{Type: ShippingFlat}, nil},
+		{"pickup", ShippingMethod{Type: ShippingPickup}, nil},
+		{"weight", ShippingMethod{Type: ShippingWeight, Price: 2, PerKg: 1.5}, nil},
+		{"weight without per_kg", ShippingMethod{Type: ShippingWeight, Price: 2}, ErrInvalidShippingMethod},
+		{"free over", ShippingMethod{Type: ShippingFreeOver, Price: 4.95, FreeOver: 50}, nil},
+		{"free over without threshold", ShippingMethod{Type: ShippingFreeOver, Price: 4.95}, ErrInvalidShippingMethod},
+		{"negative price", ShippingMethod{Type: ShippingFlat, Price: -1}, ErrInvalidShippingMethod},
+		{"negative per_kg", ShippingMethod{Type: ShippingWeight, PerKg: -1}, ErrInvalidShippingMethod},
+		{"negative threshold", ShippingMethod{Type: ShippingFreeOver, FreeOver: -1}, ErrInvalidShippingMethod},
+		{"negative weight limit", ShippingMethod{Type: ShippingFlat, MaxWeightGrams: -1}, ErrInvalidShippingMethod},
+		{"unknown type", ShippingMethod{Type: "drone"}, ErrInvalidShippingMethod},
+		{"missing type", ShippingMethod{}, ErrInvalidShippingMethod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.method.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShippingMethodQuote(t *testing.T) {
+	flat := ShippingMethod{ID: 1, Name: "Standard", Type: ShippingFlat, Price: 4.95}
+	weight := ShippingMethod{ID: 2, Name: "Parcel", Type: ShippingWeight, Price: 2, PerKg: 1.5, MaxWeightGrams: 5000}
+	freeOver := ShippingMethod{ID: 3, Name: "Letter", Type: ShippingFreeOver, Price: 3.5, FreeOver: 50}
+	pickup := ShippingMethod{ID: 4, Name: "Store", Type: ShippingPickup}
+
+	tests := []struct {
+		name     string
+		method   ShippingMethod
+		shipment Shipment
+		wantOK   bool
+		wantCost float64
+	}{
+		{"flat", flat, Shipment{Subtotal: 100, WeightGrams: 20000}, true, 4.95},
+		{"weight of exactly one kilogram", weight, Shipment{WeightGrams: 1000}, true, 3.5},
+		{"started kilogram is charged in full", weight, Shipment{WeightGrams: 1001}, true, 5},
+		{"a few grams are a started kilogram", weight, Shipment{WeightGrams: 1}, true, 3.5},
+		{"weightless shipment only pays the base price", weight, Shipment{}, true, 2},
+		{"weight at the limit", weight, Shipment{WeightGrams: 5000}, true, 9.5},
+		{"weight over the limit", weight, Shipment{WeightGrams: 5001}, false, 0},
+		{"free over below the threshold", freeOver, Shipment{Subtotal: 49.99}, true, 3.5},
+		{"free over at exactly the threshold", freeOver, Shipment{Subtotal: 50}, true, 0},
+		{"free over above the threshold", freeOver, Shipment{Subtotal: 80}, true, 0},
+		{"pickup", pickup, Shipment{Subtotal: 10, WeightGrams: 3000}, true, 0},
+		{"pickup over its weight limit", ShippingMethod{Type: ShippingPickup, MaxWeightGrams: 1000}, Shipment{WeightGrams: 1500}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, ok := tt.method.Quote(tt.shipment)
+			if ok != tt.wantOK {
+				t.Fatalf("Quote() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if quote.Cost != tt.wantCost {
+				t.Errorf("cost = %v, want %v", quote.Cost, tt.wantCost)
+			}
+			if quote.MethodID != tt.method.ID || quote.Name != tt.method.Name || quote.Type != tt.method.Type {
+				t.Errorf("quote = %+v, want it to name method %d", quote, tt.method.ID)
+			}
+			if quote.Explanation == "" {
+				t.Error("quote has no explanation")
+			}
+		})
+	}
+}
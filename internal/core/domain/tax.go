@@ -0,0 +1,78 @@
+package domain
+
+import "fmt"
+
+// ProductClass groups books that are taxed alike, printed books and e-books often have
+// different rates
+type ProductClass string
+
+const (
+	ProductClassPrinted ProductClass = "printed"
+	ProductClassEbook   ProductClass = "ebook"
+)
+
+// TaxMode tells whether catalog prices already include tax
+type TaxMode string
+
+const (
+	// TaxInclusive prices include tax, the tax is extracted from them and the total stays the same
+	TaxInclusive TaxMode = "inclusive"
+	// TaxExclusive prices are net, the tax is added on top of them
+	TaxExclusive TaxMode = "exclusive"
+)
+
+// ParseTaxMode validates a tax mode, an empty mode is exclusive
+func ParseTaxMode(mode string) (TaxMode, error) {
+	switch TaxMode(mode) {
+	case "", TaxExclusive:
+		return TaxExclusive, nil
+	case TaxInclusive:
+		return TaxInclusive, nil
+	}
+	return "", fmt.Errorf("unknown tax mode %q", mode)
+}
+
+// TaxRate is the rate of a product class in a country, optionally narrowed down to a region.
+// Rate is a fraction, 0.07 is 7%.
+type TaxRate struct {
+	Country string
+	Region  string
+	Class   ProductClass
+	Name    string
+	Rate    float64
+}
+
+// TaxableLine is an order item to tax, Amount is its price after discounts. Item is the position of
+// the item in the order.
+type TaxableLine struct {
+	Item   int
+	BookID int64
+	Class  ProductClass
+	Amount float64
+}
+
+// TaxRequest is what a TaxCalculator needs to tax an order
+type TaxRequest struct {
+	Country string
+	Region  string
+	Mode    TaxMode
+	Lines   []TaxableLine
+}
+
+// TaxLine is the tax of one order item, it is stored on the order. Item is the position of the
+// item in the order, the same book can be ordered in several items.
+type TaxLine struct {
+	Item    int          `json:"item"`
+	BookID  int64        `json:"book_id"`
+	Class   ProductClass `json:"class"`
+	Name    string       `json:"name"`
+	Rate    float64      `json:"rate"`
+	Taxable float64      `json:"taxable"`
+	Amount  float64      `json:"amount"`
+}
+
+// TaxResult is the tax of an order
+type TaxResult struct {
+	Lines []TaxLine
+	Total float64
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// TwoFactor is the TOTP second factor of a user, it is only enforced once confirmed
+type TwoFactor struct {
+	UserID       int64
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+}
+
+// IsEnabled reports whether the user confirmed the enrollment
+func (tf *TwoFactor) IsEnabled() bool {
+	return tf != nil && tf.ConfirmedAt != nil
+}
+
+// TwoFactorEnrollment is returned when a user starts enrolling a TOTP authenticator
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// TwoFactorPolicy configures which roles must use a second factor
+type TwoFactorPolicy struct {
+	RequiredRoles []UserRole
+}
+
+// Requires reports whether users with the role must use a second factor
+func (tp TwoFactorPolicy) Requires(role UserRole) bool {
+	for _, r := range tp.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// LoginResult is the outcome of a password login. When the user has two-factor
+// authentication enabled only a challenge token is returned.
+type LoginResult struct {
+	AccessToken       string
+	ChallengeToken    string
+	TwoFactorRequired bool
+}
+
+// Identity is the authenticated caller of a request, either a user with an access token or an API key
+type Identity struct {
+	User              *User
+	TwoFactorVerified bool
+	APIKey            *APIKey
+}
+
+// HasScope reports whether the identity may use the scope. Access tokens carry every scope,
+// what the user can do is limited by their role.
+func (i *Identity) HasScope(scope APIKeyScope) bool {
+	if i.APIKey == nil {
+		return true
+	}
+	return i.APIKey.HasScope(scope)
+}
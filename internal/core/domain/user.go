@@ -1,8 +1,23 @@
 package domain
 
+import "time"
+
+// UserRole is the role of a user
+type UserRole string
+
+const (
+	Customer UserRole = "customer"
+	Staff    UserRole = "staff"
+	Admin    UserRole = "admin"
+)
+
 type User struct {
-	ID       int64
-	Email    string
-	Name     string
-	Password string
+	ID           int64
+	Email        string
+	Name         string
+	Password     string
+	Role         UserRole
+	TokenVersion int64
+	Version      int64
+	DeletedAt    *time.Time
 }
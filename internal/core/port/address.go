@@ -0,0 +1,37 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// AddressRepository is an interface for interacting with address book data
+type AddressRepository interface {
+	// CreateAddress inserts a new address, clearing the user's other defaults it replaces
+	CreateAddress(ctx context.Context, address *domain.Address) (*domain.Address, error)
+	// GetAddressById selects an address of a user by id
+	GetAddressById(ctx context.Context, userID, id int64) (*domain.Address, error)
+	// GetDefaultAddress selects the default shipping or billing address of a user
+	GetDefaultAddress(ctx context.Context, userID int64, shipping bool) (*domain.Address, error)
+	// ListAddresses selects the addresses of a user
+	ListAddresses(ctx context.Context, userID int64) ([]domain.Address, error)
+	// UpdateAddress updates an address, clearing the user's other defaults it replaces
+	UpdateAddress(ctx context.Context, address *domain.Address) (*domain.Address, error)
+	// DeleteAddress deletes an address of a user
+	DeleteAddress(ctx context.Context, userID, id int64) error
+}
+
+// AddressService is an interface for interacting with address book business logic
+type AddressService interface {
+	// CreateAddress validates and adds an address to the user's address book
+	CreateAddress(ctx context.Context, address *domain.Address) (*domain.Address, error)
+	// GetAddress returns an address of a user
+	GetAddress(ctx context.Context, userID, id int64) (*domain.Address, error)
+	// ListAddresses returns the address book of a user
+	ListAddresses(ctx context.Context, userID int64) ([]domain.Address, error)
+	// UpdateAddress validates and updates an address of a user
+	UpdateAddress(ctx context.Context, address *domain.Address) (*domain.Address, error)
+	// DeleteAddress deletes an address of a user
+	DeleteAddress(ctx context.Context, userID, id int64) error
+}
@@ -0,0 +1,36 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// APIKeyRepository is an interface for interacting with api key data
+type APIKeyRepository interface {
+	// CreateAPIKey inserts a new api key into the database
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+	// GetAPIKeyByPrefix selects an api key by its prefix
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	// GetAPIKeyById selects an api key by id
+	GetAPIKeyById(ctx context.Context, id int64) (*domain.APIKey, error)
+	// ListAPIKeys selects the api keys of a user
+	ListAPIKeys(ctx context.Context, userID int64) ([]domain.APIKey, error)
+	// TouchAPIKey records that an api key was used
+	TouchAPIKey(ctx context.Context, id int64) error
+	// RevokeAPIKey revokes an api key
+	RevokeAPIKey(ctx context.Context, id int64) error
+}
+
+// APIKeyService is an interface for interacting with api key business logic
+type APIKeyService interface {
+	// CreateAPIKey creates an api key for the user and returns it with the plain key, which is only shown once
+	CreateAPIKey(ctx context.Context, user *domain.User, name string, scopes []domain.APIKeyScope, expiresIn time.Duration) (*domain.APIKey, string, error)
+	// ListAPIKeys returns the api keys of a user
+	ListAPIKeys(ctx context.Context, user *domain.User) ([]domain.APIKey, error)
+	// RevokeAPIKey revokes an api key of the user, admins can revoke any key
+	RevokeAPIKey(ctx context.Context, user *domain.User, id int64) error
+	// Authenticate verifies a plain api key and returns the identity it belongs to
+	Authenticate(ctx context.Context, key string) (*domain.Identity, error)
+}
@@ -0,0 +1,25 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// AuditRepository is an interface for interacting with the append-only audit log
+type AuditRepository interface {
+	// CreateAuditEntry appends an entry to the audit log
+	CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error)
+	// ListAuditEntries selects audit entries matching the filter, newest first
+	ListAuditEntries(ctx context.Context, filter *domain.AuditFilter) ([]domain.AuditEntry, error)
+}
+
+// AuditService is an interface for recording and reading changes to entities
+type AuditService interface {
+	// Record stores the difference between the before and after state of an entity together with
+	// the actor, request ID and IP address found in the context. Either state may be nil. The values
+	// of personal fields are redacted since erasure cannot remove entries.
+	Record(ctx context.Context, action, entityType string, entityID int64, before, after any)
+	// ListAuditEntries returns audit entries matching the filter
+	ListAuditEntries(ctx context.Context, filter *domain.AuditFilter) ([]domain.AuditEntry, error)
+}
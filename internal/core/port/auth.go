@@ -2,20 +2,60 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 )
 
 // TokenService is an interface for interacting with token-related business logic
 type TokenService interface {
-	// CreateToken creates a new token for a given user
-	CreateToken(user *domain.User) (string, error)
+	// CreateToken creates a new access token for a given user
+	CreateToken(user *domain.User, twoFactorVerified bool) (string, error)
+	// CreateChallengeToken creates a short lived token that can only be exchanged
+	// for an access token after the second factor was verified
+	CreateChallengeToken(user *domain.User) (string, error)
 	// VerifyToken verifies the token and returns the payload
 	VerifyToken(token string) (*domain.TokenPayload, error)
 }
 
+// PasswordResetRepository is an interface for interacting with password reset data
+type PasswordResetRepository interface {
+	// CreatePasswordReset inserts a new password reset into the database
+	CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) (*domain.PasswordReset, error)
+	// GetPasswordResetByTokenHash selects a password reset by its token hash
+	GetPasswordResetByTokenHash(ctx context.Context, tokenHash string) (*domain.PasswordReset, error)
+	// ResetPassword marks the reset as used, stores the new password hash,
+	// invalidates the user's sessions and any other pending resets in a single transaction
+	ResetPassword(ctx context.Context, reset *domain.PasswordReset, hashedPassword string) error
+}
+
+// LoginAttemptRepository is an interface for tracking failed login attempts per account or IP address
+type LoginAttemptRepository interface {
+	// GetLoginAttempts returns the failed attempts recorded for a key
+	GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error)
+	// RecordLoginFailure increments the failures of a key, failures expire after window
+	RecordLoginFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempts, error)
+	// LockLogin locks a key until the given time
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	// ResetLoginAttempts clears the failures and lock of a key
+	ResetLoginAttempts(ctx context.Context, key string) error
+}
+
 // UserService is an interface for interacting with user authentication-related business logic
 type AuthService interface {
-	// Login authenticates a user by email and password and returns a token
-	Login(ctx context.Context, email, password string) (string, error)
+	// Login authenticates a user by email and password and returns a token,
+	// or a challenge token when the user has two-factor authentication enabled
+	Login(ctx context.Context, email, password, ip string) (*domain.LoginResult, error)
+	// VerifyTwoFactor exchanges a challenge token and a TOTP or recovery code for an access token
+	VerifyTwoFactor(ctx context.Context, challengeToken, code string) (string, error)
+	// UnlockUser clears the failed login attempts and lockout of a user
+	UnlockUser(ctx context.Context, id int64) error
+	// Authenticate verifies an access token and returns the identity it belongs to
+	Authenticate(ctx context.Context, token string) (*domain.Identity, error)
+	// AuthorizeTwoFactor returns an error when the identity's role requires a second factor it did not verify
+	AuthorizeTwoFactor(identity *domain.Identity) error
+	// ForgotPassword sends a password reset token to the email if it belongs to a user
+	ForgotPassword(ctx context.Context, email string) error
+	// ResetPassword sets a new password using a password reset token
+	ResetPassword(ctx context.Context, token, password string) error
 }
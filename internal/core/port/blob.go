@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+	"io"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// BlobStorage is an interface for storing files such as invoices. Keys are slash separated paths,
+// Open fails with ErrDataNotFound for a key that was never stored.
+type BlobStorage interface {
+	// Put stores the content of r under key, replacing what was stored there
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns a blob for reading, it can seek so it can be served in ranges
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, *domain.BlobInfo, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 )
@@ -13,12 +14,20 @@ type BookRepository interface {
 	// GetBookById selects a book by id
 	GetBookById(ctx context.Context, id int64) (*domain.Book, error)
 
-	// ListBooks selects a list of books with pagination
-	ListBooks(ctx context.Context, skip, limit int64) ([]domain.Book, error)
-	// UpdateBook updates a book
+	// ListBooks selects a list of books with pagination, optionally including soft-deleted books
+	ListBooks(ctx context.Context, skip, limit int64, includeDeleted bool) ([]domain.Book, error)
+	// SearchBooks selects the books matching a search with their matching editions
+	SearchBooks(ctx context.Context, search *domain.BookSearch) ([]domain.Book, error)
+	// ListSeriesVolumes selects the books of a series in reading order
+	ListSeriesVolumes(ctx context.Context, seriesID int64) ([]domain.Book, error)
+	// UpdateBook updates a book and bumps its version, a non-zero version must match the stored one
 	UpdateBook(ctx context.Context, book *domain.Book) (*domain.Book, error)
-	// DeleteBook deletes a book
-	DeleteBook(ctx context.Context, id int64) error
+	// DeleteBook soft-deletes a book, a non-zero version must match the stored one
+	DeleteBook(ctx context.Context, id, version int64) error
+	// RestoreBook undoes the soft delete of a book
+	RestoreBook(ctx context.Context, id int64) (*domain.Book, error)
+	// PurgeBooks hard-deletes books soft-deleted before the given time that no order references
+	PurgeBooks(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 // BookService is an interface for interacting with book-related business logic
@@ -27,10 +36,16 @@ type BookService interface {
 	CreateBook(ctx context.Context, book *domain.Book) (*domain.Book, error)
 	// GetBook returns a book by id
 	GetBook(ctx context.Context, id int64) (*domain.Book, error)
-	// ListBooks returns a list of books with pagination
-	ListBooks(ctx context.Context, skip, limit int64) ([]domain.Book, error)
-	// UpdateBook updates a book
+	// ListBooks returns a list of books with pagination, optionally including soft-deleted books
+	ListBooks(ctx context.Context, skip, limit int64, includeDeleted bool) ([]domain.Book, error)
+	// SearchBooks returns the books matching a search, grouping their matching editions
+	SearchBooks(ctx context.Context, search *domain.BookSearch) ([]domain.Book, error)
+	// UpdateBook updates a book, a non-zero version must match the current one
 	UpdateBook(ctx context.Context, book *domain.Book) (*domain.Book, error)
-	// DeleteBook deletes a book
-	DeleteBook(ctx context.Context, id int64) error
+	// DeleteBook soft-deletes a book, a non-zero version must match the current one
+	DeleteBook(ctx context.Context, id, version int64) error
+	// RestoreBook restores a soft-deleted book
+	RestoreBook(ctx context.Context, id int64) (*domain.Book, error)
+	// PurgeDeletedBooks hard-deletes books that were soft-deleted longer than the retention ago
+	PurgeDeletedBooks(ctx context.Context, retention time.Duration) (int64, error)
 }
@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// CategoryRepository is an interface for interacting with book categories
+type CategoryRepository interface {
+	// CreateCategory inserts a category, failing with ErrConflictingData when the name is taken
+	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
+	// ListCategories selects all categories ordered by name
+	ListCategories(ctx context.Context) ([]domain.Category, error)
+}
+
+// CategoryService is an interface for managing book categories
+type CategoryService interface {
+	// CreateCategory creates a category
+	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
+	// ListCategories returns all categories
+	ListCategories(ctx context.Context) ([]domain.Category, error)
+}
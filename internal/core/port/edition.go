@@ -0,0 +1,53 @@
+package port
+
+import (
+	"context"
+	"io"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+type EditionRepository interface {
+	// CreateEdition inserts an edition of a book and starts its price history, ISBNs and the
+	// format, language and edition number of the editions of a book are unique
+	CreateEdition(ctx context.Context, edition *domain.Edition) (*domain.Edition, error)
+	GetEdition(ctx context.Context, id int64) (*domain.Edition, error)
+	ListBookEditions(ctx context.Context, bookID int64) ([]domain.Edition, error)
+	// UpdateEdition updates the details, price, stock and weight of an edition and records a price
+	// change in its price history
+	UpdateEdition(ctx context.Context, edition *domain.Edition) (*domain.Edition, error)
+	// SetEditionFile records the file stored for a digital edition
+	SetEditionFile(ctx context.Context, edition *domain.Edition) (*domain.Edition, error)
+}
+
+type EditionService interface {
+	CreateEdition(ctx context.Context, edition *domain.Edition) (*domain.Edition, error)
+	ListBookEditions(ctx context.Context, bookID int64) ([]domain.Edition, error)
+	UpdateEdition(ctx context.Context, edition *domain.Edition) (*domain.Edition, error)
+	// UploadFile stores the file of a digital edition, replacing the previous one
+	UploadFile(ctx context.Context, bookID, editionID int64, fileName, contentType string, r io.Reader) (*domain.Edition, error)
+}
+
+type DownloadRepository interface {
+	// CreateOrderGrants adds a grant for every digital item of an order that has none yet and
+	// returns all grants of the order
+	CreateOrderGrants(ctx context.Context, orderID int64, maxDownloads int) ([]domain.DownloadGrant, error)
+	GetGrant(ctx context.Context, id int64) (*domain.DownloadGrant, error)
+	// UseDownload counts a download of a grant, failing with ErrDownloadLimit when none is left
+	UseDownload(ctx context.Context, id int64) (*domain.DownloadGrant, error)
+}
+
+type DownloadService interface {
+	// ListOrderDownloads returns the download grants of the digital items of an order. Fails with
+	// ErrOrderNotPaid when the payment of the order was not recorded.
+	ListOrderDownloads(ctx context.Context, orderID int64) ([]domain.DownloadGrant, error)
+	// GrantOrderDownloads grants the downloads of the digital items of a paid order
+	GrantOrderDownloads(ctx context.Context, order *domain.Order) ([]domain.DownloadGrant, error)
+	// CreateLink signs an expiring download URL for a grant of the user, the order of the grant
+	// must be paid
+	CreateLink(ctx context.Context, grantID, userID int64) (*domain.DownloadLink, error)
+	// OpenDownload checks a signed download URL and opens the file. Every download is counted and
+	// fails with ErrDownloadLimit once none is left, only range requests with the resume token of a
+	// counted download continue it for free.
+	OpenDownload(ctx context.Context, request *domain.DownloadRequest) (*domain.Download, io.ReadSeekCloser, *domain.BlobInfo, error)
+}
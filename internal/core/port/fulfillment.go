@@ -0,0 +1,56 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// Carrier is an interface for booking shipments with a parcel carrier and following them. A real
+// carrier is plugged in by implementing this interface in an adapter and selecting it with
+// CARRIER_PROVIDER.
+type Carrier interface {
+	// Name identifies the carrier on fulfillments
+	Name() string
+	// CreateShipment books a shipment for the parcel and returns its tracking number
+	CreateShipment(ctx context.Context, parcel *domain.Parcel) (string, error)
+	// Track returns the tracking events of a shipment so far, oldest first
+	Track(ctx context.Context, trackingNumber string) ([]domain.TrackingEvent, error)
+}
+
+type FulfillmentRepository interface {
+	// CreateFulfillment inserts a fulfillment with its items, an empty item list takes everything
+	// of the order that is not fulfilled yet. Fails with ErrInvalidFulfillment when an item is
+	// not a printed item of the order or more would be fulfilled than was ordered.
+	CreateFulfillment(ctx context.Context, fulfillment *domain.Fulfillment) (*domain.Fulfillment, error)
+	GetFulfillment(ctx context.Context, id int64) (*domain.Fulfillment, error)
+	ListOrderFulfillments(ctx context.Context, orderID int64) ([]domain.Fulfillment, error)
+	// ListCarrierShipments selects the fulfillments of a carrier that are on their way
+	ListCarrierShipments(ctx context.Context, carrier string) ([]domain.Fulfillment, error)
+	// UpdateFulfillment stores the status, carrier, tracking number and timestamps of a
+	// fulfillment, failing with ErrConflictingData when its status is no longer from
+	UpdateFulfillment(ctx context.Context, fulfillment *domain.Fulfillment, from domain.FulfillmentStatus) (*domain.Fulfillment, error)
+	// AddTrackingEvents stores the events of a fulfillment, events already stored are skipped
+	AddTrackingEvents(ctx context.Context, events []domain.TrackingEvent) error
+	ListTrackingEvents(ctx context.Context, fulfillmentID int64) ([]domain.TrackingEvent, error)
+}
+
+type FulfillmentService interface {
+	// CreateFulfillment starts a shipment for some or all of the printed items of an order
+	CreateFulfillment(ctx context.Context, fulfillment *domain.Fulfillment) (*domain.Fulfillment, error)
+	GetFulfillment(ctx context.Context, id int64) (*domain.Fulfillment, error)
+	ListOrderFulfillments(ctx context.Context, orderID int64) ([]domain.Fulfillment, error)
+	// UpdateStatus moves a fulfillment through picking and packing, cancels it or marks it
+	// delivered. Shipping goes through Ship.
+	UpdateStatus(ctx context.Context, id int64, status domain.FulfillmentStatus) (*domain.Fulfillment, error)
+	// Ship hands a packed fulfillment to a carrier. Without a tracking number the shipment is
+	// booked with the configured carrier.
+	Ship(ctx context.Context, id int64, carrier, trackingNumber string) (*domain.Fulfillment, error)
+	// PackingSlip returns what goes into the parcel of a fulfillment and where it goes
+	PackingSlip(ctx context.Context, id int64) (*domain.PackingSlip, error)
+	// Tracking returns the tracking events of a fulfillment, oldest first
+	Tracking(ctx context.Context, id int64) ([]domain.TrackingEvent, error)
+	// RefreshTracking asks the configured carrier about every shipment on its way and applies
+	// what it reports
+	RefreshTracking(ctx context.Context) error
+}
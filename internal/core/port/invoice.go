@@ -0,0 +1,30 @@
+package port
+
+import (
+	"context"
+	"io"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+type InvoiceRepository interface {
+	// CreateInvoice takes the next number of the invoice's year and inserts the invoice in one
+	// transaction, so a failed insert does not use up a number. Fails with ErrConflictingData when
+	// the order already has an invoice.
+	CreateInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error)
+	GetOrderInvoice(ctx context.Context, orderID int64) (*domain.Invoice, error)
+}
+
+// InvoiceRenderer is an interface for turning an invoice into a printable document
+type InvoiceRenderer interface {
+	// Render returns the PDF of the invoice
+	Render(document *domain.InvoiceDocument) ([]byte, error)
+}
+
+type InvoiceService interface {
+	// IssueInvoice numbers the invoice of a paid order from its items, an order is invoiced once.
+	// Fails with ErrOrderNotPaid when the payment of the order was not recorded.
+	IssueInvoice(ctx context.Context, order *domain.Order) (*domain.Invoice, error)
+	// InvoicePDF returns the PDF invoice of a paid order
+	InvoicePDF(ctx context.Context, orderID int64) (*domain.Invoice, io.ReadSeekCloser, *domain.BlobInfo, error)
+}
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// MailService is an interface for sending emails
+type MailService interface {
+	// Send sends an email message
+	Send(ctx context.Context, mail *domain.Mail) error
+}
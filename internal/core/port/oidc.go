@@ -0,0 +1,44 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// OIDCProvider is an interface for an OpenID Connect identity provider
+type OIDCProvider interface {
+	// Name returns the name the provider is configured under
+	Name() string
+	// AuthCodeURL returns the URL to redirect the user to for the authorization code flow with PKCE
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange exchanges an authorization code and verifies the returned ID token
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCClaims, error)
+}
+
+// OIDCStateRepository is an interface for storing pending authorization requests
+type OIDCStateRepository interface {
+	// SaveAuthRequest stores an authorization request until it expires
+	SaveAuthRequest(ctx context.Context, request *domain.OIDCAuthRequest, ttl time.Duration) error
+	// TakeAuthRequest returns and deletes the authorization request of a state
+	TakeAuthRequest(ctx context.Context, state string) (*domain.OIDCAuthRequest, error)
+}
+
+// UserIdentityRepository is an interface for interacting with external identity data
+type UserIdentityRepository interface {
+	// GetUserIdentity selects the identity of a provider subject
+	GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	// CreateUserIdentity inserts a new identity
+	CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error)
+}
+
+// OIDCService is an interface for signing in with external identity providers
+type OIDCService interface {
+	// AuthURL starts a login with the provider and returns the URL to redirect the user to
+	// with the binding the browser has to present on the callback
+	AuthURL(ctx context.Context, provider string) (*domain.OIDCLogin, error)
+	// Callback completes a login started by the browser with the binding and returns our own
+	// tokens for the linked user
+	Callback(ctx context.Context, provider, state, code string, binding domain.OIDCBinding) (*domain.LoginResult, error)
+}
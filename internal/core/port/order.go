@@ -2,18 +2,41 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 )
 
 type OrderRepository interface {
+	// CreateOrder inserts an order with its items and coupon redemption in one transaction and
+	// takes the ordered physical editions out of stock, failing with ErrCouponExhausted when the
+	// coupon reached a usage limit in the meantime and ErrOutOfStock when a format sold out
 	CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error)
 	GetOrderById(ctx context.Context, id int64) (*domain.Order, error)
 	OrderLists(ctx context.Context, skip, limit int64) ([]domain.Order, error)
+	// ListUserOrders selects the orders of a user with pagination
+	ListUserOrders(ctx context.Context, userID, skip, limit int64) ([]domain.Order, error)
+	// UpdateOrderStatus stores the status of an order
+	UpdateOrderStatus(ctx context.Context, id int64, status domain.OrderStatus) error
+	// MarkOrderPaid stores when an order was paid, failing with ErrOrderAlreadyPaid when its
+	// payment was recorded before
+	MarkOrderPaid(ctx context.Context, id int64, paidAt time.Time) error
 }
 
 type OrderService interface {
-	CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error)
+	// CreateOrder places an order, the shipping and billing addresses are copied from the
+	// address book, falling back to the user's defaults when the ids are zero
+	CreateOrder(ctx context.Context, order *domain.Order, shippingAddressID, billingAddressID int64) (*domain.Order, error)
+	// QuoteOrder prices the items of an order, applies its coupon and the running promotions and
+	// ships and taxes it for the shipping address without placing it
+	QuoteOrder(ctx context.Context, order *domain.Order, shippingAddressID int64) (*domain.Order, error)
+	// QuoteShipping returns the cost of every shipping method available for an order and the
+	// shipping address, cheapest first
+	QuoteShipping(ctx context.Context, order *domain.Order, shippingAddressID int64) ([]domain.ShippingQuote, error)
 	GetOrder(ctx context.Context, id int64) (*domain.Order, error)
 	OrderLists(ctx context.Context, skip, limit int64) ([]domain.Order, error)
+	// ListUserOrders returns the orders of a user with pagination
+	ListUserOrders(ctx context.Context, userID, skip, limit int64) ([]domain.Order, error)
+	// MarkOrderPaid records the payment of an order and issues its invoice
+	MarkOrderPaid(ctx context.Context, id int64) (*domain.Order, error)
 }
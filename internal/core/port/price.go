@@ -0,0 +1,49 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// PriceRepository is an interface for interacting with price history and scheduled prices
+type PriceRepository interface {
+	// ListPriceHistory selects the price changes of an edition, oldest first
+	ListPriceHistory(ctx context.Context, editionID int64) ([]domain.PriceChange, error)
+	// GetLowestPrice selects the lowest price an edition had at any point since the given time
+	GetLowestPrice(ctx context.Context, editionID int64, since time.Time) (float64, error)
+	// CreatePriceSchedule inserts a scheduled price, failing with ErrPriceScheduleOverlap when it
+	// overlaps a pending or active schedule of the edition
+	CreatePriceSchedule(ctx context.Context, schedule *domain.PriceSchedule) (*domain.PriceSchedule, error)
+	// GetPriceSchedule selects a scheduled price of an edition by id
+	GetPriceSchedule(ctx context.Context, editionID, id int64) (*domain.PriceSchedule, error)
+	// ListPriceSchedules selects the scheduled prices of an edition, optionally only pending and active ones
+	ListPriceSchedules(ctx context.Context, editionID int64, openOnly bool) ([]domain.PriceSchedule, error)
+	// CancelPriceSchedule marks a pending scheduled price as cancelled
+	CancelPriceSchedule(ctx context.Context, id int64) error
+	// EndPriceSchedule moves the end of an active scheduled price to the given time
+	EndPriceSchedule(ctx context.Context, id int64, at time.Time) error
+	// ListDuePriceSchedules selects the pending schedules that should have started and the
+	// active schedules that should have ended by the given time
+	ListDuePriceSchedules(ctx context.Context, now time.Time) ([]domain.PriceSchedule, error)
+	// ApplyPriceSchedule sets the edition's price to the scheduled one and remembers the previous price
+	ApplyPriceSchedule(ctx context.Context, id int64) (*domain.PriceSchedule, error)
+	// RevertPriceSchedule restores the price the edition had before the schedule was applied
+	RevertPriceSchedule(ctx context.Context, id int64) (*domain.PriceSchedule, error)
+}
+
+// PricingService is an interface for managing the price history and scheduled prices of editions
+type PricingService interface {
+	// GetPriceTimeline returns the price history, lowest recent price and upcoming prices of an
+	// edition of a book
+	GetPriceTimeline(ctx context.Context, bookID, editionID int64) (*domain.PriceTimeline, error)
+	// SchedulePrice schedules a future price for an edition of a book
+	SchedulePrice(ctx context.Context, schedule *domain.PriceSchedule) (*domain.PriceSchedule, error)
+	// ListPriceSchedules returns all scheduled prices of an edition of a book
+	ListPriceSchedules(ctx context.Context, bookID, editionID int64) ([]domain.PriceSchedule, error)
+	// CancelPriceSchedule cancels a pending schedule or ends an active one right away
+	CancelPriceSchedule(ctx context.Context, bookID, editionID, id int64) error
+	// ApplyDuePriceSchedules starts and ends the scheduled prices that are due
+	ApplyDuePriceSchedules(ctx context.Context) error
+}
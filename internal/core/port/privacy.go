@@ -0,0 +1,37 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// ErasureRepository is an interface for interacting with erasure requests
+type ErasureRepository interface {
+	// CreateErasureRequest inserts a new erasure request
+	CreateErasureRequest(ctx context.Context, request *domain.ErasureRequest) (*domain.ErasureRequest, error)
+	// GetPendingErasureRequest selects the pending erasure request of a user
+	GetPendingErasureRequest(ctx context.Context, userID int64) (*domain.ErasureRequest, error)
+	// CancelErasureRequest marks a pending erasure request as cancelled
+	CancelErasureRequest(ctx context.Context, id int64) error
+	// ListDueErasureRequests selects the pending erasure requests scheduled at or before the given time
+	ListDueErasureRequests(ctx context.Context, before time.Time) ([]domain.ErasureRequest, error)
+	// EraseUser anonymizes the user, removes their personal data while keeping their orders and
+	// marks the request as completed in a single transaction
+	EraseUser(ctx context.Context, request *domain.ErasureRequest) error
+}
+
+// PrivacyService is an interface for exporting and erasing personal data
+type PrivacyService interface {
+	// ExportUserData collects the personal data held about a user
+	ExportUserData(ctx context.Context, userID int64) (*domain.DataExport, error)
+	// RequestErasure schedules the erasure of a user's data after the grace period
+	RequestErasure(ctx context.Context, userID, requestedBy int64) (*domain.ErasureRequest, error)
+	// GetErasureRequest returns the pending erasure request of a user
+	GetErasureRequest(ctx context.Context, userID int64) (*domain.ErasureRequest, error)
+	// CancelErasure cancels the pending erasure request of a user
+	CancelErasure(ctx context.Context, userID int64) error
+	// ProcessDueErasures carries out the erasures whose grace period has passed
+	ProcessDueErasures(ctx context.Context) error
+}
@@ -0,0 +1,48 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// PromotionRepository is an interface for interacting with coupons and automatic promotions
+type PromotionRepository interface {
+	// CreateCoupon inserts a coupon, failing with ErrConflictingData when the code is taken
+	CreateCoupon(ctx context.Context, coupon *domain.Coupon) (*domain.Coupon, error)
+	// GetCouponByCode selects a coupon by its normalized code
+	GetCouponByCode(ctx context.Context, code string) (*domain.Coupon, error)
+	// ListCoupons selects coupons with pagination
+	ListCoupons(ctx context.Context, skip, limit int64) ([]domain.Coupon, error)
+	// DeactivateCoupon marks a coupon as inactive
+	DeactivateCoupon(ctx context.Context, id int64) (*domain.Coupon, error)
+	// GetCouponUsage counts the redemptions of a coupon in total and by the given user
+	GetCouponUsage(ctx context.Context, couponID, userID int64) (domain.CouponUsage, error)
+	// CreatePromotion inserts an automatic promotion
+	CreatePromotion(ctx context.Context, promotion *domain.AutomaticPromotion) (*domain.AutomaticPromotion, error)
+	// ListPromotions selects automatic promotions with pagination
+	ListPromotions(ctx context.Context, skip, limit int64) ([]domain.AutomaticPromotion, error)
+	// ListActivePromotions selects the automatic promotions running at the given time
+	ListActivePromotions(ctx context.Context, now time.Time) ([]domain.AutomaticPromotion, error)
+	// DeactivatePromotion marks an automatic promotion as inactive
+	DeactivatePromotion(ctx context.Context, id int64) (*domain.AutomaticPromotion, error)
+}
+
+// PromotionService is an interface for managing promotions and evaluating them against baskets
+type PromotionService interface {
+	// CreateCoupon validates and creates a coupon
+	CreateCoupon(ctx context.Context, coupon *domain.Coupon) (*domain.Coupon, error)
+	// ListCoupons returns coupons with pagination
+	ListCoupons(ctx context.Context, skip, limit int64) ([]domain.Coupon, error)
+	// DeactivateCoupon stops a coupon from being redeemed
+	DeactivateCoupon(ctx context.Context, id int64) error
+	// CreatePromotion validates and creates an automatic promotion
+	CreatePromotion(ctx context.Context, promotion *domain.AutomaticPromotion) (*domain.AutomaticPromotion, error)
+	// ListPromotions returns automatic promotions with pagination
+	ListPromotions(ctx context.Context, skip, limit int64) ([]domain.AutomaticPromotion, error)
+	// DeactivatePromotion stops an automatic promotion
+	DeactivatePromotion(ctx context.Context, id int64) error
+	// Evaluate loads the running promotions and the basket's coupon and applies them to the basket
+	Evaluate(ctx context.Context, basket *domain.Basket) (*domain.PromotionResult, error)
+}
@@ -0,0 +1,65 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// PublisherRepository is an interface for interacting with publishers
+type PublisherRepository interface {
+	// CreatePublisher inserts a publisher, failing with ErrConflictingData when the name is taken
+	CreatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error)
+	// GetPublisher selects a publisher by id
+	GetPublisher(ctx context.Context, id int64) (*domain.Publisher, error)
+	// ListPublishers selects all publishers ordered by name
+	ListPublishers(ctx context.Context) ([]domain.Publisher, error)
+	// UpdatePublisher updates the name and website of a publisher
+	UpdatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error)
+	// DeletePublisher deletes a publisher, its books and series are kept without a publisher
+	DeletePublisher(ctx context.Context, id int64) error
+}
+
+// PublisherService is an interface for managing publishers
+type PublisherService interface {
+	// CreatePublisher creates a publisher
+	CreatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error)
+	// GetPublisher returns a publisher by id
+	GetPublisher(ctx context.Context, id int64) (*domain.Publisher, error)
+	// ListPublishers returns all publishers
+	ListPublishers(ctx context.Context) ([]domain.Publisher, error)
+	// ListPublisherBooks returns the books of a publisher with pagination
+	ListPublisherBooks(ctx context.Context, id, skip, limit int64) ([]domain.Book, error)
+	// UpdatePublisher updates a publisher
+	UpdatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error)
+	// DeletePublisher deletes a publisher
+	DeletePublisher(ctx context.Context, id int64) error
+}
+
+// SeriesRepository is an interface for interacting with book series
+type SeriesRepository interface {
+	// CreateSeries inserts a series, failing with ErrUnknownPublisher when its publisher does not exist
+	CreateSeries(ctx context.Context, series *domain.Series) (*domain.Series, error)
+	// GetSeries selects a series by id without its volumes
+	GetSeries(ctx context.Context, id int64) (*domain.Series, error)
+	// ListSeries selects all series ordered by name
+	ListSeries(ctx context.Context) ([]domain.Series, error)
+	// UpdateSeries updates the name, description and publisher of a series
+	UpdateSeries(ctx context.Context, series *domain.Series) (*domain.Series, error)
+	// DeleteSeries deletes a series, its books are kept outside of any series
+	DeleteSeries(ctx context.Context, id int64) error
+}
+
+// SeriesService is an interface for managing book series
+type SeriesService interface {
+	// CreateSeries creates a series
+	CreateSeries(ctx context.Context, series *domain.Series) (*domain.Series, error)
+	// GetSeries returns a series with its volumes in reading order
+	GetSeries(ctx context.Context, id int64) (*domain.Series, error)
+	// ListSeries returns all series without their volumes
+	ListSeries(ctx context.Context) ([]domain.Series, error)
+	// UpdateSeries updates a series
+	UpdateSeries(ctx context.Context, series *domain.Series) (*domain.Series, error)
+	// DeleteSeries deletes a series
+	DeleteSeries(ctx context.Context, id int64) error
+}
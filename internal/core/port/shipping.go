@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// ShippingRepository is an interface for interacting with shipping zones and methods
+type ShippingRepository interface {
+	// CreateZone inserts a shipping zone
+	CreateZone(ctx context.Context, zone *domain.ShippingZone) (*domain.ShippingZone, error)
+	// ListZones selects all shipping zones
+	ListZones(ctx context.Context) ([]domain.ShippingZone, error)
+	// CreateMethod inserts a shipping method, failing with ErrDataNotFound when its zone does not exist
+	CreateMethod(ctx context.Context, method *domain.ShippingMethod) (*domain.ShippingMethod, error)
+	// ListMethods selects the shipping methods of all zones
+	ListMethods(ctx context.Context) ([]domain.ShippingMethod, error)
+	// DisableMethod marks a shipping method as inactive
+	DisableMethod(ctx context.Context, id int64) error
+	// ListMethodsForCountry selects the active methods of the zones listing the country, or of the
+	// fallback zones when no zone lists it
+	ListMethodsForCountry(ctx context.Context, country string) ([]domain.ShippingMethod, error)
+}
+
+// ShippingService is an interface for managing shipping and quoting shipments
+type ShippingService interface {
+	// CreateZone validates and creates a shipping zone
+	CreateZone(ctx context.Context, zone *domain.ShippingZone) (*domain.ShippingZone, error)
+	// ListZones returns all shipping zones
+	ListZones(ctx context.Context) ([]domain.ShippingZone, error)
+	// CreateMethod validates and creates a shipping method
+	CreateMethod(ctx context.Context, method *domain.ShippingMethod) (*domain.ShippingMethod, error)
+	// ListMethods returns the shipping methods of all zones
+	ListMethods(ctx context.Context) ([]domain.ShippingMethod, error)
+	// DisableMethod stops offering a shipping method
+	DisableMethod(ctx context.Context, id int64) error
+	// QuoteMethods returns the cost of every method available for the shipment, cheapest first
+	QuoteMethods(ctx context.Context, shipment domain.Shipment) ([]domain.ShippingQuote, error)
+}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// TaxCalculator is an interface for computing the tax of an order. The built-in implementation
+// looks rates up in a table, an external tax provider is plugged in by implementing this
+// interface in an adapter and selecting it with TAX_PROVIDER.
+type TaxCalculator interface {
+	// Calculate returns a tax line for every line of the request
+	Calculate(ctx context.Context, request *domain.TaxRequest) (*domain.TaxResult, error)
+}
@@ -0,0 +1,52 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// TranslationRepository is an interface for interacting with the translations of catalog content
+type TranslationRepository interface {
+	// UpsertBookTranslation inserts or replaces the translation of a book in a locale, failing with
+	// ErrDataNotFound when the book does not exist
+	UpsertBookTranslation(ctx context.Context, translation *domain.BookTranslation) (*domain.BookTranslation, error)
+	// ListBookTranslations selects the translations of a book ordered by locale
+	ListBookTranslations(ctx context.Context, bookID int64) ([]domain.BookTranslation, error)
+	// FindBookTranslations selects the translations of the books in the locales
+	FindBookTranslations(ctx context.Context, bookIDs []int64, locales []string) ([]domain.BookTranslation, error)
+	// DeleteBookTranslation deletes the translation of a book in a locale
+	DeleteBookTranslation(ctx context.Context, bookID int64, locale string) error
+	// UpsertCategoryTranslation inserts or replaces the translation of a category in a locale,
+	// failing with ErrDataNotFound when the category does not exist
+	UpsertCategoryTranslation(ctx context.Context, translation *domain.CategoryTranslation) (*domain.CategoryTranslation, error)
+	// ListCategoryTranslations selects the translations of a category ordered by locale
+	ListCategoryTranslations(ctx context.Context, categoryID int64) ([]domain.CategoryTranslation, error)
+	// FindCategoryTranslations selects the translations of the categories in the locales
+	FindCategoryTranslations(ctx context.Context, categoryIDs []int64, locales []string) ([]domain.CategoryTranslation, error)
+	// DeleteCategoryTranslation deletes the translation of a category in a locale
+	DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error
+}
+
+// TranslationService is an interface for translating catalog content and localizing it to the
+// locale chain of the request
+type TranslationService interface {
+	// SetBookTranslation creates or replaces the translation of a book
+	SetBookTranslation(ctx context.Context, translation *domain.BookTranslation) (*domain.BookTranslation, error)
+	// ListBookTranslations returns the translations of a book
+	ListBookTranslations(ctx context.Context, bookID int64) ([]domain.BookTranslation, error)
+	// DeleteBookTranslation deletes the translation of a book in a locale
+	DeleteBookTranslation(ctx context.Context, bookID int64, locale string) error
+	// SetCategoryTranslation creates or replaces the translation of a category
+	SetCategoryTranslation(ctx context.Context, translation *domain.CategoryTranslation) (*domain.CategoryTranslation, error)
+	// ListCategoryTranslations returns the translations of a category
+	ListCategoryTranslations(ctx context.Context, categoryID int64) ([]domain.CategoryTranslation, error)
+	// DeleteCategoryTranslation deletes the translation of a category in a locale
+	DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error
+	// LocalizeBooks replaces the name and description of the books with their translations in the
+	// locale chain of the context, books without a translation keep their base content
+	LocalizeBooks(ctx context.Context, books []domain.Book) error
+	// LocalizeCategories replaces the name of the categories with their translations in the locale
+	// chain of the context
+	LocalizeCategories(ctx context.Context, categories []domain.Category) error
+}
@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// TwoFactorRepository is an interface for interacting with two-factor authentication data
+type TwoFactorRepository interface {
+	// GetTwoFactor selects the second factor of a user
+	GetTwoFactor(ctx context.Context, userID int64) (*domain.TwoFactor, error)
+	// SaveTwoFactor inserts or replaces a pending, unconfirmed second factor
+	SaveTwoFactor(ctx context.Context, twoFactor *domain.TwoFactor) error
+	// ConfirmTwoFactor enables the second factor and replaces the user's recovery codes
+	ConfirmTwoFactor(ctx context.Context, userID, step int64, recoveryCodeHashes []string) error
+	// UseTwoFactorStep records the time step of an accepted code, it fails if the step was already used
+	UseTwoFactorStep(ctx context.Context, userID, step int64) error
+	// UseRecoveryCode marks an unused recovery code as used
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error
+	// DeleteTwoFactor deletes the second factor and recovery codes of a user
+	DeleteTwoFactor(ctx context.Context, userID int64) error
+}
+
+// TwoFactorService is an interface for managing two-factor authentication enrollment
+type TwoFactorService interface {
+	// Enroll generates a new TOTP secret for the user, it has to be confirmed before it is enforced
+	Enroll(ctx context.Context, user *domain.User) (*domain.TwoFactorEnrollment, error)
+	// Confirm enables two-factor authentication with a valid code and returns the recovery codes
+	Confirm(ctx context.Context, user *domain.User, code string) ([]string, error)
+	// Disable disables two-factor authentication with a valid TOTP or recovery code
+	Disable(ctx context.Context, user *domain.User, code string) error
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 )
@@ -13,12 +14,28 @@ type UserRepository interface {
 	GetUserById(ctx context.Context, id int64) (*domain.User, error)
 	// GetUserByEmai selects a User by email
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
-	// ListUsers selects a list of Users with pagination
-	ListUsers(ctx context.Context, skip, limit int64) ([]domain.User, error)
-	// UpdateUser updates a User
+	// ListUsers selects a list of Users with pagination, optionally including soft-deleted users
+	ListUsers(ctx context.Context, skip, limit int64, includeDeleted bool) ([]domain.User, error)
+	// UpdateUser updates the name of a User and bumps its version, a non-zero version must match the stored one
 	UpdateUser(ctx context.Context, User *domain.User) (*domain.User, error)
-	// DeleteUser deletes a User
-	DeleteUser(ctx context.Context, id int64) error
+	// UpdatePassword stores a new password hash and invalidates the user's sessions
+	UpdatePassword(ctx context.Context, id int64, hashedPassword string) (*domain.User, error)
+	// DeleteUser soft-deletes a User and invalidates their sessions, a non-zero version must match the stored one
+	DeleteUser(ctx context.Context, id, version int64) error
+	// RestoreUser undoes the soft delete of a User
+	RestoreUser(ctx context.Context, id int64) (*domain.User, error)
+	// PurgeUsers hard-deletes Users soft-deleted before the given time that nothing references
+	PurgeUsers(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// EmailChangeRepository is an interface for interacting with pending email changes
+type EmailChangeRepository interface {
+	// CreateEmailChange inserts a new email change into the database
+	CreateEmailChange(ctx context.Context, change *domain.EmailChange) (*domain.EmailChange, error)
+	// GetEmailChangeByTokenHash selects an email change by its token hash
+	GetEmailChangeByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailChange, error)
+	// ConfirmEmailChange marks the change as used and updates the user's email in a single transaction
+	ConfirmEmailChange(ctx context.Context, change *domain.EmailChange) (*domain.User, error)
 }
 
 type UserService interface {
@@ -26,10 +43,21 @@ type UserService interface {
 	Register(ctx context.Context, user *domain.User) (*domain.User, error)
 	// GetUser returns a user by id
 	GetUser(ctx context.Context, id int64) (*domain.User, error)
-	// ListUsers returns a list of users with pagination
-	ListUsers(ctx context.Context, skip, limit int64) ([]domain.User, error)
-	// UpdateUser updates a user
-	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
-	// DeleteUser deletes a user
-	DeleteUser(ctx context.Context, id int64) error
+	// ListUsers returns a list of users with pagination, optionally including soft-deleted users
+	ListUsers(ctx context.Context, skip, limit int64, includeDeleted bool) ([]domain.User, error)
+	// UpdateUser updates a user's name and email, an email change has to be verified first
+	UpdateUser(ctx context.Context, user *domain.User) (*domain.UserUpdate, error)
+	// PatchUser partially updates a user, an email change has to be verified first
+	PatchUser(ctx context.Context, id int64, patch *domain.UserPatch) (*domain.UserUpdate, error)
+	// ChangePassword changes the password after checking the current one and returns a new access token
+	// that keeps the two-factor state of the caller's session
+	ChangePassword(ctx context.Context, id int64, twoFactorVerified bool, currentPassword, newPassword string) (string, error)
+	// VerifyEmailChange applies a pending email change using its verification token
+	VerifyEmailChange(ctx context.Context, token string) (*domain.User, error)
+	// DeleteUser soft-deletes a user, a non-zero version must match the current one
+	DeleteUser(ctx context.Context, id, version int64) error
+	// RestoreUser restores a soft-deleted user
+	RestoreUser(ctx context.Context, id int64) (*domain.User, error)
+	// PurgeDeletedUsers hard-deletes users that were soft-deleted longer than the retention ago
+	PurgeDeletedUsers(ctx context.Context, retention time.Duration) (int64, error)
 }
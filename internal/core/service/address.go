@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type AddressService struct {
+	repo  port.AddressRepository
+	audit port.AuditService
+}
+
+func NewAddressService(repo port.AddressRepository, audit port.AuditService) *AddressService {
+	return &AddressService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+// CreateAddress validates and adds an address to the user's address book
+func (as *AddressService) CreateAddress(ctx context.Context, address *domain.Address) (*domain.Address, error) {
+	address.Normalize()
+	if err := address.Validate(); err != nil {
+		return nil, err
+	}
+	address, err := as.repo.CreateAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	as.audit.Record(ctx, domain.AuditAddressCreate, domain.AuditEntityAddress, address.ID, nil, address)
+	return address, nil
+}
+
+// GetAddress returns an address of a user
+func (as *AddressService) GetAddress(ctx context.Context, userID, id int64) (*domain.Address, error) {
+	return as.repo.GetAddressById(ctx, userID, id)
+}
+
+// ListAddresses returns the address book of a user
+func (as *AddressService) ListAddresses(ctx context.Context, userID int64) ([]domain.Address, error) {
+	return as.repo.ListAddresses(ctx, userID)
+}
+
+// UpdateAddress validates and updates an address of a user
+func (as *AddressService) UpdateAddress(ctx context.Context, address *domain.Address) (*domain.Address, error) {
+	address.Normalize()
+	if err := address.Validate(); err != nil {
+		return nil, err
+	}
+	before, err := as.repo.GetAddressById(ctx, address.UserID, address.ID)
+	if err != nil {
+		return nil, err
+	}
+	address, err = as.repo.UpdateAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	as.audit.Record(ctx, domain.AuditAddressUpdate, domain.AuditEntityAddress, address.ID, before, address)
+	return address, nil
+}
+
+// DeleteAddress deletes an address of a user, orders keep their own copy of it
+func (as *AddressService) DeleteAddress(ctx context.Context, userID, id int64) error {
+	before, err := as.repo.GetAddressById(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := as.repo.DeleteAddress(ctx, userID, id); err != nil {
+		return err
+	}
+	as.audit.Record(ctx, domain.AuditAddressDelete, domain.AuditEntityAddress, id, before, nil)
+	return nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
+)
+
+// apiKeyPrefix marks our api keys so they are easy to recognise, e.g. in secret scanners
+const apiKeyPrefix = "bsk_"
+
+type APIKeyService struct {
+	repo     port.APIKeyRepository
+	userRepo port.UserRepository
+	audit    port.AuditService
+}
+
+func NewAPIKeyService(repo port.APIKeyRepository, userRepo port.UserRepository, audit port.AuditService) *APIKeyService {
+	return &APIKeyService{
+		repo:     repo,
+		userRepo: userRepo,
+		audit:    audit,
+	}
+}
+
+// CreateAPIKey creates an api key, a zero expiresIn creates a key that does not expire
+func (as *APIKeyService) CreateAPIKey(ctx context.Context, user *domain.User, name string, scopes []domain.APIKeyScope, expiresIn time.Duration) (*domain.APIKey, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", domain.ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(domain.APIKeyScopes, scope) {
+			return nil, "", domain.ErrInvalidScope
+		}
+	}
+
+	prefix, err := util.GenerateRandomToken(6)
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+	secret, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+	prefix = apiKeyPrefix + prefix
+
+	key := &domain.APIKey{
+		UserID:     user.ID,
+		Name:       name,
+		Prefix:     prefix,
+		SecretHash: util.HashToken(secret),
+		Scopes:     scopes,
+	}
+	if expiresIn > 0 {
+		expiresAt := time.Now().Add(expiresIn)
+		key.ExpiresAt = &expiresAt
+	}
+
+	key, err = as.repo.CreateAPIKey(ctx, key)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, "", err
+		}
+		return nil, "", domain.ErrInternal
+	}
+	as.audit.Record(ctx, domain.AuditAPIKeyCreate, domain.AuditEntityAPIKey, key.ID, nil, key)
+	return key, prefix + "." + secret, nil
+}
+
+// ListAPIKeys returns the api keys of a user
+func (as *APIKeyService) ListAPIKeys(ctx context.Context, user *domain.User) ([]domain.APIKey, error) {
+	keys, err := as.repo.ListAPIKeys(ctx, user.ID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes an api key, keys of other users are reported as not found unless the user is an admin
+func (as *APIKeyService) RevokeAPIKey(ctx context.Context, user *domain.User, id int64) error {
+	key, err := as.repo.GetAPIKeyById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.UserID != user.ID && user.Role != domain.Admin {
+		return domain.ErrDataNotFound
+	}
+	if err := as.repo.RevokeAPIKey(ctx, id); err != nil {
+		return err
+	}
+	as.audit.Record(ctx, domain.AuditAPIKeyRevoke, domain.AuditEntityAPIKey, id, key, nil)
+	return nil
+}
+
+// Authenticate verifies a plain api key and returns the identity of its owner limited to the key's scopes
+func (as *APIKeyService) Authenticate(ctx context.Context, plain string) (*domain.Identity, error) {
+	prefix, secret, ok := strings.Cut(plain, ".")
+	if !ok || !strings.HasPrefix(prefix, apiKeyPrefix) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := as.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, domain.ErrInternal
+	}
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(util.HashToken(secret))) != 1 {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if !key.IsActive(time.Now()) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	user, err := as.userRepo.GetUserById(ctx, key.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if err := as.repo.TouchAPIKey(ctx, key.ID); err != nil {
+		slog.Warn("failed to record api key usage", "api_key_id", key.ID, "error", err)
+	}
+
+	return &domain.Identity{
+		User:   user,
+		APIKey: key,
+	}, nil
+}
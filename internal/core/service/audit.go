@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// redactedAuditFields never end up in the audit log
+var redactedAuditFields = map[string]bool{
+	"password":      true,
+	"secret":        true,
+	"secret_hash":   true,
+	"token_hash":    true,
+	"code_verifier": true,
+}
+
+// redactedAuditValue replaces personal data in the audit log. Entries can never be changed, so
+// erasing a user could not remove it later, the log only keeps that such a field changed.
+const redactedAuditValue = "[redacted]"
+
+// personalAuditFields hold personal data wherever they appear, including inside the address
+// snapshots of orders
+var personalAuditFields = map[string]bool{
+	"email":       true,
+	"full_name":   true,
+	"line1":       true,
+	"line2":       true,
+	"postal_code": true,
+	"phone":       true,
+	"subject":     true,
+}
+
+// personalEntityAuditFields hold personal data only on some entities, the name of a publisher
+// or category is not personal
+var personalEntityAuditFields = map[string]map[string]bool{
+	domain.AuditEntityUser: {"name": true},
+}
+
+type AuditService struct {
+	repo port.AuditRepository
+}
+
+func NewAuditService(repo port.AuditRepository) *AuditService {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+// Record appends the change to the audit log. Auditing never fails the change itself,
+// errors are logged instead.
+func (as *AuditService) Record(ctx context.Context, action, entityType string, entityID int64, before, after any) {
+	meta := domain.AuditMetaFrom(ctx)
+	entry := &domain.AuditEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		RequestID:  meta.RequestID,
+		IP:         meta.IP,
+	}
+	if meta.ActorID != 0 {
+		entry.ActorID = &meta.ActorID
+	}
+
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		slog.Error("failed to diff audited change", "action", action, "entity_id", entityID, "error", err)
+		changes = map[string]domain.AuditChange{}
+	}
+	entry.Changes = redactAuditChanges(entityType, changes)
+
+	// The change has already happened, so the entry is written even if the request was cancelled
+	if _, err := as.repo.CreateAuditEntry(context.WithoutCancel(ctx), entry); err != nil {
+		slog.Error("failed to write audit entry", "action", action, "entity_id", entityID, "error", err)
+	}
+}
+
+func (as *AuditService) ListAuditEntries(ctx context.Context, filter *domain.AuditFilter) ([]domain.AuditEntry, error) {
+	return as.repo.ListAuditEntries(ctx, filter)
+}
+
+// auditDiff returns the fields that differ between the JSON forms of before and after
+func auditDiff(before, after any) (map[string]domain.AuditChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]domain.AuditChange{}
+	for field, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[field]) {
+			changes[field] = domain.AuditChange{Before: value, After: afterFields[field]}
+		}
+	}
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok && value != nil {
+			changes[field] = domain.AuditChange{After: value}
+		}
+	}
+	return changes, nil
+}
+
+// redactAuditChanges replaces the values of personal fields, at any depth, by redactedAuditValue
+func redactAuditChanges(entityType string, changes map[string]domain.AuditChange) map[string]domain.AuditChange {
+	personal := personalEntityAuditFields[entityType]
+	for field, change := range changes {
+		if personalAuditFields[field] || personal[field] {
+			changes[field] = domain.AuditChange{Before: redactedValue(change.Before), After: redactedValue(change.After)}
+			continue
+		}
+		changes[field] = domain.AuditChange{Before: redactNested(change.Before), After: redactNested(change.After)}
+	}
+	return changes
+}
+
+// redactedValue keeps whether a value was there but not the value itself
+func redactedValue(value any) any {
+	if value == nil {
+		return nil
+	}
+	return redactedAuditValue
+}
+
+// redactNested redacts the personal fields of the objects nested in a JSON value
+func redactNested(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for field, nested := range v {
+			if personalAuditFields[snakeCase(field)] {
+				v[field] = redactedValue(nested)
+			} else {
+				v[field] = redactNested(nested)
+			}
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = redactNested(nested)
+		}
+	}
+	return value
+}
+
+// auditFields flattens a value into its top level JSON fields with snake_case names
+func auditFields(value any) (map[string]any, error) {
+	fields := map[string]any{}
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		return fields, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for field, v := range raw {
+		field = snakeCase(field)
+		if !redactedAuditFields[field] {
+			fields[field] = v
+		}
+	}
+	return fields, nil
+}
+
+// snakeCase converts Go field names such as TokenVersion or UserID to token_version and user_id
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// memoryAudit is a port.AuditRepository keeping the entries it was given
+type memoryAudit struct {
+	port.AuditRepository
+	entries []*domain.AuditEntry
+}
+
+func (m *memoryAudit) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error) {
+	m.entries = append(m.entries, entry)
+	return entry, nil
+}
+
+func TestRecordRedactsPersonalData(t *testing.T) {
+	address := &domain.AddressSnapshot{FullName: "Ada Reader", Line1: "1 Main St", City: "Khartoum", Country: "SD", Phone: "+249"}
+
+	tests := []struct {
+		name       string
+		entityType string
+		before     any
+		after      any
+		want       map[string]domain.AuditChange
+	}{
+		{
+			name:       "user profile",
+			entityType: domain.AuditEntityUser,
+			before:     &domain.User{ID: 1, Email: "old@example.com", Name: "Ada", Password: "hash", Version: 1},
+			after:      &domain.User{ID: 1, Email: "new@example.com", Name: "Ada Reader", Password: "hash", Version: 2},
+			want: map[string]domain.AuditChange{
+				"email":   {Before: redactedAuditValue, After: redactedAuditValue},
+				"name":    {Before: redactedAuditValue, After: redactedAuditValue},
+				"version": {Before: float64(1), After: float64(2)},
+			},
+		},
+		{
+			name:       "name of another entity",
+			entityType: domain.AuditEntityPublisher,
+			after:      map[string]string{"name": "Penguin"},
+			want:       map[string]domain.AuditChange{"name": {After: "Penguin"}},
+		},
+		{
+			name:       "new address",
+			entityType: domain.AuditEntityAddress,
+			after:      &domain.Address{FullName: "Ada Reader", Line1: "1 Main St", City: "Khartoum", Country: "SD"},
+			want: map[string]domain.AuditChange{
+				"full_name": {After: redactedAuditValue},
+				"line1":     {After: redactedAuditValue},
+				"city":      {After: "Khartoum"},
+				"country":   {After: "SD"},
+			},
+		},
+		{
+			name:       "address snapshot of an order",
+			entityType: domain.AuditEntityOrder,
+			after:      map[string]any{"shipping_address": address},
+			want: map[string]domain.AuditChange{
+				"shipping_address": {After: map[string]any{
+					"full_name": redactedAuditValue,
+					"line1":     redactedAuditValue,
+					"city":      "Khartoum",
+					"country":   "SD",
+					"phone":     redactedAuditValue,
+				}},
+			},
+		},
+		{
+			name:       "linked identity",
+			entityType: domain.AuditEntityUser,
+			after:      &domain.UserIdentity{Provider: "google", Subject: "1234", Email: "ada@example.com"},
+			want: map[string]domain.AuditChange{
+				"provider": {After: "google"},
+				"subject":  {After: redactedAuditValue},
+				"email":    {After: redactedAuditValue},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryAudit{}
+			NewAuditService(repo).Record(context.Background(), "test.change", tt.entityType, 1, tt.before, tt.after)
+
+			if len(repo.entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(repo.entries))
+			}
+			got := repo.entries[0].Changes
+			for field, want := range tt.want {
+				if !reflect.DeepEqual(got[field], want) {
+					t.Errorf("changes[%s] = %#v, want %#v", field, got[field], want)
+				}
+			}
+		})
+	}
+}
@@ -2,42 +2,376 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
+	"github.com/golang-jwt/jwt/v5"
 )
 
+// PasswordResetTokenTTL is how long a password reset token can be redeemed
+var PasswordResetTokenTTL = time.Hour
+
 type AuthService struct {
-	repo         port.UserRepository
-	tokenService port.TokenService
+	repo          port.UserRepository
+	tokenService  port.TokenService
+	resetRepo     port.PasswordResetRepository
+	mailService   port.MailService
+	attemptRepo   port.LoginAttemptRepository
+	lockoutPolicy domain.LockoutPolicy
+	twoFactorRepo port.TwoFactorRepository
+	twoFactor     domain.TwoFactorPolicy
+	audit         port.AuditService
 }
 
-func NewAuthService(repo port.UserRepository, tokenService port.TokenService) *AuthService {
+func NewAuthService(
+	repo port.UserRepository,
+	tokenService port.TokenService,
+	resetRepo port.PasswordResetRepository,
+	mailService port.MailService,
+	attemptRepo port.LoginAttemptRepository,
+	lockoutPolicy domain.LockoutPolicy,
+	twoFactorRepo port.TwoFactorRepository,
+	twoFactor domain.TwoFactorPolicy,
+	audit port.AuditService,
+) *AuthService {
 	return &AuthService{
-		repo:         repo,
-		tokenService: tokenService,
+		repo:          repo,
+		tokenService:  tokenService,
+		resetRepo:     resetRepo,
+		mailService:   mailService,
+		attemptRepo:   attemptRepo,
+		lockoutPolicy: lockoutPolicy,
+		twoFactorRepo: twoFactorRepo,
+		twoFactor:     twoFactor,
+		audit:         audit,
 	}
 }
 
-func (as *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+// Login authenticates a user, failed attempts are tracked per account and per IP address
+func (as *AuthService) Login(ctx context.Context, email, password, ip string) (*domain.LoginResult, error) {
+	accountKey := accountLoginKey(email)
+	ipKey := ipLoginKey(ip)
+	now := time.Now()
+
+	ipAttempts, err := as.attemptRepo.GetLoginAttempts(ctx, ipKey)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	if ipAttempts.IsLocked(now) {
+		return nil, domain.ErrTooManyAttempts
+	}
+	accountAttempts, err := as.attemptRepo.GetLoginAttempts(ctx, accountKey)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	if accountAttempts.IsLocked(now) {
+		return nil, domain.ErrAccountLocked
+	}
+
+	// Unknown emails are tracked like known ones so lockouts do not reveal which accounts exist
 	user, err := as.repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
-			return "", domain.ErrInvalidCredentials
+			return nil, as.loginFailed(ctx, accountKey, ipKey)
 		}
-		return "", domain.ErrInternal
+		return nil, domain.ErrInternal
 	}
 
 	err = util.ComparePassword(password, user.Password)
 	if err != nil {
-		return "", domain.ErrInvalidCredentials
+		return nil, as.loginFailed(ctx, accountKey, ipKey)
+	}
+
+	if err := as.attemptRepo.ResetLoginAttempts(ctx, accountKey); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	twoFactor, err := as.twoFactorRepo.GetTwoFactor(ctx, user.ID)
+	if err != nil && err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+	if twoFactor.IsEnabled() {
+		challengeToken, err := as.tokenService.CreateChallengeToken(user)
+		if err != nil {
+			return nil, domain.ErrTokenCreation
+		}
+		return &domain.LoginResult{
+			ChallengeToken:    challengeToken,
+			TwoFactorRequired: true,
+		}, nil
+	}
+
+	accessToken, err := as.tokenService.CreateToken(user, false)
+	if err != nil {
+		return nil, domain.ErrTokenCreation
 	}
 
-	accessToken, err := as.tokenService.CreateToken(user)
+	return &domain.LoginResult{
+		AccessToken: accessToken,
+	}, nil
+}
+
+// loginFailed records a failed attempt, locks the account or IP address once a threshold
+// is reached and otherwise slows the caller down progressively
+func (as *AuthService) loginFailed(ctx context.Context, accountKey, ipKey string) error {
+	policy := as.lockoutPolicy
+	now := time.Now()
+
+	account, err := as.attemptRepo.RecordLoginFailure(ctx, accountKey, policy.FailureWindow)
 	if err != nil {
-		return "", domain.ErrTokenCreation
+		return domain.ErrInternal
+	}
+	ip, err := as.attemptRepo.RecordLoginFailure(ctx, ipKey, policy.FailureWindow)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if policy.MaxIPFailures > 0 && ip.Failures >= policy.MaxIPFailures {
+		if err := as.attemptRepo.LockLogin(ctx, ipKey, now.Add(policy.LockoutDuration)); err != nil {
+			return domain.ErrInternal
+		}
+		return domain.ErrTooManyAttempts
+	}
+	if policy.MaxAccountFailures > 0 && account.Failures >= policy.MaxAccountFailures {
+		if err := as.attemptRepo.LockLogin(ctx, accountKey, now.Add(policy.LockoutDuration)); err != nil {
+			return domain.ErrInternal
+		}
+		return domain.ErrAccountLocked
+	}
+
+	timer := time.NewTimer(policy.Delay(max(account.Failures, ip.Failures)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return domain.ErrInvalidCredentials
+}
+
+// UnlockUser clears the failed login attempts and lockout of a user
+func (as *AuthService) UnlockUser(ctx context.Context, id int64) error {
+	user, err := as.repo.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := as.attemptRepo.ResetLoginAttempts(ctx, accountLoginKey(user.Email)); err != nil {
+		return domain.ErrInternal
+	}
+	as.audit.Record(ctx, domain.AuditUserUnlock, domain.AuditEntityUser, user.ID, nil, nil)
+	return nil
+}
+
+func accountLoginKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipLoginKey(ip string) string {
+	return "ip:" + ip
+}
+
+func twoFactorLoginKey(userID int64) string {
+	return fmt.Sprintf("2fa:%d", userID)
+}
+
+// VerifyTwoFactor exchanges a challenge token and a TOTP or recovery code for an access token.
+// Failed codes count towards a lockout of the user's second factor.
+func (as *AuthService) VerifyTwoFactor(ctx context.Context, challengeToken, code string) (string, error) {
+	user, _, err := as.verifyToken(ctx, challengeToken, challengeTokenType)
+	if err != nil {
+		return "", err
+	}
+
+	key := twoFactorLoginKey(user.ID)
+	attempts, err := as.attemptRepo.GetLoginAttempts(ctx, key)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+	if attempts.IsLocked(time.Now()) {
+		return "", domain.ErrAccountLocked
+	}
+
+	twoFactor, err := as.twoFactorRepo.GetTwoFactor(ctx, user.ID)
+	if err != nil && err != domain.ErrDataNotFound {
+		return "", domain.ErrInternal
+	}
+	if !twoFactor.IsEnabled() {
+		return "", domain.ErrTwoFactorDisabled
+	}
+
+	if err := verifySecondFactor(ctx, as.twoFactorRepo, twoFactor, code); err != nil {
+		if err != domain.ErrInvalidTwoFactor {
+			return "", domain.ErrInternal
+		}
+		attempts, err := as.attemptRepo.RecordLoginFailure(ctx, key, as.lockoutPolicy.FailureWindow)
+		if err != nil {
+			return "", domain.ErrInternal
+		}
+		if as.lockoutPolicy.MaxAccountFailures > 0 && attempts.Failures >= as.lockoutPolicy.MaxAccountFailures {
+			if err := as.attemptRepo.LockLogin(ctx, key, time.Now().Add(as.lockoutPolicy.LockoutDuration)); err != nil {
+				return "", domain.ErrInternal
+			}
+			return "", domain.ErrAccountLocked
+		}
+		return "", domain.ErrInvalidTwoFactor
+	}
+
+	if err := as.attemptRepo.ResetLoginAttempts(ctx, key); err != nil {
+		return "", domain.ErrInternal
 	}
 
+	accessToken, err := as.tokenService.CreateToken(user, true)
+	if err != nil {
+		return "", domain.ErrTokenCreation
+	}
 	return accessToken, nil
 }
+
+// Authenticate verifies the access token and makes sure it was issued for the user's current session version
+func (as *AuthService) Authenticate(ctx context.Context, token string) (*domain.Identity, error) {
+	user, claims, err := as.verifyToken(ctx, token, accessTokenType)
+	if err != nil {
+		return nil, err
+	}
+	mfa, _ := claims["mfa"].(bool)
+
+	return &domain.Identity{
+		User:              user,
+		TwoFactorVerified: mfa,
+	}, nil
+}
+
+// AuthorizeTwoFactor enforces the two-factor policy of the identity's role.
+// API keys are exempt, creating one already required passing the policy and they are only
+// accepted by endpoints that ask for one of their scopes.
+func (as *AuthService) AuthorizeTwoFactor(identity *domain.Identity) error {
+	if identity.APIKey != nil {
+		return nil
+	}
+	if as.twoFactor.Requires(identity.User.Role) && !identity.TwoFactorVerified {
+		return domain.ErrTwoFactorRequired
+	}
+	return nil
+}
+
+// verifyToken verifies a token of the given type and loads the user it was issued for
+func (as *AuthService) verifyToken(ctx context.Context, token, typ string) (*domain.User, jwt.MapClaims, error) {
+	payload, err := as.tokenService.VerifyToken(token)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	claims, ok := payload.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	// Tokens issued before token types were introduced are access tokens
+	tokenType, _ := claims["typ"].(string)
+	if tokenType == "" {
+		tokenType = accessTokenType
+	}
+	if tokenType != typ {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	sub, ok := claims["sub"].(float64)
+	if !ok {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	ver, _ := claims["ver"].(float64)
+
+	user, err := as.repo.GetUserById(ctx, int64(sub))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, nil, domain.ErrInvalidToken
+		}
+		return nil, nil, domain.ErrInternal
+	}
+	if user.TokenVersion != int64(ver) {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	return user, claims, nil
+}
+
+// ForgotPassword emails a password reset token to the user.
+// It returns nil for unknown emails and when the email cannot be sent so callers cannot tell
+// whether an account exists. The token is generated for unknown emails too and the reset is
+// stored and mailed in the background so the response takes the same time either way.
+func (as *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := as.repo.GetUserByEmail(ctx, email)
+	if err != nil && err != domain.ErrDataNotFound {
+		return domain.ErrInternal
+	}
+
+	token, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	tokenHash := util.HashToken(token)
+	if user == nil {
+		return nil
+	}
+
+	go as.sendPasswordReset(context.WithoutCancel(ctx), user, token, tokenHash)
+	return nil
+}
+
+// PasswordResetMailTimeout bounds how long storing and mailing a password reset may take
+var PasswordResetMailTimeout = 30 * time.Second
+
+// sendPasswordReset stores the reset token and emails it, failures are only logged
+func (as *AuthService) sendPasswordReset(ctx context.Context, user *domain.User, token, tokenHash string) {
+	ctx, cancel := context.WithTimeout(ctx, PasswordResetMailTimeout)
+	defer cancel()
+
+	reset := &domain.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(PasswordResetTokenTTL),
+	}
+	if _, err := as.resetRepo.CreatePasswordReset(ctx, reset); err != nil {
+		slog.Error("failed to store password reset", "user_id", user.ID, "error", err)
+		return
+	}
+
+	mail := &domain.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the following token to reset your password: %s\n\nThe token expires in %s. If you did not request a password reset you can ignore this email.",
+			user.Name, token, PasswordResetTokenTTL,
+		),
+	}
+	if err := as.mailService.Send(ctx, mail); err != nil {
+		slog.Error("failed to send password reset email", "user_id", user.ID, "error", err)
+	}
+}
+
+// ResetPassword redeems a reset token, stores the new password and invalidates all existing sessions
+func (as *AuthService) ResetPassword(ctx context.Context, token, password string) error {
+	reset, err := as.resetRepo.GetPasswordResetByTokenHash(ctx, util.HashToken(token))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidResetToken
+		}
+		return domain.ErrInternal
+	}
+	if !reset.IsUsable(time.Now()) {
+		return domain.ErrInvalidResetToken
+	}
+
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if err := as.resetRepo.ResetPassword(ctx, reset, hashedPassword); err != nil {
+		if err == domain.ErrInvalidResetToken {
+			return err
+		}
+		return domain.ErrInternal
+	}
+	as.audit.Record(ctx, domain.AuditUserPasswordReset, domain.AuditEntityUser, reset.UserID, nil, nil)
+	return nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
+)
+
+// memoryLoginAttempts is an in-memory port.LoginAttemptRepository
+type memoryLoginAttempts struct {
+	attempts map[string]*domain.LoginAttempts
+}
+
+func newMemoryLoginAttempts() *memoryLoginAttempts {
+	return &memoryLoginAttempts{attempts: map[string]*domain.LoginAttempts{}}
+}
+
+func (m *memoryLoginAttempts) get(key string) *domain.LoginAttempts {
+	if m.attempts[key] == nil {
+		m.attempts[key] = &domain.LoginAttempts{}
+	}
+	return m.attempts[key]
+}
+
+func (m *memoryLoginAttempts) GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	attempts := *m.get(key)
+	return &attempts, nil
+}
+
+func (m *memoryLoginAttempts) RecordLoginFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempts, error) {
+	attempts := m.get(key)
+	attempts.Failures++
+	copied := *attempts
+	return &copied, nil
+}
+
+func (m *memoryLoginAttempts) LockLogin(ctx context.Context, key string, until time.Time) error {
+	m.get(key).LockedUntil = until
+	return nil
+}
+
+func (m *memoryLoginAttempts) ResetLoginAttempts(ctx context.Context, key string) error {
+	delete(m.attempts, key)
+	return nil
+}
+
+func TestLoginFailedLockout(t *testing.T) {
+	policy := domain.LockoutPolicy{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      5,
+		FailureWindow:      time.Minute,
+		LockoutDuration:    time.Hour,
+	}
+	account := accountLoginKey("Reader@Example.com ")
+	otherAccount := accountLoginKey("other@example.com")
+	ip := ipLoginKey("192.0.2.1")
+
+	tests := []struct {
+		name     string
+		failures []string
+		want     error
+		locked   string
+	}{
+		{
+			name:     "below the thresholds",
+			failures: []string{account, account},
+			want:     domain.ErrInvalidCredentials,
+		},
+		{
+			name:     "account threshold locks the account",
+			failures: []string{account, account, account},
+			want:     domain.ErrAccountLocked,
+			locked:   account,
+		},
+		{
+			name:     "ip threshold blocks the ip across accounts",
+			failures: []string{account, otherAccount, account, otherAccount, otherAccount},
+			want:     domain.ErrTooManyAttempts,
+			locked:   ip,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := newMemoryLoginAttempts()
+			as := &AuthService{attemptRepo: attempts, lockoutPolicy: policy}
+
+			var err error
+			for _, key := range tt.failures {
+				err = as.loginFailed(context.Background(), key, ip)
+			}
+			if err != tt.want {
+				t.Fatalf("loginFailed() = %v, want %v", err, tt.want)
+			}
+			for key, state := range attempts.attempts {
+				if locked := state.IsLocked(time.Now()); locked != (key == tt.locked) {
+					t.Errorf("%s locked = %v", key, locked)
+				}
+			}
+		})
+	}
+}
+
+func TestLoginFailedDelay(t *testing.T) {
+	policy := domain.LockoutPolicy{
+		MaxAccountFailures: 10,
+		FailureWindow:      time.Minute,
+		BaseDelay:          20 * time.Millisecond,
+		MaxDelay:           time.Second,
+	}
+	as := &AuthService{attemptRepo: newMemoryLoginAttempts(), lockoutPolicy: policy}
+	account, ip := accountLoginKey("reader@example.com"), ipLoginKey("192.0.2.1")
+
+	as.loginFailed(context.Background(), account, ip)
+	start := time.Now()
+	as.loginFailed(context.Background(), account, ip)
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("second failure delayed %s, want at least 40ms", elapsed)
+	}
+
+	// A cancelled request is not held for the delay
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start = time.Now()
+	if err := as.loginFailed(ctx, account, ip); err != domain.ErrInvalidCredentials {
+		t.Fatalf("loginFailed() = %v, want %v", err, domain.ErrInvalidCredentials)
+	}
+	if elapsed := time.Since(start); elapsed >= 80*time.Millisecond {
+		t.Errorf("cancelled failure delayed %s", elapsed)
+	}
+}
+
+// memoryResets is an in-memory port.PasswordResetRepository that resets passwords of the users
+type memoryResets struct {
+	mu     sync.Mutex
+	users  *memoryUsers
+	resets []*domain.PasswordReset
+}
+
+func (m *memoryResets) CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) (*domain.PasswordReset, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	created := *reset
+	created.ID = int64(len(m.resets) + 1)
+	m.resets = append(m.resets, &created)
+	return &created, nil
+}
+
+func (m *memoryResets) GetPasswordResetByTokenHash(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, reset := range m.resets {
+		if reset.TokenHash == tokenHash {
+			copied := *reset
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *memoryResets) ResetPassword(ctx context.Context, reset *domain.PasswordReset, hashedPassword string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, stored := range m.resets {
+		if stored.ID == reset.ID && !stored.IsUsable(now) {
+			return domain.ErrInvalidResetToken
+		}
+	}
+	for _, stored := range m.resets {
+		if stored.UserID == reset.UserID && stored.UsedAt == nil {
+			stored.UsedAt = &now
+		}
+	}
+	_, err := m.users.UpdatePassword(ctx, reset.UserID, hashedPassword)
+	return err
+}
+
+// mailbox is a port.MailService delivering mails to a channel
+type mailbox chan *domain.Mail
+
+func (m mailbox) Send(ctx context.Context, mail *domain.Mail) error {
+	m <- mail
+	return nil
+}
+
+// resetToken returns the token of a password reset mail
+func resetToken(t *testing.T, mail *domain.Mail) string {
+	t.Helper()
+	_, rest, ok := strings.Cut(mail.Body, "reset your password: ")
+	if !ok {
+		t.Fatalf("mail has no reset token: %q", mail.Body)
+	}
+	return strings.Fields(rest)[0]
+}
+
+func TestForgotPassword(t *testing.T) {
+	users := &memoryUsers{users: []*domain.User{{ID: 1, Name: "Paul", Email: "paul@example.com"}}}
+	resets := &memoryResets{users: users}
+	mails := make(mailbox, 1)
+	as := NewAuthService(users, fakeTokens{}, resets, mails, nil, domain.LockoutPolicy{}, nil, domain.TwoFactorPolicy{}, nopAudit{})
+
+	if err := as.ForgotPassword(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("ForgotPassword() of an unknown email error = %v, want nil", err)
+	}
+	if err := as.ForgotPassword(context.Background(), "paul@example.com"); err != nil {
+		t.Fatalf("ForgotPassword() error = %v", err)
+	}
+
+	var mail *domain.Mail
+	select {
+	case mail = <-mails:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no password reset mail was sent")
+	}
+	if mail.To != "paul@example.com" {
+		t.Errorf("mail sent to %q, want paul@example.com", mail.To)
+	}
+	select {
+	case extra := <-mails:
+		t.Errorf("mail sent to %q, the unknown email must not get one", extra.To)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	resets.mu.Lock()
+	defer resets.mu.Unlock()
+	if len(resets.resets) != 1 {
+		t.Fatalf("%d resets stored, want 1", len(resets.resets))
+	}
+	reset := resets.resets[0]
+	if reset.UserID != 1 || reset.TokenHash != util.HashToken(resetToken(t, mail)) {
+		t.Errorf("reset = %+v, want the hash of the mailed token for user 1", reset)
+	}
+	if ttl := time.Until(reset.ExpiresAt); ttl <= 0 || ttl > PasswordResetTokenTTL {
+		t.Errorf("reset expires in %s, want within %s", ttl, PasswordResetTokenTTL)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	used := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		reset   domain.PasswordReset
+		token   string
+		wantErr error
+	}{
+		{"valid token", domain.PasswordReset{ExpiresAt: time.Now().Add(time.Hour)}, "secret", nil},
+		{"unknown token", domain.PasswordReset{ExpiresAt: time.Now().Add(time.Hour)}, "guessed", domain.ErrInvalidResetToken},
+		{"expired token", domain.PasswordReset{ExpiresAt: time.Now().Add(-time.Second)}, "secret", domain.ErrInvalidResetToken},
+		{"used token", domain.PasswordReset{ExpiresAt: time.Now().Add(time.Hour), UsedAt: &used}, "secret", domain.ErrInvalidResetToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &memoryUsers{users: []*domain.User{{ID: 1, Email: "paul@example.com", Password: "old", TokenVersion: 3}}}
+			resets := &memoryResets{users: users}
+			tt.reset.UserID, tt.reset.TokenHash = 1, util.HashToken("secret")
+			resets.CreatePasswordReset(context.Background(), &tt.reset)
+			as := NewAuthService(users, fakeTokens{}, resets, nil, nil, domain.LockoutPolicy{}, nil, domain.TwoFactorPolicy{}, nopAudit{})
+
+			err := as.ResetPassword(context.Background(), tt.token, "n3w-Password!")
+			if err != tt.wantErr {
+				t.Fatalf("ResetPassword() error = %v, want %v", err, tt.wantErr)
+			}
+			user := users.users[0]
+			if tt.wantErr != nil {
+				if user.Password != "old" || user.TokenVersion != 3 {
+					t.Errorf("password changed by a rejected reset")
+				}
+				return
+			}
+			if err := util.ComparePassword("n3w-Password!", user.Password); err != nil {
+				t.Errorf("new password was not stored: %v", err)
+			}
+			if user.TokenVersion != 4 {
+				t.Errorf("token version = %d, want 4 so existing sessions are invalidated", user.TokenVersion)
+			}
+			if err := as.ResetPassword(context.Background(), tt.token, "an0ther-Password!"); err != domain.ErrInvalidResetToken {
+				t.Errorf("second ResetPassword() error = %v, want %v", err, domain.ErrInvalidResetToken)
+			}
+		})
+	}
+}
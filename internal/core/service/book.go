@@ -2,27 +2,38 @@ package service
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
 )
 
 type BookService struct {
-	repo port.BookRepository
+	repo         port.BookRepository
+	translations port.TranslationService
+	audit        port.AuditService
 }
 
-func NewBookService(repo port.BookRepository) *BookService {
+func NewBookService(repo port.BookRepository, translations port.TranslationService, audit port.AuditService) *BookService {
 	return &BookService{
-		repo: repo,
+		repo:         repo,
+		translations: translations,
+		audit:        audit,
 	}
 }
 
 func (bs *BookService) CreateBook(ctx context.Context, book *domain.Book) (*domain.Book, error) {
+	book.NormalizeAuthors()
+	if err := book.ValidateSeries(); err != nil {
+		return nil, err
+	}
 
 	book, err := bs.repo.CreateBook(ctx, book)
 	if err != nil {
 		return nil, err
 	}
+	bs.audit.Record(ctx, domain.AuditBookCreate, domain.AuditEntityBook, book.ID, nil, book)
 	return book, nil
 }
 
@@ -31,30 +42,93 @@ func (bs *BookService) GetBook(ctx context.Context, id int64) (*domain.Book, err
 	if err != nil {
 		return nil, err
 	}
-	return book, nil
+	books := []domain.Book{*book}
+	if err := bs.translations.LocalizeBooks(ctx, books); err != nil {
+		return nil, err
+	}
+	return &books[0], nil
 }
 
-func (bs *BookService) ListBooks(ctx context.Context, skip, limt int64) ([]domain.Book, error) {
+func (bs *BookService) ListBooks(ctx context.Context, skip, limt int64, includeDeleted bool) ([]domain.Book, error) {
 
-	books, err := bs.repo.ListBooks(ctx, skip, limt)
+	books, err := bs.repo.ListBooks(ctx, skip, limt, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
+	if err := bs.translations.LocalizeBooks(ctx, books); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// SearchBooks searches the books and their editions. A search text that is an ISBN looks up the
+// edition with that ISBN instead.
+func (bs *BookService) SearchBooks(ctx context.Context, search *domain.BookSearch) ([]domain.Book, error) {
+	search.Text = strings.TrimSpace(search.Text)
+	if isbn, err := domain.NormalizeISBN(search.Text); err == nil {
+		search.Text = ""
+		search.ISBN = isbn
+	}
+	if search.ISBN != "" {
+		isbn, err := domain.NormalizeISBN(search.ISBN)
+		if err != nil {
+			return nil, err
+		}
+		search.ISBN = isbn
+	}
+	search.Language = strings.ToLower(search.Language)
+	books, err := bs.repo.SearchBooks(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.translations.LocalizeBooks(ctx, books); err != nil {
+		return nil, err
+	}
 	return books, nil
 }
 
 func (bs *BookService) UpdateBook(ctx context.Context, book *domain.Book) (*domain.Book, error) {
-	book, err := bs.repo.UpdateBook(ctx, book)
+	book.NormalizeAuthors()
+	if err := book.ValidateSeries(); err != nil {
+		return nil, err
+	}
+	before, err := bs.repo.GetBookById(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+	book, err = bs.repo.UpdateBook(ctx, book)
 	if err != nil {
 		return nil, err
 	}
+	bs.audit.Record(ctx, domain.AuditBookUpdate, domain.AuditEntityBook, book.ID, before, book)
 	return book, nil
 }
 
-func (bs *BookService) DeleteBook(ctx context.Context, id int64) error {
-	err := bs.repo.DeleteBook(ctx, id)
+func (bs *BookService) DeleteBook(ctx context.Context, id, version int64) error {
+	before, err := bs.repo.GetBookById(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = bs.repo.DeleteBook(ctx, id, version)
 	if err != nil {
 		return err
 	}
+	bs.audit.Record(ctx, domain.AuditBookDelete, domain.AuditEntityBook, id, before, nil)
 	return nil
 }
+
+// RestoreBook restores a soft-deleted book
+func (bs *BookService) RestoreBook(ctx context.Context, id int64) (*domain.Book, error) {
+	book, err := bs.repo.RestoreBook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	bs.audit.Record(ctx, domain.AuditBookRestore, domain.AuditEntityBook, id, nil, book)
+	return book, nil
+}
+
+// PurgeDeletedBooks hard-deletes books soft-deleted longer than the retention ago, books that
+// orders still reference are kept
+func (bs *BookService) PurgeDeletedBooks(ctx context.Context, retention time.Duration) (int64, error) {
+	return bs.repo.PurgeBooks(ctx, time.Now().Add(-retention))
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+func TestSearchBooksNormalizesTheSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		search  domain.BookSearch
+		want    domain.BookSearch
+		wantErr error
+	}{
+		{
+			name:   "text is trimmed",
+			search: domain.BookSearch{Text: "  dune "},
+			want:   domain.BookSearch{Text: "dune"},
+		},
+		{
+			name:   "text that is an ISBN looks up the edition",
+			search: domain.BookSearch{Text: " 978-0-306-40615-7 "},
+			want:   domain.BookSearch{EditionFilter: domain.EditionFilter{ISBN: "9780306406157"}},
+		},
+		{
+			name:   "ISBN filter is normalized",
+			search: domain.BookSearch{Text: "dune", EditionFilter: domain.EditionFilter{ISBN: "0-306-40615-2"}},
+			want:   domain.BookSearch{Text: "dune", EditionFilter: domain.EditionFilter{ISBN: "0306406152"}},
+		},
+		{
+			name:    "invalid ISBN filter",
+			search:  domain.BookSearch{EditionFilter: domain.EditionFilter{ISBN: "978-0-306-40615-8"}},
+			wantErr: domain.ErrInvalidEdition,
+		},
+		{
+			name:   "language is lower-cased",
+			search: domain.BookSearch{EditionFilter: domain.EditionFilter{Language: "DE"}},
+			want:   domain.BookSearch{EditionFilter: domain.EditionFilter{Language: "de"}},
+		},
+		{
+			name: "publisher, series and category filters are kept",
+			search: domain.BookSearch{
+				CategoryID: 1, PublisherID: 2, SeriesID: 3, Skip: 20, Limit: 10,
+				EditionFilter: domain.EditionFilter{Format: domain.FormatEbook},
+			},
+			want: domain.BookSearch{
+				CategoryID: 1, PublisherID: 2, SeriesID: 3, Skip: 20, Limit: 10,
+				EditionFilter: domain.EditionFilter{Format: domain.FormatEbook},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books := &memoryBooks{}
+			bs := NewBookService(books, nopTranslations{}, nopAudit{})
+
+			_, err := bs.SearchBooks(context.Background(), &tt.search)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SearchBooks() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(books.searches) != 0 {
+					t.Error("an invalid search reached the repository")
+				}
+				return
+			}
+			if len(books.searches) != 1 || books.searches[0] != tt.want {
+				t.Errorf("searches = %+v, want %+v", books.searches, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type CategoryService struct {
+	repo         port.CategoryRepository
+	translations port.TranslationService
+	audit        port.AuditService
+}
+
+func NewCategoryService(repo port.CategoryRepository, translations port.TranslationService, audit port.AuditService) *CategoryService {
+	return &CategoryService{
+		repo:         repo,
+		translations: translations,
+		audit:        audit,
+	}
+}
+
+// CreateCategory creates a category with a trimmed name
+func (cs *CategoryService) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	category.Name = strings.TrimSpace(category.Name)
+	category, err := cs.repo.CreateCategory(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	cs.audit.Record(ctx, domain.AuditCategoryCreate, domain.AuditEntityCategory, category.ID, nil, category)
+	return category, nil
+}
+
+// ListCategories returns all categories, localized to the locale of the context
+func (cs *CategoryService) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	categories, err := cs.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.translations.LocalizeCategories(ctx, categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
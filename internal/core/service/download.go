@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type DownloadService struct {
+	repo         port.DownloadRepository
+	orderRepo    port.OrderRepository
+	editionRepo  port.EditionRepository
+	blobs        port.BlobStorage
+	signingKey   []byte
+	linkTTL      time.Duration
+	resumeTTL    time.Duration
+	maxDownloads int
+}
+
+func NewDownloadService(repo port.DownloadRepository, orderRepo port.OrderRepository, editionRepo port.EditionRepository, blobs port.BlobStorage, signingKey string, linkTTL, resumeTTL time.Duration, maxDownloads int) *DownloadService {
+	return &DownloadService{
+		repo:         repo,
+		orderRepo:    orderRepo,
+		editionRepo:  editionRepo,
+		blobs:        blobs,
+		signingKey:   []byte(signingKey),
+		linkTTL:      linkTTL,
+		resumeTTL:    resumeTTL,
+		maxDownloads: maxDownloads,
+	}
+}
+
+// ListOrderDownloads returns the download grants of a paid order. Granting normally happens when
+// the payment is recorded, a paid order without grants failed to get them then.
+func (ds *DownloadService) ListOrderDownloads(ctx context.Context, orderID int64) ([]domain.DownloadGrant, error) {
+	order, err := ds.orderRepo.GetOrderById(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return ds.GrantOrderDownloads(ctx, order)
+}
+
+// GrantOrderDownloads grants the downloads of the digital items of a paid order, items that
+// already have a grant keep it
+func (ds *DownloadService) GrantOrderDownloads(ctx context.Context, order *domain.Order) ([]domain.DownloadGrant, error) {
+	if order.PaidAt == nil {
+		return nil, domain.ErrOrderNotPaid
+	}
+	return ds.repo.CreateOrderGrants(ctx, order.ID, ds.maxDownloads)
+}
+
+// checkPaid fails with ErrOrderNotPaid when the order of a grant has not been paid
+func (ds *DownloadService) checkPaid(ctx context.Context, grant *domain.DownloadGrant) error {
+	order, err := ds.orderRepo.GetOrderById(ctx, grant.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.PaidAt == nil {
+		return domain.ErrOrderNotPaid
+	}
+	return nil
+}
+
+// sign returns the signature of a download link
+func (ds *DownloadService) sign(grantID, expires int64) string {
+	mac := hmac.New(sha256.New, ds.signingKey)
+	fmt.Fprintf(mac, "%d:%d", grantID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateLink signs a download URL for a grant of the user that is valid for the link TTL
+func (ds *DownloadService) CreateLink(ctx context.Context, grantID, userID int64) (*domain.DownloadLink, error) {
+	grant, err := ds.repo.GetGrant(ctx, grantID)
+	if err != nil {
+		return nil, err
+	}
+	if grant.UserID != userID {
+		return nil, domain.ErrDataNotFound
+	}
+	if err := ds.checkPaid(ctx, grant); err != nil {
+		return nil, err
+	}
+	if grant.Remaining() == 0 {
+		return nil, domain.ErrDownloadLimit
+	}
+
+	expiresAt := time.Now().Add(ds.linkTTL).Truncate(time.Second)
+	expires := expiresAt.Unix()
+	return &domain.DownloadLink{
+		GrantID:   grant.ID,
+		URL:       fmt.Sprintf("/v1/downloads/%d/file?expires=%d&signature=%s", grant.ID, expires, ds.sign(grant.ID, expires)),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// resumeToken signs the continuation of the download-th download of a grant until expires
+func (ds *DownloadService) resumeToken(grantID int64, download int, expires int64) string {
+	mac := hmac.New(sha256.New, ds.signingKey)
+	fmt.Fprintf(mac, "resume:%d:%d:%d", grantID, download, expires)
+	return fmt.Sprintf("%d.%d.%s", download, expires, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// validResumeToken checks the signature and expiry of a resume token of a grant
+func (ds *DownloadService) validResumeToken(grantID int64, token string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	download, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(token), []byte(ds.resumeToken(grantID, download, expires)))
+}
+
+// OpenDownload checks the signature and expiry of a download link, counts the download and opens
+// the file of the grant. A range request with a resume token continues a counted download
+// without counting it again.
+func (ds *DownloadService) OpenDownload(ctx context.Context, request *domain.DownloadRequest) (*domain.Download, io.ReadSeekCloser, *domain.BlobInfo, error) {
+	resumed := request.Resume != ""
+	if resumed {
+		// A resume token cannot start a download over, that needs a new link and counts
+		if !request.Continues || !ds.validResumeToken(request.GrantID, request.Resume) {
+			return nil, nil, nil, domain.ErrInvalidDownloadLink
+		}
+	} else if !hmac.Equal([]byte(request.Signature), []byte(ds.sign(request.GrantID, request.Expires))) || time.Now().Unix() > request.Expires {
+		return nil, nil, nil, domain.ErrInvalidDownloadLink
+	}
+
+	grant, err := ds.repo.GetGrant(ctx, request.GrantID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, nil, nil, domain.ErrInvalidDownloadLink
+		}
+		return nil, nil, nil, err
+	}
+	if err := ds.checkPaid(ctx, grant); err != nil {
+		return nil, nil, nil, err
+	}
+	if !resumed && grant.Remaining() == 0 {
+		return nil, nil, nil, domain.ErrDownloadLimit
+	}
+	edition, err := ds.editionRepo.GetEdition(ctx, grant.EditionID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if edition.FileKey == "" {
+		return nil, nil, nil, domain.ErrNoDigitalFile
+	}
+
+	download := &domain.Download{Edition: edition}
+	if !resumed {
+		// Counting fails when concurrent downloads used up the grant since it was read
+		counted, err := ds.repo.UseDownload(ctx, grant.ID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		download.ResumeExpiresAt = time.Now().Add(ds.resumeTTL).Truncate(time.Second)
+		token := ds.resumeToken(grant.ID, counted.Downloads, download.ResumeExpiresAt.Unix())
+		download.ResumeURL = fmt.Sprintf("/v1/downloads/%d/file?resume=%s", grant.ID, token)
+	}
+
+	file, info, err := ds.blobs.Open(ctx, edition.FileKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return download, file, info, nil
+}
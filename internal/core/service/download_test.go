@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// memoryDownloads is an in-memory port.DownloadRepository
+type memoryDownloads map[int64]*domain.DownloadGrant
+
+func (m memoryDownloads) CreateOrderGrants(ctx context.Context, orderID int64, maxDownloads int) ([]domain.DownloadGrant, error) {
+	var grants []domain.DownloadGrant
+	for _, grant := range m {
+		if grant.OrderID == orderID {
+			grants = append(grants, *grant)
+		}
+	}
+	return grants, nil
+}
+
+func (m memoryDownloads) GetGrant(ctx context.Context, id int64) (*domain.DownloadGrant, error) {
+	grant, ok := m[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	copied := *grant
+	return &copied, nil
+}
+
+func (m memoryDownloads) UseDownload(ctx context.Context, id int64) (*domain.DownloadGrant, error) {
+	grant, ok := m[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	if grant.Remaining() == 0 {
+		return nil, domain.ErrDownloadLimit
+	}
+	grant.Downloads++
+	copied := *grant
+	return &copied, nil
+}
+
+// grantingDownloads is a memoryDownloads granting every e-book item of an order whether it is paid
+// or not, so that only the service keeps unpaid orders from their downloads
+type grantingDownloads struct {
+	memoryDownloads
+	orders *memoryOrders
+}
+
+func (g grantingDownloads) CreateOrderGrants(ctx context.Context, orderID int64, maxDownloads int) ([]domain.DownloadGrant, error) {
+	order := g.orders.orders[orderID]
+	for _, item := range order.Items {
+		granted := false
+		for _, grant := range g.memoryDownloads {
+			granted = granted || grant.OrderItemID == item.ID
+		}
+		if item.Format == domain.FormatEbook && !granted {
+			id := int64(len(g.memoryDownloads) + 1)
+			g.memoryDownloads[id] = &domain.DownloadGrant{ID: id, OrderID: order.ID, OrderItemID: item.ID, UserID: order.UserId, EditionID: *item.EditionID, MaxDownloads: maxDownloads}
+		}
+	}
+	return g.memoryDownloads.CreateOrderGrants(ctx, orderID, maxDownloads)
+}
+
+// paidOrder returns the orders of the grants of the tests, order 1 of user 1 is paid
+func paidOrder() *memoryOrders {
+	paidAt := time.Now()
+	return &memoryOrders{orders: map[int64]*domain.Order{1: {ID: 1, UserId: 1, PaidAt: &paidAt}}}
+}
+
+func newTestDownloadService(grants port.DownloadRepository, orders *memoryOrders) *DownloadService {
+	editions := &memoryEditions{editions: map[int64]*domain.Edition{
+		1: {ID: 1, BookID: 1, Format: domain.FormatEbook, FileKey: "editions/1.epub"},
+	}}
+	return NewDownloadService(grants, orders, editions, memoryBlobs{"editions/1.epub": []byte("epub")}, "signing-key", time.Minute, time.Hour, 2)
+}
+
+// linkRequest turns a download link into the request its URL makes
+func linkRequest(t *testing.T, link string, continues bool) *domain.DownloadRequest {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grantID, err := strconv.ParseInt(strings.Split(u.Path, "/")[3], 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires, _ := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	return &domain.DownloadRequest{
+		GrantID:   grantID,
+		Expires:   expires,
+		Signature: u.Query().Get("signature"),
+		Resume:    u.Query().Get("resume"),
+		Continues: continues,
+	}
+}
+
+func TestOpenDownloadCountsEveryRequestThroughTheLink(t *testing.T) {
+	grants := memoryDownloads{1: {ID: 1, OrderID: 1, UserID: 1, EditionID: 1, MaxDownloads: 2}}
+	ds := newTestDownloadService(grants, paidOrder())
+	ctx := context.Background()
+
+	link, err := ds.CreateLink(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A range that does not start at the first byte is still counted without a resume token
+	for i, continues := range []bool{false, true} {
+		download, file, _, err := ds.OpenDownload(ctx, linkRequest(t, link.URL, continues))
+		if err != nil {
+			t.Fatalf("download %d error = %v", i+1, err)
+		}
+		file.Close()
+		if grants[1].Downloads != i+1 {
+			t.Errorf("downloads = %d, want %d", grants[1].Downloads, i+1)
+		}
+		if download.ResumeURL == "" {
+			t.Error("a counted download has no resume URL")
+		}
+	}
+
+	for _, continues := range []bool{false, true} {
+		if _, _, _, err := ds.OpenDownload(ctx, linkRequest(t, link.URL, continues)); err != domain.ErrDownloadLimit {
+			t.Errorf("download past the limit, continues %v: error = %v, want %v", continues, err, domain.ErrDownloadLimit)
+		}
+	}
+}
+
+func TestOpenDownloadResume(t *testing.T) {
+	grants := memoryDownloads{
+		1: {ID: 1, OrderID: 1, UserID: 1, EditionID: 1, MaxDownloads: 1},
+		2: {ID: 2, OrderID: 1, UserID: 1, EditionID: 1, MaxDownloads: 1},
+	}
+	ds := newTestDownloadService(grants, paidOrder())
+	ctx := context.Background()
+
+	link, err := ds.CreateLink(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	download, file, _, err := ds.OpenDownload(ctx, linkRequest(t, link.URL, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	resume := download.ResumeURL
+
+	tamper := func(request *domain.DownloadRequest) *domain.DownloadRequest {
+		request.Resume = strings.Replace(request.Resume, "1.", "9.", 1)
+		return request
+	}
+	otherGrant := func(request *domain.DownloadRequest) *domain.DownloadRequest {
+		request.GrantID = 2
+		return request
+	}
+
+	tests := []struct {
+		name    string
+		request *domain.DownloadRequest
+		wantErr error
+	}{
+		{"continues the counted download", linkRequest(t, resume, true), nil},
+		{"continues it again", linkRequest(t, resume, true), nil},
+		{"cannot start the download over", linkRequest(t, resume, false), domain.ErrInvalidDownloadLink},
+		{"tampered token", tamper(linkRequest(t, resume, true)), domain.ErrInvalidDownloadLink},
+		{"token of another grant", otherGrant(linkRequest(t, resume, true)), domain.ErrInvalidDownloadLink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			download, file, _, err := ds.OpenDownload(ctx, tt.request)
+			if err != tt.wantErr {
+				t.Fatalf("OpenDownload() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				file.Close()
+				if download.ResumeURL != "" {
+					t.Error("a resumed download issued another resume URL")
+				}
+			}
+			if grants[1].Downloads != 1 || grants[2].Downloads != 0 {
+				t.Errorf("downloads = %d and %d, want the resumed download counted once", grants[1].Downloads, grants[2].Downloads)
+			}
+		})
+	}
+}
+
+func TestOpenDownloadRejectsExpiredLinks(t *testing.T) {
+	grants := memoryDownloads{1: {ID: 1, OrderID: 1, UserID: 1, EditionID: 1, MaxDownloads: 2}}
+	ds := newTestDownloadService(grants, paidOrder())
+
+	expires := time.Now().Add(-time.Second).Unix()
+	request := &domain.DownloadRequest{GrantID: 1, Expires: expires, Signature: ds.sign(1, expires)}
+	if _, _, _, err := ds.OpenDownload(context.Background(), request); err != domain.ErrInvalidDownloadLink {
+		t.Errorf("OpenDownload() of an expired link error = %v, want %v", err, domain.ErrInvalidDownloadLink)
+	}
+
+	resume := &domain.DownloadRequest{GrantID: 1, Resume: ds.resumeToken(1, 1, expires), Continues: true}
+	if _, _, _, err := ds.OpenDownload(context.Background(), resume); err != domain.ErrInvalidDownloadLink {
+		t.Errorf("OpenDownload() with an expired resume token error = %v, want %v", err, domain.ErrInvalidDownloadLink)
+	}
+	if grants[1].Downloads != 0 {
+		t.Error("a rejected download was counted")
+	}
+}
+
+func TestDownloadsNeedAPaidOrder(t *testing.T) {
+	ebook := int64(1)
+	orders := &memoryOrders{orders: map[int64]*domain.Order{
+		1: {ID: 1, UserId: 1, Items: []domain.OrderItem{{ID: 1, OrderID: 1, BookID: 1, EditionID: &ebook, Format: domain.FormatEbook, Quantity: 1, UnitPrice: 8}}},
+	}}
+	grants := grantingDownloads{memoryDownloads: memoryDownloads{}, orders: orders}
+	ds := newTestDownloadService(grants, orders)
+	ctx := context.Background()
+
+	if _, err := ds.ListOrderDownloads(ctx, 1); err != domain.ErrOrderNotPaid {
+		t.Errorf("ListOrderDownloads() of an unpaid order error = %v, want %v", err, domain.ErrOrderNotPaid)
+	}
+	if len(grants.memoryDownloads) != 0 {
+		t.Fatalf("grants = %+v, want none for an unpaid order", grants.memoryDownloads)
+	}
+
+	// A grant left from before the order was found unpaid cannot be downloaded
+	grants.memoryDownloads[1] = &domain.DownloadGrant{ID: 1, OrderID: 1, OrderItemID: 1, UserID: 1, EditionID: 1, MaxDownloads: 2}
+	if _, err := ds.CreateLink(ctx, 1, 1); err != domain.ErrOrderNotPaid {
+		t.Errorf("CreateLink() for an unpaid order error = %v, want %v", err, domain.ErrOrderNotPaid)
+	}
+	expires := time.Now().Add(time.Minute).Unix()
+	request := &domain.DownloadRequest{GrantID: 1, Expires: expires, Signature: ds.sign(1, expires)}
+	if _, _, _, err := ds.OpenDownload(ctx, request); err != domain.ErrOrderNotPaid {
+		t.Errorf("OpenDownload() for an unpaid order error = %v, want %v", err, domain.ErrOrderNotPaid)
+	}
+	if grants.memoryDownloads[1].Downloads != 0 {
+		t.Error("a download of an unpaid order was counted")
+	}
+	delete(grants.memoryDownloads, 1)
+
+	// Recording the payment grants the downloads
+	is := newTestInvoiceService(orders, &memoryBooks{}, memoryInvoices{}, memoryBlobs{}, nopAudit{})
+	os := NewOrderService(orders, nil, nil, nil, nil, nil, nil, is, ds, nopAudit{}, domain.TaxExclusive)
+	if _, err := os.MarkOrderPaid(ctx, 1); err != nil {
+		t.Fatalf("MarkOrderPaid() error = %v", err)
+	}
+	listed, err := ds.ListOrderDownloads(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListOrderDownloads() of a paid order error = %v", err)
+	}
+	if len(listed) != 1 || listed[0].OrderItemID != 1 || listed[0].UserID != 1 {
+		t.Fatalf("grants = %+v, want one for the e-book item", listed)
+	}
+	if _, err := ds.CreateLink(ctx, listed[0].ID, 1); err != nil {
+		t.Errorf("CreateLink() for a paid order error = %v", err)
+	}
+}
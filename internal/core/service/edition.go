@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type EditionService struct {
+	repo     port.EditionRepository
+	bookRepo port.BookRepository
+	blobs    port.BlobStorage
+	audit    port.AuditService
+}
+
+func NewEditionService(repo port.EditionRepository, bookRepo port.BookRepository, blobs port.BlobStorage, audit port.AuditService) *EditionService {
+	return &EditionService{
+		repo:     repo,
+		bookRepo: bookRepo,
+		blobs:    blobs,
+		audit:    audit,
+	}
+}
+
+// CreateEdition adds an edition to a book
+func (es *EditionService) CreateEdition(ctx context.Context, edition *domain.Edition) (*domain.Edition, error) {
+	if err := edition.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := es.bookRepo.GetBookById(ctx, edition.BookID); err != nil {
+		return nil, err
+	}
+	edition, err := es.repo.CreateEdition(ctx, edition)
+	if err != nil {
+		return nil, err
+	}
+	es.audit.Record(ctx, domain.AuditBookEditionCreate, domain.AuditEntityBook, edition.BookID, nil, edition)
+	return edition, nil
+}
+
+// ListBookEditions returns the editions a book is sold in
+func (es *EditionService) ListBookEditions(ctx context.Context, bookID int64) ([]domain.Edition, error) {
+	return es.repo.ListBookEditions(ctx, bookID)
+}
+
+// getBookEdition returns an edition of a book, editions of other books are reported as missing
+func getBookEdition(ctx context.Context, repo port.EditionRepository, bookID, editionID int64) (*domain.Edition, error) {
+	edition, err := repo.GetEdition(ctx, editionID)
+	if err != nil {
+		return nil, err
+	}
+	if edition.BookID != bookID {
+		return nil, domain.ErrDataNotFound
+	}
+	return edition, nil
+}
+
+// UpdateEdition changes the details, price, stock and weight of an edition, its format cannot change
+func (es *EditionService) UpdateEdition(ctx context.Context, edition *domain.Edition) (*domain.Edition, error) {
+	before, err := getBookEdition(ctx, es.repo, edition.BookID, edition.ID)
+	if err != nil {
+		return nil, err
+	}
+	edition.Format = before.Format
+	if err := edition.Validate(); err != nil {
+		return nil, err
+	}
+	edition, err = es.repo.UpdateEdition(ctx, edition)
+	if err != nil {
+		return nil, err
+	}
+	es.audit.Record(ctx, domain.AuditBookEditionUpdate, domain.AuditEntityBook, edition.BookID, before, edition)
+	return edition, nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// UploadFile stores the file of a digital edition under a key of its own, the file of the edition
+// is replaced
+func (es *EditionService) UploadFile(ctx context.Context, bookID, editionID int64, fileName, contentType string, r io.Reader) (*domain.Edition, error) {
+	edition, err := getBookEdition(ctx, es.repo, bookID, editionID)
+	if err != nil {
+		return nil, err
+	}
+	if !edition.Format.IsDigital() {
+		return nil, fmt.Errorf("%w: %s has no file", domain.ErrInvalidEdition, edition.Format)
+	}
+	if !edition.AcceptsFile(contentType) {
+		return nil, fmt.Errorf("%w: %s files cannot be sold as %s", domain.ErrInvalidEdition, contentType, edition.Format)
+	}
+	before := *edition
+
+	key := domain.EditionFileKey(edition.BookID, edition.ID)
+	counter := &countingReader{r: r}
+	if err := es.blobs.Put(ctx, key, counter); err != nil {
+		return nil, err
+	}
+	edition.FileKey = key
+	edition.FileName = fileName
+	edition.ContentType = contentType
+	edition.FileSize = counter.n
+	edition, err = es.repo.SetEditionFile(ctx, edition)
+	if err != nil {
+		return nil, err
+	}
+	es.audit.Record(ctx, domain.AuditBookEditionUpload, domain.AuditEntityBook, edition.BookID, &before, edition)
+	return edition, nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// memoryUsers is an in-memory port.UserRepository for the methods the tests use, the others panic
+type memoryUsers struct {
+	port.UserRepository
+	users []*domain.User
+}
+
+func (m *memoryUsers) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	created := *user
+	created.ID = int64(len(m.users) + 1)
+	created.Role = domain.Customer
+	m.users = append(m.users, &created)
+	return &created, nil
+}
+
+func (m *memoryUsers) GetUserById(ctx context.Context, id int64) (*domain.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *memoryUsers) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *memoryUsers) UpdatePassword(ctx context.Context, id int64, hashedPassword string) (*domain.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			user.Password = hashedPassword
+			user.TokenVersion++
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+// fakeTokens is a port.TokenService that describes the token it creates instead of signing it
+type fakeTokens struct{}
+
+func (fakeTokens) CreateToken(user *domain.User, twoFactorVerified bool) (string, error) {
+	return fmt.Sprintf("access:%d:%v", user.ID, twoFactorVerified), nil
+}
+
+func (fakeTokens) CreateChallengeToken(user *domain.User) (string, error) {
+	return fmt.Sprintf("challenge:%d", user.ID), nil
+}
+
+func (fakeTokens) VerifyToken(token string) (*domain.TokenPayload, error) {
+	return nil, domain.ErrInvalidToken
+}
+
+// nopAudit is a port.AuditService that drops every entry
+type nopAudit struct{}
+
+func (nopAudit) Record(ctx context.Context, action, entityType string, entityID int64, before, after any) {
+}
+
+func (nopAudit) ListAuditEntries(ctx context.Context, filter *domain.AuditFilter) ([]domain.AuditEntry, error) {
+	return nil, nil
+}
+
+// memoryBooks is an in-memory port.BookRepository for the methods the tests use, searches are kept
+// in searches and only filter by publisher
+type memoryBooks struct {
+	port.BookRepository
+	books    map[int64]*domain.Book
+	searches []domain.BookSearch
+}
+
+func (m *memoryBooks) GetBookById(ctx context.Context, id int64) (*domain.Book, error) {
+	book, ok := m.books[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	copied := *book
+	return &copied, nil
+}
+
+func (m *memoryBooks) SearchBooks(ctx context.Context, search *domain.BookSearch) ([]domain.Book, error) {
+	m.searches = append(m.searches, *search)
+	var books []domain.Book
+	for _, book := range m.books {
+		if search.PublisherID == 0 || (book.PublisherID != nil && *book.PublisherID == search.PublisherID) {
+			books = append(books, *book)
+		}
+	}
+	slices.SortFunc(books, func(a, b domain.Book) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return books, nil
+}
+
+// ListSeriesVolumes lists the books of a series in the order of their volume numbers
+func (m *memoryBooks) ListSeriesVolumes(ctx context.Context, seriesID int64) ([]domain.Book, error) {
+	var books []domain.Book
+	for _, book := range m.books {
+		if book.SeriesID != nil && *book.SeriesID == seriesID {
+			books = append(books, *book)
+		}
+	}
+	slices.SortFunc(books, func(a, b domain.Book) int {
+		return cmp.Compare(*a.SeriesVolume, *b.SeriesVolume)
+	})
+	return books, nil
+}
+
+// nopTranslations is a port.TranslationService without translations
+type nopTranslations struct {
+	port.TranslationService
+}
+
+func (nopTranslations) LocalizeBooks(ctx context.Context, books []domain.Book) error {
+	return nil
+}
+
+// memoryBlobs is an in-memory port.BlobStorage
+type memoryBlobs map[string][]byte
+
+func (m memoryBlobs) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m[key] = data
+	return nil
+}
+
+func (m memoryBlobs) Open(ctx context.Context, key string) (io.ReadSeekCloser, *domain.BlobInfo, error) {
+	data, ok := m[key]
+	if !ok {
+		return nil, nil, domain.ErrDataNotFound
+	}
+	info := &domain.BlobInfo{Key: key, Size: int64(len(data)), ModifiedAt: time.Now()}
+	return nopCloser{bytes.NewReader(data)}, info, nil
+}
+
+func (m memoryBlobs) Delete(ctx context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// memoryEditions is an in-memory port.EditionRepository for the methods the tests use
+type memoryEditions struct {
+	port.EditionRepository
+	editions map[int64]*domain.Edition
+}
+
+func (m *memoryEditions) GetEdition(ctx context.Context, id int64) (*domain.Edition, error) {
+	edition, ok := m.editions[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	copied := *edition
+	return &copied, nil
+}
+
+// recordingAudit is a port.AuditService keeping the actions it was asked to record
+type recordingAudit struct {
+	port.AuditService
+	entries []recordedAudit
+}
+
+type recordedAudit struct {
+	action   string
+	entityID int64
+	actorID  int64
+	after    any
+}
+
+func (r *recordingAudit) Record(ctx context.Context, action, entityType string, entityID int64, before, after any) {
+	r.entries = append(r.entries, recordedAudit{action: action, entityID: entityID, actorID: domain.AuditMetaFrom(ctx).ActorID, after: after})
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type FulfillmentService struct {
+	repo      port.FulfillmentRepository
+	orderRepo port.OrderRepository
+	bookRepo  port.BookRepository
+	carrier   port.Carrier
+	audit     port.AuditService
+}
+
+func NewFulfillmentService(repo port.FulfillmentRepository, orderRepo port.OrderRepository, bookRepo port.BookRepository, carrier port.Carrier, audit port.AuditService) *FulfillmentService {
+	return &FulfillmentService{
+		repo:      repo,
+		orderRepo: orderRepo,
+		bookRepo:  bookRepo,
+		carrier:   carrier,
+		audit:     audit,
+	}
+}
+
+// CreateFulfillment starts a shipment for some or all of the printed items of an order
+func (fs *FulfillmentService) CreateFulfillment(ctx context.Context, fulfillment *domain.Fulfillment) (*domain.Fulfillment, error) {
+	fulfillment, err := fs.repo.CreateFulfillment(ctx, fulfillment)
+	if err != nil {
+		return nil, err
+	}
+	fs.audit.Record(ctx, domain.AuditFulfillmentCreate, domain.AuditEntityFulfillment, fulfillment.ID, nil, fulfillment)
+	if err := fs.syncOrderStatus(ctx, fulfillment.OrderID); err != nil {
+		return nil, err
+	}
+	return fulfillment, nil
+}
+
+// GetFulfillment returns a fulfillment by id
+func (fs *FulfillmentService) GetFulfillment(ctx context.Context, id int64) (*domain.Fulfillment, error) {
+	return fs.repo.GetFulfillment(ctx, id)
+}
+
+// ListOrderFulfillments returns the fulfillments of an order
+func (fs *FulfillmentService) ListOrderFulfillments(ctx context.Context, orderID int64) ([]domain.Fulfillment, error) {
+	return fs.repo.ListOrderFulfillments(ctx, orderID)
+}
+
+// UpdateStatus moves a fulfillment to the next status, shipping needs a carrier and goes through
+// Ship instead
+func (fs *FulfillmentService) UpdateStatus(ctx context.Context, id int64, status domain.FulfillmentStatus) (*domain.Fulfillment, error) {
+	if status == domain.FulfillmentShipped {
+		return nil, fmt.Errorf("%w: ship the fulfillment with a carrier instead", domain.ErrFulfillmentStatus)
+	}
+	fulfillment, err := fs.repo.GetFulfillment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return fs.transition(ctx, fulfillment, domain.TrackingEvent{
+		Status:      status,
+		Description: fmt.Sprintf("Marked %s by staff", status),
+		OccurredAt:  time.Now(),
+	})
+}
+
+// Ship hands a packed fulfillment to a carrier. Without a tracking number the shipment is booked
+// with the configured carrier, otherwise the label was bought elsewhere and carrier names it.
+func (fs *FulfillmentService) Ship(ctx context.Context, id int64, carrier, trackingNumber string) (*domain.Fulfillment, error) {
+	fulfillment, err := fs.repo.GetFulfillment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !fulfillment.Status.CanTransition(domain.FulfillmentShipped) {
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrFulfillmentStatus, fulfillment.Status, domain.FulfillmentShipped)
+	}
+
+	switch {
+	case trackingNumber != "" && carrier == "":
+		return nil, fmt.Errorf("%w: a tracking number needs the carrier it belongs to", domain.ErrInvalidFulfillment)
+	case trackingNumber == "":
+		if carrier != "" && carrier != fs.carrier.Name() {
+			return nil, fmt.Errorf("%w: shipments can only be booked with %s", domain.ErrInvalidFulfillment, fs.carrier.Name())
+		}
+		parcel, err := fs.parcel(ctx, fulfillment)
+		if err != nil {
+			return nil, err
+		}
+		if trackingNumber, err = fs.carrier.CreateShipment(ctx, parcel); err != nil {
+			return nil, err
+		}
+		carrier = fs.carrier.Name()
+	}
+
+	fulfillment.Carrier = carrier
+	fulfillment.TrackingNumber = trackingNumber
+	return fs.transition(ctx, fulfillment, domain.TrackingEvent{
+		Status:      domain.FulfillmentShipped,
+		Description: fmt.Sprintf("Handed to %s", carrier),
+		OccurredAt:  time.Now(),
+	})
+}
+
+// parcel describes the parcel of a fulfillment for the carrier
+func (fs *FulfillmentService) parcel(ctx context.Context, fulfillment *domain.Fulfillment) (*domain.Parcel, error) {
+	order, err := fs.orderRepo.GetOrderById(ctx, fulfillment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[int64]int, len(order.Items))
+	for _, item := range order.Items {
+		weights[item.ID] = item.WeightGrams
+	}
+
+	parcel := &domain.Parcel{
+		FulfillmentID: fulfillment.ID,
+		OrderID:       order.ID,
+		ShipTo:        order.ShippingAddress,
+	}
+	for _, item := range fulfillment.Items {
+		parcel.WeightGrams += weights[item.OrderItemID] * item.Quantity
+	}
+	return parcel, nil
+}
+
+// transition moves a fulfillment to the status of the event, records the event and updates the
+// status of the order
+func (fs *FulfillmentService) transition(ctx context.Context, fulfillment *domain.Fulfillment, event domain.TrackingEvent) (*domain.Fulfillment, error) {
+	from := fulfillment.Status
+	if !from.CanTransition(event.Status) {
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrFulfillmentStatus, from, event.Status)
+	}
+	before := *fulfillment
+
+	fulfillment.Status = event.Status
+	switch event.Status {
+	case domain.FulfillmentShipped:
+		fulfillment.ShippedAt = &event.OccurredAt
+	case domain.FulfillmentDelivered:
+		fulfillment.DeliveredAt = &event.OccurredAt
+	}
+	fulfillment, err := fs.repo.UpdateFulfillment(ctx, fulfillment, from)
+	if err != nil {
+		return nil, err
+	}
+
+	event.FulfillmentID = fulfillment.ID
+	if err := fs.repo.AddTrackingEvents(ctx, []domain.TrackingEvent{event}); err != nil {
+		return nil, err
+	}
+	action := domain.AuditFulfillmentStatus
+	if event.Status == domain.FulfillmentShipped {
+		action = domain.AuditFulfillmentShip
+	}
+	fs.audit.Record(ctx, action, domain.AuditEntityFulfillment, fulfillment.ID, &before, fulfillment)
+
+	if err := fs.syncOrderStatus(ctx, fulfillment.OrderID); err != nil {
+		return nil, err
+	}
+	return fulfillment, nil
+}
+
+// syncOrderStatus derives the status of an order from its fulfillments and stores it
+func (fs *FulfillmentService) syncOrderStatus(ctx context.Context, orderID int64) error {
+	order, err := fs.orderRepo.GetOrderById(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	fulfillments, err := fs.repo.ListOrderFulfillments(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if status := domain.FulfillmentOrderStatus(order, fulfillments); status != order.Status {
+		return fs.orderRepo.UpdateOrderStatus(ctx, orderID, status)
+	}
+	return nil
+}
+
+// PackingSlip lists the books of a fulfillment with the address they go to
+func (fs *FulfillmentService) PackingSlip(ctx context.Context, id int64) (*domain.PackingSlip, error) {
+	fulfillment, err := fs.repo.GetFulfillment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	order, err := fs.orderRepo.GetOrderById(ctx, fulfillment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	slip := &domain.PackingSlip{
+		FulfillmentID:  fulfillment.ID,
+		OrderID:        order.ID,
+		OrderedAt:      order.CreatedAt,
+		ShipTo:         order.ShippingAddress,
+		ShippingMethod: order.ShippingMethod,
+		Carrier:        fulfillment.Carrier,
+		TrackingNumber: fulfillment.TrackingNumber,
+	}
+	for _, item := range fulfillment.Items {
+		line := domain.PackingSlipLine{
+			BookID:   item.BookID,
+			Name:     fmt.Sprintf("Book #%d", item.BookID),
+			Quantity: item.Quantity,
+		}
+		// Books deleted since the order was placed are listed by id
+		book, err := fs.bookRepo.GetBookById(ctx, item.BookID)
+		switch err {
+		case nil:
+			line.Name = book.Name
+			line.Author = book.Author
+		case domain.ErrDataNotFound:
+		default:
+			return nil, err
+		}
+		slip.Lines = append(slip.Lines, line)
+	}
+	return slip, nil
+}
+
+// Tracking returns the tracking events of a fulfillment, oldest first
+func (fs *FulfillmentService) Tracking(ctx context.Context, id int64) ([]domain.TrackingEvent, error) {
+	if _, err := fs.repo.GetFulfillment(ctx, id); err != nil {
+		return nil, err
+	}
+	return fs.repo.ListTrackingEvents(ctx, id)
+}
+
+// RefreshTracking asks the configured carrier about every shipment on its way, stores the events
+// it reports and moves the fulfillments to the status of the latest one
+func (fs *FulfillmentService) RefreshTracking(ctx context.Context) error {
+	fulfillments, err := fs.repo.ListCarrierShipments(ctx, fs.carrier.Name())
+	if err != nil {
+		return err
+	}
+	for _, fulfillment := range fulfillments {
+		if err := fs.refresh(ctx, &fulfillment); err != nil {
+			slog.Error("failed to refresh shipment tracking", "fulfillment_id", fulfillment.ID, "tracking_number", fulfillment.TrackingNumber, "error", err)
+		}
+	}
+	return nil
+}
+
+func (fs *FulfillmentService) refresh(ctx context.Context, fulfillment *domain.Fulfillment) error {
+	events, err := fs.carrier.Track(ctx, fulfillment.TrackingNumber)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	for i := range events {
+		events[i].FulfillmentID = fulfillment.ID
+	}
+	if err := fs.repo.AddTrackingEvents(ctx, events); err != nil {
+		return err
+	}
+
+	latest := events[len(events)-1]
+	if latest.Status == fulfillment.Status || !fulfillment.Status.CanTransition(latest.Status) {
+		return nil
+	}
+	from := fulfillment.Status
+	before := *fulfillment
+	fulfillment.Status = latest.Status
+	if latest.Status == domain.FulfillmentDelivered {
+		fulfillment.DeliveredAt = &latest.OccurredAt
+	}
+	if _, err := fs.repo.UpdateFulfillment(ctx, fulfillment, from); err != nil {
+		return err
+	}
+	fs.audit.Record(ctx, domain.AuditFulfillmentStatus, domain.AuditEntityFulfillment, fulfillment.ID, &before, fulfillment)
+	return fs.syncOrderStatus(ctx, fulfillment.OrderID)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type InvoiceService struct {
+	repo      port.InvoiceRepository
+	orderRepo port.OrderRepository
+	bookRepo  port.BookRepository
+	renderer  port.InvoiceRenderer
+	blobs     port.BlobStorage
+	seller    domain.InvoiceSeller
+	audit     port.AuditService
+}
+
+func NewInvoiceService(repo port.InvoiceRepository, orderRepo port.OrderRepository, bookRepo port.BookRepository, renderer port.InvoiceRenderer, blobs port.BlobStorage, seller domain.InvoiceSeller, audit port.AuditService) *InvoiceService {
+	return &InvoiceService{
+		repo:      repo,
+		orderRepo: orderRepo,
+		bookRepo:  bookRepo,
+		renderer:  renderer,
+		blobs:     blobs,
+		seller:    seller,
+		audit:     audit,
+	}
+}
+
+// InvoicePDF returns the PDF invoice of an order. Orders that are not paid have no invoice, the PDF
+// is rendered again whenever it is missing from the blob storage.
+func (is *InvoiceService) InvoicePDF(ctx context.Context, orderID int64) (*domain.Invoice, io.ReadSeekCloser, *domain.BlobInfo, error) {
+	order, err := is.orderRepo.GetOrderById(ctx, orderID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Issuing normally happens when the payment is recorded, a paid order without an invoice
+	// failed to issue it then
+	invoice, err := is.IssueInvoice(ctx, order)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	file, info, err := is.blobs.Open(ctx, invoice.BlobKey)
+	if err == domain.ErrDataNotFound {
+		if err := is.render(ctx, invoice, order); err != nil {
+			return nil, nil, nil, err
+		}
+		file, info, err = is.blobs.Open(ctx, invoice.BlobKey)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return invoice, file, info, nil
+}
+
+// IssueInvoice returns the invoice of a paid order, numbering a new one from the order items if
+// the order has none yet
+func (is *InvoiceService) IssueInvoice(ctx context.Context, order *domain.Order) (*domain.Invoice, error) {
+	invoice, err := is.repo.GetOrderInvoice(ctx, order.ID)
+	if err != domain.ErrDataNotFound {
+		return invoice, err
+	}
+	if order.PaidAt == nil {
+		return nil, domain.ErrOrderNotPaid
+	}
+
+	lines, err := is.invoiceLines(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	invoice, err = is.repo.CreateInvoice(ctx, &domain.Invoice{
+		OrderID:  order.ID,
+		Year:     now.Year(),
+		Lines:    lines,
+		IssuedAt: now,
+	})
+	// Another request issued the invoice in the meantime
+	if err == domain.ErrConflictingData {
+		return is.repo.GetOrderInvoice(ctx, order.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	is.audit.Record(ctx, domain.AuditOrderInvoice, domain.AuditEntityOrder, order.ID, nil, map[string]any{"number": invoice.Number})
+	return invoice, nil
+}
+
+// invoiceLines describes every item of an order with the tax computed for it when it was placed
+func (is *InvoiceService) invoiceLines(ctx context.Context, order *domain.Order) ([]domain.InvoiceLine, error) {
+	taxes := make(map[int]domain.TaxLine, len(order.TaxLines))
+	for _, line := range order.TaxLines {
+		taxes[line.Item] = line
+	}
+
+	lines := make([]domain.InvoiceLine, 0, len(order.Items))
+	for i, item := range order.Items {
+		line := domain.InvoiceLine{
+			Description: fmt.Sprintf("Book #%d", item.BookID),
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      domain.RoundPrice(item.UnitPrice * float64(item.Quantity)),
+		}
+		// Books deleted since the order was placed are described by id
+		book, err := is.bookRepo.GetBookById(ctx, item.BookID)
+		switch err {
+		case nil:
+			line.Description = fmt.Sprintf("%s, %s", book.Name, book.Author)
+		case domain.ErrDataNotFound:
+		default:
+			return nil, err
+		}
+		if item.Format != "" {
+			line.Description += fmt.Sprintf(" (%s)", item.Format)
+		}
+		if tax, ok := taxes[i]; ok {
+			line.TaxName = tax.Name
+			line.TaxRate = tax.Rate
+			line.TaxAmount = tax.Amount
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// render renders the PDF of an invoice and stores it
+func (is *InvoiceService) render(ctx context.Context, invoice *domain.Invoice, order *domain.Order) error {
+	pdf, err := is.renderer.Render(&domain.InvoiceDocument{
+		Invoice: invoice,
+		Seller:  is.seller,
+		Order:   order,
+	})
+	if err != nil {
+		return err
+	}
+	return is.blobs.Put(ctx, invoice.BlobKey, bytes.NewReader(pdf))
+}
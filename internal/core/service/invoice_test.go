@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// memoryOrders is an in-memory port.OrderRepository for the methods the tests use
+type memoryOrders struct {
+	port.OrderRepository
+	orders map[int64]*domain.Order
+}
+
+func (m *memoryOrders) GetOrderById(ctx context.Context, id int64) (*domain.Order, error) {
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (m *memoryOrders) MarkOrderPaid(ctx context.Context, id int64, paidAt time.Time) error {
+	order, ok := m.orders[id]
+	if !ok {
+		return domain.ErrDataNotFound
+	}
+	if order.PaidAt != nil {
+		return domain.ErrOrderAlreadyPaid
+	}
+	order.PaidAt = &paidAt
+	return nil
+}
+
+// memoryInvoices is an in-memory port.InvoiceRepository numbering the invoices of a single year
+type memoryInvoices map[int64]*domain.Invoice
+
+func (m memoryInvoices) CreateInvoice(ctx context.Context, invoice *domain.Invoice) (*domain.Invoice, error) {
+	if _, ok := m[invoice.OrderID]; ok {
+		return nil, domain.ErrConflictingData
+	}
+	invoice.ID = int64(len(m) + 1)
+	invoice.Sequence = len(m) + 1
+	invoice.Number = domain.InvoiceNumber(invoice.Year, invoice.Sequence)
+	invoice.BlobKey = domain.InvoiceBlobKey(invoice.Number)
+	m[invoice.OrderID] = invoice
+	return invoice, nil
+}
+
+func (m memoryInvoices) GetOrderInvoice(ctx context.Context, orderID int64) (*domain.Invoice, error) {
+	invoice, ok := m[orderID]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return invoice, nil
+}
+
+// jsonRenderer is a port.InvoiceRenderer writing the lines of the invoice as JSON
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(document *domain.InvoiceDocument) ([]byte, error) {
+	return json.Marshal(document.Invoice.Lines)
+}
+
+func newTestInvoiceService(orders *memoryOrders, books *memoryBooks, invoices memoryInvoices, blobs memoryBlobs, audit port.AuditService) *InvoiceService {
+	return NewInvoiceService(invoices, orders, books, jsonRenderer{}, blobs, domain.InvoiceSeller{Name: "Book Store"}, audit)
+}
+
+func TestIssueInvoice(t *testing.T) {
+	paidAt := time.Date(2025, 2, 9, 10, 0, 0, 0, time.UTC)
+	hardcover, ebook := int64(10), int64(11)
+	books := &memoryBooks{books: map[int64]*domain.Book{1: {ID: 1, Name: "Dune", Author: "Frank Herbert"}}}
+
+	tests := []struct {
+		name      string
+		order     *domain.Order
+		wantErr   error
+		wantLines []domain.InvoiceLine
+	}{
+		{
+			name:    "unpaid order",
+			order:   &domain.Order{ID: 1, Items: []domain.OrderItem{{ID: 1, BookID: 1, Quantity: 1, UnitPrice: 10}}},
+			wantErr: domain.ErrOrderNotPaid,
+		},
+		{
+			name: "tax lines follow their item when a book is ordered twice",
+			order: &domain.Order{
+				ID:     2,
+				PaidAt: &paidAt,
+				Items: []domain.OrderItem{
+					{ID: 1, BookID: 1, EditionID: &hardcover, Format: domain.FormatHardcover, Quantity: 2, UnitPrice: 20},
+					{ID: 2, BookID: 1, EditionID: &ebook, Format: domain.FormatEbook, Quantity: 1, UnitPrice: 8},
+				},
+				TaxLines: []domain.TaxLine{
+					{Item: 1, BookID: 1, Class: domain.ProductClassEbook, Name: "VAT", Rate: 0.19, Taxable: 8, Amount: 1.52},
+				},
+			},
+			wantLines: []domain.InvoiceLine{
+				{Description: "Dune, Frank Herbert (hardcover)", Quantity: 2, UnitPrice: 20, Amount: 40},
+				{Description: "Dune, Frank Herbert (ebook)", Quantity: 1, UnitPrice: 8, TaxName: "VAT", TaxRate: 0.19, TaxAmount: 1.52, Amount: 8},
+			},
+		},
+		{
+			name:      "deleted book",
+			order:     &domain.Order{ID: 3, PaidAt: &paidAt, Items: []domain.OrderItem{{ID: 1, BookID: 9, Quantity: 1, UnitPrice: 5}}},
+			wantLines: []domain.InvoiceLine{{Description: "Book #9", Quantity: 1, UnitPrice: 5, Amount: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoices, audit := memoryInvoices{}, &recordingAudit{}
+			is := newTestInvoiceService(&memoryOrders{}, books, invoices, memoryBlobs{}, audit)
+
+			invoice, err := is.IssueInvoice(context.Background(), tt.order)
+			if err != tt.wantErr {
+				t.Fatalf("IssueInvoice() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(invoices) != 0 {
+					t.Error("a number was used for an order that cannot be invoiced")
+				}
+				if len(audit.entries) != 0 {
+					t.Errorf("audit entries = %+v, want none", audit.entries)
+				}
+				return
+			}
+			if len(invoice.Lines) != len(tt.wantLines) {
+				t.Fatalf("lines = %+v, want %+v", invoice.Lines, tt.wantLines)
+			}
+			for i := range tt.wantLines {
+				if invoice.Lines[i] != tt.wantLines[i] {
+					t.Errorf("line %d = %+v, want %+v", i, invoice.Lines[i], tt.wantLines[i])
+				}
+			}
+
+			// The order keeps its invoice
+			again, err := is.IssueInvoice(context.Background(), tt.order)
+			if err != nil || again.Number != invoice.Number || len(invoices) != 1 {
+				t.Errorf("second IssueInvoice() = %v, %v, want the invoice %s", again, err, invoice.Number)
+			}
+			// Only issuing the invoice is audited, not finding it again
+			if len(audit.entries) != 1 || audit.entries[0].action != domain.AuditOrderInvoice || audit.entries[0].entityID != tt.order.ID {
+				t.Errorf("audit entries = %+v, want one %s of order %d", audit.entries, domain.AuditOrderInvoice, tt.order.ID)
+			}
+		})
+	}
+}
+
+func TestInvoicePDF(t *testing.T) {
+	paidAt := time.Date(2025, 2, 9, 10, 0, 0, 0, time.UTC)
+	orders := &memoryOrders{orders: map[int64]*domain.Order{
+		1: {ID: 1, Items: []domain.OrderItem{{ID: 1, BookID: 1, Quantity: 1, UnitPrice: 10}}},
+		2: {ID: 2, PaidAt: &paidAt, Items: []domain.OrderItem{{ID: 2, BookID: 1, Quantity: 1, UnitPrice: 10}}},
+	}}
+	books := &memoryBooks{books: map[int64]*domain.Book{1: {ID: 1, Name: "Dune", Author: "Frank Herbert"}}}
+	invoices, blobs := memoryInvoices{}, memoryBlobs{}
+	is := newTestInvoiceService(orders, books, invoices, blobs, nopAudit{})
+	ctx := context.Background()
+
+	if _, _, _, err := is.InvoicePDF(ctx, 1); err != domain.ErrOrderNotPaid {
+		t.Errorf("InvoicePDF() of an unpaid order error = %v, want %v", err, domain.ErrOrderNotPaid)
+	}
+
+	// A paid order without an invoice gets it when it is first asked for
+	invoice, file, _, err := is.InvoicePDF(ctx, 2)
+	if err != nil {
+		t.Fatalf("InvoicePDF() error = %v", err)
+	}
+	file.Close()
+
+	// The PDF is rendered again from the lines of the invoice, not from the book as it is now
+	books.books[1].Name = "Dune Messiah"
+	delete(blobs, invoice.BlobKey)
+	if _, file, _, err = is.InvoicePDF(ctx, 2); err != nil {
+		t.Fatalf("InvoicePDF() after losing the PDF error = %v", err)
+	}
+	file.Close()
+	var lines []domain.InvoiceLine
+	if err := json.Unmarshal(blobs[invoice.BlobKey], &lines); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0].Description != "Dune, Frank Herbert" {
+		t.Errorf("rendered lines = %+v, want the description of the issued invoice", lines)
+	}
+}
+
+func TestMarkOrderPaid(t *testing.T) {
+	orders := &memoryOrders{orders: map[int64]*domain.Order{
+		1: {ID: 1, Items: []domain.OrderItem{{ID: 1, BookID: 1, Quantity: 1, UnitPrice: 10}}},
+	}}
+	invoices := memoryInvoices{}
+	is := newTestInvoiceService(orders, &memoryBooks{}, invoices, memoryBlobs{}, nopAudit{})
+	ds := newTestDownloadService(grantingDownloads{memoryDownloads: memoryDownloads{}, orders: orders}, orders)
+	os := NewOrderService(orders, nil, nil, nil, nil, nil, nil, is, ds, nopAudit{}, domain.TaxExclusive)
+	ctx := context.Background()
+
+	order, err := os.MarkOrderPaid(ctx, 1)
+	if err != nil {
+		t.Fatalf("MarkOrderPaid() error = %v", err)
+	}
+	if order.PaidAt == nil {
+		t.Error("the order is not paid")
+	}
+	if _, ok := invoices[1]; !ok {
+		t.Error("no invoice was issued for the paid order")
+	}
+
+	if _, err := os.MarkOrderPaid(ctx, 1); err != domain.ErrOrderAlreadyPaid {
+		t.Errorf("second MarkOrderPaid() error = %v, want %v", err, domain.ErrOrderAlreadyPaid)
+	}
+	if _, err := os.MarkOrderPaid(ctx, 2); err != domain.ErrDataNotFound {
+		t.Errorf("MarkOrderPaid() of a missing order error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
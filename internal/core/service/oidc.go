@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
+)
+
+// OIDCStateTTL is how long a user has to complete a login at the identity provider
+var OIDCStateTTL = 10 * time.Minute
+
+type OIDCService struct {
+	providers     map[string]port.OIDCProvider
+	stateRepo     port.OIDCStateRepository
+	identityRepo  port.UserIdentityRepository
+	userRepo      port.UserRepository
+	twoFactorRepo port.TwoFactorRepository
+	tokenService  port.TokenService
+	audit         port.AuditService
+}
+
+func NewOIDCService(
+	providers []port.OIDCProvider,
+	stateRepo port.OIDCStateRepository,
+	identityRepo port.UserIdentityRepository,
+	userRepo port.UserRepository,
+	twoFactorRepo port.TwoFactorRepository,
+	tokenService port.TokenService,
+	audit port.AuditService,
+) *OIDCService {
+	byName := make(map[string]port.OIDCProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &OIDCService{
+		providers:     byName,
+		stateRepo:     stateRepo,
+		identityRepo:  identityRepo,
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		tokenService:  tokenService,
+		audit:         audit,
+	}
+}
+
+// AuthURL stores a new state, nonce and PKCE verifier and returns the provider's authorization URL
+// with the state and verifier the browser has to keep
+func (os *OIDCService) AuthURL(ctx context.Context, provider string) (*domain.OIDCLogin, error) {
+	p, ok := os.providers[provider]
+	if !ok {
+		return nil, domain.ErrUnknownProvider
+	}
+
+	request := &domain.OIDCAuthRequest{Provider: provider}
+	var err error
+	if request.State, err = util.GenerateRandomToken(32); err != nil {
+		return nil, domain.ErrInternal
+	}
+	if request.Nonce, err = util.GenerateRandomToken(32); err != nil {
+		return nil, domain.ErrInternal
+	}
+	if request.CodeVerifier, err = util.GenerateRandomToken(32); err != nil {
+		return nil, domain.ErrInternal
+	}
+	if err := os.stateRepo.SaveAuthRequest(ctx, request, OIDCStateTTL); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	url, err := p.AuthCodeURL(ctx, request.State, request.Nonce, util.PKCEChallenge(request.CodeVerifier))
+	if err != nil {
+		slog.Error("failed to build oidc authorization url", "provider", provider, "error", err)
+		return nil, domain.ErrInternal
+	}
+	return &domain.OIDCLogin{
+		URL: url,
+		Binding: domain.OIDCBinding{
+			State:        request.State,
+			CodeVerifier: request.CodeVerifier,
+		},
+		ExpiresAt: time.Now().Add(OIDCStateTTL),
+	}, nil
+}
+
+// Callback verifies the login at the provider, finds or links the user and issues our own tokens.
+// The state has to match the binding of the browser so a login cannot be completed in another browser.
+func (os *OIDCService) Callback(ctx context.Context, provider, state, code string, binding domain.OIDCBinding) (*domain.LoginResult, error) {
+	p, ok := os.providers[provider]
+	if !ok {
+		return nil, domain.ErrUnknownProvider
+	}
+	if binding.State == "" || !equalSecret(binding.State, state) {
+		return nil, domain.ErrInvalidOIDCState
+	}
+
+	request, err := os.stateRepo.TakeAuthRequest(ctx, state)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidOIDCState
+		}
+		return nil, domain.ErrInternal
+	}
+	if request.Provider != provider || !equalSecret(binding.CodeVerifier, request.CodeVerifier) {
+		return nil, domain.ErrInvalidOIDCState
+	}
+
+	claims, err := p.Exchange(ctx, code, request.CodeVerifier, request.Nonce)
+	if err != nil {
+		slog.Warn("oidc code exchange failed", "provider", provider, "error", err)
+		return nil, domain.ErrOIDCExchange
+	}
+
+	user, err := os.linkUser(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users with a second factor still have to pass it
+	twoFactor, err := os.twoFactorRepo.GetTwoFactor(ctx, user.ID)
+	if err != nil && err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+	if twoFactor.IsEnabled() {
+		challengeToken, err := os.tokenService.CreateChallengeToken(user)
+		if err != nil {
+			return nil, domain.ErrTokenCreation
+		}
+		return &domain.LoginResult{
+			ChallengeToken:    challengeToken,
+			TwoFactorRequired: true,
+		}, nil
+	}
+
+	accessToken, err := os.tokenService.CreateToken(user, false)
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+	return &domain.LoginResult{
+		AccessToken: accessToken,
+	}, nil
+}
+
+// linkUser returns the user of an identity, linking it to an existing user by verified email
+// or creating a new user without a usable password
+func (os *OIDCService) linkUser(ctx context.Context, provider string, claims *domain.OIDCClaims) (*domain.User, error) {
+	identity, err := os.identityRepo.GetUserIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		user, err := os.userRepo.GetUserById(ctx, identity.UserID)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		return user, nil
+	}
+	if err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, domain.ErrUnverifiedEmail
+	}
+	email := strings.ToLower(claims.Email)
+
+	user, err := os.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err != domain.ErrDataNotFound {
+			return nil, domain.ErrInternal
+		}
+		user, err = os.createUser(ctx, email, claims.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	identity, err = os.identityRepo.CreateUserIdentity(ctx, &domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    email,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	os.audit.Record(ctx, domain.AuditUserIdentityLink, domain.AuditEntityUser, user.ID, nil, identity)
+	return user, nil
+}
+
+func (os *OIDCService) createUser(ctx context.Context, email, name string) (*domain.User, error) {
+	if name == "" {
+		name = email
+	}
+	// The user signs in through the provider, the random password can only be replaced by a reset
+	password, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	user, err := os.userRepo.CreateUser(ctx, &domain.User{
+		Email:    email,
+		Name:     name,
+		Password: hashedPassword,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	os.audit.Record(ctx, domain.AuditUserRegister, domain.AuditEntityUser, user.ID, nil, user)
+	return user, nil
+}
+
+// equalSecret compares two secrets in constant time
+func equalSecret(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
+)
+
+// stubProvider is a port.OIDCProvider that accepts one code and checks the PKCE pair
+type stubProvider struct {
+	challenge string
+	claims    domain.OIDCClaims
+}
+
+func (sp *stubProvider) Name() string {
+	return "stub"
+}
+
+func (sp *stubProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	sp.challenge = codeChallenge
+	values := url.Values{"state": {state}, "nonce": {nonce}, "code_challenge": {codeChallenge}}
+	return "https://idp.example/authorize?" + values.Encode(), nil
+}
+
+func (sp *stubProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCClaims, error) {
+	if code != "valid-code" || util.PKCEChallenge(codeVerifier) != sp.challenge {
+		return nil, domain.ErrInvalidToken
+	}
+	claims := sp.claims
+	return &claims, nil
+}
+
+// memoryOIDCState is an in-memory port.OIDCStateRepository
+type memoryOIDCState map[string]*domain.OIDCAuthRequest
+
+func (m memoryOIDCState) SaveAuthRequest(ctx context.Context, request *domain.OIDCAuthRequest, ttl time.Duration) error {
+	m[request.State] = request
+	return nil
+}
+
+func (m memoryOIDCState) TakeAuthRequest(ctx context.Context, state string) (*domain.OIDCAuthRequest, error) {
+	request, ok := m[state]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	delete(m, state)
+	return request, nil
+}
+
+// memoryIdentities is an in-memory port.UserIdentityRepository
+type memoryIdentities []*domain.UserIdentity
+
+func (m *memoryIdentities) GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	for _, identity := range *m {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *memoryIdentities) CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error) {
+	*m = append(*m, identity)
+	return identity, nil
+}
+
+func newTestOIDCService(provider *stubProvider, users *memoryUsers) *OIDCService {
+	return NewOIDCService(
+		[]port.OIDCProvider{provider},
+		memoryOIDCState{},
+		&memoryIdentities{},
+		users,
+		&memoryTwoFactors{},
+		fakeTokens{},
+		nopAudit{},
+	)
+}
+
+func TestOIDCCallback(t *testing.T) {
+	verified := domain.OIDCClaims{Subject: "subject-1", Email: "Reader@Example.com", EmailVerified: true, Name: "Reader"}
+
+	tests := []struct {
+		name    string
+		claims  domain.OIDCClaims
+		state   func(login *domain.OIDCLogin) string
+		binding func(login *domain.OIDCLogin) domain.OIDCBinding
+		code    string
+		want    error
+	}{
+		{
+			name:   "valid login",
+			claims: verified,
+			code:   "valid-code",
+		},
+		{
+			name:    "no cookie",
+			claims:  verified,
+			binding: func(login *domain.OIDCLogin) domain.OIDCBinding { return domain.OIDCBinding{} },
+			code:    "valid-code",
+			want:    domain.ErrInvalidOIDCState,
+		},
+		{
+			name:   "state of another browser",
+			claims: verified,
+			binding: func(login *domain.OIDCLogin) domain.OIDCBinding {
+				return domain.OIDCBinding{State: "attacker-state", CodeVerifier: login.Binding.CodeVerifier}
+			},
+			code: "valid-code",
+			want: domain.ErrInvalidOIDCState,
+		},
+		{
+			name:   "unknown state in both",
+			claims: verified,
+			state:  func(login *domain.OIDCLogin) string { return "forged" },
+			binding: func(login *domain.OIDCLogin) domain.OIDCBinding {
+				return domain.OIDCBinding{State: "forged", CodeVerifier: login.Binding.CodeVerifier}
+			},
+			code: "valid-code",
+			want: domain.ErrInvalidOIDCState,
+		},
+		{
+			name:   "wrong verifier",
+			claims: verified,
+			binding: func(login *domain.OIDCLogin) domain.OIDCBinding {
+				return domain.OIDCBinding{State: login.Binding.State, CodeVerifier: "other-verifier"}
+			},
+			code: "valid-code",
+			want: domain.ErrInvalidOIDCState,
+		},
+		{
+			name:   "rejected code",
+			claims: verified,
+			code:   "wrong-code",
+			want:   domain.ErrOIDCExchange,
+		},
+		{
+			name:   "unverified email",
+			claims: domain.OIDCClaims{Subject: "subject-1", Email: "reader@example.com"},
+			code:   "valid-code",
+			want:   domain.ErrUnverifiedEmail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &stubProvider{claims: tt.claims}
+			users := &memoryUsers{}
+			os := newTestOIDCService(provider, users)
+			ctx := context.Background()
+
+			login, err := os.AuthURL(ctx, "stub")
+			if err != nil {
+				t.Fatalf("AuthURL() error = %v", err)
+			}
+			state, binding := login.Binding.State, login.Binding
+			if tt.state != nil {
+				state = tt.state(login)
+			}
+			if tt.binding != nil {
+				binding = tt.binding(login)
+			}
+
+			result, err := os.Callback(ctx, "stub", state, tt.code, binding)
+			if err != tt.want {
+				t.Fatalf("Callback() error = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				return
+			}
+			if result.AccessToken != "access:1:false" {
+				t.Errorf("Callback() access token = %q", result.AccessToken)
+			}
+			if len(users.users) != 1 || users.users[0].Email != "reader@example.com" {
+				t.Errorf("users = %+v, want one user with the lowercased email", users.users)
+			}
+
+			// A state can only be used once
+			if _, err := os.Callback(ctx, "stub", state, tt.code, binding); err != domain.ErrInvalidOIDCState {
+				t.Errorf("replayed Callback() error = %v, want %v", err, domain.ErrInvalidOIDCState)
+			}
+		})
+	}
+}
+
+func TestOIDCAuthURL(t *testing.T) {
+	provider := &stubProvider{}
+	os := newTestOIDCService(provider, &memoryUsers{})
+
+	if _, err := os.AuthURL(context.Background(), "unknown"); err != domain.ErrUnknownProvider {
+		t.Errorf("AuthURL() of an unknown provider error = %v, want %v", err, domain.ErrUnknownProvider)
+	}
+
+	login, err := os.AuthURL(context.Background(), "stub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(login.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("state") != login.Binding.State {
+		t.Error("the authorization URL does not carry the state of the binding")
+	}
+	if provider.challenge != util.PKCEChallenge(login.Binding.CodeVerifier) {
+		t.Error("the code challenge is not derived from the verifier of the binding")
+	}
+	if until := time.Until(login.ExpiresAt); until <= 0 || until > OIDCStateTTL {
+		t.Errorf("login expires in %s, want within %s", until, OIDCStateTTL)
+	}
+}
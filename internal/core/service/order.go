@@ -2,28 +2,341 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
 )
 
 type OrderService struct {
-	repo port.OrderRepository
+	repo        port.OrderRepository
+	bookRepo    port.BookRepository
+	editionRepo port.EditionRepository
+	addressRepo port.AddressRepository
+	promotions  port.PromotionService
+	shipping    port.ShippingService
+	taxes       port.TaxCalculator
+	invoices    port.InvoiceService
+	downloads   port.DownloadService
+	audit       port.AuditService
+	taxMode     domain.TaxMode
 }
 
-func NewOrderService(repo port.OrderRepository) *OrderService {
+func NewOrderService(repo port.OrderRepository, bookRepo port.BookRepository, editionRepo port.EditionRepository, addressRepo port.AddressRepository, promotions port.PromotionService, shipping port.ShippingService, taxes port.TaxCalculator, invoices port.InvoiceService, downloads port.DownloadService, audit port.AuditService, taxMode domain.TaxMode) *OrderService {
 	return &OrderService{
-		repo: repo,
+		repo:        repo,
+		bookRepo:    bookRepo,
+		editionRepo: editionRepo,
+		addressRepo: addressRepo,
+		promotions:  promotions,
+		shipping:    shipping,
+		taxes:       taxes,
+		invoices:    invoices,
+		downloads:   downloads,
+		audit:       audit,
+		taxMode:     taxMode,
 	}
 }
 
-func (os *OrderService) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
-	return nil, nil
+// priceOrder copies the current price, class and weight of the ordered edition and the category
+// of its book onto every item and applies the promotions. A coupon that was entered but does not
+// apply fails the order.
+func (os *OrderService) priceOrder(ctx context.Context, order *domain.Order) error {
+	if len(order.Items) == 0 {
+		return domain.ErrEmptyOrder
+	}
+
+	basket := &domain.Basket{
+		UserID:     order.UserId,
+		CouponCode: order.CouponCode,
+	}
+	for i := range order.Items {
+		item := &order.Items[i]
+		if err := os.priceEdition(ctx, item); err != nil {
+			return err
+		}
+		book, err := os.bookRepo.GetBookById(ctx, item.BookID)
+		if err != nil {
+			return err
+		}
+		item.CategoryID = book.CategoryID
+		basket.Lines = append(basket.Lines, domain.BasketLine{
+			BookID:     item.BookID,
+			CategoryID: item.CategoryID,
+			Quantity:   item.Quantity,
+			UnitPrice:  item.UnitPrice,
+		})
+	}
+
+	result, err := os.promotions.Evaluate(ctx, basket)
+	if err != nil {
+		return err
+	}
+	if result.CouponRejection != "" {
+		return fmt.Errorf("%w: %s", domain.ErrCouponNotApplicable, result.CouponRejection)
+	}
+	order.BookId = order.Items[0].BookID
+	order.CouponCode = basket.CouponCode
+	order.Subtotal = result.Subtotal
+	order.DiscountTotal = result.Discount
+	order.Total = result.Total
+	order.Promotions = result.Applied
+	order.Redemption = nil
+	if coupon := result.AppliedCoupon(); coupon != nil {
+		order.Redemption = &domain.CouponRedemption{
+			CouponID: coupon.ID,
+			UserID:   order.UserId,
+			Amount:   coupon.Amount,
+		}
+	}
+	return nil
+}
+
+// priceEdition copies the book, price, class and weight of the ordered edition onto an item, items
+// are always ordered from an edition
+func (os *OrderService) priceEdition(ctx context.Context, item *domain.OrderItem) error {
+	if item.EditionID == nil {
+		return fmt.Errorf("%w: every item needs an edition", domain.ErrInvalidEdition)
+	}
+	edition, err := os.editionRepo.GetEdition(ctx, *item.EditionID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return fmt.Errorf("%w: edition %d does not exist", domain.ErrInvalidEdition, *item.EditionID)
+		}
+		return err
+	}
+	if edition.Stock != nil && *edition.Stock < item.Quantity {
+		return domain.ErrOutOfStock
+	}
+	if edition.Format.IsDigital() && edition.FileKey == "" {
+		return domain.ErrNoDigitalFile
+	}
+	item.BookID = edition.BookID
+	item.Format = edition.Format
+	item.UnitPrice = edition.Price
+	item.Class = edition.Format.Class()
+	item.WeightGrams = edition.WeightGrams
+	return nil
+}
+
+// checkoutOrder prices the order and, when it has a shipping address, adds the shipping and the
+// tax for that address
+func (os *OrderService) checkoutOrder(ctx context.Context, order *domain.Order) error {
+	if err := os.priceOrder(ctx, order); err != nil {
+		return err
+	}
+	if order.ShippingAddress == nil {
+		return nil
+	}
+	if err := os.shipOrder(ctx, order); err != nil {
+		return err
+	}
+	if err := os.taxOrder(ctx, order); err != nil {
+		return err
+	}
+
+	order.Total = order.Subtotal - order.DiscountTotal + order.ShippingCost
+	// Inclusive prices already contain the tax
+	if order.TaxMode == domain.TaxExclusive {
+		order.Total += order.TaxTotal
+	}
+	order.Total = domain.RoundPrice(order.Total)
+	return nil
+}
+
+// shipment describes the printed books of a priced order, e-books are not shipped
+func shipment(order *domain.Order) (domain.Shipment, bool) {
+	shipment := domain.Shipment{
+		Country:  order.ShippingAddress.Country,
+		Subtotal: domain.RoundPrice(order.Subtotal - order.DiscountTotal),
+	}
+	physical := false
+	for _, item := range order.Items {
+		if item.Class == domain.ProductClassEbook {
+			continue
+		}
+		physical = true
+		shipment.WeightGrams += item.Quantity * item.WeightGrams
+	}
+	return shipment, physical
+}
+
+// shipOrder applies the chosen shipping method to the order, or the cheapest available one when
+// none was chosen
+func (os *OrderService) shipOrder(ctx context.Context, order *domain.Order) error {
+	chosen := order.ShippingMethodID
+	order.ShippingMethodID = nil
+	order.ShippingMethod = ""
+	order.ShippingCost = 0
+
+	shipment, physical := shipment(order)
+	if !physical {
+		return nil
+	}
+	quotes, err := os.shipping.QuoteMethods(ctx, shipment)
+	if err != nil {
+		return err
+	}
+	for _, quote := range quotes {
+		if chosen != nil && *chosen != quote.MethodID {
+			continue
+		}
+		order.ShippingMethodID = &quote.MethodID
+		order.ShippingMethod = quote.Name
+		order.ShippingCost = quote.Cost
+		return nil
+	}
+	return domain.ErrNoShippingMethod
+}
+
+// QuoteShipping prices an order and quotes every shipping method available for it and the
+// shipping address, cheapest first
+func (os *OrderService) QuoteShipping(ctx context.Context, order *domain.Order, shippingAddressID int64) ([]domain.ShippingQuote, error) {
+	address, err := os.resolveAddress(ctx, order.UserId, shippingAddressID, true)
+	if err != nil {
+		if err == domain.ErrDataNotFound && shippingAddressID == 0 {
+			return nil, domain.ErrNoShippingAddress
+		}
+		return nil, err
+	}
+	order.ShippingAddress = address.Snapshot()
+	if err := os.priceOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	shipment, physical := shipment(order)
+	if !physical {
+		return []domain.ShippingQuote{}, nil
+	}
+	return os.shipping.QuoteMethods(ctx, shipment)
+}
+
+// taxOrder computes the tax lines of a priced order. The discount is spread over the items in
+// proportion to their price before they are taxed.
+func (os *OrderService) taxOrder(ctx context.Context, order *domain.Order) error {
+	request := &domain.TaxRequest{
+		Country: order.ShippingAddress.Country,
+		Region:  order.ShippingAddress.Region,
+		Mode:    os.taxMode,
+	}
+	remaining := order.DiscountTotal
+	for i, item := range order.Items {
+		amount := float64(item.Quantity) * item.UnitPrice
+		discount := remaining
+		if i < len(order.Items)-1 && order.Subtotal > 0 {
+			discount = domain.RoundPrice(order.DiscountTotal * amount / order.Subtotal)
+		}
+		remaining -= discount
+		request.Lines = append(request.Lines, domain.TaxableLine{
+			Item:   i,
+			BookID: item.BookID,
+			Class:  item.Class,
+			Amount: domain.RoundPrice(amount - discount),
+		})
+	}
+
+	result, err := os.taxes.Calculate(ctx, request)
+	if err != nil {
+		return err
+	}
+	order.TaxMode = os.taxMode
+	order.TaxLines = result.Lines
+	if order.TaxLines == nil {
+		order.TaxLines = []domain.TaxLine{}
+	}
+	order.TaxTotal = result.Total
+	return nil
+}
+
+// QuoteOrder prices an order, applies its promotions, ships and taxes it without placing it.
+// Without a shipping address the quote has no shipping and tax.
+func (os *OrderService) QuoteOrder(ctx context.Context, order *domain.Order, shippingAddressID int64) (*domain.Order, error) {
+	shipping, err := os.resolveAddress(ctx, order.UserId, shippingAddressID, true)
+	if err != nil {
+		if err != domain.ErrDataNotFound || shippingAddressID != 0 {
+			return nil, err
+		}
+	} else {
+		order.ShippingAddress = shipping.Snapshot()
+	}
+
+	if err := os.checkoutOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// CreateOrder places an order with snapshots of the shipping and billing addresses. The coupon
+// redemption is stored in the same transaction as the order.
+func (os *OrderService) CreateOrder(ctx context.Context, order *domain.Order, shippingAddressID, billingAddressID int64) (*domain.Order, error) {
+	shipping, err := os.resolveAddress(ctx, order.UserId, shippingAddressID, true)
+	if err != nil {
+		if err == domain.ErrDataNotFound && shippingAddressID == 0 {
+			return nil, domain.ErrNoShippingAddress
+		}
+		return nil, err
+	}
+	// Billing falls back to the default billing address and then to the shipping address
+	billing, err := os.resolveAddress(ctx, order.UserId, billingAddressID, false)
+	if err != nil {
+		if err != domain.ErrDataNotFound || billingAddressID != 0 {
+			return nil, err
+		}
+		billing = shipping
+	}
+	order.ShippingAddress = shipping.Snapshot()
+	order.BillingAddress = billing.Snapshot()
+
+	if err := os.checkoutOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	order, err = os.repo.CreateOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	os.audit.Record(ctx, domain.AuditOrderCreate, domain.AuditEntityOrder, order.ID, nil, order)
+	return order, nil
+}
+
+func (os *OrderService) resolveAddress(ctx context.Context, userID, id int64, shipping bool) (*domain.Address, error) {
+	if id != 0 {
+		return os.addressRepo.GetAddressById(ctx, userID, id)
+	}
+	return os.addressRepo.GetDefaultAddress(ctx, userID, shipping)
 }
 
 func (os *OrderService) GetOrder(ctx context.Context, id int64) (*domain.Order, error) {
-	return nil, nil
+	return os.repo.GetOrderById(ctx, id)
+}
+
+func (os *OrderService) OrderLists(ctx context.Context, skip, limit int64) ([]domain.Order, error) {
+	return os.repo.OrderLists(ctx, skip, limit)
 }
-func (os *OrderService) ListsOrder(ctx context.Context, skip, limit int64) ([]domain.Order, error) {
-	return nil, nil
+
+// ListUserOrders returns the orders of a user with pagination
+func (os *OrderService) ListUserOrders(ctx context.Context, userID, skip, limit int64) ([]domain.Order, error) {
+	return os.repo.ListUserOrders(ctx, userID, skip, limit)
+}
+
+// MarkOrderPaid records the payment of an order, issues its invoice and grants its downloads. An
+// invoice or grants that fail are created again when they are first asked for.
+func (os *OrderService) MarkOrderPaid(ctx context.Context, id int64) (*domain.Order, error) {
+	if err := os.repo.MarkOrderPaid(ctx, id, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	order, err := os.repo.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	os.audit.Record(ctx, domain.AuditOrderPay, domain.AuditEntityOrder, id, nil, map[string]any{"paid_at": order.PaidAt})
+
+	if _, err := os.invoices.IssueInvoice(ctx, order); err != nil {
+		slog.Error("failed to issue invoice", "order_id", id, "error", err)
+	}
+	if _, err := os.downloads.GrantOrderDownloads(ctx, order); err != nil {
+		slog.Error("failed to grant downloads", "order_id", id, "error", err)
+	}
+	return order, nil
 }
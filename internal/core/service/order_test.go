@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// noPromotions is a port.PromotionService evaluating baskets without any promotion or coupon
+type noPromotions struct {
+	port.PromotionService
+}
+
+func (noPromotions) Evaluate(ctx context.Context, basket *domain.Basket) (*domain.PromotionResult, error) {
+	return PromotionEngine{}.Evaluate(basket, nil, nil, domain.CouponUsage{}, time.Now()), nil
+}
+
+func TestPriceOrder(t *testing.T) {
+	fiction := int64(3)
+	stock, soldOut := 5, 0
+	books := &memoryBooks{books: map[int64]*domain.Book{1: {ID: 1, Name: "Dune", CategoryID: &fiction}}}
+	editions := &memoryEditions{editions: map[int64]*domain.Edition{
+		10: {ID: 10, BookID: 1, Format: domain.FormatHardcover, Price: 25, Stock: &stock, WeightGrams: 700},
+		11: {ID: 11, BookID: 1, Format: domain.FormatEbook, Price: 9.5, FileKey: "books/1/editions/11"},
+		12: {ID: 12, BookID: 1, Format: domain.FormatPaperback, Price: 12, Stock: &soldOut, WeightGrams: 300},
+		13: {ID: 13, BookID: 1, Format: domain.FormatAudiobook, Price: 20},
+		14: {ID: 14, BookID: 2, Format: domain.FormatEbook, Price: 5, FileKey: "books/2/editions/14"},
+	}}
+	edition := func(id int64) *int64 { return &id }
+
+	tests := []struct {
+		name      string
+		items     []domain.OrderItem
+		wantErr   error
+		wantItems []domain.OrderItem
+		wantTotal float64
+	}{
+		{
+			name:    "no items",
+			wantErr: domain.ErrEmptyOrder,
+		},
+		{
+			name:    "item without an edition",
+			items:   []domain.OrderItem{{BookID: 1, Quantity: 1}},
+			wantErr: domain.ErrInvalidEdition,
+		},
+		{
+			name:    "unknown edition",
+			items:   []domain.OrderItem{{EditionID: edition(99), Quantity: 1}},
+			wantErr: domain.ErrInvalidEdition,
+		},
+		{
+			name: "items are priced from their edition",
+			items: []domain.OrderItem{
+				{EditionID: edition(10), Quantity: 2},
+				{EditionID: edition(11), Quantity: 1},
+			},
+			wantItems: []domain.OrderItem{
+				{BookID: 1, EditionID: edition(10), Format: domain.FormatHardcover, CategoryID: &fiction, Class: domain.ProductClassPrinted, WeightGrams: 700, Quantity: 2, UnitPrice: 25},
+				{BookID: 1, EditionID: edition(11), Format: domain.FormatEbook, CategoryID: &fiction, Class: domain.ProductClassEbook, Quantity: 1, UnitPrice: 9.5},
+			},
+			wantTotal: 59.5,
+		},
+		{
+			name:    "more than the stock",
+			items:   []domain.OrderItem{{EditionID: edition(10), Quantity: 6}},
+			wantErr: domain.ErrOutOfStock,
+		},
+		{
+			name:    "sold out",
+			items:   []domain.OrderItem{{EditionID: edition(12), Quantity: 1}},
+			wantErr: domain.ErrOutOfStock,
+		},
+		{
+			name:    "digital edition without a file",
+			items:   []domain.OrderItem{{EditionID: edition(13), Quantity: 1}},
+			wantErr: domain.ErrNoDigitalFile,
+		},
+		{
+			name:    "edition of a deleted book",
+			items:   []domain.OrderItem{{EditionID: edition(14), Quantity: 1}},
+			wantErr: domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os := NewOrderService(nil, books, editions, nil, noPromotions{}, nil, nil, nil, nil, nopAudit{}, domain.TaxExclusive)
+			order := &domain.Order{UserId: 1, Items: tt.items}
+
+			err := os.priceOrder(context.Background(), order)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("priceOrder() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if order.Total != tt.wantTotal {
+				t.Errorf("total = %.2f, want %.2f", order.Total, tt.wantTotal)
+			}
+			for i, want := range tt.wantItems {
+				got := order.Items[i]
+				if got.BookID != want.BookID || *got.EditionID != *want.EditionID || got.Format != want.Format ||
+					*got.CategoryID != *want.CategoryID || got.Class != want.Class || got.WeightGrams != want.WeightGrams ||
+					got.UnitPrice != want.UnitPrice {
+					t.Errorf("item %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
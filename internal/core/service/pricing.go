@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// LowestPriceWindow is how far back the lowest price of an edition is looked up, price reduction
+// rules require showing the lowest price of the last 30 days
+var LowestPriceWindow = 30 * 24 * time.Hour
+
+type PricingService struct {
+	repo        port.PriceRepository
+	editionRepo port.EditionRepository
+	audit       port.AuditService
+}
+
+func NewPricingService(repo port.PriceRepository, editionRepo port.EditionRepository, audit port.AuditService) *PricingService {
+	return &PricingService{
+		repo:        repo,
+		editionRepo: editionRepo,
+		audit:       audit,
+	}
+}
+
+// GetPriceTimeline returns the price history of an edition, its lowest price within
+// LowestPriceWindow and the prices scheduled but not yet completed
+func (ps *PricingService) GetPriceTimeline(ctx context.Context, bookID, editionID int64) (*domain.PriceTimeline, error) {
+	edition, err := getBookEdition(ctx, ps.editionRepo, bookID, editionID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := ps.repo.ListPriceHistory(ctx, editionID)
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().Add(-LowestPriceWindow)
+	lowest, err := ps.repo.GetLowestPrice(ctx, editionID, since)
+	if err != nil {
+		if err != domain.ErrDataNotFound {
+			return nil, err
+		}
+		lowest = edition.Price
+	}
+	schedules, err := ps.repo.ListPriceSchedules(ctx, editionID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PriceTimeline{
+		BookID:       bookID,
+		EditionID:    editionID,
+		CurrentPrice: edition.Price,
+		LowestPrice:  lowest,
+		LowestSince:  since,
+		History:      history,
+		Schedules:    schedules,
+	}, nil
+}
+
+// SchedulePrice validates and stores a future price of an edition
+func (ps *PricingService) SchedulePrice(ctx context.Context, schedule *domain.PriceSchedule) (*domain.PriceSchedule, error) {
+	if err := schedule.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := getBookEdition(ctx, ps.editionRepo, schedule.BookID, schedule.EditionID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := ps.repo.CreatePriceSchedule(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	ps.audit.Record(ctx, domain.AuditBookPriceSchedule, domain.AuditEntityBook, schedule.BookID, nil, schedule)
+	return schedule, nil
+}
+
+// ListPriceSchedules returns every scheduled price of an edition, including past ones
+func (ps *PricingService) ListPriceSchedules(ctx context.Context, bookID, editionID int64) ([]domain.PriceSchedule, error) {
+	if _, err := getBookEdition(ctx, ps.editionRepo, bookID, editionID); err != nil {
+		return nil, err
+	}
+	return ps.repo.ListPriceSchedules(ctx, editionID, false)
+}
+
+// CancelPriceSchedule cancels a pending schedule. An active schedule is ended instead so the
+// scheduler restores the previous price on its next run.
+func (ps *PricingService) CancelPriceSchedule(ctx context.Context, bookID, editionID, id int64) error {
+	schedule, err := ps.repo.GetPriceSchedule(ctx, editionID, id)
+	if err != nil {
+		return err
+	}
+	if schedule.BookID != bookID {
+		return domain.ErrDataNotFound
+	}
+
+	switch schedule.Status {
+	case domain.PriceSchedulePending:
+		err = ps.repo.CancelPriceSchedule(ctx, id)
+	case domain.PriceScheduleActive:
+		err = ps.repo.EndPriceSchedule(ctx, id, time.Now())
+	default:
+		return domain.ErrDataNotFound
+	}
+	if err != nil {
+		return err
+	}
+	ps.audit.Record(ctx, domain.AuditBookPriceScheduleCancel, domain.AuditEntityBook, bookID, schedule, nil)
+	return nil
+}
+
+// ApplyDuePriceSchedules applies the schedules whose start has passed and reverts those whose end
+// has passed. A failing schedule is logged and retried on the next run.
+func (ps *PricingService) ApplyDuePriceSchedules(ctx context.Context) error {
+	schedules, err := ps.repo.ListDuePriceSchedules(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		// Scheduled changes are attributed to the staff member who scheduled them
+		ctx := domain.WithAuditMeta(ctx, domain.AuditMeta{ActorID: schedule.CreatedBy})
+
+		var applied *domain.PriceSchedule
+		var err error
+		action := domain.AuditBookPriceApply
+		if schedule.Status == domain.PriceScheduleActive {
+			action = domain.AuditBookPriceRevert
+			applied, err = ps.repo.RevertPriceSchedule(ctx, schedule.ID)
+		} else {
+			applied, err = ps.repo.ApplyPriceSchedule(ctx, schedule.ID)
+		}
+		if err != nil {
+			slog.Error("failed to run price schedule", "schedule_id", schedule.ID, "edition_id", schedule.EditionID, "error", err)
+			continue
+		}
+		ps.audit.Record(ctx, action, domain.AuditEntityBook, schedule.BookID, &schedule, applied)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// memoryPrices is an in-memory port.PriceRepository running schedules the way the database does
+type memoryPrices struct {
+	port.PriceRepository
+	schedules map[int64]*domain.PriceSchedule
+	prices    map[int64]float64
+	deleted   map[int64]bool
+}
+
+func (m *memoryPrices) ListDuePriceSchedules(ctx context.Context, now time.Time) ([]domain.PriceSchedule, error) {
+	var due []domain.PriceSchedule
+	for id := int64(1); id <= int64(len(m.schedules)); id++ {
+		schedule := m.schedules[id]
+		if schedule.Status == domain.PriceSchedulePending && !schedule.StartsAt.After(now) ||
+			schedule.Status == domain.PriceScheduleActive && !schedule.EndsAt.After(now) {
+			due = append(due, *schedule)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryPrices) ApplyPriceSchedule(ctx context.Context, id int64) (*domain.PriceSchedule, error) {
+	return m.run(id, domain.PriceSchedulePending)
+}
+
+func (m *memoryPrices) RevertPriceSchedule(ctx context.Context, id int64) (*domain.PriceSchedule, error) {
+	return m.run(id, domain.PriceScheduleActive)
+}
+
+func (m *memoryPrices) run(id int64, status domain.PriceScheduleStatus) (*domain.PriceSchedule, error) {
+	schedule, ok := m.schedules[id]
+	if !ok || schedule.Status != status {
+		return nil, domain.ErrDataNotFound
+	}
+	deleted := m.deleted[schedule.BookID]
+	run := schedule.Run(m.prices[schedule.EditionID], deleted)
+	if run.Price != nil {
+		m.prices[schedule.EditionID] = *run.Price
+	}
+	schedule.PreviousPrice, schedule.Status = run.PreviousPrice, run.Status
+	if deleted {
+		return nil, domain.ErrDataNotFound
+	}
+	copied := *schedule
+	return &copied, nil
+}
+
+func TestApplyDuePriceSchedules(t *testing.T) {
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	ten := 10.0
+	prices := &memoryPrices{
+		schedules: map[int64]*domain.PriceSchedule{
+			1: {ID: 1, BookID: 1, EditionID: 1, Price: 8, StartsAt: past, EndsAt: &future, Status: domain.PriceSchedulePending, CreatedBy: 7},
+			2: {ID: 2, BookID: 2, EditionID: 2, Price: 8, PreviousPrice: &ten, StartsAt: past, EndsAt: &past, Status: domain.PriceScheduleActive, CreatedBy: 7},
+			3: {ID: 3, BookID: 3, EditionID: 3, Price: 8, PreviousPrice: &ten, StartsAt: past, EndsAt: &past, Status: domain.PriceScheduleActive, CreatedBy: 7},
+			4: {ID: 4, BookID: 4, EditionID: 4, Price: 8, StartsAt: past, Status: domain.PriceSchedulePending, CreatedBy: 7},
+			5: {ID: 5, BookID: 5, EditionID: 5, Price: 8, StartsAt: future, Status: domain.PriceSchedulePending, CreatedBy: 7},
+		},
+		// Staff changed the price of edition 3 while its schedule was active
+		prices:  map[int64]float64{1: 10, 2: 8, 3: 9, 4: 10, 5: 10},
+		deleted: map[int64]bool{4: true},
+	}
+	audit := &recordingAudit{}
+	ps := NewPricingService(prices, &memoryEditions{}, audit)
+
+	if err := ps.ApplyDuePriceSchedules(context.Background()); err != nil {
+		t.Fatalf("ApplyDuePriceSchedules() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		id         int64
+		wantPrice  float64
+		wantStatus domain.PriceScheduleStatus
+	}{
+		{"pending schedule is applied", 1, 8, domain.PriceScheduleActive},
+		{"ended schedule is reverted", 2, 10, domain.PriceScheduleCompleted},
+		{"price changed by staff is kept", 3, 9, domain.PriceScheduleCompleted},
+		{"schedule of a deleted book is cancelled", 4, 10, domain.PriceScheduleCancelled},
+		{"future schedule is left alone", 5, 10, domain.PriceSchedulePending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := prices.schedules[tt.id]
+			if price := prices.prices[schedule.EditionID]; price != tt.wantPrice {
+				t.Errorf("price = %v, want %v", price, tt.wantPrice)
+			}
+			if schedule.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", schedule.Status, tt.wantStatus)
+			}
+		})
+	}
+
+	// The cancelled schedule is not audited and does not stop the others
+	wantActions := []struct {
+		action string
+		bookID int64
+	}{
+		{domain.AuditBookPriceApply, 1},
+		{domain.AuditBookPriceRevert, 2},
+		{domain.AuditBookPriceRevert, 3},
+	}
+	if len(audit.entries) != len(wantActions) {
+		t.Fatalf("audit entries = %+v, want %d", audit.entries, len(wantActions))
+	}
+	for i, want := range wantActions {
+		entry := audit.entries[i]
+		if entry.action != want.action || entry.entityID != want.bookID || entry.actorID != 7 {
+			t.Errorf("audit entry %d = %+v, want %s of book %d by 7", i, entry, want.action, want.bookID)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// exportPageSize is the number of orders read per query when exporting a user's data
+const exportPageSize = 100
+
+type PrivacyService struct {
+	repo        port.ErasureRepository
+	userRepo    port.UserRepository
+	addressRepo port.AddressRepository
+	orderRepo   port.OrderRepository
+	mailService port.MailService
+	audit       port.AuditService
+	gracePeriod time.Duration
+}
+
+func NewPrivacyService(repo port.ErasureRepository, userRepo port.UserRepository, addressRepo port.AddressRepository, orderRepo port.OrderRepository, mailService port.MailService, audit port.AuditService, gracePeriod time.Duration) *PrivacyService {
+	return &PrivacyService{
+		repo:        repo,
+		userRepo:    userRepo,
+		addressRepo: addressRepo,
+		orderRepo:   orderRepo,
+		mailService: mailService,
+		audit:       audit,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// ExportUserData collects the profile, address book and orders of a user
+func (ps *PrivacyService) ExportUserData(ctx context.Context, userID int64) (*domain.DataExport, error) {
+	user, err := ps.userRepo.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := ps.addressRepo.ListAddresses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []domain.Order
+	for skip := int64(0); ; skip += exportPageSize {
+		page, err := ps.orderRepo.ListUserOrders(ctx, userID, skip, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, page...)
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	return &domain.DataExport{
+		User:        user,
+		Addresses:   addresses,
+		Orders:      orders,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// RequestErasure schedules the erasure of a user's data, the user can cancel it during the grace period
+func (ps *PrivacyService) RequestErasure(ctx context.Context, userID, requestedBy int64) (*domain.ErasureRequest, error) {
+	user, err := ps.userRepo.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = ps.repo.GetPendingErasureRequest(ctx, userID)
+	if err == nil {
+		return nil, domain.ErrErasurePending
+	}
+	if err != domain.ErrDataNotFound {
+		return nil, err
+	}
+
+	now := time.Now()
+	request, err := ps.repo.CreateErasureRequest(ctx, &domain.ErasureRequest{
+		UserID:       userID,
+		RequestedBy:  requestedBy,
+		RequestedAt:  now,
+		ScheduledFor: now.Add(ps.gracePeriod),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ps.audit.Record(ctx, domain.AuditUserErasureRequest, domain.AuditEntityUser, userID, nil, request)
+
+	mail := &domain.Mail{
+		To:      user.Email,
+		Subject: "Your account is scheduled for erasure",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nYour personal data will be erased on %s. Until then you can cancel the erasure from your account settings.",
+			user.Name, request.ScheduledFor.Format(time.RFC1123),
+		),
+	}
+	if err := ps.mailService.Send(ctx, mail); err != nil {
+		slog.Warn("failed to send erasure notice", "user_id", user.ID, "error", err)
+	}
+	return request, nil
+}
+
+// GetErasureRequest returns the pending erasure request of a user
+func (ps *PrivacyService) GetErasureRequest(ctx context.Context, userID int64) (*domain.ErasureRequest, error) {
+	return ps.repo.GetPendingErasureRequest(ctx, userID)
+}
+
+// CancelErasure cancels the pending erasure request of a user
+func (ps *PrivacyService) CancelErasure(ctx context.Context, userID int64) error {
+	request, err := ps.repo.GetPendingErasureRequest(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := ps.repo.CancelErasureRequest(ctx, request.ID); err != nil {
+		return err
+	}
+	ps.audit.Record(ctx, domain.AuditUserErasureCancel, domain.AuditEntityUser, userID, request, nil)
+	return nil
+}
+
+// ProcessDueErasures erases the users whose grace period has passed, a failing erasure
+// is logged and retried on the next run
+func (ps *PrivacyService) ProcessDueErasures(ctx context.Context) error {
+	requests, err := ps.repo.ListDueErasureRequests(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, request := range requests {
+		if err := ps.repo.EraseUser(ctx, &request); err != nil {
+			slog.Error("failed to erase user", "user_id", request.UserID, "request_id", request.ID, "error", err)
+			continue
+		}
+		slog.Info("erased user", "user_id", request.UserID, "request_id", request.ID, "requested_by", request.RequestedBy)
+		// The erasure is attributed to whoever asked for it
+		ctx := domain.WithAuditMeta(ctx, domain.AuditMeta{ActorID: request.RequestedBy})
+		ps.audit.Record(ctx, domain.AuditUserErase, domain.AuditEntityUser, request.UserID, nil, nil)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// memoryAddresses is an in-memory port.AddressRepository for the methods the tests use
+type memoryAddresses struct {
+	port.AddressRepository
+	addresses []domain.Address
+}
+
+func (m *memoryAddresses) ListAddresses(ctx context.Context, userID int64) ([]domain.Address, error) {
+	var addresses []domain.Address
+	for _, address := range m.addresses {
+		if address.UserID == userID {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+func (m *memoryOrders) ListUserOrders(ctx context.Context, userID, skip, limit int64) ([]domain.Order, error) {
+	var orders []domain.Order
+	for _, order := range m.orders {
+		if order.UserId == userID {
+			orders = append(orders, *order)
+		}
+	}
+	slices.SortFunc(orders, func(a, b domain.Order) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	if skip >= int64(len(orders)) {
+		return nil, nil
+	}
+	return orders[skip:min(skip+limit, int64(len(orders)))], nil
+}
+
+// memoryErasures is an in-memory port.ErasureRepository erasing the users and addresses it
+// was built with like the database does
+type memoryErasures struct {
+	users     *memoryUsers
+	addresses *memoryAddresses
+	requests  []*domain.ErasureRequest
+}
+
+func (m *memoryErasures) CreateErasureRequest(ctx context.Context, request *domain.ErasureRequest) (*domain.ErasureRequest, error) {
+	created := *request
+	created.ID = int64(len(m.requests) + 1)
+	m.requests = append(m.requests, &created)
+	return &created, nil
+}
+
+func (m *memoryErasures) GetPendingErasureRequest(ctx context.Context, userID int64) (*domain.ErasureRequest, error) {
+	for _, request := range m.requests {
+		if request.UserID == userID && request.IsPending() {
+			copied := *request
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *memoryErasures) CancelErasureRequest(ctx context.Context, id int64) error {
+	for _, request := range m.requests {
+		if request.ID == id && request.IsPending() {
+			now := time.Now()
+			request.CancelledAt = &now
+			return nil
+		}
+	}
+	return domain.ErrDataNotFound
+}
+
+func (m *memoryErasures) ListDueErasureRequests(ctx context.Context, before time.Time) ([]domain.ErasureRequest, error) {
+	var due []domain.ErasureRequest
+	for _, request := range m.requests {
+		if request.IsPending() && !request.ScheduledFor.After(before) {
+			due = append(due, *request)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryErasures) EraseUser(ctx context.Context, request *domain.ErasureRequest) error {
+	for _, user := range m.users.users {
+		if user.ID == request.UserID {
+			user.Name, user.Email, user.Password = "Erased user", "erased@erased.invalid", "!"
+			user.TokenVersion++
+		}
+	}
+	m.addresses.addresses = slices.DeleteFunc(m.addresses.addresses, func(address domain.Address) bool {
+		return address.UserID == request.UserID
+	})
+	for _, stored := range m.requests {
+		if stored.ID == request.ID {
+			now := time.Now()
+			stored.CompletedAt = &now
+		}
+	}
+	return nil
+}
+
+func TestProcessDueErasures(t *testing.T) {
+	tests := []struct {
+		name        string
+		gracePeriod time.Duration
+		cancel      bool
+		wantErased  bool
+	}{
+		{"due erasure", 0, false, true},
+		{"erasure in its grace period", time.Hour, false, false},
+		{"cancelled erasure", 0, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &memoryUsers{users: []*domain.User{
+				{ID: 1, Name: "Paul", Email: "paul@example.com", Password: "hash"},
+				{ID: 2, Name: "Jessica", Email: "jessica@example.com", Password: "hash"},
+			}}
+			addresses := &memoryAddresses{addresses: []domain.Address{
+				{ID: 1, UserID: 1, FullName: "Paul Atreides", Line1: "1 Arrakeen Way"},
+				{ID: 2, UserID: 2, FullName: "Jessica Atreides", Line1: "1 Arrakeen Way"},
+			}}
+			erasures := &memoryErasures{users: users, addresses: addresses}
+			audit := &recordingAudit{}
+			ps := NewPrivacyService(erasures, users, addresses, &memoryOrders{}, make(mailbox, 1), audit, tt.gracePeriod)
+			ctx := context.Background()
+
+			if _, err := ps.RequestErasure(ctx, 1, 3); err != nil {
+				t.Fatalf("RequestErasure() error = %v", err)
+			}
+			if tt.cancel {
+				if err := ps.CancelErasure(ctx, 1); err != nil {
+					t.Fatalf("CancelErasure() error = %v", err)
+				}
+			}
+			if err := ps.ProcessDueErasures(ctx); err != nil {
+				t.Fatalf("ProcessDueErasures() error = %v", err)
+			}
+
+			user := users.users[0]
+			erased := user.Name == "Erased user" && user.Email != "paul@example.com" && user.Password == "!" && user.TokenVersion == 1
+			if erased != tt.wantErased {
+				t.Errorf("user = %+v, erased = %t, want %t", user, erased, tt.wantErased)
+			}
+			left, _ := addresses.ListAddresses(ctx, 1)
+			if (len(left) == 0) != tt.wantErased {
+				t.Errorf("%d addresses left, erased = %t", len(left), tt.wantErased)
+			}
+			if other := users.users[1]; other.Email != "jessica@example.com" || len(addresses.addresses) == 0 {
+				t.Error("the data of another user was erased")
+			}
+
+			var erasedEntry *recordedAudit
+			for i, entry := range audit.entries {
+				if entry.action == domain.AuditUserErase {
+					erasedEntry = &audit.entries[i]
+				}
+			}
+			if (erasedEntry != nil) != tt.wantErased {
+				t.Fatalf("erasure audited = %t, want %t", erasedEntry != nil, tt.wantErased)
+			}
+			if erasedEntry != nil && erasedEntry.actorID != 3 {
+				t.Errorf("erasure attributed to %d, want the requester 3", erasedEntry.actorID)
+			}
+
+			// A processed or cancelled erasure is not carried out again
+			before := *user
+			if err := ps.ProcessDueErasures(ctx); err != nil {
+				t.Fatalf("ProcessDueErasures() error = %v", err)
+			}
+			if *users.users[0] != before {
+				t.Error("a second run changed the user again")
+			}
+		})
+	}
+}
+
+func TestRequestErasureTwice(t *testing.T) {
+	users := &memoryUsers{users: []*domain.User{{ID: 1, Email: "paul@example.com"}}}
+	addresses := &memoryAddresses{}
+	ps := NewPrivacyService(&memoryErasures{users: users, addresses: addresses}, users, addresses, &memoryOrders{}, make(mailbox, 2), nopAudit{}, time.Hour)
+
+	if _, err := ps.RequestErasure(context.Background(), 1, 1); err != nil {
+		t.Fatalf("RequestErasure() error = %v", err)
+	}
+	if _, err := ps.RequestErasure(context.Background(), 1, 1); err != domain.ErrErasurePending {
+		t.Errorf("second RequestErasure() error = %v, want %v", err, domain.ErrErasurePending)
+	}
+}
+
+func TestExportUserDataReadsEveryOrder(t *testing.T) {
+	users := &memoryUsers{users: []*domain.User{{ID: 1, Email: "paul@example.com"}, {ID: 2, Email: "jessica@example.com"}}}
+	addresses := &memoryAddresses{addresses: []domain.Address{{ID: 1, UserID: 1}, {ID: 2, UserID: 2}}}
+	orders := &memoryOrders{orders: map[int64]*domain.Order{}}
+	for id := int64(1); id <= 2*exportPageSize+10; id++ {
+		orders.orders[id] = &domain.Order{ID: id, UserId: 1}
+	}
+	orders.orders[1000] = &domain.Order{ID: 1000, UserId: 2}
+	ps := NewPrivacyService(&memoryErasures{}, users, addresses, orders, nil, nopAudit{}, time.Hour)
+
+	export, err := ps.ExportUserData(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("ExportUserData() error = %v", err)
+	}
+	if export.User.ID != 1 || len(export.Addresses) != 1 || export.Addresses[0].ID != 1 {
+		t.Errorf("export = %+v, want the profile and address of user 1", export)
+	}
+	if len(export.Orders) != 2*exportPageSize+10 {
+		t.Errorf("%d orders exported, want %d", len(export.Orders), 2*exportPageSize+10)
+	}
+	for i, order := range export.Orders {
+		if order.ID != int64(i+1) {
+			t.Fatalf("order %d is %d, want every order once in order", i, order.ID)
+		}
+	}
+
+	if _, err := ps.ExportUserData(context.Background(), 9); err != domain.ErrDataNotFound {
+		t.Errorf("ExportUserData() of an unknown user error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
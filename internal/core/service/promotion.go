@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type PromotionService struct {
+	repo   port.PromotionRepository
+	engine PromotionEngine
+	audit  port.AuditService
+}
+
+func NewPromotionService(repo port.PromotionRepository, audit port.AuditService) *PromotionService {
+	return &PromotionService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+// CreateCoupon validates and creates a coupon, codes are stored upper case and a coupon without a
+// start is valid right away
+func (ps *PromotionService) CreateCoupon(ctx context.Context, coupon *domain.Coupon) (*domain.Coupon, error) {
+	coupon.Code = domain.NormalizeCouponCode(coupon.Code)
+	if coupon.StartsAt.IsZero() {
+		coupon.StartsAt = time.Now()
+	}
+	if err := coupon.Validate(); err != nil {
+		return nil, err
+	}
+	coupon, err := ps.repo.CreateCoupon(ctx, coupon)
+	if err != nil {
+		return nil, err
+	}
+	ps.audit.Record(ctx, domain.AuditCouponCreate, domain.AuditEntityCoupon, coupon.ID, nil, coupon)
+	return coupon, nil
+}
+
+// ListCoupons returns coupons with pagination
+func (ps *PromotionService) ListCoupons(ctx context.Context, skip, limit int64) ([]domain.Coupon, error) {
+	return ps.repo.ListCoupons(ctx, skip, limit)
+}
+
+// DeactivateCoupon stops a coupon from being redeemed, past redemptions are kept
+func (ps *PromotionService) DeactivateCoupon(ctx context.Context, id int64) error {
+	coupon, err := ps.repo.DeactivateCoupon(ctx, id)
+	if err != nil {
+		return err
+	}
+	ps.audit.Record(ctx, domain.AuditCouponDeactivate, domain.AuditEntityCoupon, id, map[string]bool{"active": true}, map[string]bool{"active": coupon.Active})
+	return nil
+}
+
+// CreatePromotion validates and creates an automatic promotion
+func (ps *PromotionService) CreatePromotion(ctx context.Context, promotion *domain.AutomaticPromotion) (*domain.AutomaticPromotion, error) {
+	if promotion.StartsAt.IsZero() {
+		promotion.StartsAt = time.Now()
+	}
+	if err := promotion.Validate(); err != nil {
+		return nil, err
+	}
+	promotion, err := ps.repo.CreatePromotion(ctx, promotion)
+	if err != nil {
+		return nil, err
+	}
+	ps.audit.Record(ctx, domain.AuditPromotionCreate, domain.AuditEntityPromotion, promotion.ID, nil, promotion)
+	return promotion, nil
+}
+
+// ListPromotions returns automatic promotions with pagination
+func (ps *PromotionService) ListPromotions(ctx context.Context, skip, limit int64) ([]domain.AutomaticPromotion, error) {
+	return ps.repo.ListPromotions(ctx, skip, limit)
+}
+
+// DeactivatePromotion stops an automatic promotion
+func (ps *PromotionService) DeactivatePromotion(ctx context.Context, id int64) error {
+	promotion, err := ps.repo.DeactivatePromotion(ctx, id)
+	if err != nil {
+		return err
+	}
+	ps.audit.Record(ctx, domain.AuditPromotionDeactivate, domain.AuditEntityPromotion, id, map[string]bool{"active": true}, map[string]bool{"active": promotion.Active})
+	return nil
+}
+
+// Evaluate loads what the PromotionEngine needs for the basket and runs it
+func (ps *PromotionService) Evaluate(ctx context.Context, basket *domain.Basket) (*domain.PromotionResult, error) {
+	now := time.Now()
+	promotions, err := ps.repo.ListActivePromotions(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var coupon *domain.Coupon
+	var usage domain.CouponUsage
+	basket.CouponCode = domain.NormalizeCouponCode(basket.CouponCode)
+	if basket.CouponCode != "" {
+		coupon, err = ps.repo.GetCouponByCode(ctx, basket.CouponCode)
+		if err != nil && err != domain.ErrDataNotFound {
+			return nil, err
+		}
+		if coupon != nil {
+			if usage, err = ps.repo.GetCouponUsage(ctx, coupon.ID, basket.UserID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ps.engine.Evaluate(basket, promotions, coupon, usage, now), nil
+}
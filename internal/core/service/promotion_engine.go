@@ -0,0 +1,140 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+// PromotionEngine applies automatic promotions and a coupon to a basket. It is pure: it only
+// reads its arguments and changes nothing, so the same inputs always give the same result.
+type PromotionEngine struct{}
+
+// unit is a single book of a basket line
+type unit struct {
+	line  int
+	price float64
+}
+
+// Evaluate applies the automatic promotions running at now and then the coupon to what is left of
+// the subtotal. Every applied discount is explained in the result, a coupon that does not apply
+// is explained in CouponRejection.
+func (PromotionEngine) Evaluate(basket *domain.Basket, promotions []domain.AutomaticPromotion, coupon *domain.Coupon, usage domain.CouponUsage, now time.Time) *domain.PromotionResult {
+	result := &domain.PromotionResult{
+		Subtotal: basket.Subtotal(),
+		Applied:  []domain.AppliedPromotion{},
+	}
+
+	// Each book takes part in at most one automatic promotion, older promotions go first
+	remaining := make([]int, len(basket.Lines))
+	for i, line := range basket.Lines {
+		remaining[i] = line.Quantity
+	}
+	promotions = slices.Clone(promotions)
+	slices.SortFunc(promotions, func(a, b domain.AutomaticPromotion) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	for _, promotion := range promotions {
+		if !promotion.ActiveAt(now) {
+			continue
+		}
+		if applied, ok := applyAutomaticPromotion(basket, remaining, &promotion); ok {
+			result.Applied = append(result.Applied, applied)
+			result.Discount += applied.Amount
+		}
+	}
+
+	if basket.CouponCode != "" {
+		applied, rejection := applyCoupon(coupon, usage, result.Subtotal, result.Subtotal-result.Discount, now)
+		if rejection != "" {
+			result.CouponRejection = rejection
+		} else {
+			result.Applied = append(result.Applied, applied)
+			result.Discount += applied.Amount
+		}
+	}
+
+	result.Discount = domain.RoundPrice(result.Discount)
+	result.Total = domain.RoundPrice(result.Subtotal - result.Discount)
+	return result
+}
+
+// applyAutomaticPromotion makes the cheapest books of every full buy+free group of the category
+// free, the books of the groups are taken out of remaining
+func applyAutomaticPromotion(basket *domain.Basket, remaining []int, promotion *domain.AutomaticPromotion) (domain.AppliedPromotion, bool) {
+	var units []unit
+	for i, line := range basket.Lines {
+		if line.CategoryID == nil || *line.CategoryID != promotion.CategoryID {
+			continue
+		}
+		for range remaining[i] {
+			units = append(units, unit{line: i, price: line.UnitPrice})
+		}
+	}
+	groups := len(units) / (promotion.BuyQuantity + promotion.FreeQuantity)
+	if groups == 0 {
+		return domain.AppliedPromotion{}, false
+	}
+
+	slices.SortStableFunc(units, func(a, b unit) int {
+		return cmp.Compare(a.price, b.price)
+	})
+	free := groups * promotion.FreeQuantity
+	paid := groups * promotion.BuyQuantity
+	var amount float64
+	for _, u := range units[:free] {
+		amount += u.price
+		remaining[u.line]--
+	}
+	for _, u := range units[len(units)-paid:] {
+		remaining[u.line]--
+	}
+
+	return domain.AppliedPromotion{
+		Kind:   domain.PromotionAutomatic,
+		ID:     promotion.ID,
+		Name:   promotion.Name,
+		Amount: domain.RoundPrice(amount),
+		Explanation: fmt.Sprintf("buy %d get %d free in category %d: %d of %d eligible books free",
+			promotion.BuyQuantity, promotion.FreeQuantity, promotion.CategoryID, free, len(units)),
+	}, true
+}
+
+// applyCoupon discounts base with the coupon, or returns why the coupon cannot be used
+func applyCoupon(coupon *domain.Coupon, usage domain.CouponUsage, subtotal, base float64, now time.Time) (domain.AppliedPromotion, string) {
+	switch {
+	case coupon == nil:
+		return domain.AppliedPromotion{}, "unknown coupon code"
+	case !coupon.Active:
+		return domain.AppliedPromotion{}, "the coupon is no longer active"
+	case now.Before(coupon.StartsAt):
+		return domain.AppliedPromotion{}, fmt.Sprintf("the coupon is valid from %s", coupon.StartsAt.Format(time.RFC3339))
+	case coupon.EndsAt != nil && !now.Before(*coupon.EndsAt):
+		return domain.AppliedPromotion{}, "the coupon has expired"
+	case coupon.MaxUses > 0 && usage.Total >= coupon.MaxUses:
+		return domain.AppliedPromotion{}, "the coupon has reached its usage limit"
+	case coupon.MaxUsesPerUser > 0 && usage.ByUser >= coupon.MaxUsesPerUser:
+		return domain.AppliedPromotion{}, "you have already used this coupon the maximum number of times"
+	case subtotal < coupon.MinBasket:
+		return domain.AppliedPromotion{}, fmt.Sprintf("the coupon needs a basket of at least %.2f", coupon.MinBasket)
+	case base <= 0:
+		return domain.AppliedPromotion{}, "nothing left to discount"
+	}
+
+	applied := domain.AppliedPromotion{
+		Kind: domain.PromotionCoupon,
+		ID:   coupon.ID,
+		Name: coupon.Code,
+	}
+	if coupon.DiscountType == domain.DiscountPercent {
+		applied.Amount = domain.RoundPrice(base * coupon.Value / 100)
+		applied.Explanation = fmt.Sprintf("%g%% off %.2f", coupon.Value, base)
+	} else {
+		applied.Amount = domain.RoundPrice(min(coupon.Value, base))
+		applied.Explanation = fmt.Sprintf("%.2f off", applied.Amount)
+	}
+	return applied, ""
+}
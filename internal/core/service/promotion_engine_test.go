@@ -0,0 +1,237 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+)
+
+func TestPromotionEngineEvaluate(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	yesterday, tomorrow := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)
+	fiction, poetry := int64(1), int64(2)
+
+	buy3get1 := domain.AutomaticPromotion{ID: 1, Name: "3 for 2", CategoryID: fiction, BuyQuantity: 2, FreeQuantity: 1, StartsAt: yesterday, Active: true}
+	percent := &domain.Coupon{ID: 7, Code: "TEN", DiscountType: domain.DiscountPercent, Value: 10, StartsAt: yesterday, Active: true}
+	fixed := &domain.Coupon{ID: 8, Code: "FIVE", DiscountType: domain.DiscountFixed, Value: 5, StartsAt: yesterday, Active: true}
+
+	tests := []struct {
+		name          string
+		lines         []domain.BasketLine
+		promotions    []domain.AutomaticPromotion
+		coupon        *domain.Coupon
+		couponCode    string
+		usage         domain.CouponUsage
+		wantDiscount  float64
+		wantTotal     float64
+		wantApplied   []int64
+		wantRejection string
+	}{
+		{
+			name:      "no promotions",
+			lines:     []domain.BasketLine{{BookID: 1, Quantity: 2, UnitPrice: 10}},
+			wantTotal: 20,
+		},
+		{
+			name:         "cheapest book of the group is free",
+			lines:        []domain.BasketLine{{BookID: 1, CategoryID: &fiction, Quantity: 2, UnitPrice: 12}, {BookID: 2, CategoryID: &fiction, Quantity: 1, UnitPrice: 8}},
+			promotions:   []domain.AutomaticPromotion{buy3get1},
+			wantDiscount: 8,
+			wantTotal:    24,
+			wantApplied:  []int64{1},
+		},
+		{
+			name:       "group not complete",
+			lines:      []domain.BasketLine{{BookID: 1, CategoryID: &fiction, Quantity: 2, UnitPrice: 10}},
+			promotions: []domain.AutomaticPromotion{buy3get1},
+			wantTotal:  20,
+		},
+		{
+			name:         "two groups",
+			lines:        []domain.BasketLine{{BookID: 1, CategoryID: &fiction, Quantity: 6, UnitPrice: 10}, {BookID: 2, CategoryID: &fiction, Quantity: 1, UnitPrice: 1}},
+			promotions:   []domain.AutomaticPromotion{buy3get1},
+			wantDiscount: 11,
+			wantTotal:    50,
+			wantApplied:  []int64{1},
+		},
+		{
+			name:       "books of another category",
+			lines:      []domain.BasketLine{{BookID: 1, CategoryID: &poetry, Quantity: 3, UnitPrice: 10}, {BookID: 2, Quantity: 3, UnitPrice: 10}},
+			promotions: []domain.AutomaticPromotion{buy3get1},
+			wantTotal:  60,
+		},
+		{
+			name:  "a book takes part in one promotion only, the older one first",
+			lines: []domain.BasketLine{{BookID: 1, CategoryID: &fiction, Quantity: 3, UnitPrice: 10}},
+			promotions: []domain.AutomaticPromotion{
+				{ID: 2, Name: "2 for 1", CategoryID: fiction, BuyQuantity: 1, FreeQuantity: 1, StartsAt: yesterday, Active: true},
+				buy3get1,
+			},
+			wantDiscount: 10,
+			wantTotal:    20,
+			wantApplied:  []int64{1},
+		},
+		{
+			name:  "promotion not running",
+			lines: []domain.BasketLine{{BookID: 1, CategoryID: &fiction, Quantity: 3, UnitPrice: 10}},
+			promotions: []domain.AutomaticPromotion{
+				{ID: 1, CategoryID: fiction, BuyQuantity: 2, FreeQuantity: 1, StartsAt: tomorrow, Active: true},
+				{ID: 2, CategoryID: fiction, BuyQuantity: 2, FreeQuantity: 1, StartsAt: yesterday, EndsAt: &now, Active: true},
+				{ID: 3, CategoryID: fiction, BuyQuantity: 2, FreeQuantity: 1, StartsAt: yesterday},
+			},
+			wantTotal: 30,
+		},
+		{
+			name:         "percent coupon after automatic promotions",
+			lines:        []domain.BasketLine{{BookID: 1, CategoryID: &fiction, Quantity: 3, UnitPrice: 10}},
+			promotions:   []domain.AutomaticPromotion{buy3get1},
+			coupon:       percent,
+			couponCode:   "TEN",
+			wantDiscount: 12,
+			wantTotal:    18,
+			wantApplied:  []int64{1, 7},
+		},
+		{
+			name:         "fixed coupon is capped by what is left",
+			lines:        []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 3.5}},
+			coupon:       fixed,
+			couponCode:   "FIVE",
+			wantDiscount: 3.5,
+			wantTotal:    0,
+			wantApplied:  []int64{8},
+		},
+		{
+			name:         "percent coupon is rounded to cents",
+			lines:        []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 9.99}},
+			coupon:       percent,
+			couponCode:   "TEN",
+			wantDiscount: 1,
+			wantTotal:    8.99,
+			wantApplied:  []int64{7},
+		},
+		{
+			name:          "unknown coupon",
+			lines:         []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 10}},
+			couponCode:    "NOPE",
+			wantTotal:     10,
+			wantRejection: "unknown coupon code",
+		},
+		{
+			name:          "inactive coupon",
+			lines:         []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 10}},
+			coupon:        &domain.Coupon{ID: 9, Code: "OFF", DiscountType: domain.DiscountFixed, Value: 1, StartsAt: yesterday},
+			couponCode:    "OFF",
+			wantTotal:     10,
+			wantRejection: "the coupon is no longer active",
+		},
+		{
+			name:          "coupon not started",
+			lines:         []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 10}},
+			coupon:        &domain.Coupon{ID: 9, Code: "SOON", DiscountType: domain.DiscountFixed, Value: 1, StartsAt: tomorrow, Active: true},
+			couponCode:    "SOON",
+			wantTotal:     10,
+			wantRejection: "the coupon is valid from " + tomorrow.Format(time.RFC3339),
+		},
+		{
+			name:          "expired coupon",
+			lines:         []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 10}},
+			coupon:        &domain.Coupon{ID: 9, Code: "OLD", DiscountType: domain.DiscountFixed, Value: 1, StartsAt: yesterday, EndsAt: &now, Active: true},
+			couponCode:    "OLD",
+			wantTotal:     10,
+			wantRejection: "the coupon has expired",
+		},
+		{
+			name:          "usage limit reached",
+			lines:         []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 10}},
+			coupon:        &domain.Coupon{ID: 9, Code: "ONCE", DiscountType: domain.DiscountFixed, Value: 1, MaxUses: 3, StartsAt: yesterday, Active: true},
+			couponCode:    "ONCE",
+			usage:         domain.CouponUsage{Total: 3},
+			wantTotal:     10,
+			wantRejection: "the coupon has reached its usage limit",
+		},
+		{
+			name:          "usage limit of the user reached",
+			lines:         []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 10}},
+			coupon:        &domain.Coupon{ID: 9, Code: "ONCE", DiscountType: domain.DiscountFixed, Value: 1, MaxUsesPerUser: 1, StartsAt: yesterday, Active: true},
+			couponCode:    "ONCE",
+			usage:         domain.CouponUsage{Total: 1, ByUser: 1},
+			wantTotal:     10,
+			wantRejection: "you have already used this coupon the maximum number of times",
+		},
+		{
+			name:          "basket below the minimum",
+			lines:         []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 10}},
+			coupon:        &domain.Coupon{ID: 9, Code: "BIG", DiscountType: domain.DiscountFixed, Value: 1, MinBasket: 25, StartsAt: yesterday, Active: true},
+			couponCode:    "BIG",
+			wantTotal:     10,
+			wantRejection: "the coupon needs a basket of at least 25.00",
+		},
+		{
+			name:         "fixed coupon after automatic promotions",
+			lines:        []domain.BasketLine{{BookID: 1, CategoryID: &fiction, Quantity: 2, UnitPrice: 10}},
+			promotions:   []domain.AutomaticPromotion{{ID: 2, CategoryID: fiction, BuyQuantity: 1, FreeQuantity: 1, StartsAt: yesterday, Active: true}},
+			coupon:       fixed,
+			couponCode:   "FIVE",
+			wantDiscount: 15,
+			wantTotal:    5,
+			wantApplied:  []int64{2, 8},
+		},
+		{
+			name:          "nothing left to discount",
+			lines:         []domain.BasketLine{{BookID: 1, Quantity: 1, UnitPrice: 0}},
+			coupon:        fixed,
+			couponCode:    "FIVE",
+			wantRejection: "nothing left to discount",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basket := &domain.Basket{UserID: 1, Lines: tt.lines, CouponCode: tt.couponCode}
+			result := PromotionEngine{}.Evaluate(basket, tt.promotions, tt.coupon, tt.usage, now)
+
+			if result.Discount != tt.wantDiscount || result.Total != tt.wantTotal {
+				t.Errorf("discount, total = %.2f, %.2f, want %.2f, %.2f", result.Discount, result.Total, tt.wantDiscount, tt.wantTotal)
+			}
+			if result.CouponRejection != tt.wantRejection {
+				t.Errorf("coupon rejection = %q, want %q", result.CouponRejection, tt.wantRejection)
+			}
+			var applied []int64
+			for _, promotion := range result.Applied {
+				applied = append(applied, promotion.ID)
+				if promotion.Explanation == "" {
+					t.Errorf("promotion %d has no explanation", promotion.ID)
+				}
+			}
+			if len(applied) != len(tt.wantApplied) {
+				t.Fatalf("applied = %v, want %v", applied, tt.wantApplied)
+			}
+			for i := range applied {
+				if applied[i] != tt.wantApplied[i] {
+					t.Errorf("applied = %v, want %v", applied, tt.wantApplied)
+				}
+			}
+		})
+	}
+}
+
+// TestPromotionEngineIsPure evaluates the same basket twice and checks nothing it was given changed
+func TestPromotionEngineIsPure(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	fiction := int64(1)
+	basket := &domain.Basket{Lines: []domain.BasketLine{{BookID: 1, CategoryID: &fiction, Quantity: 3, UnitPrice: 10}}}
+	promotions := []domain.AutomaticPromotion{
+		{ID: 2, CategoryID: fiction, BuyQuantity: 2, FreeQuantity: 1, StartsAt: now, Active: true},
+		{ID: 1, CategoryID: fiction, BuyQuantity: 1, FreeQuantity: 1, StartsAt: now, Active: true},
+	}
+
+	first := PromotionEngine{}.Evaluate(basket, promotions, nil, domain.CouponUsage{}, now)
+	second := PromotionEngine{}.Evaluate(basket, promotions, nil, domain.CouponUsage{}, now)
+
+	if first.Total != second.Total || len(first.Applied) != len(second.Applied) {
+		t.Errorf("evaluations differ: %+v and %+v", first, second)
+	}
+	if promotions[0].ID != 2 || basket.Lines[0].Quantity != 3 {
+		t.Error("Evaluate() changed its arguments")
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type PublisherService struct {
+	repo         port.PublisherRepository
+	bookRepo     port.BookRepository
+	translations port.TranslationService
+	audit        port.AuditService
+}
+
+func NewPublisherService(repo port.PublisherRepository, bookRepo port.BookRepository, translations port.TranslationService, audit port.AuditService) *PublisherService {
+	return &PublisherService{
+		repo:         repo,
+		bookRepo:     bookRepo,
+		translations: translations,
+		audit:        audit,
+	}
+}
+
+// CreatePublisher creates a publisher with a trimmed name
+func (ps *PublisherService) CreatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error) {
+	publisher.Name = strings.TrimSpace(publisher.Name)
+	publisher, err := ps.repo.CreatePublisher(ctx, publisher)
+	if err != nil {
+		return nil, err
+	}
+	ps.audit.Record(ctx, domain.AuditPublisherCreate, domain.AuditEntityPublisher, publisher.ID, nil, publisher)
+	return publisher, nil
+}
+
+func (ps *PublisherService) GetPublisher(ctx context.Context, id int64) (*domain.Publisher, error) {
+	return ps.repo.GetPublisher(ctx, id)
+}
+
+func (ps *PublisherService) ListPublishers(ctx context.Context) ([]domain.Publisher, error) {
+	return ps.repo.ListPublishers(ctx)
+}
+
+// ListPublisherBooks returns the books of a publisher, an unknown publisher is reported as missing
+// rather than as having no books
+func (ps *PublisherService) ListPublisherBooks(ctx context.Context, id, skip, limit int64) ([]domain.Book, error) {
+	if _, err := ps.repo.GetPublisher(ctx, id); err != nil {
+		return nil, err
+	}
+	books, err := ps.bookRepo.SearchBooks(ctx, &domain.BookSearch{
+		PublisherID: id,
+		Skip:        skip,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ps.translations.LocalizeBooks(ctx, books); err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+func (ps *PublisherService) UpdatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error) {
+	publisher.Name = strings.TrimSpace(publisher.Name)
+	before, err := ps.repo.GetPublisher(ctx, publisher.ID)
+	if err != nil {
+		return nil, err
+	}
+	publisher, err = ps.repo.UpdatePublisher(ctx, publisher)
+	if err != nil {
+		return nil, err
+	}
+	ps.audit.Record(ctx, domain.AuditPublisherUpdate, domain.AuditEntityPublisher, publisher.ID, before, publisher)
+	return publisher, nil
+}
+
+func (ps *PublisherService) DeletePublisher(ctx context.Context, id int64) error {
+	before, err := ps.repo.GetPublisher(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := ps.repo.DeletePublisher(ctx, id); err != nil {
+		return err
+	}
+	ps.audit.Record(ctx, domain.AuditPublisherDelete, domain.AuditEntityPublisher, id, before, nil)
+	return nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// memoryPublishers is an in-memory port.PublisherRepository for the methods the tests use
+type memoryPublishers struct {
+	port.PublisherRepository
+	publishers map[int64]*domain.Publisher
+}
+
+func (m *memoryPublishers) CreatePublisher(ctx context.Context, publisher *domain.Publisher) (*domain.Publisher, error) {
+	publisher.ID = int64(len(m.publishers) + 1)
+	m.publishers[publisher.ID] = publisher
+	return publisher, nil
+}
+
+func (m *memoryPublishers) GetPublisher(ctx context.Context, id int64) (*domain.Publisher, error) {
+	publisher, ok := m.publishers[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return publisher, nil
+}
+
+// memorySeries is an in-memory port.SeriesRepository for the methods the tests use
+type memorySeries struct {
+	port.SeriesRepository
+	series map[int64]*domain.Series
+}
+
+func (m *memorySeries) GetSeries(ctx context.Context, id int64) (*domain.Series, error) {
+	series, ok := m.series[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	copied := *series
+	return &copied, nil
+}
+
+func TestCreatePublisherTrimsTheName(t *testing.T) {
+	publishers := &memoryPublishers{publishers: map[int64]*domain.Publisher{}}
+	ps := NewPublisherService(publishers, &memoryBooks{}, nopTranslations{}, nopAudit{})
+
+	publisher, err := ps.CreatePublisher(context.Background(), &domain.Publisher{Name: "  Ace Books \n"})
+	if err != nil {
+		t.Fatalf("CreatePublisher() error = %v", err)
+	}
+	if publisher.Name != "Ace Books" {
+		t.Errorf("name = %q, want %q", publisher.Name, "Ace Books")
+	}
+}
+
+func TestListPublisherBooks(t *testing.T) {
+	ace, gollancz := int64(1), int64(2)
+	publishers := &memoryPublishers{publishers: map[int64]*domain.Publisher{
+		ace:      {ID: ace, Name: "Ace Books"},
+		gollancz: {ID: gollancz, Name: "Gollancz"},
+		3:        {ID: 3, Name: "Tor"},
+	}}
+	books := map[int64]*domain.Book{
+		1: {ID: 1, Name: "Dune", PublisherID: &ace},
+		2: {ID: 2, Name: "The Colour of Magic", PublisherID: &gollancz},
+		3: {ID: 3, Name: "Dune Messiah", PublisherID: &ace},
+		4: {ID: 4, Name: "Self-published"},
+	}
+
+	tests := []struct {
+		name      string
+		id        int64
+		wantErr   error
+		wantBooks []int64
+	}{
+		{"books of the publisher", ace, nil, []int64{1, 3}},
+		{"publisher without books", 3, nil, nil},
+		{"unknown publisher", 9, domain.ErrDataNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookRepo := &memoryBooks{books: books}
+			ps := NewPublisherService(publishers, bookRepo, nopTranslations{}, nopAudit{})
+
+			got, err := ps.ListPublisherBooks(context.Background(), tt.id, 0, 20)
+			if err != tt.wantErr {
+				t.Fatalf("ListPublisherBooks() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(bookRepo.searches) != 0 {
+					t.Error("the books of an unknown publisher were searched")
+				}
+				return
+			}
+			var ids []int64
+			for _, book := range got {
+				ids = append(ids, book.ID)
+			}
+			if !slices.Equal(ids, tt.wantBooks) {
+				t.Errorf("books = %v, want %v", ids, tt.wantBooks)
+			}
+			if search := bookRepo.searches[0]; search.PublisherID != tt.id || search.Limit != 20 {
+				t.Errorf("search = %+v, want the books of publisher %d", search, tt.id)
+			}
+		})
+	}
+}
+
+// TestGetSeriesLoadsTheVolumes checks that a series comes with its books in the order the
+// repository lists them
+func TestGetSeriesLoadsTheVolumes(t *testing.T) {
+	dune := int64(1)
+	volume := func(v int) *int { return &v }
+	series := &memorySeries{series: map[int64]*domain.Series{dune: {ID: dune, Name: "Dune"}}}
+	books := &memoryBooks{books: map[int64]*domain.Book{
+		1: {ID: 1, Name: "Children of Dune", SeriesID: &dune, SeriesVolume: volume(3)},
+		2: {ID: 2, Name: "Dune", SeriesID: &dune, SeriesVolume: volume(1)},
+		3: {ID: 3, Name: "Dune Messiah", SeriesID: &dune, SeriesVolume: volume(2)},
+		4: {ID: 4, Name: "Hyperion"},
+	}}
+	ss := NewSeriesService(series, books, nopTranslations{}, nopAudit{})
+
+	got, err := ss.GetSeries(context.Background(), dune)
+	if err != nil {
+		t.Fatalf("GetSeries() error = %v", err)
+	}
+	var names []string
+	for _, book := range got.Volumes {
+		names = append(names, book.Name)
+	}
+	want := []string{"Dune", "Dune Messiah", "Children of Dune"}
+	if !slices.Equal(names, want) {
+		t.Errorf("volumes = %q, want %q", names, want)
+	}
+
+	if _, err := ss.GetSeries(context.Background(), 9); err != domain.ErrDataNotFound {
+		t.Errorf("GetSeries() of an unknown series error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type SeriesService struct {
+	repo         port.SeriesRepository
+	bookRepo     port.BookRepository
+	translations port.TranslationService
+	audit        port.AuditService
+}
+
+func NewSeriesService(repo port.SeriesRepository, bookRepo port.BookRepository, translations port.TranslationService, audit port.AuditService) *SeriesService {
+	return &SeriesService{
+		repo:         repo,
+		bookRepo:     bookRepo,
+		translations: translations,
+		audit:        audit,
+	}
+}
+
+// CreateSeries creates a series with a trimmed name
+func (ss *SeriesService) CreateSeries(ctx context.Context, series *domain.Series) (*domain.Series, error) {
+	series.Name = strings.TrimSpace(series.Name)
+	series, err := ss.repo.CreateSeries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+	ss.audit.Record(ctx, domain.AuditSeriesCreate, domain.AuditEntitySeries, series.ID, nil, series)
+	return series, nil
+}
+
+// GetSeries returns a series with its volumes in reading order
+func (ss *SeriesService) GetSeries(ctx context.Context, id int64) (*domain.Series, error) {
+	series, err := ss.repo.GetSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	series.Volumes, err = ss.bookRepo.ListSeriesVolumes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.translations.LocalizeBooks(ctx, series.Volumes); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (ss *SeriesService) ListSeries(ctx context.Context) ([]domain.Series, error) {
+	return ss.repo.ListSeries(ctx)
+}
+
+func (ss *SeriesService) UpdateSeries(ctx context.Context, series *domain.Series) (*domain.Series, error) {
+	series.Name = strings.TrimSpace(series.Name)
+	before, err := ss.repo.GetSeries(ctx, series.ID)
+	if err != nil {
+		return nil, err
+	}
+	series, err = ss.repo.UpdateSeries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+	ss.audit.Record(ctx, domain.AuditSeriesUpdate, domain.AuditEntitySeries, series.ID, before, series)
+	return series, nil
+}
+
+func (ss *SeriesService) DeleteSeries(ctx context.Context, id int64) error {
+	before, err := ss.repo.GetSeries(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := ss.repo.DeleteSeries(ctx, id); err != nil {
+		return err
+	}
+	ss.audit.Record(ctx, domain.AuditSeriesDelete, domain.AuditEntitySeries, id, before, nil)
+	return nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type ShippingService struct {
+	repo  port.ShippingRepository
+	audit port.AuditService
+}
+
+func NewShippingService(repo port.ShippingRepository, audit port.AuditService) *ShippingService {
+	return &ShippingService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+// CreateZone creates a shipping zone, a zone needs at least one country or the AnyCountry wildcard
+func (ss *ShippingService) CreateZone(ctx context.Context, zone *domain.ShippingZone) (*domain.ShippingZone, error) {
+	zone.Normalize()
+	if len(zone.Countries) == 0 {
+		return nil, fmt.Errorf("%w: a zone needs at least one country", domain.ErrInvalidShippingZone)
+	}
+	zone, err := ss.repo.CreateZone(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	ss.audit.Record(ctx, domain.AuditShippingZoneCreate, domain.AuditEntityShipping, zone.ID, nil, zone)
+	return zone, nil
+}
+
+// ListZones returns all shipping zones
+func (ss *ShippingService) ListZones(ctx context.Context) ([]domain.ShippingZone, error) {
+	return ss.repo.ListZones(ctx)
+}
+
+// CreateMethod validates and creates a shipping method
+func (ss *ShippingService) CreateMethod(ctx context.Context, method *domain.ShippingMethod) (*domain.ShippingMethod, error) {
+	if err := method.Validate(); err != nil {
+		return nil, err
+	}
+	method, err := ss.repo.CreateMethod(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	ss.audit.Record(ctx, domain.AuditShippingMethodCreate, domain.AuditEntityShipping, method.ID, nil, method)
+	return method, nil
+}
+
+// ListMethods returns the shipping methods of all zones
+func (ss *ShippingService) ListMethods(ctx context.Context) ([]domain.ShippingMethod, error) {
+	return ss.repo.ListMethods(ctx)
+}
+
+// DisableMethod stops offering a shipping method, orders keep the method they were shipped with
+func (ss *ShippingService) DisableMethod(ctx context.Context, id int64) error {
+	if err := ss.repo.DisableMethod(ctx, id); err != nil {
+		return err
+	}
+	ss.audit.Record(ctx, domain.AuditShippingMethodDisable, domain.AuditEntityShipping, id, map[string]bool{"active": true}, map[string]bool{"active": false})
+	return nil
+}
+
+// QuoteMethods quotes every method available for the country of the shipment, cheapest first.
+// Methods the shipment is too heavy for are left out.
+func (ss *ShippingService) QuoteMethods(ctx context.Context, shipment domain.Shipment) ([]domain.ShippingQuote, error) {
+	methods, err := ss.repo.ListMethodsForCountry(ctx, shipment.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := []domain.ShippingQuote{}
+	for _, method := range methods {
+		if quote, ok := method.Quote(shipment); ok {
+			quotes = append(quotes, quote)
+		}
+	}
+	slices.SortStableFunc(quotes, func(a, b domain.ShippingQuote) int {
+		return cmp.Compare(a.Cost, b.Cost)
+	})
+	return quotes, nil
+}
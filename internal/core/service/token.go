@@ -9,19 +9,40 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	accessTokenType    = "access"
+	challengeTokenType = "2fa_challenge"
+)
+
 type TokenService struct {
 }
 
-// CreateToken creates a new token for a given user
-func (ts *TokenService) CreateToken(user *domain.User) (string, error) {
+// CreateToken creates a new access token for a given user
+func (ts *TokenService) CreateToken(user *domain.User, twoFactorVerified bool) (string, error) {
+	return ts.createToken(user, accessTokenType, 3*24*time.Hour, jwt.MapClaims{
+		"mfa": twoFactorVerified,
+	})
+}
+
+// CreateChallengeToken creates a short lived token used between the password and the second factor steps
+func (ts *TokenService) CreateChallengeToken(user *domain.User) (string, error) {
+	return ts.createToken(user, challengeTokenType, 5*time.Minute, nil)
+}
+
+func (ts *TokenService) createToken(user *domain.User, typ string, ttl time.Duration, extra jwt.MapClaims) (string, error) {
 	claims := jwt.MapClaims{
 		"sub": user.ID,
-		"exp": time.Now().Add(3 * 24 * time.Hour).Unix(),
+		"ver": user.TokenVersion,
+		"typ": typ,
+		"exp": time.Now().Add(ttl).Unix(),
 		"iat": time.Now().Unix(),
 		"nbf": time.Now().Unix(),
 		"iss": os.Getenv("JWT_ISS"),
 		"aud": os.Getenv("JWT_AUD"),
 	}
+	for k, v := range extra {
+		claims[k] = v
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+type TranslationService struct {
+	repo          port.TranslationRepository
+	audit         port.AuditService
+	defaultLocale string
+}
+
+func NewTranslationService(repo port.TranslationRepository, audit port.AuditService, defaultLocale string) *TranslationService {
+	return &TranslationService{
+		repo:          repo,
+		audit:         audit,
+		defaultLocale: domain.NormalizeLocale(defaultLocale),
+	}
+}
+
+// translationLocale normalizes the locale of a translation. Base content is written in the
+// default locale and edited on the book or category itself, so it cannot be translated to.
+func (ts *TranslationService) translationLocale(locale string) (string, error) {
+	locale = domain.NormalizeLocale(locale)
+	if locale == "" || locale == ts.defaultLocale {
+		return "", fmt.Errorf("%w: %q is the default locale, edit the base content instead", domain.ErrInvalidTranslation, locale)
+	}
+	return locale, nil
+}
+
+func (ts *TranslationService) SetBookTranslation(ctx context.Context, translation *domain.BookTranslation) (*domain.BookTranslation, error) {
+	locale, err := ts.translationLocale(translation.Locale)
+	if err != nil {
+		return nil, err
+	}
+	translation.Locale = locale
+	translation.Name = strings.TrimSpace(translation.Name)
+	translation, err = ts.repo.UpsertBookTranslation(ctx, translation)
+	if err != nil {
+		return nil, err
+	}
+	ts.audit.Record(ctx, domain.AuditBookTranslate, domain.AuditEntityBook, translation.BookID, nil, translation)
+	return translation, nil
+}
+
+func (ts *TranslationService) ListBookTranslations(ctx context.Context, bookID int64) ([]domain.BookTranslation, error) {
+	return ts.repo.ListBookTranslations(ctx, bookID)
+}
+
+func (ts *TranslationService) DeleteBookTranslation(ctx context.Context, bookID int64, locale string) error {
+	locale = domain.NormalizeLocale(locale)
+	if err := ts.repo.DeleteBookTranslation(ctx, bookID, locale); err != nil {
+		return err
+	}
+	ts.audit.Record(ctx, domain.AuditBookTranslationDelete, domain.AuditEntityBook, bookID, map[string]string{"locale": locale}, nil)
+	return nil
+}
+
+func (ts *TranslationService) SetCategoryTranslation(ctx context.Context, translation *domain.CategoryTranslation) (*domain.CategoryTranslation, error) {
+	locale, err := ts.translationLocale(translation.Locale)
+	if err != nil {
+		return nil, err
+	}
+	translation.Locale = locale
+	translation.Name = strings.TrimSpace(translation.Name)
+	translation, err = ts.repo.UpsertCategoryTranslation(ctx, translation)
+	if err != nil {
+		return nil, err
+	}
+	ts.audit.Record(ctx, domain.AuditCategoryTranslate, domain.AuditEntityCategory, translation.CategoryID, nil, translation)
+	return translation, nil
+}
+
+func (ts *TranslationService) ListCategoryTranslations(ctx context.Context, categoryID int64) ([]domain.CategoryTranslation, error) {
+	return ts.repo.ListCategoryTranslations(ctx, categoryID)
+}
+
+func (ts *TranslationService) DeleteCategoryTranslation(ctx context.Context, categoryID int64, locale string) error {
+	locale = domain.NormalizeLocale(locale)
+	if err := ts.repo.DeleteCategoryTranslation(ctx, categoryID, locale); err != nil {
+		return err
+	}
+	ts.audit.Record(ctx, domain.AuditCategoryTranslationDelete, domain.AuditEntityCategory, categoryID, map[string]string{"locale": locale}, nil)
+	return nil
+}
+
+// translatedLocales are the locales of the chain translations are looked up in, the default
+// locale is the base content
+func translatedLocales(chain domain.LocaleChain) []string {
+	if len(chain) == 0 {
+		return nil
+	}
+	return chain[:len(chain)-1]
+}
+
+// LocalizeBooks localizes every field on its own: a translation without a description still
+// translates the name and the description falls back along the chain
+func (ts *TranslationService) LocalizeBooks(ctx context.Context, books []domain.Book) error {
+	chain := domain.LocaleFrom(ctx)
+	if len(chain) == 0 || len(books) == 0 {
+		return nil
+	}
+	for i := range books {
+		books[i].Locale = chain.Default()
+	}
+	locales := translatedLocales(chain)
+	if len(locales) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(books))
+	for i := range books {
+		ids[i] = books[i].ID
+	}
+	translations, err := ts.repo.FindBookTranslations(ctx, ids, locales)
+	if err != nil {
+		return err
+	}
+	byBook := make(map[int64]map[string]domain.BookTranslation)
+	for _, translation := range translations {
+		if byBook[translation.BookID] == nil {
+			byBook[translation.BookID] = make(map[string]domain.BookTranslation)
+		}
+		byBook[translation.BookID][translation.Locale] = translation
+	}
+
+	for i := range books {
+		book := &books[i]
+		// Walk from the least specific locale so the most specific translation wins
+		for j := len(locales) - 1; j >= 0; j-- {
+			translation, ok := byBook[book.ID][locales[j]]
+			if !ok {
+				continue
+			}
+			if translation.Name != "" {
+				book.Name = translation.Name
+				book.Locale = translation.Locale
+			}
+			if translation.Description != "" {
+				book.Description = translation.Description
+			}
+		}
+	}
+	return nil
+}
+
+func (ts *TranslationService) LocalizeCategories(ctx context.Context, categories []domain.Category) error {
+	chain := domain.LocaleFrom(ctx)
+	if len(chain) == 0 || len(categories) == 0 {
+		return nil
+	}
+	for i := range categories {
+		categories[i].Locale = chain.Default()
+	}
+	locales := translatedLocales(chain)
+	if len(locales) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(categories))
+	for i := range categories {
+		ids[i] = categories[i].ID
+	}
+	translations, err := ts.repo.FindCategoryTranslations(ctx, ids, locales)
+	if err != nil {
+		return err
+	}
+	byCategory := make(map[int64]map[string]domain.CategoryTranslation)
+	for _, translation := range translations {
+		if byCategory[translation.CategoryID] == nil {
+			byCategory[translation.CategoryID] = make(map[string]domain.CategoryTranslation)
+		}
+		byCategory[translation.CategoryID][translation.Locale] = translation
+	}
+
+	for i := range categories {
+		category := &categories[i]
+		for _, locale := range locales {
+			if translation, ok := byCategory[category.ID][locale]; ok {
+				category.Name = translation.Name
+				category.Locale = translation.Locale
+				break
+			}
+		}
+	}
+	return nil
+}
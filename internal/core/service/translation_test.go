@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+)
+
+// memoryTranslations is an in-memory port.TranslationRepository for the methods the tests use
+type memoryTranslations struct {
+	port.TranslationRepository
+	books      []domain.BookTranslation
+	categories []domain.CategoryTranslation
+	// lookups are the locales translations were looked up in
+	lookups [][]string
+}
+
+func (m *memoryTranslations) UpsertBookTranslation(ctx context.Context, translation *domain.BookTranslation) (*domain.BookTranslation, error) {
+	m.books = append(m.books, *translation)
+	return translation, nil
+}
+
+func (m *memoryTranslations) FindBookTranslations(ctx context.Context, bookIDs []int64, locales []string) ([]domain.BookTranslation, error) {
+	m.lookups = append(m.lookups, locales)
+	var found []domain.BookTranslation
+	for _, translation := range m.books {
+		if slices.Contains(bookIDs, translation.BookID) && slices.Contains(locales, translation.Locale) {
+			found = append(found, translation)
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryTranslations) FindCategoryTranslations(ctx context.Context, categoryIDs []int64, locales []string) ([]domain.CategoryTranslation, error) {
+	m.lookups = append(m.lookups, locales)
+	var found []domain.CategoryTranslation
+	for _, translation := range m.categories {
+		if slices.Contains(categoryIDs, translation.CategoryID) && slices.Contains(locales, translation.Locale) {
+			found = append(found, translation)
+		}
+	}
+	return found, nil
+}
+
+func TestLocalizeBooks(t *testing.T) {
+	translations := []domain.BookTranslation{
+		{BookID: 1, Locale: "pt", Name: "Duna", Description: "Um planeta deserto"},
+		{BookID: 1, Locale: "pt-br", Name: "Duna (Brasil)"},
+		{BookID: 2, Locale: "pt", Description: "Só a descrição"},
+		{BookID: 3, Locale: "de", Name: "Der Wüstenplanet", Description: "Ein Wüstenplanet"},
+	}
+
+	tests := []struct {
+		name       string
+		chain      domain.LocaleChain
+		wantLookup bool
+		wantBooks  []domain.Book
+	}{
+		{
+			name: "not localized",
+			wantBooks: []domain.Book{
+				{ID: 1, Name: "Dune", Description: "A desert planet"},
+				{ID: 2, Name: "Hyperion", Description: "Pilgrims"},
+				{ID: 3, Name: "Dune Messiah", Description: "A messiah"},
+			},
+		},
+		{
+			name:  "default locale is not looked up",
+			chain: domain.LocaleChain{"en"},
+			wantBooks: []domain.Book{
+				{ID: 1, Name: "Dune", Description: "A desert planet", Locale: "en"},
+				{ID: 2, Name: "Hyperion", Description: "Pilgrims", Locale: "en"},
+				{ID: 3, Name: "Dune Messiah", Description: "A messiah", Locale: "en"},
+			},
+		},
+		{
+			name:       "language",
+			chain:      domain.LocaleChain{"pt", "en"},
+			wantLookup: true,
+			wantBooks: []domain.Book{
+				{ID: 1, Name: "Duna", Description: "Um planeta deserto", Locale: "pt"},
+				{ID: 2, Name: "Hyperion", Description: "Só a descrição", Locale: "en"},
+				{ID: 3, Name: "Dune Messiah", Description: "A messiah", Locale: "en"},
+			},
+		},
+		{
+			name:       "every field falls back along the chain on its own",
+			chain:      domain.LocaleChain{"pt-br", "pt", "en"},
+			wantLookup: true,
+			wantBooks: []domain.Book{
+				{ID: 1, Name: "Duna (Brasil)", Description: "Um planeta deserto", Locale: "pt-br"},
+				{ID: 2, Name: "Hyperion", Description: "Só a descrição", Locale: "en"},
+				{ID: 3, Name: "Dune Messiah", Description: "A messiah", Locale: "en"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryTranslations{books: translations}
+			ts := NewTranslationService(repo, nopAudit{}, "en")
+			books := []domain.Book{
+				{ID: 1, Name: "Dune", Description: "A desert planet"},
+				{ID: 2, Name: "Hyperion", Description: "Pilgrims"},
+				{ID: 3, Name: "Dune Messiah", Description: "A messiah"},
+			}
+			ctx := context.Background()
+			if tt.chain != nil {
+				ctx = domain.WithLocale(ctx, tt.chain)
+			}
+
+			if err := ts.LocalizeBooks(ctx, books); err != nil {
+				t.Fatalf("LocalizeBooks() error = %v", err)
+			}
+			if looked := len(repo.lookups) > 0; looked != tt.wantLookup {
+				t.Errorf("translations looked up = %t, want %t", looked, tt.wantLookup)
+			}
+			for _, locales := range repo.lookups {
+				if slices.Contains(locales, "en") {
+					t.Errorf("translations looked up in %q, the default locale is the base content", locales)
+				}
+			}
+			for i, want := range tt.wantBooks {
+				got := books[i]
+				if got.Name != want.Name || got.Description != want.Description || got.Locale != want.Locale {
+					t.Errorf("book %d = %q, %q, %q, want %q, %q, %q", got.ID, got.Name, got.Description, got.Locale, want.Name, want.Description, want.Locale)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalizeCategories(t *testing.T) {
+	translations := []domain.CategoryTranslation{
+		{CategoryID: 1, Locale: "pt", Name: "Ficção"},
+		{CategoryID: 1, Locale: "pt-br", Name: "Ficção (Brasil)"},
+		{CategoryID: 2, Locale: "pt", Name: "Poesia"},
+	}
+
+	tests := []struct {
+		name  string
+		chain domain.LocaleChain
+		want  []string
+	}{
+		{"not localized", nil, []string{"Fiction", "Poetry", "History"}},
+		{"language", domain.LocaleChain{"pt", "en"}, []string{"Ficção", "Poesia", "History"}},
+		{"most specific locale", domain.LocaleChain{"pt-br", "pt", "en"}, []string{"Ficção (Brasil)", "Poesia", "History"}},
+		{"no translations", domain.LocaleChain{"de", "en"}, []string{"Fiction", "Poetry", "History"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTranslationService(&memoryTranslations{categories: translations}, nopAudit{}, "en")
+			categories := []domain.Category{{ID: 1, Name: "Fiction"}, {ID: 2, Name: "Poetry"}, {ID: 3, Name: "History"}}
+			ctx := context.Background()
+			if tt.chain != nil {
+				ctx = domain.WithLocale(ctx, tt.chain)
+			}
+
+			if err := ts.LocalizeCategories(ctx, categories); err != nil {
+				t.Fatalf("LocalizeCategories() error = %v", err)
+			}
+			var names []string
+			for _, category := range categories {
+				names = append(names, category.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("names = %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetBookTranslationLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		locale     string
+		wantLocale string
+		wantErr    error
+	}{
+		{"locale is normalized", " pt_BR ", "pt-br", nil},
+		{"default locale", "EN", "", domain.ErrInvalidTranslation},
+		{"no locale", "", "", domain.ErrInvalidTranslation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTranslationService(&memoryTranslations{}, nopAudit{}, "en")
+
+			got, err := ts.SetBookTranslation(context.Background(), &domain.BookTranslation{BookID: 1, Locale: tt.locale, Name: " Duna "})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetBookTranslation() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.Locale != tt.wantLocale || got.Name != "Duna") {
+				t.Errorf("translation = %q, %q, want %q, %q", got.Locale, got.Name, tt.wantLocale, "Duna")
+			}
+		})
+	}
+}
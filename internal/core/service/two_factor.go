@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
+)
+
+// recoveryCodeCount is the number of recovery codes generated on enrollment
+const recoveryCodeCount = 10
+
+type TwoFactorService struct {
+	repo   port.TwoFactorRepository
+	audit  port.AuditService
+	issuer string
+}
+
+func NewTwoFactorService(repo port.TwoFactorRepository, audit port.AuditService, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		repo:   repo,
+		audit:  audit,
+		issuer: issuer,
+	}
+}
+
+// Enroll generates a new secret, replacing any enrollment that was not confirmed yet
+func (ts *TwoFactorService) Enroll(ctx context.Context, user *domain.User) (*domain.TwoFactorEnrollment, error) {
+	current, err := ts.repo.GetTwoFactor(ctx, user.ID)
+	if err != nil && err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+	if current.IsEnabled() {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	twoFactor := &domain.TwoFactor{
+		UserID: user.ID,
+		Secret: secret,
+	}
+	if err := ts.repo.SaveTwoFactor(ctx, twoFactor); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    util.TOTPURI(ts.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication and returns the plain recovery codes, only their hashes are stored
+func (ts *TwoFactorService) Confirm(ctx context.Context, user *domain.User, code string) ([]string, error) {
+	twoFactor, err := ts.repo.GetTwoFactor(ctx, user.ID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrTwoFactorDisabled
+		}
+		return nil, domain.ErrInternal
+	}
+	if twoFactor.IsEnabled() {
+		return nil, domain.ErrTwoFactorEnabled
+	}
+
+	step, ok := util.ValidateTOTP(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactor
+	}
+
+	codes, err := util.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = util.HashToken(c)
+	}
+
+	if err := ts.repo.ConfirmTwoFactor(ctx, user.ID, step, hashes); err != nil {
+		return nil, domain.ErrInternal
+	}
+	ts.audit.Record(ctx, domain.AuditTwoFactorEnable, domain.AuditEntityTwoFactor, user.ID, nil, nil)
+	return codes, nil
+}
+
+// Disable removes the second factor after checking a TOTP or recovery code
+func (ts *TwoFactorService) Disable(ctx context.Context, user *domain.User, code string) error {
+	twoFactor, err := ts.repo.GetTwoFactor(ctx, user.ID)
+	if err != nil && err != domain.ErrDataNotFound {
+		return domain.ErrInternal
+	}
+	if !twoFactor.IsEnabled() {
+		return domain.ErrTwoFactorDisabled
+	}
+
+	if err := verifySecondFactor(ctx, ts.repo, twoFactor, code); err != nil {
+		return err
+	}
+	if err := ts.repo.DeleteTwoFactor(ctx, user.ID); err != nil {
+		return domain.ErrInternal
+	}
+	ts.audit.Record(ctx, domain.AuditTwoFactorDisable, domain.AuditEntityTwoFactor, user.ID, nil, nil)
+	return nil
+}
+
+// verifySecondFactor accepts a TOTP code that was not used before or an unused recovery code
+func verifySecondFactor(ctx context.Context, repo port.TwoFactorRepository, twoFactor *domain.TwoFactor, code string) error {
+	code = strings.ToLower(strings.TrimSpace(code))
+
+	if step, ok := util.ValidateTOTP(twoFactor.Secret, code, time.Now()); ok {
+		if err := repo.UseTwoFactorStep(ctx, twoFactor.UserID, step); err != nil {
+			if err == domain.ErrInvalidTwoFactor {
+				return err
+			}
+			return domain.ErrInternal
+		}
+		return nil
+	}
+
+	if err := repo.UseRecoveryCode(ctx, twoFactor.UserID, util.HashToken(code)); err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidTwoFactor
+		}
+		return domain.ErrInternal
+	}
+	return nil
+}
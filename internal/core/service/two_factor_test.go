@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
+)
+
+// memoryTwoFactors is an in-memory port.TwoFactorRepository for a single user
+type memoryTwoFactors struct {
+	twoFactor     *domain.TwoFactor
+	recoveryCodes map[string]bool
+}
+
+func (m *memoryTwoFactors) GetTwoFactor(ctx context.Context, userID int64) (*domain.TwoFactor, error) {
+	if m.twoFactor == nil {
+		return nil, domain.ErrDataNotFound
+	}
+	twoFactor := *m.twoFactor
+	return &twoFactor, nil
+}
+
+func (m *memoryTwoFactors) SaveTwoFactor(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	m.twoFactor = twoFactor
+	return nil
+}
+
+func (m *memoryTwoFactors) ConfirmTwoFactor(ctx context.Context, userID, step int64, recoveryCodeHashes []string) error {
+	now := time.Now()
+	m.twoFactor.ConfirmedAt = &now
+	m.twoFactor.LastUsedStep = step
+	m.recoveryCodes = map[string]bool{}
+	for _, hash := range recoveryCodeHashes {
+		m.recoveryCodes[hash] = false
+	}
+	return nil
+}
+
+func (m *memoryTwoFactors) UseTwoFactorStep(ctx context.Context, userID, step int64) error {
+	if step <= m.twoFactor.LastUsedStep {
+		return domain.ErrInvalidTwoFactor
+	}
+	m.twoFactor.LastUsedStep = step
+	return nil
+}
+
+func (m *memoryTwoFactors) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error {
+	used, ok := m.recoveryCodes[codeHash]
+	if !ok || used {
+		return domain.ErrDataNotFound
+	}
+	m.recoveryCodes[codeHash] = true
+	return nil
+}
+
+func (m *memoryTwoFactors) DeleteTwoFactor(ctx context.Context, userID int64) error {
+	m.twoFactor = nil
+	m.recoveryCodes = nil
+	return nil
+}
+
+func TestVerifySecondFactor(t *testing.T) {
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := util.TOTPStep(time.Now())
+	code := func(step int64) string {
+		c, err := util.TOTPCode(secret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	ctx := context.Background()
+	repo := &memoryTwoFactors{}
+	repo.SaveTwoFactor(ctx, &domain.TwoFactor{UserID: 1, Secret: secret})
+	repo.ConfirmTwoFactor(ctx, 1, step-2, []string{util.HashToken("abcde-fghij")})
+
+	tests := []struct {
+		name string
+		code string
+		want error
+	}{
+		{"current step", code(step), nil},
+		{"replayed code", code(step), domain.ErrInvalidTwoFactor},
+		{"next step within drift", code(step + 1), nil},
+		{"older step after a newer one", code(step), domain.ErrInvalidTwoFactor},
+		{"replayed next code", code(step + 1), domain.ErrInvalidTwoFactor},
+		{"wrong code", "123456x", domain.ErrInvalidTwoFactor},
+		{"recovery code with spaces and capitals", " ABCDE-FGHIJ ", nil},
+		{"reused recovery code", "abcde-fghij", domain.ErrInvalidTwoFactor},
+	}
+	// The cases share the repository and run in order
+	for _, tt := range tests {
+		twoFactor, _ := repo.GetTwoFactor(ctx, 1)
+		if err := verifySecondFactor(ctx, repo, twoFactor, tt.code); err != tt.want {
+			t.Errorf("%s: verifySecondFactor() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -2,19 +2,34 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/port"
 	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
 )
 
+// EmailChangeTokenTTL is how long an email change can be verified
+var EmailChangeTokenTTL = 24 * time.Hour
+
 type UserService struct {
-	repo port.UserRepository
+	repo            port.UserRepository
+	emailChangeRepo port.EmailChangeRepository
+	mailService     port.MailService
+	tokenService    port.TokenService
+	audit           port.AuditService
 }
 
-func NewUserService(repo port.UserRepository) *UserService {
+func NewUserService(repo port.UserRepository, emailChangeRepo port.EmailChangeRepository, mailService port.MailService, tokenService port.TokenService, audit port.AuditService) *UserService {
 	return &UserService{
 		repo,
+		emailChangeRepo,
+		mailService,
+		tokenService,
+		audit,
 	}
 }
 
@@ -29,6 +44,7 @@ func (us *UserService) Register(ctx context.Context, user *domain.User) (*domain
 	if err != nil {
 		return nil, err
 	}
+	us.audit.Record(ctx, domain.AuditUserRegister, domain.AuditEntityUser, user.ID, nil, user)
 	return user, nil
 }
 
@@ -43,34 +59,196 @@ func (us *UserService) GetUser(ctx context.Context, id int64) (*domain.User, err
 
 }
 
-// ListUsers lists all users
-func (us *UserService) ListUsers(ctx context.Context, skip, limit int64) ([]domain.User, error) {
+// ListUsers lists users, soft-deleted users only when includeDeleted is set
+func (us *UserService) ListUsers(ctx context.Context, skip, limit int64, includeDeleted bool) ([]domain.User, error) {
 
-	users, err := us.repo.ListUsers(ctx, skip, limit)
+	users, err := us.repo.ListUsers(ctx, skip, limit, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
-// UpdateUser updates a user's name, email, and password
-func (us *UserService) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
-	hashedPassword, err := util.HashPassword(user.Password)
+// UpdateUser updates a user's name and email
+func (us *UserService) UpdateUser(ctx context.Context, user *domain.User) (*domain.UserUpdate, error) {
+	return us.PatchUser(ctx, user.ID, &domain.UserPatch{
+		Name:    &user.Name,
+		Email:   &user.Email,
+		Version: user.Version,
+	})
+}
+
+// PatchUser applies the set fields of a patch. The name is updated right away, a new
+// email is only stored after the user followed the verification link sent to it.
+func (us *UserService) PatchUser(ctx context.Context, id int64, patch *domain.UserPatch) (*domain.UserUpdate, error) {
+	user, err := us.repo.GetUserById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	user.Password = hashedPassword
-	user, err = us.repo.UpdateUser(ctx, user)
+	// Checked up front as well since an email change alone does not write the user
+	if patch.Version != 0 && patch.Version != user.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	if patch.Name != nil && *patch.Name != user.Name {
+		before := *user
+		user.Name = *patch.Name
+		user.Version = patch.Version
+		user, err = us.repo.UpdateUser(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		us.audit.Record(ctx, domain.AuditUserUpdate, domain.AuditEntityUser, user.ID, &before, user)
+	}
+
+	update := &domain.UserUpdate{User: user}
+	if patch.Email != nil && !strings.EqualFold(*patch.Email, user.Email) {
+		if err := us.requestEmailChange(ctx, user, *patch.Email); err != nil {
+			return nil, err
+		}
+		update.PendingEmail = *patch.Email
+	}
+	return update, nil
+}
+
+func (us *UserService) requestEmailChange(ctx context.Context, user *domain.User, email string) error {
+	// Fail early if the address is taken, the unique index still guards the confirmation
+	if _, err := us.repo.GetUserByEmail(ctx, email); err == nil {
+		return domain.ErrConflictingData
+	} else if err != domain.ErrDataNotFound {
+		return err
+	}
+
+	token, err := util.GenerateRandomToken(32)
 	if err != nil {
-		return nil, err
+		return domain.ErrInternal
+	}
+	change := &domain.EmailChange{
+		UserID:    user.ID,
+		NewEmail:  email,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(EmailChangeTokenTTL),
+	}
+	if _, err := us.emailChangeRepo.CreateEmailChange(ctx, change); err != nil {
+		return domain.ErrInternal
+	}
+
+	verify := &domain.Mail{
+		To:      email,
+		Subject: "Verify your new email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the following token to confirm %s as your new email address: %s\n\nThe token expires in %s.",
+			user.Name, email, token, EmailChangeTokenTTL,
+		),
+	}
+	if err := us.mailService.Send(ctx, verify); err != nil {
+		slog.Error("failed to send email change verification", "user_id", user.ID, "error", err)
+		return domain.ErrInternal
+	}
+
+	notice := &domain.Mail{
+		To:      user.Email,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nA change of your email address to %s was requested. If this was not you, reset your password right away.",
+			user.Name, email,
+		),
 	}
+	if err := us.mailService.Send(ctx, notice); err != nil {
+		slog.Warn("failed to send email change notice", "user_id", user.ID, "error", err)
+	}
+	return nil
+}
+
+// VerifyEmailChange applies a pending email change
+func (us *UserService) VerifyEmailChange(ctx context.Context, token string) (*domain.User, error) {
+	change, err := us.emailChangeRepo.GetEmailChangeByTokenHash(ctx, util.HashToken(token))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidEmailToken
+		}
+		return nil, domain.ErrInternal
+	}
+	if !change.IsUsable(time.Now()) {
+		return nil, domain.ErrInvalidEmailToken
+	}
+
+	before, err := us.repo.GetUserById(ctx, change.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidEmailToken
+		}
+		return nil, domain.ErrInternal
+	}
+	user, err := us.emailChangeRepo.ConfirmEmailChange(ctx, change)
+	if err != nil {
+		if err == domain.ErrInvalidEmailToken || err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	us.audit.Record(ctx, domain.AuditUserEmailChange, domain.AuditEntityUser, user.ID, before, user)
 	return user, nil
 }
 
-// DeleteUser deletes a user by ID
-func (us *UserService) DeleteUser(ctx context.Context, id int64) error {
-	if err := us.repo.DeleteUser(ctx, id); err != nil {
+// ChangePassword replaces the password, invalidating every session, and returns a fresh access token.
+// The new token carries over whether the caller's session passed the second factor.
+func (us *UserService) ChangePassword(ctx context.Context, id int64, twoFactorVerified bool, currentPassword, newPassword string) (string, error) {
+	user, err := us.repo.GetUserById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	// GetUserById does not load the password hash
+	user, err = us.repo.GetUserByEmail(ctx, user.Email)
+	if err != nil {
+		return "", err
+	}
+	if err := util.ComparePassword(currentPassword, user.Password); err != nil {
+		return "", domain.ErrInvalidCredentials
+	}
+
+	hashedPassword, err := util.HashPassword(newPassword)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+	user, err = us.repo.UpdatePassword(ctx, id, hashedPassword)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+	us.audit.Record(ctx, domain.AuditUserPasswordChange, domain.AuditEntityUser, id, nil, nil)
+
+	token, err := us.tokenService.CreateToken(user, twoFactorVerified)
+	if err != nil {
+		return "", domain.ErrTokenCreation
+	}
+	return token, nil
+}
+
+// DeleteUser soft-deletes a user by ID
+func (us *UserService) DeleteUser(ctx context.Context, id, version int64) error {
+	before, err := us.repo.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := us.repo.DeleteUser(ctx, id, version); err != nil {
 		return err
 	}
+	us.audit.Record(ctx, domain.AuditUserDelete, domain.AuditEntityUser, id, before, nil)
 	return nil
 }
+
+// RestoreUser restores a soft-deleted user
+func (us *UserService) RestoreUser(ctx context.Context, id int64) (*domain.User, error) {
+	user, err := us.repo.RestoreUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	us.audit.Record(ctx, domain.AuditUserRestore, domain.AuditEntityUser, id, nil, user)
+	return user, nil
+}
+
+// PurgeDeletedUsers hard-deletes users soft-deleted longer than the retention ago, users that
+// orders still reference are kept
+func (us *UserService) PurgeDeletedUsers(ctx context.Context, retention time.Duration) (int64, error) {
+	return us.repo.PurgeUsers(ctx, time.Now().Add(-retention))
+}
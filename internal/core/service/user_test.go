@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Mazin-Ibrahim/book-store/internal/core/domain"
+	"github.com/Mazin-Ibrahim/book-store/internal/core/util"
+)
+
+func TestChangePassword(t *testing.T) {
+	hashed, err := util.HashPassword("current")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name              string
+		current           string
+		twoFactorVerified bool
+		wantToken         string
+		wantErr           error
+	}{
+		{"session without second factor", "current", false, "access:1:false", nil},
+		{"session that passed the second factor keeps it", "current", true, "access:1:true", nil},
+		{"wrong current password", "wrong", true, "", domain.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &memoryUsers{users: []*domain.User{{ID: 1, Email: "reader@example.com", Password: hashed}}}
+			us := NewUserService(users, nil, nil, fakeTokens{}, nopAudit{})
+
+			token, err := us.ChangePassword(context.Background(), 1, tt.twoFactorVerified, tt.current, "new-password")
+			if err != tt.wantErr {
+				t.Fatalf("ChangePassword() error = %v, want %v", err, tt.wantErr)
+			}
+			if token != tt.wantToken {
+				t.Errorf("ChangePassword() token = %q, want %q", token, tt.wantToken)
+			}
+
+			changed := tt.wantErr == nil
+			if err := util.ComparePassword("new-password", users.users[0].Password); (err == nil) != changed {
+				t.Errorf("password changed = %v, want %v", err == nil, changed)
+			}
+			if changed && users.users[0].TokenVersion != 1 {
+				t.Error("the sessions of the user were not invalidated")
+			}
+		})
+	}
+}